@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tidwall/buntdb"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Bunt is a Cache stored in BuntDB, for example bunt.Wrapper.DB. Expired keys are removed
+// by BuntDB itself.
+type Bunt[K comparable, V any] struct {
+	db      *buntdb.DB
+	options RemoteOptions[K]
+}
+
+var _ Cache[string, any] = (*Bunt[string, any])(nil)
+
+// NewBunt returns a Bunt cache on db.
+func NewBunt[K comparable, V any](db *buntdb.DB, options RemoteOptions[K]) *Bunt[K, V] {
+	return &Bunt[K, V]{db: db, options: options.withDefaults()}
+}
+
+// Get returns the value of key and whether it is present.
+func (b *Bunt[K, V]) Get(_ context.Context, key K) (V, bool, error) {
+	var (
+		value V
+		data  string
+	)
+
+	err := b.db.View(func(tx *buntdb.Tx) error {
+		var err error
+		data, err = tx.Get(b.options.key(key))
+
+		return err
+	})
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return value, false, nil
+	}
+
+	if err != nil {
+		return value, false, coreerrors.WrapBoundary("buntdb", "cache get", err)
+	}
+
+	if err := b.options.Codec.Unmarshal([]byte(data), &value); err != nil {
+		return value, false, err
+	}
+
+	return value, true, nil
+}
+
+// Set stores value under key.
+func (b *Bunt[K, V]) Set(_ context.Context, key K, value V, ttl time.Duration) error {
+	data, err := b.options.Codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var options *buntdb.SetOptions
+	if ttl > 0 {
+		options = &buntdb.SetOptions{Expires: true, TTL: ttl}
+	}
+
+	err = b.db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(b.options.key(key), string(data), options)
+
+		return err
+	})
+
+	return coreerrors.WrapBoundary("buntdb", "cache set", err)
+}
+
+// Delete removes keys in one transaction.
+func (b *Bunt[K, V]) Delete(_ context.Context, keys ...K) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := b.db.Update(func(tx *buntdb.Tx) error {
+		for _, key := range keys {
+			if _, err := tx.Delete(b.options.key(key)); err != nil && !errors.Is(err, buntdb.ErrNotFound) {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return coreerrors.WrapBoundary("buntdb", "cache delete", err)
+}
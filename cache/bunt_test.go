@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	"github.com/tidwall/buntdb"
+)
+
+func TestBunt(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := buntdb.Open(":memory:")
+	testutils.Equal(t, err, nil)
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	cache := NewBunt[string, testProfile](db, RemoteOptions[string]{Prefix: "profiles:", Codec: GobCodec{}})
+
+	_, ok, err := cache.Get(ctx, "ada")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ok, false)
+
+	testutils.Equal(t, cache.Set(ctx, "ada", testProfile{Name: "ada", Age: 36}, time.Hour), nil)
+	testutils.Equal(t, cache.Set(ctx, "alan", testProfile{Name: "alan"}, time.Millisecond), nil)
+
+	value, ok, err := cache.Get(ctx, "ada")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ok, true)
+	testutils.Equal(t, value, testProfile{Name: "ada", Age: 36})
+
+	err = db.View(func(tx *buntdb.Tx) error {
+		ttl, err := tx.TTL("profiles:ada")
+		testutils.Equal(t, ttl > 0 && ttl <= time.Hour, true)
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok, err = cache.Get(ctx, "alan")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ok, false)
+
+	testutils.Equal(t, cache.Delete(ctx, "ada", "missing"), nil)
+
+	_, ok, _ = cache.Get(ctx, "ada")
+	testutils.Equal(t, ok, false)
+}
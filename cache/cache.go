@@ -0,0 +1,76 @@
+// Package cache provides one generic cache interface over in-memory, Redis and BuntDB
+// storage, with pluggable codecs, a two-tier near/far layering and hit/miss metrics.
+//
+//	import "github.com/InsideGallery/core/cache"
+//
+//	near := cache.NewMemory[string, Profile](cache.MemoryOptions[string, Profile]{MaxEntries: 10_000})
+//	far := cache.NewRedis[string, Profile](client, cache.RemoteOptions[string]{Prefix: "profiles:"})
+//	profiles, err := cache.NewTiered[string, Profile](near, far, cache.TieredOptions{
+//		Invalidator: cache.NewRedisInvalidator(client, "profiles:invalidate"),
+//	})
+//
+// Every implementation is safe for concurrent use.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/InsideGallery/core/dataconv/codec"
+)
+
+// ErrTierIsNil is returned by NewTiered when a tier is missing.
+var ErrTierIsNil = errors.New("cache tier is not set")
+
+// Cache stores values of type V under keys of type K.
+type Cache[K comparable, V any] interface {
+	// Get returns the value of key and whether it is present and not expired.
+	Get(ctx context.Context, key K) (V, bool, error)
+	// Set stores value under key; a ttl of zero keeps it until it is deleted or evicted.
+	Set(ctx context.Context, key K, value V, ttl time.Duration) error
+	// Delete removes keys; missing keys are ignored.
+	Delete(ctx context.Context, keys ...K) error
+}
+
+// KeyFunc converts a key to the string stored by remote backends.
+type KeyFunc[K comparable] func(key K) string
+
+// DefaultKey formats key with fmt.Sprint, returning strings unchanged.
+func DefaultKey[K comparable](key K) string {
+	if s, ok := any(key).(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(key)
+}
+
+func keyFuncOrDefault[K comparable](keyFunc KeyFunc[K]) KeyFunc[K] {
+	if keyFunc == nil {
+		return DefaultKey[K]
+	}
+
+	return keyFunc
+}
+
+// RemoteOptions configures the Redis and BuntDB backends.
+type RemoteOptions[K comparable] struct {
+	// Prefix is prepended to every stored key.
+	Prefix string
+	// Codec encodes values; default JSONCodec.
+	Codec Codec
+	// Key converts keys to strings; default DefaultKey.
+	Key KeyFunc[K]
+}
+
+func (o RemoteOptions[K]) key(key K) string {
+	return o.Prefix + o.Key(key)
+}
+
+func (o RemoteOptions[K]) withDefaults() RemoteOptions[K] {
+	o.Codec = codec.OrDefault(o.Codec)
+	o.Key = keyFuncOrDefault(o.Key)
+
+	return o
+}
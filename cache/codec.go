@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"github.com/InsideGallery/core/dataconv/codec"
+)
+
+// Codec converts cached values to and from bytes.
+type Codec = codec.Codec
+
+// JSONCodec encodes values as JSON.
+type JSONCodec = codec.JSON
+
+// MsgpackCodec encodes values as MessagePack; struct fields use `msgpack` tags.
+type MsgpackCodec = codec.Msgpack
+
+// GobCodec encodes values with encoding/gob.
+type GobCodec = codec.Gob
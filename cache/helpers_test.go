@@ -0,0 +1,6 @@
+package cache
+
+type testProfile struct {
+	Name string `json:"name" msgpack:"name"`
+	Age  int    `json:"age" msgpack:"age"`
+}
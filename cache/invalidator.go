@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Invalidator broadcasts invalidation messages between the processes sharing a far tier.
+type Invalidator interface {
+	// Publish sends message to every subscriber, including those of this process.
+	Publish(ctx context.Context, message []byte) error
+	// Subscribe calls handler for every published message until the returned closer is closed.
+	Subscribe(ctx context.Context, handler func(message []byte)) (io.Closer, error)
+}
+
+// RedisInvalidator broadcasts over a Redis Pub/Sub channel. Messages published while a
+// subscriber is disconnected are lost, so near tiers should also have a TTL.
+type RedisInvalidator struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+var _ Invalidator = (*RedisInvalidator)(nil)
+
+// NewRedisInvalidator returns a RedisInvalidator on channel.
+func NewRedisInvalidator(client redis.UniversalClient, channel string) *RedisInvalidator {
+	return &RedisInvalidator{client: client, channel: channel}
+}
+
+// Publish publishes message on the channel.
+func (i *RedisInvalidator) Publish(ctx context.Context, message []byte) error {
+	return coreerrors.WrapBoundary("redis", "cache invalidate", i.client.Publish(ctx, i.channel, message).Err())
+}
+
+// Subscribe subscribes to the channel and returns once the subscription is confirmed.
+func (i *RedisInvalidator) Subscribe(ctx context.Context, handler func(message []byte)) (io.Closer, error) {
+	pubsub := i.client.Subscribe(ctx, i.channel)
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return nil, coreerrors.Combine(
+			coreerrors.WrapBoundary("redis", "cache subscribe", err),
+			pubsub.Close(),
+		)
+	}
+
+	go func() {
+		for message := range pubsub.Channel() {
+			handler([]byte(message.Payload))
+		}
+	}()
+
+	return pubsub, nil
+}
+
+// LocalInvalidator broadcasts to subscribers in the same process, for tests and for
+// several tiered caches sharing one far tier in one process.
+type LocalInvalidator struct {
+	mu       sync.RWMutex
+	next     uint64
+	handlers map[uint64]func(message []byte)
+}
+
+var _ Invalidator = (*LocalInvalidator)(nil)
+
+// NewLocalInvalidator returns a LocalInvalidator without subscribers.
+func NewLocalInvalidator() *LocalInvalidator {
+	return &LocalInvalidator{handlers: make(map[uint64]func(message []byte))}
+}
+
+// Publish calls every subscribed handler synchronously.
+func (i *LocalInvalidator) Publish(_ context.Context, message []byte) error {
+	i.mu.RLock()
+
+	handlers := make([]func(message []byte), 0, len(i.handlers))
+	for _, handler := range i.handlers {
+		handlers = append(handlers, handler)
+	}
+
+	i.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(message)
+	}
+
+	return nil
+}
+
+// Subscribe registers handler.
+func (i *LocalInvalidator) Subscribe(_ context.Context, handler func(message []byte)) (io.Closer, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.next++
+	id := i.next
+	i.handlers[id] = handler
+
+	return closerFunc(func() error {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+
+		delete(i.handlers, id)
+
+		return nil
+	}), nil
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
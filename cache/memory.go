@@ -0,0 +1,356 @@
+package cache
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Policy selects which entry Memory evicts when it is full.
+type Policy int
+
+// Eviction policies.
+const (
+	// PolicyLRU evicts the least recently used entry.
+	PolicyLRU Policy = iota
+	// PolicyLFU evicts the least frequently used entry, the least recently used among equals.
+	PolicyLFU
+)
+
+// MemoryOptions configures a Memory cache.
+type MemoryOptions[K comparable, V any] struct {
+	// Policy is the eviction policy; default PolicyLRU.
+	Policy Policy
+	// MaxEntries bounds the number of entries; zero means unbounded.
+	MaxEntries int
+	// MaxSize bounds the total size reported by Sizer; zero means unbounded. A value larger
+	// than MaxSize is evicted as soon as it is stored.
+	MaxSize int64
+	// Sizer returns the size of an entry; default 1 per entry.
+	Sizer func(key K, value V) int64
+	// OnEvict is called, outside the cache lock, for entries evicted by capacity or expiry.
+	OnEvict func(key K, value V)
+	// Now returns the current time; default time.Now.
+	Now func() time.Time
+}
+
+type memoryEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	size    int64
+	expires time.Time
+
+	// element is the LRU list position.
+	element *list.Element
+	// index, hits and tick are the LFU heap position and ordering.
+	index int
+	hits  uint64
+	tick  uint64
+}
+
+func (e *memoryEntry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// evictor tracks entry usage for an eviction policy.
+type evictor[K comparable, V any] interface {
+	add(entry *memoryEntry[K, V])
+	touch(entry *memoryEntry[K, V])
+	remove(entry *memoryEntry[K, V])
+	victim() *memoryEntry[K, V]
+}
+
+// Memory is an in-process cache with TTL, entry and size limits, and LRU or LFU eviction.
+// Expired entries are dropped when read or by DeleteExpired.
+type Memory[K comparable, V any] struct {
+	mu      sync.Mutex
+	options MemoryOptions[K, V]
+	entries map[K]*memoryEntry[K, V]
+	evictor evictor[K, V]
+	size    int64
+}
+
+var _ Cache[string, any] = (*Memory[string, any])(nil)
+
+// NewMemory returns an empty Memory cache.
+func NewMemory[K comparable, V any](options MemoryOptions[K, V]) *Memory[K, V] {
+	if options.Sizer == nil {
+		options.Sizer = func(K, V) int64 { return 1 }
+	}
+
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	var policy evictor[K, V] = &lruEvictor[K, V]{order: list.New()}
+	if options.Policy == PolicyLFU {
+		policy = &lfuEvictor[K, V]{}
+	}
+
+	return &Memory[K, V]{
+		options: options,
+		entries: make(map[K]*memoryEntry[K, V]),
+		evictor: policy,
+	}
+}
+
+// Get returns the value of key and whether it is present and not expired.
+func (m *Memory[K, V]) Get(_ context.Context, key K) (V, bool, error) {
+	var zero V
+
+	m.mu.Lock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		m.mu.Unlock()
+
+		return zero, false, nil
+	}
+
+	if entry.expired(m.options.Now()) {
+		m.removeEntry(entry)
+		m.mu.Unlock()
+		m.notify([]*memoryEntry[K, V]{entry})
+
+		return zero, false, nil
+	}
+
+	m.evictor.touch(entry)
+	value := entry.value
+
+	m.mu.Unlock()
+
+	return value, true, nil
+}
+
+// Set stores value under key and evicts entries until the cache is within its limits.
+func (m *Memory[K, V]) Set(_ context.Context, key K, value V, ttl time.Duration) error {
+	var expires time.Time
+	if ttl > 0 {
+		expires = m.options.Now().Add(ttl)
+	}
+
+	size := m.options.Sizer(key, value)
+
+	m.mu.Lock()
+
+	entry, ok := m.entries[key]
+	if ok {
+		m.size += size - entry.size
+		entry.value, entry.size, entry.expires = value, size, expires
+		m.evictor.touch(entry)
+	} else {
+		entry = &memoryEntry[K, V]{key: key, value: value, size: size, expires: expires}
+		m.entries[key] = entry
+		m.size += size
+		m.evictor.add(entry)
+	}
+
+	evicted := m.evict(entry)
+
+	m.mu.Unlock()
+	m.notify(evicted)
+
+	return nil
+}
+
+// Delete removes keys.
+func (m *Memory[K, V]) Delete(_ context.Context, keys ...K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if entry, ok := m.entries[key]; ok {
+			m.removeEntry(entry)
+		}
+	}
+
+	return nil
+}
+
+// DeleteExpired removes every expired entry and returns how many were removed.
+func (m *Memory[K, V]) DeleteExpired() int {
+	now := m.options.Now()
+
+	m.mu.Lock()
+
+	var expired []*memoryEntry[K, V]
+
+	for _, entry := range m.entries {
+		if entry.expired(now) {
+			m.removeEntry(entry)
+			expired = append(expired, entry)
+		}
+	}
+
+	m.mu.Unlock()
+	m.notify(expired)
+
+	return len(expired)
+}
+
+// Clear removes every entry.
+func (m *Memory[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, entry := range m.entries {
+		m.removeEntry(entry)
+	}
+}
+
+// Len returns the number of entries, including expired ones not dropped yet.
+func (m *Memory[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
+
+// Size returns the total size of the entries as reported by MemoryOptions.Sizer.
+func (m *Memory[K, V]) Size() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.size
+}
+
+// evict removes victims until the cache is within its limits; it must hold the lock.
+// The entry just written is evicted last, so that a new entry is not the LFU victim
+// before it had a chance to be read.
+func (m *Memory[K, V]) evict(written *memoryEntry[K, V]) []*memoryEntry[K, V] {
+	if !m.overLimit() {
+		return nil
+	}
+
+	var evicted []*memoryEntry[K, V]
+
+	m.evictor.remove(written)
+
+	for len(m.entries) > 1 && m.overLimit() {
+		entry := m.evictor.victim()
+		m.removeEntry(entry)
+		evicted = append(evicted, entry)
+	}
+
+	m.evictor.add(written)
+
+	if m.overLimit() {
+		m.removeEntry(written)
+		evicted = append(evicted, written)
+	}
+
+	return evicted
+}
+
+func (m *Memory[K, V]) overLimit() bool {
+	return (m.options.MaxEntries > 0 && len(m.entries) > m.options.MaxEntries) ||
+		(m.options.MaxSize > 0 && m.size > m.options.MaxSize)
+}
+
+func (m *Memory[K, V]) removeEntry(entry *memoryEntry[K, V]) {
+	delete(m.entries, entry.key)
+	m.size -= entry.size
+	m.evictor.remove(entry)
+}
+
+func (m *Memory[K, V]) notify(entries []*memoryEntry[K, V]) {
+	if m.options.OnEvict == nil {
+		return
+	}
+
+	for _, entry := range entries {
+		m.options.OnEvict(entry.key, entry.value)
+	}
+}
+
+// lruEvictor keeps entries in a list ordered from most to least recently used.
+type lruEvictor[K comparable, V any] struct {
+	order *list.List
+}
+
+func (e *lruEvictor[K, V]) add(entry *memoryEntry[K, V]) {
+	entry.element = e.order.PushFront(entry)
+}
+
+func (e *lruEvictor[K, V]) touch(entry *memoryEntry[K, V]) {
+	e.order.MoveToFront(entry.element)
+}
+
+func (e *lruEvictor[K, V]) remove(entry *memoryEntry[K, V]) {
+	e.order.Remove(entry.element)
+	entry.element = nil
+}
+
+func (e *lruEvictor[K, V]) victim() *memoryEntry[K, V] {
+	return e.order.Back().Value.(*memoryEntry[K, V]) //nolint:forcetypeassert // the list only holds entries
+}
+
+// lfuEvictor keeps entries in a min-heap ordered by hits, then by last access.
+type lfuEvictor[K comparable, V any] struct {
+	entries []*memoryEntry[K, V]
+	clock   uint64
+}
+
+func (e *lfuEvictor[K, V]) add(entry *memoryEntry[K, V]) {
+	e.clock++
+	entry.tick = e.clock
+
+	if entry.hits == 0 {
+		entry.hits = 1
+	}
+
+	heap.Push(e, entry)
+}
+
+func (e *lfuEvictor[K, V]) touch(entry *memoryEntry[K, V]) {
+	e.clock++
+	entry.hits++
+	entry.tick = e.clock
+	heap.Fix(e, entry.index)
+}
+
+func (e *lfuEvictor[K, V]) remove(entry *memoryEntry[K, V]) {
+	heap.Remove(e, entry.index)
+}
+
+func (e *lfuEvictor[K, V]) victim() *memoryEntry[K, V] {
+	return e.entries[0]
+}
+
+// Len, Less, Swap, Push and Pop implement heap.Interface.
+func (e *lfuEvictor[K, V]) Len() int {
+	return len(e.entries)
+}
+
+func (e *lfuEvictor[K, V]) Less(i, j int) bool {
+	if e.entries[i].hits != e.entries[j].hits {
+		return e.entries[i].hits < e.entries[j].hits
+	}
+
+	return e.entries[i].tick < e.entries[j].tick
+}
+
+func (e *lfuEvictor[K, V]) Swap(i, j int) {
+	e.entries[i], e.entries[j] = e.entries[j], e.entries[i]
+	e.entries[i].index = i
+	e.entries[j].index = j
+}
+
+func (e *lfuEvictor[K, V]) Push(x any) {
+	entry := x.(*memoryEntry[K, V]) //nolint:forcetypeassert // the heap only holds entries
+	entry.index = len(e.entries)
+	e.entries = append(e.entries, entry)
+}
+
+func (e *lfuEvictor[K, V]) Pop() any {
+	last := len(e.entries) - 1
+	entry := e.entries[last]
+	e.entries[last] = nil
+	e.entries = e.entries[:last]
+	entry.index = -1
+
+	return entry
+}
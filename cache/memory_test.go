@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lru evicts the least recently used entry", func(t *testing.T) {
+		var evicted []string
+
+		cache := NewMemory(MemoryOptions[string, int]{
+			MaxEntries: 2,
+			OnEvict:    func(key string, _ int) { evicted = append(evicted, key) },
+		})
+
+		testutils.Equal(t, cache.Set(ctx, "a", 1, 0), nil)
+		testutils.Equal(t, cache.Set(ctx, "b", 2, 0), nil)
+
+		_, ok, _ := cache.Get(ctx, "a")
+		testutils.Equal(t, ok, true)
+
+		testutils.Equal(t, cache.Set(ctx, "c", 3, 0), nil)
+		testutils.Equal(t, evicted, []string{"b"})
+		testutils.Equal(t, cache.Len(), 2)
+	})
+
+	t.Run("lfu evicts the least frequently used entry", func(t *testing.T) {
+		cache := NewMemory(MemoryOptions[string, int]{Policy: PolicyLFU, MaxEntries: 2})
+
+		testutils.Equal(t, cache.Set(ctx, "a", 1, 0), nil)
+		testutils.Equal(t, cache.Set(ctx, "b", 2, 0), nil)
+
+		for range 3 {
+			_, _, _ = cache.Get(ctx, "a")
+		}
+
+		_, _, _ = cache.Get(ctx, "b")
+
+		testutils.Equal(t, cache.Set(ctx, "c", 3, 0), nil)
+
+		_, ok, _ := cache.Get(ctx, "b")
+		testutils.Equal(t, ok, false)
+
+		value, ok, _ := cache.Get(ctx, "a")
+		testutils.Equal(t, ok, true)
+		testutils.Equal(t, value, 1)
+	})
+
+	t.Run("lfu breaks ties by recency", func(t *testing.T) {
+		cache := NewMemory(MemoryOptions[string, int]{Policy: PolicyLFU, MaxEntries: 2})
+
+		testutils.Equal(t, cache.Set(ctx, "a", 1, 0), nil)
+		testutils.Equal(t, cache.Set(ctx, "b", 2, 0), nil)
+		testutils.Equal(t, cache.Set(ctx, "c", 3, 0), nil)
+
+		_, ok, _ := cache.Get(ctx, "a")
+		testutils.Equal(t, ok, false)
+	})
+
+	t.Run("size accounting bounds the total size", func(t *testing.T) {
+		cache := NewMemory(MemoryOptions[string, string]{
+			MaxSize: 10,
+			Sizer:   func(key, value string) int64 { return int64(len(key) + len(value)) },
+		})
+
+		testutils.Equal(t, cache.Set(ctx, "a", "1234", 0), nil)
+		testutils.Equal(t, cache.Set(ctx, "b", "1234", 0), nil)
+		testutils.Equal(t, cache.Size(), int64(10))
+
+		testutils.Equal(t, cache.Set(ctx, "a", "12", 0), nil)
+		testutils.Equal(t, cache.Size(), int64(8))
+
+		testutils.Equal(t, cache.Set(ctx, "c", "1234", 0), nil)
+		testutils.Equal(t, cache.Size(), int64(8))
+		testutils.Equal(t, cache.Len(), 2)
+
+		testutils.Equal(t, cache.Set(ctx, "huge", "12345678901", 0), nil)
+		testutils.Equal(t, cache.Len(), 0)
+		testutils.Equal(t, cache.Size(), int64(0))
+	})
+
+	t.Run("expired entries are dropped", func(t *testing.T) {
+		now := time.Unix(1_700_000_000, 0)
+
+		var evicted int
+
+		cache := NewMemory(MemoryOptions[string, int]{
+			Now:     func() time.Time { return now },
+			OnEvict: func(string, int) { evicted++ },
+		})
+
+		testutils.Equal(t, cache.Set(ctx, "a", 1, time.Minute), nil)
+		testutils.Equal(t, cache.Set(ctx, "b", 2, time.Hour), nil)
+		testutils.Equal(t, cache.Set(ctx, "c", 3, 0), nil)
+
+		now = now.Add(time.Minute)
+
+		_, ok, _ := cache.Get(ctx, "a")
+		testutils.Equal(t, ok, false)
+		testutils.Equal(t, cache.Len(), 2)
+
+		now = now.Add(time.Hour)
+
+		testutils.Equal(t, cache.DeleteExpired(), 1)
+		testutils.Equal(t, evicted, 2)
+
+		value, ok, _ := cache.Get(ctx, "c")
+		testutils.Equal(t, ok, true)
+		testutils.Equal(t, value, 3)
+	})
+
+	t.Run("delete and clear", func(t *testing.T) {
+		for _, policy := range []Policy{PolicyLRU, PolicyLFU} {
+			cache := NewMemory(MemoryOptions[int, int]{Policy: policy, MaxEntries: 10})
+
+			for i := range 5 {
+				testutils.Equal(t, cache.Set(ctx, i, i, 0), nil)
+			}
+
+			testutils.Equal(t, cache.Delete(ctx, 1, 3, 7), nil)
+			testutils.Equal(t, cache.Len(), 3)
+
+			cache.Clear()
+			testutils.Equal(t, cache.Len(), 0)
+			testutils.Equal(t, cache.Size(), int64(0))
+		}
+	})
+}
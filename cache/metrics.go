@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/InsideGallery/core/metrics"
+)
+
+// Metric names recorded by Instrumented, tagged with cache and operation.
+const (
+	MetricHits   = "cache.hits"
+	MetricMisses = "cache.misses"
+	MetricErrors = "cache.errors"
+
+	operationGet    = "get"
+	operationSet    = "set"
+	operationDelete = "delete"
+)
+
+// Instrumented counts hits, misses and errors of a wrapped Cache.
+type Instrumented[K comparable, V any] struct {
+	cache  Cache[K, V]
+	client *metrics.Client
+	name   string
+}
+
+var _ Cache[string, any] = (*Instrumented[string, any])(nil)
+
+// WithMetrics wraps cache to send counts to client tagged with name. Wrap the tiers of a
+// Tiered cache separately to see the near and far hit ratios.
+func WithMetrics[K comparable, V any](cache Cache[K, V], client *metrics.Client, name string) *Instrumented[K, V] {
+	return &Instrumented[K, V]{cache: cache, client: client, name: name}
+}
+
+// Get reads key and counts a hit, a miss or an error.
+func (i *Instrumented[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	value, ok, err := i.cache.Get(ctx, key)
+
+	switch {
+	case err != nil:
+		i.count(MetricErrors, operationGet)
+	case ok:
+		i.count(MetricHits, operationGet)
+	default:
+		i.count(MetricMisses, operationGet)
+	}
+
+	return value, ok, err
+}
+
+// Set writes key and counts errors.
+func (i *Instrumented[K, V]) Set(ctx context.Context, key K, value V, ttl time.Duration) error {
+	err := i.cache.Set(ctx, key, value, ttl)
+	if err != nil {
+		i.count(MetricErrors, operationSet)
+	}
+
+	return err
+}
+
+// Delete removes keys and counts errors.
+func (i *Instrumented[K, V]) Delete(ctx context.Context, keys ...K) error {
+	err := i.cache.Delete(ctx, keys...)
+	if err != nil {
+		i.count(MetricErrors, operationDelete)
+	}
+
+	return err
+}
+
+func (i *Instrumented[K, V]) count(name, operation string) {
+	err := i.client.Count(name, 1, []string{"cache:" + i.name, "operation:" + operation})
+	if err != nil {
+		slog.Default().Warn("record cache metric failed", "metric", name, "error", err)
+	}
+}
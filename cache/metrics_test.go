@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/metrics"
+)
+
+type countingProcessor struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (p *countingProcessor) Close() error {
+	return nil
+}
+
+func (p *countingProcessor) Count(name string, value int64, tags []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.counts[name+" "+metrics.TagSet(tags)] += value
+
+	return nil
+}
+
+func (p *countingProcessor) Gauge(string, float64, []string) error {
+	return nil
+}
+
+func (p *countingProcessor) Distribution(string, float64, []string) error {
+	return nil
+}
+
+func TestWithMetrics(t *testing.T) {
+	ctx := context.Background()
+	processor := &countingProcessor{counts: make(map[string]int64)}
+	kind := "cache-" + t.Name()
+
+	metrics.Register(kind, func(metrics.Config, string) (metrics.Processor, error) {
+		return processor, nil
+	})
+
+	client, err := metrics.New(metrics.Config{Processors: []string{kind}}, "test")
+	testutils.Equal(t, err, nil)
+
+	cache := WithMetrics[string, int](NewMemory(MemoryOptions[string, int]{}), client, "profiles")
+
+	testutils.Equal(t, cache.Set(ctx, "a", 1, 0), nil)
+
+	_, _, _ = cache.Get(ctx, "a")
+	_, _, _ = cache.Get(ctx, "a")
+	_, _, _ = cache.Get(ctx, "b")
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+
+	tags := metrics.TagSet([]string{"cache:profiles", "operation:get"})
+	testutils.Equal(t, processor.counts[MetricHits+" "+tags], int64(2))
+	testutils.Equal(t, processor.counts[MetricMisses+" "+tags], int64(1))
+	testutils.Equal(t, processor.counts[MetricErrors+" "+tags], int64(0))
+}
+
+func TestWithMetricsNilClient(t *testing.T) {
+	cache := WithMetrics[string, int](NewMemory(MemoryOptions[string, int]{}), nil, "profiles")
+
+	_, ok, err := cache.Get(context.Background(), "a")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ok, false)
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Redis is a Cache stored in Redis. Keys and values are encoded by RemoteOptions.
+type Redis[K comparable, V any] struct {
+	client  redis.UniversalClient
+	options RemoteOptions[K]
+}
+
+var _ Cache[string, any] = (*Redis[string, any])(nil)
+
+// NewRedis returns a Redis cache on client, for example redis.Connection.UniversalClient.
+func NewRedis[K comparable, V any](client redis.UniversalClient, options RemoteOptions[K]) *Redis[K, V] {
+	return &Redis[K, V]{client: client, options: options.withDefaults()}
+}
+
+// Get returns the value of key and whether it is present.
+func (r *Redis[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	var value V
+
+	data, err := r.client.Get(ctx, r.options.key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return value, false, nil
+	}
+
+	if err != nil {
+		return value, false, coreerrors.WrapBoundary("redis", "cache get", err)
+	}
+
+	if err := r.options.Codec.Unmarshal(data, &value); err != nil {
+		return value, false, err
+	}
+
+	return value, true, nil
+}
+
+// Set stores value under key.
+func (r *Redis[K, V]) Set(ctx context.Context, key K, value V, ttl time.Duration) error {
+	data, err := r.options.Codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return coreerrors.WrapBoundary("redis", "cache set", r.client.Set(ctx, r.options.key(key), data, ttl).Err())
+}
+
+// Delete removes keys one command per key, so keys on different Cluster slots work.
+func (r *Redis[K, V]) Delete(ctx context.Context, keys ...K) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, r.options.key(key))
+		}
+
+		return nil
+	})
+
+	return coreerrors.WrapBoundary("redis", "cache delete", err)
+}
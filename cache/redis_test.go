@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/redis/redistest"
+)
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	client, server := redistest.NewClient(t)
+
+	cache := NewRedis[int, testProfile](client, RemoteOptions[int]{Prefix: "profiles:", Codec: MsgpackCodec{}})
+
+	_, ok, err := cache.Get(ctx, 1)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ok, false)
+
+	testutils.Equal(t, cache.Set(ctx, 1, testProfile{Name: "ada"}, time.Minute), nil)
+	testutils.Equal(t, cache.Set(ctx, 2, testProfile{Name: "alan"}, 0), nil)
+	testutils.Equal(t, server.Exists("profiles:1"), true)
+	testutils.Equal(t, server.TTL("profiles:1"), time.Minute)
+
+	value, ok, err := cache.Get(ctx, 1)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ok, true)
+	testutils.Equal(t, value, testProfile{Name: "ada"})
+
+	server.FastForward(time.Minute)
+
+	_, ok, err = cache.Get(ctx, 1)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ok, false)
+
+	testutils.Equal(t, cache.Delete(ctx, 1, 2), nil)
+	testutils.Equal(t, server.Exists("profiles:2"), false)
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/InsideGallery/core/dataconv/codec"
+)
+
+// DefaultNearTTL caps the near-tier TTL of a Tiered cache when TieredOptions.NearTTL is
+// not set.
+const DefaultNearTTL = time.Minute
+
+// TieredOptions configures a Tiered cache.
+type TieredOptions struct {
+	// NearTTL caps how long a value stays in the near tier; default DefaultNearTTL. Values
+	// read from the far tier do not carry their remaining TTL and are kept for NearTTL.
+	NearTTL time.Duration
+	// Invalidator broadcasts writes to the near tiers of other processes; nil disables it.
+	Invalidator Invalidator
+	// KeyCodec encodes keys in invalidation messages; default JSONCodec.
+	KeyCodec Codec
+}
+
+// invalidation is the message broadcast on every write.
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   [][]byte `json:"keys"`
+}
+
+// Tiered layers a fast near cache, usually Memory, over a shared far cache such as Redis.
+// Reads fill the near tier from the far tier; writes go to both and invalidate the near
+// tiers of other processes through the Invalidator.
+type Tiered[K comparable, V any] struct {
+	near         Cache[K, V]
+	far          Cache[K, V]
+	options      TieredOptions
+	origin       string
+	subscription io.Closer
+}
+
+var _ Cache[string, any] = (*Tiered[string, any])(nil)
+
+// NewTiered returns a Tiered cache and subscribes to the invalidator when one is set.
+func NewTiered[K comparable, V any](near, far Cache[K, V], options TieredOptions) (*Tiered[K, V], error) {
+	if near == nil || far == nil {
+		return nil, ErrTierIsNil
+	}
+
+	if options.NearTTL <= 0 {
+		options.NearTTL = DefaultNearTTL
+	}
+
+	options.KeyCodec = codec.OrDefault(options.KeyCodec)
+
+	t := &Tiered[K, V]{near: near, far: far, options: options, origin: uuid.NewString()}
+
+	if options.Invalidator != nil {
+		subscription, err := options.Invalidator.Subscribe(context.Background(), t.invalidate)
+		if err != nil {
+			return nil, err
+		}
+
+		t.subscription = subscription
+	}
+
+	return t, nil
+}
+
+// Get returns the near value of key, falling back to the far tier and keeping a far hit
+// in the near tier. Near-tier errors are logged and treated as misses.
+func (t *Tiered[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	value, ok, err := t.near.Get(ctx, key)
+	if err != nil {
+		slog.Default().Warn("Error reading near cache", "key", key, "err", err)
+	}
+
+	if ok {
+		return value, true, nil
+	}
+
+	value, ok, err = t.far.Get(ctx, key)
+	if err != nil || !ok {
+		return value, false, err
+	}
+
+	if err := t.near.Set(ctx, key, value, t.nearTTL(0)); err != nil {
+		slog.Default().Warn("Error filling near cache", "key", key, "err", err)
+	}
+
+	return value, true, nil
+}
+
+// Set writes value to the far tier, then to the near tier, then broadcasts the key.
+func (t *Tiered[K, V]) Set(ctx context.Context, key K, value V, ttl time.Duration) error {
+	if err := t.far.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	if err := t.near.Set(ctx, key, value, t.nearTTL(ttl)); err != nil {
+		return err
+	}
+
+	return t.publish(ctx, key)
+}
+
+// Delete removes keys from both tiers, then broadcasts them.
+func (t *Tiered[K, V]) Delete(ctx context.Context, keys ...K) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := t.far.Delete(ctx, keys...); err != nil {
+		return err
+	}
+
+	if err := t.near.Delete(ctx, keys...); err != nil {
+		return err
+	}
+
+	return t.publish(ctx, keys...)
+}
+
+// Close stops listening for invalidations.
+func (t *Tiered[K, V]) Close() error {
+	if t.subscription == nil {
+		return nil
+	}
+
+	return t.subscription.Close()
+}
+
+func (t *Tiered[K, V]) nearTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > t.options.NearTTL {
+		return t.options.NearTTL
+	}
+
+	return ttl
+}
+
+func (t *Tiered[K, V]) publish(ctx context.Context, keys ...K) error {
+	if t.options.Invalidator == nil {
+		return nil
+	}
+
+	message := invalidation{Origin: t.origin, Keys: make([][]byte, len(keys))}
+
+	for i, key := range keys {
+		data, err := t.options.KeyCodec.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		message.Keys[i] = data
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return t.options.Invalidator.Publish(ctx, data)
+}
+
+// invalidate drops the broadcast keys from the near tier, ignoring messages of this cache.
+func (t *Tiered[K, V]) invalidate(data []byte) {
+	var message invalidation
+	if err := json.Unmarshal(data, &message); err != nil {
+		slog.Default().Warn("Dropping unreadable cache invalidation", "err", err)
+
+		return
+	}
+
+	if message.Origin == t.origin {
+		return
+	}
+
+	keys := make([]K, 0, len(message.Keys))
+
+	for _, encoded := range message.Keys {
+		var key K
+		if err := t.options.KeyCodec.Unmarshal(encoded, &key); err != nil {
+			slog.Default().Warn("Dropping unreadable cache invalidation key", "err", err)
+
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := t.near.Delete(context.Background(), keys...); err != nil {
+		slog.Default().Warn("Error invalidating near cache", "keys", len(keys), "err", err)
+	}
+}
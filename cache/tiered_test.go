@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/redis/redistest"
+)
+
+func newTestTiered(t *testing.T, far Cache[string, int], invalidator Invalidator) (*Tiered[string, int], *Memory[string, int]) {
+	t.Helper()
+
+	near := NewMemory(MemoryOptions[string, int]{})
+
+	tiered, err := NewTiered[string, int](near, far, TieredOptions{NearTTL: time.Minute, Invalidator: invalidator})
+	testutils.Equal(t, err, nil)
+
+	t.Cleanup(func() {
+		_ = tiered.Close()
+	})
+
+	return tiered, near
+}
+
+func TestTiered(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reads fill the near tier", func(t *testing.T) {
+		far := NewMemory(MemoryOptions[string, int]{})
+		tiered, near := newTestTiered(t, far, nil)
+
+		testutils.Equal(t, far.Set(ctx, "a", 1, 0), nil)
+
+		value, ok, err := tiered.Get(ctx, "a")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, ok, true)
+		testutils.Equal(t, value, 1)
+
+		value, ok, _ = near.Get(ctx, "a")
+		testutils.Equal(t, ok, true)
+		testutils.Equal(t, value, 1)
+
+		_, ok, err = tiered.Get(ctx, "missing")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, ok, false)
+	})
+
+	t.Run("writes invalidate the near tiers of other caches", func(t *testing.T) {
+		client, _ := redistest.NewClient(t)
+		far := NewRedis[string, int](client, RemoteOptions[string]{Prefix: "tiered:"})
+
+		first, firstNear := newTestTiered(t, far, NewRedisInvalidator(client, "tiered:invalidate"))
+		second, secondNear := newTestTiered(t, far, NewRedisInvalidator(client, "tiered:invalidate"))
+
+		testutils.Equal(t, first.Set(ctx, "a", 1, 0), nil)
+
+		value, _, _ := second.Get(ctx, "a")
+		testutils.Equal(t, value, 1)
+
+		testutils.Equal(t, first.Set(ctx, "a", 2, 0), nil)
+
+		waitFor(t, func() bool {
+			_, ok, _ := secondNear.Get(ctx, "a")
+
+			return !ok
+		})
+
+		value, _, _ = second.Get(ctx, "a")
+		testutils.Equal(t, value, 2)
+
+		value, ok, _ := firstNear.Get(ctx, "a")
+		testutils.Equal(t, ok, true)
+		testutils.Equal(t, value, 2)
+
+		testutils.Equal(t, second.Delete(ctx, "a"), nil)
+
+		waitFor(t, func() bool {
+			_, ok, _ := firstNear.Get(ctx, "a")
+
+			return !ok
+		})
+
+		_, ok, _ = first.Get(ctx, "a")
+		testutils.Equal(t, ok, false)
+	})
+
+	t.Run("near ttl caps the write ttl", func(t *testing.T) {
+		invalidator := NewLocalInvalidator()
+		far := NewMemory(MemoryOptions[string, int]{})
+		tiered, _ := newTestTiered(t, far, invalidator)
+
+		testutils.Equal(t, tiered.nearTTL(0), time.Minute)
+		testutils.Equal(t, tiered.nearTTL(time.Hour), time.Minute)
+		testutils.Equal(t, tiered.nearTTL(time.Second), time.Second)
+	})
+
+	t.Run("near ttl defaults to a finite ttl", func(t *testing.T) {
+		tiered, err := NewTiered[string, int](
+			NewMemory(MemoryOptions[string, int]{}), NewMemory(MemoryOptions[string, int]{}), TieredOptions{},
+		)
+		testutils.Equal(t, err, nil)
+
+		testutils.Equal(t, tiered.nearTTL(0), DefaultNearTTL)
+		testutils.Equal(t, tiered.nearTTL(time.Second), time.Second)
+	})
+
+	t.Run("missing tier", func(t *testing.T) {
+		_, err := NewTiered[string, int](nil, NewMemory(MemoryOptions[string, int]{}), TieredOptions{})
+		testutils.Equal(t, errors.Is(err, ErrTierIsNil), true)
+	})
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition was not met in time")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
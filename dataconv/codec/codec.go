@@ -0,0 +1,77 @@
+// Package codec provides the value encodings shared by the cache and Redis helpers.
+//
+//	import "github.com/InsideGallery/core/dataconv/codec"
+//
+//	data, err := codec.OrDefault(nil).Marshal(profile)
+//
+// JSON is the default. Its output is deterministic for a given value, which helpers that
+// compare encoded bytes, such as Redis set members, rely on.
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec converts values to and from bytes.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSON encodes values as JSON.
+type JSON struct{}
+
+// Marshal encodes v as JSON.
+func (JSON) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes JSON data into v.
+func (JSON) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Msgpack encodes values as MessagePack; struct fields use `msgpack` tags.
+type Msgpack struct{}
+
+// Marshal encodes v as MessagePack.
+func (Msgpack) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal decodes MessagePack data into v.
+func (Msgpack) Unmarshal(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+// Gob encodes values with encoding/gob. Every value carries its type description, so it
+// suits few large values better than many small ones.
+type Gob struct{}
+
+// Marshal encodes v with gob.
+func (Gob) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes gob data into v.
+func (Gob) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// OrDefault returns c, or JSON when c is nil.
+func OrDefault(c Codec) Codec { //nolint:ireturn // codecs are pluggable
+	if c == nil {
+		return JSON{}
+	}
+
+	return c
+}
@@ -0,0 +1,34 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+)
+
+type profile struct {
+	Name string `json:"name" msgpack:"name"`
+	Age  int    `json:"age" msgpack:"age"`
+}
+
+func TestCodecs(t *testing.T) {
+	for name, c := range map[string]Codec{
+		"json":    JSON{},
+		"msgpack": Msgpack{},
+		"gob":     Gob{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := c.Marshal(profile{Name: "ada", Age: 36})
+			testutils.Equal(t, err, nil)
+
+			var decoded profile
+			testutils.Equal(t, c.Unmarshal(data, &decoded), nil)
+			testutils.Equal(t, decoded, profile{Name: "ada", Age: 36})
+		})
+	}
+}
+
+func TestOrDefault(t *testing.T) {
+	testutils.Equal(t, OrDefault(nil), Codec(JSON{}))
+	testutils.Equal(t, OrDefault(Gob{}), Codec(Gob{}))
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	aero "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/InsideGallery/core/db/instrument"
 )
 
 type Mapper interface {
@@ -55,6 +57,7 @@ func NewBin(name string, value interface{}) *aero.Bin {
 type NamespaceInstance struct {
 	conn      *aero.Client
 	namespace string
+	observer  instrument.Observer
 }
 
 func NewNamespaceInstance(namespace string, names ...string) (*NamespaceInstance, error) {
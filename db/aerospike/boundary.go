@@ -19,6 +19,7 @@ import (
 
 	aero "github.com/aerospike/aerospike-client-go/v7"
 
+	"github.com/InsideGallery/core/db/instrument"
 	coreerrors "github.com/InsideGallery/core/errors"
 )
 
@@ -83,10 +84,17 @@ func (ni *NamespaceInstance) PutRecord(ctx context.Context, options PutOptions)
 		bins[name] = value
 	}
 
-	if err := ni.Put(nil, options.Key.Set, options.Key.Value, bins); err != nil {
+	done := ni.observe(ctx, "put record", options.Key.Set)
+
+	err := ni.Put(nil, options.Key.Set, options.Key.Value, bins)
+	if err != nil {
+		done(0, err)
+
 		return Result{}, coreerrors.WrapBoundary("aerospike", "put record", err)
 	}
 
+	done(1, nil)
+
 	return Result{Affected: 1}, nil
 }
 
@@ -96,15 +104,23 @@ func (ni *NamespaceInstance) GetRecord(ctx context.Context, options GetOptions)
 		return RecordResult{}, coreerrors.WrapBoundary("aerospike", "get record", err)
 	}
 
+	done := ni.observe(ctx, "get record", options.Key.Set)
+
 	record, err := ni.Get(nil, options.Key.Set, options.Key.Value, options.BinNames...)
 	if err != nil {
+		done(0, err)
+
 		return RecordResult{}, coreerrors.WrapBoundary("aerospike", "get record", err)
 	}
 
 	if record == nil {
+		done(0, nil)
+
 		return RecordResult{}, nil
 	}
 
+	done(1, nil)
+
 	return RecordResult{
 		Found:  true,
 		Record: newRecord(options.Key, record),
@@ -117,18 +133,40 @@ func (ni *NamespaceInstance) DeleteRecord(ctx context.Context, options DeleteOpt
 		return Result{}, coreerrors.WrapBoundary("aerospike", "delete record", err)
 	}
 
+	done := ni.observe(ctx, "delete record", options.Key.Set)
+
 	deleted, err := ni.Delete(nil, options.Key.Set, options.Key.Value)
 	if err != nil {
+		done(0, err)
+
 		return Result{}, coreerrors.WrapBoundary("aerospike", "delete record", err)
 	}
 
 	if !deleted {
+		done(0, nil)
+
 		return Result{}, nil
 	}
 
+	done(1, nil)
+
 	return Result{Affected: 1, Deleted: true}, nil
 }
 
+// SetObserver sets the observer of the core-owned record operations; nil uses
+// instrument.Default. Call it before the instance is shared.
+func (ni *NamespaceInstance) SetObserver(observer instrument.Observer) {
+	ni.observer = observer
+}
+
+func (ni *NamespaceInstance) observe(ctx context.Context, operation, set string) func(rows int64, err error) {
+	return instrument.Begin(ctx, ni.observer, instrument.Event{
+		System:    instrument.SystemAerospike,
+		Operation: operation,
+		Target:    ni.namespace + "." + set,
+	})
+}
+
 func newRecord(key Key, record *aero.Record) Record {
 	bins := make(map[string]any, len(record.Bins))
 	for name, value := range record.Bins {
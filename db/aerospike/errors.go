@@ -1,8 +1,36 @@
 package aerospike
 
-import "errors"
+import (
+	"errors"
+
+	aero "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
 
 // All kind of errors for aerospike
 var (
 	ErrConnectionIsNotSet = errors.New("connection is not set")
 )
+
+func init() {
+	coreerrors.RegisterClassifier(classifyError)
+}
+
+// classifyError maps missing keys to not found and key or generation clashes to conflicts.
+func classifyError(err error) coreerrors.Category {
+	var aeroErr aero.Error
+	if !errors.As(err, &aeroErr) {
+		return coreerrors.CategoryUnknown
+	}
+
+	switch {
+	case aeroErr.Matches(types.KEY_NOT_FOUND_ERROR):
+		return coreerrors.CategoryNotFound
+	case aeroErr.Matches(types.KEY_EXISTS_ERROR, types.GENERATION_ERROR):
+		return coreerrors.CategoryConflict
+	default:
+		return coreerrors.CategoryUnknown
+	}
+}
@@ -0,0 +1,53 @@
+package aerospike
+
+import (
+	"errors"
+	"testing"
+
+	aero "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestErrorCategory(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want coreerrors.Category
+	}{
+		{
+			name: "missing key",
+			err:  coreerrors.WrapBoundary("aerospike", "get record", aero.ErrKeyNotFound),
+			want: coreerrors.CategoryNotFound,
+		},
+		{
+			name: "generation clash",
+			err:  coreerrors.WrapBoundary("aerospike", "put record", &aero.AerospikeError{ResultCode: types.GENERATION_ERROR}),
+			want: coreerrors.CategoryConflict,
+		},
+		{
+			name: "key exists",
+			err:  &aero.AerospikeError{ResultCode: types.KEY_EXISTS_ERROR},
+			want: coreerrors.CategoryConflict,
+		},
+		{
+			name: "timeout",
+			err:  coreerrors.WrapBoundary("aerospike", "get record", aero.ErrTimeout),
+			want: coreerrors.CategoryUpstream,
+		},
+		{name: "other", err: errors.New("boom"), want: coreerrors.CategoryInternal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := coreerrors.CategoryOf(test.err); got != test.want {
+				t.Fatalf("CategoryOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
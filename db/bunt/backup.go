@@ -0,0 +1,111 @@
+package bunt
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/tidwall/buntdb"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Backup writes a consistent snapshot of every key, with remaining TTLs, to wr. Writes
+// wait while it runs; reads do not.
+func (w *Wrapper) Backup(wr io.Writer) error {
+	return coreerrors.WrapBoundary("buntdb", "backup", w.Save(wr))
+}
+
+// BackupFile writes a snapshot to path through a temporary file in the same directory,
+// so that path always holds a complete snapshot.
+func (w *Wrapper) BackupFile(path string) (err error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	if err = w.Backup(file); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// Restore replaces every key with the snapshot read from rd, keeping indexes. Unlike
+// buntdb.DB.Load it also works for databases persisted to disk. The snapshot is read into
+// memory first, so a corrupt snapshot leaves the database untouched.
+func (w *Wrapper) Restore(rd io.Reader) error {
+	snapshot, err := buntdb.Open(":memory:")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = snapshot.Close() }()
+
+	if err := snapshot.Load(rd); err != nil {
+		return coreerrors.WrapBoundary("buntdb", "restore", err)
+	}
+
+	err = snapshot.View(func(source *buntdb.Tx) error {
+		return w.Update(func(target *buntdb.Tx) error {
+			if err := target.DeleteAll(); err != nil {
+				return err
+			}
+
+			var setErr error
+
+			err := source.AscendKeys("*", func(key, value string) bool {
+				setErr = restoreKey(source, target, key, value)
+
+				return setErr == nil
+			})
+			if err != nil {
+				return err
+			}
+
+			return setErr
+		})
+	})
+
+	return coreerrors.WrapBoundary("buntdb", "restore", err)
+}
+
+// restoreKey copies one key with its remaining TTL, skipping keys expired since the snapshot.
+func restoreKey(source, target *buntdb.Tx, key, value string) error {
+	ttl, err := source.TTL(key)
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var options *buntdb.SetOptions
+	if ttl > 0 {
+		options = &buntdb.SetOptions{Expires: true, TTL: ttl}
+	}
+
+	_, _, err = target.Set(key, value, options)
+
+	return err
+}
@@ -0,0 +1,69 @@
+package bunt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+)
+
+func TestBackupRestore(t *testing.T) {
+	source := newTestWrapper(t)
+	users := NewCollection[testUser](source, "users")
+
+	testutils.Equal(t, users.Put("1", testUser{Name: "ada", Age: 36}, 0), nil)
+	testutils.Equal(t, users.Put("2", testUser{Name: "alan", Age: 41}, time.Hour), nil)
+
+	var snapshot bytes.Buffer
+	testutils.Equal(t, source.Backup(&snapshot), nil)
+
+	target, err := Open(&ConnectionConfig{Filename: filepath.Join(t.TempDir(), "target.db")})
+	testutils.Equal(t, err, nil)
+
+	t.Cleanup(func() {
+		_ = target.Close()
+	})
+
+	restored := NewCollection[testUser](target, "users")
+	testutils.Equal(t, restored.EnsureIndexes(Index{Name: "age", Paths: []string{"age"}}), nil)
+	testutils.Equal(t, restored.Put("stale", testUser{Name: "stale"}, 0), nil)
+
+	testutils.Equal(t, target.Restore(bytes.NewReader(snapshot.Bytes())), nil)
+
+	count, err := restored.Len()
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, count, 2)
+
+	ttl, err := restored.TTL("2")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ttl > 0 && ttl <= time.Hour, true)
+
+	records, err := restored.Query(Range{Index: "age", From: 40})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, recordIDs(records), []string{"2"})
+
+	err = target.Restore(bytes.NewReader([]byte("*garbage")))
+	testutils.Equal(t, err != nil, true)
+
+	count, _ = restored.Len()
+	testutils.Equal(t, count, 2)
+}
+
+func TestBackupFile(t *testing.T) {
+	w := newTestWrapper(t)
+	testutils.Equal(t, NewCollection[testUser](w, "users").Put("1", testUser{Name: "ada"}, 0), nil)
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	testutils.Equal(t, w.BackupFile(path), nil)
+
+	content, err := os.ReadFile(path)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, bytes.Contains(content, []byte("users:1")), true)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(entries), 1)
+}
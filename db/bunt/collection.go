@@ -0,0 +1,195 @@
+package bunt
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/buntdb"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+const keySeparator = ":"
+
+// Record is a stored value with its id.
+type Record[T any] struct {
+	ID    string
+	Value T
+}
+
+// Collection stores T values as JSON under "name:id" keys. Indexes created through the
+// collection only cover its keys.
+type Collection[T any] struct {
+	db     *buntdb.DB
+	name   string
+	prefix string
+
+	mu      sync.RWMutex
+	indexes map[string]Index
+	spatial map[string]string
+}
+
+// NewCollection returns the collection called name in w.
+func NewCollection[T any](w *Wrapper, name string) *Collection[T] {
+	return &Collection[T]{
+		db:      w.DB,
+		name:    name,
+		prefix:  name + keySeparator,
+		indexes: make(map[string]Index),
+		spatial: make(map[string]string),
+	}
+}
+
+// Name returns the collection name.
+func (c *Collection[T]) Name() string {
+	return c.name
+}
+
+// Key returns the BuntDB key of id.
+func (c *Collection[T]) Key(id string) string {
+	return c.prefix + id
+}
+
+// Put stores value under id; a positive ttl expires it after that duration.
+func (c *Collection[T]) Put(id string, value T, ttl time.Duration) error {
+	return c.Batch(func(b *Batch[T]) error {
+		return b.Put(id, value, ttl)
+	})
+}
+
+// Get returns the value of id, or an error wrapping buntdb.ErrNotFound.
+func (c *Collection[T]) Get(id string) (T, error) {
+	var value T
+
+	err := c.db.View(func(tx *buntdb.Tx) error {
+		var err error
+		value, err = c.With(tx).Get(id)
+
+		return err
+	})
+
+	return value, err
+}
+
+// Delete removes ids; missing ids are ignored.
+func (c *Collection[T]) Delete(ids ...string) error {
+	return c.Batch(func(b *Batch[T]) error {
+		return b.Delete(ids...)
+	})
+}
+
+// TTL returns the remaining lifetime of id, or a negative duration when it does not expire.
+func (c *Collection[T]) TTL(id string) (time.Duration, error) {
+	var ttl time.Duration
+
+	err := c.db.View(func(tx *buntdb.Tx) error {
+		var err error
+		ttl, err = tx.TTL(c.Key(id))
+
+		return err
+	})
+
+	return ttl, coreerrors.WrapBoundary("buntdb", "ttl", err)
+}
+
+// Len returns the number of records.
+func (c *Collection[T]) Len() (int, error) {
+	count := 0
+
+	err := c.db.View(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(c.prefix+"*", func(string, string) bool {
+			count++
+
+			return true
+		})
+	})
+
+	return count, coreerrors.WrapBoundary("buntdb", "len", err)
+}
+
+// Ascend calls fn for every record in id order until fn returns false.
+func (c *Collection[T]) Ascend(fn func(record Record[T]) bool) error {
+	return c.db.View(func(tx *buntdb.Tx) error {
+		var decodeErr error
+
+		err := tx.AscendKeys(c.prefix+"*", c.iterator(fn, &decodeErr))
+
+		return errors.Join(coreerrors.WrapBoundary("buntdb", "ascend", err), decodeErr)
+	})
+}
+
+// Batch runs fn in one write transaction, rolled back when fn returns an error.
+func (c *Collection[T]) Batch(fn func(b *Batch[T]) error) error {
+	return c.db.Update(func(tx *buntdb.Tx) error {
+		return fn(c.With(tx))
+	})
+}
+
+// With binds the collection to tx, so that several collections share one transaction
+// opened with Wrapper.Update or Wrapper.View.
+func (c *Collection[T]) With(tx *buntdb.Tx) *Batch[T] {
+	return &Batch[T]{collection: c, tx: tx}
+}
+
+// iterator decodes records for a BuntDB iterator, stopping at the first decode error.
+func (c *Collection[T]) iterator(fn func(record Record[T]) bool, decodeErr *error) func(key, value string) bool {
+	return func(key, value string) bool {
+		record := Record[T]{ID: strings.TrimPrefix(key, c.prefix)}
+		if err := json.Unmarshal([]byte(value), &record.Value); err != nil {
+			*decodeErr = err
+
+			return false
+		}
+
+		return fn(record)
+	}
+}
+
+// Batch is a collection bound to a transaction.
+type Batch[T any] struct {
+	collection *Collection[T]
+	tx         *buntdb.Tx
+}
+
+// Put stores value under id; a positive ttl expires it after that duration.
+func (b *Batch[T]) Put(id string, value T, ttl time.Duration) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var options *buntdb.SetOptions
+	if ttl > 0 {
+		options = &buntdb.SetOptions{Expires: true, TTL: ttl}
+	}
+
+	_, _, err = b.tx.Set(b.collection.Key(id), string(content), options)
+
+	return coreerrors.WrapBoundary("buntdb", "put", err)
+}
+
+// Get returns the value of id, or an error wrapping buntdb.ErrNotFound.
+func (b *Batch[T]) Get(id string) (T, error) {
+	var value T
+
+	content, err := b.tx.Get(b.collection.Key(id))
+	if err != nil {
+		return value, coreerrors.WrapBoundary("buntdb", "get", err)
+	}
+
+	return value, json.Unmarshal([]byte(content), &value)
+}
+
+// Delete removes ids; missing ids are ignored.
+func (b *Batch[T]) Delete(ids ...string) error {
+	for _, id := range ids {
+		if _, err := b.tx.Delete(b.collection.Key(id)); err != nil && !errors.Is(err, buntdb.ErrNotFound) {
+			return coreerrors.WrapBoundary("buntdb", "delete", err)
+		}
+	}
+
+	return nil
+}
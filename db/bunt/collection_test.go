@@ -0,0 +1,115 @@
+package bunt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	"github.com/tidwall/buntdb"
+)
+
+type testUser struct {
+	Name    string      `json:"name"`
+	Age     int         `json:"age"`
+	Address testAddress `json:"address"`
+}
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+func newTestWrapper(t *testing.T) *Wrapper {
+	t.Helper()
+
+	w, err := Open(&ConnectionConfig{Filename: ":memory:"})
+	testutils.Equal(t, err, nil)
+
+	t.Cleanup(func() {
+		_ = w.Close()
+	})
+
+	return w
+}
+
+func TestCollection(t *testing.T) {
+	w := newTestWrapper(t)
+	users := NewCollection[testUser](w, "users")
+	groups := NewCollection[testUser](w, "groups")
+
+	testutils.Equal(t, users.Key("1"), "users:1")
+
+	testutils.Equal(t, users.Put("1", testUser{Name: "ada", Age: 36}, 0), nil)
+	testutils.Equal(t, users.Put("2", testUser{Name: "alan", Age: 41}, time.Hour), nil)
+	testutils.Equal(t, groups.Put("1", testUser{Name: "admins"}, 0), nil)
+
+	user, err := users.Get("1")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, user.Name, "ada")
+
+	_, err = users.Get("missing")
+	testutils.Equal(t, errors.Is(err, buntdb.ErrNotFound), true)
+
+	ttl, err := users.TTL("1")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ttl < 0, true)
+
+	ttl, err = users.TTL("2")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ttl > 0 && ttl <= time.Hour, true)
+
+	count, err := users.Len()
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, count, 2)
+
+	var ids []string
+
+	err = users.Ascend(func(record Record[testUser]) bool {
+		ids = append(ids, record.ID)
+
+		return true
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ids, []string{"1", "2"})
+
+	testutils.Equal(t, users.Delete("1", "missing"), nil)
+
+	count, _ = users.Len()
+	testutils.Equal(t, count, 1)
+}
+
+func TestCollectionBatch(t *testing.T) {
+	w := newTestWrapper(t)
+	users := NewCollection[testUser](w, "users")
+	groups := NewCollection[testUser](w, "groups")
+
+	boom := errors.New("boom")
+
+	err := users.Batch(func(b *Batch[testUser]) error {
+		testutils.Equal(t, b.Put("1", testUser{Name: "ada"}, 0), nil)
+
+		return boom
+	})
+	testutils.Equal(t, errors.Is(err, boom), true)
+
+	_, err = users.Get("1")
+	testutils.Equal(t, errors.Is(err, buntdb.ErrNotFound), true)
+
+	err = w.Update(func(tx *buntdb.Tx) error {
+		if err := users.With(tx).Put("1", testUser{Name: "ada"}, 0); err != nil {
+			return err
+		}
+
+		user, err := users.With(tx).Get("1")
+		if err != nil {
+			return err
+		}
+
+		return groups.With(tx).Put("admins", user, 0)
+	})
+	testutils.Equal(t, err, nil)
+
+	user, err := groups.Get("admins")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, user.Name, "ada")
+}
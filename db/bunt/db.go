@@ -1,4 +1,5 @@
-// Package bunt provides BuntDB connection helpers.
+// Package bunt provides BuntDB connection helpers and typed collections with
+// secondary and spatial indexes, transactions and backups.
 //
 // New code should pass explicit configuration or an explicit environment prefix:
 //
@@ -0,0 +1,19 @@
+package bunt
+
+import (
+	"errors"
+
+	"github.com/tidwall/buntdb"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// All kind of errors for buntdb
+var (
+	ErrIndexIsNotFound     = errors.New("index is not found in collection")
+	ErrIndexPathsAreNotSet = errors.New("index paths are not set")
+)
+
+func init() {
+	coreerrors.RegisterClassifier(coreerrors.CategoryFor(buntdb.ErrNotFound, coreerrors.CategoryNotFound))
+}
@@ -0,0 +1,37 @@
+package bunt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tidwall/buntdb"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestErrorCategory(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want coreerrors.Category
+	}{
+		{
+			name: "boundary missing key",
+			err:  coreerrors.WrapBoundary("buntdb", "get", buntdb.ErrNotFound),
+			want: coreerrors.CategoryNotFound,
+		},
+		{name: "other", err: errors.New("boom"), want: coreerrors.CategoryInternal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := coreerrors.CategoryOf(test.err); got != test.want {
+				t.Fatalf("CategoryOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
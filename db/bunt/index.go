@@ -0,0 +1,241 @@
+package bunt
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/tidwall/buntdb"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Index is a secondary index over JSON paths of the records of a collection.
+type Index struct {
+	Name string
+	// Paths are dotted JSON paths compared in order, such as "age" or "address.city".
+	Paths []string
+	// CaseSensitive compares strings case-sensitively; by default they compare case-insensitively.
+	CaseSensitive bool
+}
+
+func (i Index) less(path string) func(a, b string) bool {
+	if i.CaseSensitive {
+		return buntdb.IndexJSONCaseSensitive(path)
+	}
+
+	return buntdb.IndexJSON(path)
+}
+
+// Range selects records by an index. Bounds compare against the first path of the index.
+type Range struct {
+	Index string
+	// Equal selects records whose indexed value equals it; From and To are then ignored.
+	Equal any
+	// From and To bound the indexed values in iteration order, From inclusive and To
+	// exclusive; nil leaves that side unbounded. Descending ranges go from high to low.
+	From, To   any
+	Descending bool
+	// Limit caps the number of records; zero means no limit.
+	Limit int
+}
+
+// EnsureIndexes creates missing indexes; indexes that already exist are kept as they are.
+func (c *Collection[T]) EnsureIndexes(indexes ...Index) error {
+	for _, index := range indexes {
+		if len(index.Paths) == 0 {
+			return ErrIndexPathsAreNotSet
+		}
+
+		less := make([]func(a, b string) bool, len(index.Paths))
+		for i, path := range index.Paths {
+			less[i] = index.less(path)
+		}
+
+		err := c.db.CreateIndex(c.indexName(index.Name), c.prefix+"*", less...)
+		if err != nil && !errors.Is(err, buntdb.ErrIndexExists) {
+			return coreerrors.WrapBoundary("buntdb", "create index", err)
+		}
+
+		c.mu.Lock()
+		c.indexes[index.Name] = index
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Query returns the records selected by r.
+func (c *Collection[T]) Query(r Range) ([]Record[T], error) {
+	var records []Record[T]
+
+	err := c.Scan(r, func(record Record[T]) bool {
+		records = append(records, record)
+
+		return true
+	})
+
+	return records, err
+}
+
+// Scan calls fn for every record selected by r until fn returns false.
+func (c *Collection[T]) Scan(r Range, fn func(record Record[T]) bool) error {
+	return c.db.View(func(tx *buntdb.Tx) error {
+		return c.With(tx).Scan(r, fn)
+	})
+}
+
+// Query returns the records selected by r.
+func (b *Batch[T]) Query(r Range) ([]Record[T], error) {
+	var records []Record[T]
+
+	err := b.Scan(r, func(record Record[T]) bool {
+		records = append(records, record)
+
+		return true
+	})
+
+	return records, err
+}
+
+// Scan calls fn for every record selected by r until fn returns false.
+func (b *Batch[T]) Scan(r Range, fn func(record Record[T]) bool) error {
+	c := b.collection
+
+	c.mu.RLock()
+	index, ok := c.indexes[r.Index]
+	c.mu.RUnlock()
+
+	if !ok {
+		return ErrIndexIsNotFound
+	}
+
+	bounds, err := newRangeBounds(index, r)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	limited := func(record Record[T]) bool {
+		count++
+
+		return fn(record) && (r.Limit <= 0 || count < r.Limit)
+	}
+
+	var decodeErr error
+
+	decode := c.iterator(limited, &decodeErr)
+	iterator := func(key, value string) bool {
+		switch {
+		case bounds.before(value):
+			return true
+		case bounds.after(value):
+			return false
+		default:
+			return decode(key, value)
+		}
+	}
+
+	name := c.indexName(r.Index)
+
+	switch {
+	case bounds.start == "" && r.Descending:
+		err = b.tx.Descend(name, iterator)
+	case bounds.start == "":
+		err = b.tx.Ascend(name, iterator)
+	case r.Descending && len(index.Paths) == 1:
+		err = b.tx.DescendLessOrEqual(name, bounds.start, iterator)
+	case r.Descending:
+		// A composite pivot sorts below every record sharing its first value, so
+		// descending scans start from the top and skip records above the range.
+		err = b.tx.Descend(name, iterator)
+	default:
+		err = b.tx.AscendGreaterOrEqual(name, bounds.start, iterator)
+	}
+
+	return errors.Join(coreerrors.WrapBoundary("buntdb", "scan", err), decodeErr)
+}
+
+func (c *Collection[T]) indexName(name string) string {
+	return c.prefix + name
+}
+
+// rangeBounds compares records with a Range on the first path of an index.
+type rangeBounds struct {
+	less       func(a, b string) bool
+	descending bool
+	equal      bool
+	// start and end are pivot documents; empty means unbounded.
+	start, end string
+}
+
+func newRangeBounds(index Index, r Range) (rangeBounds, error) {
+	bounds := rangeBounds{less: index.less(index.Paths[0]), descending: r.Descending}
+
+	from, to := r.From, r.To
+	if r.Equal != nil {
+		bounds.equal = true
+		from, to = r.Equal, r.Equal
+	}
+
+	var err error
+
+	if from != nil {
+		if bounds.start, err = pivot(index.Paths[0], from); err != nil {
+			return bounds, err
+		}
+	}
+
+	if to != nil {
+		if bounds.end, err = pivot(index.Paths[0], to); err != nil {
+			return bounds, err
+		}
+	}
+
+	return bounds, nil
+}
+
+// before reports whether value comes before the start of the range in iteration order.
+func (b rangeBounds) before(value string) bool {
+	if b.start == "" {
+		return false
+	}
+
+	if b.descending {
+		return b.less(b.start, value)
+	}
+
+	return b.less(value, b.start)
+}
+
+// after reports whether value comes at or after the exclusive end of the range, or after
+// the inclusive end of an Equal range, in iteration order.
+func (b rangeBounds) after(value string) bool {
+	if b.end == "" {
+		return false
+	}
+
+	switch {
+	case b.equal && b.descending:
+		return b.less(value, b.end)
+	case b.equal:
+		return b.less(b.end, value)
+	case b.descending:
+		return !b.less(b.end, value)
+	default:
+		return !b.less(value, b.end)
+	}
+}
+
+// pivot returns a JSON document holding value at the dotted path.
+func pivot(path string, value any) (string, error) {
+	parts := strings.Split(path, ".")
+
+	for i := len(parts) - 1; i >= 0; i-- {
+		value = map[string]any{parts[i]: value}
+	}
+
+	content, err := json.Marshal(value)
+
+	return string(content), err
+}
@@ -0,0 +1,75 @@
+package bunt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+)
+
+func recordIDs(records []Record[testUser]) []string {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+
+	return ids
+}
+
+func TestCollectionQuery(t *testing.T) {
+	w := newTestWrapper(t)
+	users := NewCollection[testUser](w, "users")
+	others := NewCollection[testUser](w, "others")
+
+	err := users.EnsureIndexes(
+		Index{Name: "age", Paths: []string{"age"}},
+		Index{Name: "city_age", Paths: []string{"address.city", "age"}},
+	)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, users.EnsureIndexes(Index{Name: "age", Paths: []string{"age"}}), nil)
+
+	for id, user := range map[string]testUser{
+		"1": {Name: "ada", Age: 36, Address: testAddress{City: "London"}},
+		"2": {Name: "alan", Age: 41, Address: testAddress{City: "london"}},
+		"3": {Name: "grace", Age: 30, Address: testAddress{City: "Arlington"}},
+		"4": {Name: "linus", Age: 36, Address: testAddress{City: "Helsinki"}},
+	} {
+		testutils.Equal(t, users.Put(id, user, 0), nil)
+	}
+
+	testutils.Equal(t, others.Put("5", testUser{Name: "other", Age: 36}, 0), nil)
+
+	cases := []struct {
+		name string
+		r    Range
+		want []string
+	}{
+		{name: "all ascending", r: Range{Index: "age"}, want: []string{"3", "1", "4", "2"}},
+		{name: "all descending", r: Range{Index: "age", Descending: true}, want: []string{"2", "4", "1", "3"}},
+		{name: "from inclusive to exclusive", r: Range{Index: "age", From: 36, To: 41}, want: []string{"1", "4"}},
+		{name: "descending range", r: Range{Index: "age", From: 36, To: 30, Descending: true}, want: []string{"4", "1"}},
+		{name: "equal", r: Range{Index: "age", Equal: 36}, want: []string{"1", "4"}},
+		{name: "equal descending", r: Range{Index: "age", Equal: 36, Descending: true}, want: []string{"4", "1"}},
+		{name: "limit", r: Range{Index: "age", From: 31, Limit: 2}, want: []string{"1", "4"}},
+		{name: "nested path equal", r: Range{Index: "city_age", Equal: "LONDON"}, want: []string{"1", "2"}},
+		{
+			name: "composite descending",
+			r:    Range{Index: "city_age", From: "london", To: "Arlington", Descending: true},
+			want: []string{"2", "1", "4"},
+		},
+		{name: "composite from", r: Range{Index: "city_age", From: "Helsinki"}, want: []string{"4", "1", "2"}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := users.Query(test.r)
+			testutils.Equal(t, err, nil)
+			testutils.Equal(t, recordIDs(records), test.want)
+		})
+	}
+
+	_, err = users.Query(Range{Index: "missing"})
+	testutils.Equal(t, errors.Is(err, ErrIndexIsNotFound), true)
+
+	testutils.Equal(t, errors.Is(users.EnsureIndexes(Index{Name: "empty"}), ErrIndexPathsAreNotSet), true)
+}
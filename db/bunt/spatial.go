@@ -0,0 +1,147 @@
+package bunt
+
+import (
+	"errors"
+	"math"
+
+	"github.com/tidwall/buntdb"
+	"github.com/tidwall/gjson"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// SpatialIndex is an R-tree index over a JSON path holding either a point such as
+// [lon, lat] or a rectangle such as [[minLon, minLat], [maxLon, maxLat]]. Records without
+// a valid geometry at the path are not indexed.
+type SpatialIndex struct {
+	Name string
+	Path string
+}
+
+// Rect is an axis-aligned box; a point has Min equal to Max.
+type Rect struct {
+	Min []float64
+	Max []float64
+}
+
+// Point returns the rect of a single point.
+func Point(coords ...float64) Rect {
+	return Rect{Min: coords, Max: coords}
+}
+
+// Neighbor is a record found by Nearby with its distance to the target.
+type Neighbor[T any] struct {
+	Record[T]
+	// Distance is the Euclidean distance between the bounding boxes, in coordinate units.
+	Distance float64
+}
+
+// EnsureSpatialIndexes creates missing spatial indexes; indexes that already exist are kept.
+func (c *Collection[T]) EnsureSpatialIndexes(indexes ...SpatialIndex) error {
+	for _, index := range indexes {
+		if index.Path == "" {
+			return ErrIndexPathsAreNotSet
+		}
+
+		err := c.db.CreateSpatialIndex(c.indexName(index.Name), c.prefix+"*", jsonRect(index.Path))
+		if err != nil && !errors.Is(err, buntdb.ErrIndexExists) {
+			return coreerrors.WrapBoundary("buntdb", "create spatial index", err)
+		}
+
+		c.mu.Lock()
+		c.spatial[index.Name] = index.Path
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Intersects calls fn for every record whose geometry intersects bounds until fn returns false.
+func (c *Collection[T]) Intersects(index string, bounds Rect, fn func(record Record[T]) bool) error {
+	target, err := c.spatialTarget(index, bounds)
+	if err != nil {
+		return err
+	}
+
+	return c.db.View(func(tx *buntdb.Tx) error {
+		var decodeErr error
+
+		err := tx.Intersects(c.indexName(index), target, c.iterator(fn, &decodeErr))
+
+		return errors.Join(coreerrors.WrapBoundary("buntdb", "intersects", err), decodeErr)
+	})
+}
+
+// Nearby returns up to limit records ordered by distance to target; zero means no limit.
+func (c *Collection[T]) Nearby(index string, target Rect, limit int) ([]Neighbor[T], error) {
+	bounds, err := c.spatialTarget(index, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var neighbors []Neighbor[T]
+
+	err = c.db.View(func(tx *buntdb.Tx) error {
+		var (
+			decodeErr error
+			distance  float64
+		)
+
+		decode := c.iterator(func(record Record[T]) bool {
+			neighbors = append(neighbors, Neighbor[T]{Record: record, Distance: distance})
+
+			return limit <= 0 || len(neighbors) < limit
+		}, &decodeErr)
+
+		err := tx.Nearby(c.indexName(index), bounds, func(key, value string, dist float64) bool {
+			distance = math.Sqrt(dist)
+
+			return decode(key, value)
+		})
+
+		return errors.Join(coreerrors.WrapBoundary("buntdb", "nearby", err), decodeErr)
+	})
+
+	return neighbors, err
+}
+
+// spatialTarget encodes a query rect as a document the index rect function reads.
+func (c *Collection[T]) spatialTarget(index string, bounds Rect) (string, error) {
+	c.mu.RLock()
+	path, ok := c.spatial[index]
+	c.mu.RUnlock()
+
+	if !ok {
+		return "", ErrIndexIsNotFound
+	}
+
+	return pivot(path, [][]float64{bounds.Min, bounds.Max})
+}
+
+// jsonRect returns the rect function of a spatial index over path.
+func jsonRect(path string) func(item string) (minimum, maximum []float64) {
+	return func(item string) (minimum, maximum []float64) {
+		value := gjson.Get(item, path)
+		if !value.IsArray() {
+			return nil, nil
+		}
+
+		coords := value.Array()
+		if len(coords) == 2 && coords[0].IsArray() && coords[1].IsArray() { //nolint:mnd // min and max corners
+			return floats(coords[0].Array()), floats(coords[1].Array())
+		}
+
+		point := floats(coords)
+
+		return point, point
+	}
+}
+
+func floats(values []gjson.Result) []float64 {
+	result := make([]float64, len(values))
+	for i, value := range values {
+		result[i] = value.Float()
+	}
+
+	return result
+}
@@ -0,0 +1,61 @@
+package bunt
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+)
+
+type testPlace struct {
+	Name     string       `json:"name"`
+	Location []float64    `json:"location,omitempty"`
+	Area     [][2]float64 `json:"area,omitempty"`
+}
+
+func TestCollectionSpatial(t *testing.T) {
+	w := newTestWrapper(t)
+	places := NewCollection[testPlace](w, "places")
+
+	err := places.EnsureSpatialIndexes(
+		SpatialIndex{Name: "location", Path: "location"},
+		SpatialIndex{Name: "area", Path: "area"},
+	)
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, places.Put("a", testPlace{Name: "a", Location: []float64{0, 0}}, 0), nil)
+	testutils.Equal(t, places.Put("b", testPlace{Name: "b", Location: []float64{3, 4}}, 0), nil)
+	testutils.Equal(t, places.Put("c", testPlace{Name: "c", Location: []float64{10, 10}}, 0), nil)
+	testutils.Equal(t, places.Put("park", testPlace{Name: "park", Area: [][2]float64{{1, 1}, {2, 2}}}, 0), nil)
+
+	var found []string
+
+	err = places.Intersects("location", Rect{Min: []float64{-1, -1}, Max: []float64{5, 5}}, func(record Record[testPlace]) bool {
+		found = append(found, record.ID)
+
+		return true
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(found), 2)
+
+	found = found[:0]
+
+	err = places.Intersects("area", Point(1.5, 1.5), func(record Record[testPlace]) bool {
+		found = append(found, record.ID)
+
+		return true
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, found, []string{"park"})
+
+	neighbors, err := places.Nearby("location", Point(0, 0), 2)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(neighbors), 2)
+	testutils.Equal(t, neighbors[0].ID, "a")
+	testutils.Equal(t, neighbors[1].ID, "b")
+	testutils.Equal(t, math.Abs(neighbors[1].Distance-5) < 1e-9, true)
+
+	_, err = places.Nearby("missing", Point(0, 0), 1)
+	testutils.Equal(t, errors.Is(err, ErrIndexIsNotFound), true)
+}
@@ -5,11 +5,13 @@
 //	import "github.com/InsideGallery/core/db/elasticsearch"
 //
 //	client, err := elasticsearch.NewSearchClient(elasticsearch.Options{
-//		Addresses: []strings{"http://localhost:9200"},
+//		Addresses: []string{"http://localhost:9200"},
 //	})
 //
 // Use Searcher, SearchOptions, and SearchResult for consumer-facing code that
-// should not expose Elasticsearch SDK request or response types.
+// should not expose Elasticsearch SDK request or response types. SearchClient
+// also manages indexes and aliases, sends bulk requests through BulkIndexer, and
+// decodes hits into typed structs with Search and SearchAfter.
 //
 // Compatibility: Client and NewClient remain available for existing SDK-shaped
 // callers. Prefer NewSearchClient for new integrations.
@@ -20,10 +22,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
 
+	"github.com/InsideGallery/core/db/instrument"
 	coreerrors "github.com/InsideGallery/core/errors"
 )
 
@@ -34,6 +39,8 @@ type Options struct {
 	Password  string
 	CloudID   string
 	APIKey    string
+	// Transport replaces the HTTP transport, for proxies, custom TLS or tests.
+	Transport http.RoundTripper
 }
 
 // SearchOptions is the core-owned input for an Elasticsearch search.
@@ -54,7 +61,9 @@ type Searcher interface {
 
 // SearchClient wraps the Elasticsearch SDK behind core-owned inputs and results.
 type SearchClient struct {
-	search esapi.Search
+	transport esapi.Transport
+	search    esapi.Search
+	observer  instrument.Observer
 }
 
 // NewSearchClient creates an Elasticsearch search client from core-owned options.
@@ -64,12 +73,25 @@ func NewSearchClient(options Options) (*SearchClient, error) {
 		return nil, fmt.Errorf("elasticsearch client: %w", err)
 	}
 
-	return &SearchClient{search: client.Search}, nil
+	return newSearchClient(client), nil
+}
+
+// newSearchClient returns a SearchClient sending requests through transport.
+func newSearchClient(transport esapi.Transport) *SearchClient {
+	return &SearchClient{transport: transport, search: esapi.New(transport).Search}
 }
 
 // Search searches indexes through core-owned inputs and results.
 func (c *SearchClient) Search(ctx context.Context, options SearchOptions) (SearchResult, error) {
+	done := instrument.Begin(ctx, c.observer, instrument.Event{
+		System:    instrument.SystemElasticsearch,
+		Operation: "search",
+		Target:    strings.Join(options.Indexes, ","),
+	})
+
 	body, err := searchByIndex(ctx, c.search, options.Indexes, options.Query)
+	done(searchHits(body), err)
+
 	if err != nil {
 		return SearchResult{}, err
 	}
@@ -77,12 +99,26 @@ func (c *SearchClient) Search(ctx context.Context, options SearchOptions) (Searc
 	return SearchResult{Body: body}, nil
 }
 
+// SetObserver sets the observer of searches; nil uses instrument.Default.
+// Call it before the client is shared.
+func (c *SearchClient) SetObserver(observer instrument.Observer) {
+	c.observer = observer
+}
+
+func searchHits(body map[string]any) int64 {
+	hits, _ := body["hits"].(map[string]any)
+	list, _ := hits["hits"].([]any)
+
+	return int64(len(list))
+}
+
 func newElasticClient(options Options) (*elasticsearch.Client, error) {
 	if len(options.Addresses) == 0 &&
 		options.Username == "" &&
 		options.Password == "" &&
 		options.CloudID == "" &&
-		options.APIKey == "" {
+		options.APIKey == "" &&
+		options.Transport == nil {
 		return elasticsearch.NewDefaultClient()
 	}
 
@@ -92,6 +128,7 @@ func newElasticClient(options Options) (*elasticsearch.Client, error) {
 		Password:  options.Password,
 		CloudID:   options.CloudID,
 		APIKey:    options.APIKey,
+		Transport: options.Transport,
 	})
 }
 
@@ -126,7 +163,7 @@ func searchByIndex(
 	}()
 
 	if res.IsError() {
-		return result, searchResponseError(res)
+		return result, responseError(res)
 	}
 
 	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
@@ -136,19 +173,22 @@ func searchByIndex(
 	return result, nil
 }
 
-func searchResponseError(res *esapi.Response) error {
+// responseError decodes an Elasticsearch error response into a ResponseError.
+func responseError(res *esapi.Response) error {
+	responseErr := &ResponseError{StatusCode: res.StatusCode, Status: res.Status()}
+
 	var body map[string]any
 	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
-		return fmt.Errorf("error parsing the response body: %w", err)
+		return responseErr
 	}
 
 	errorInfo, ok := body["error"].(map[string]any)
 	if !ok {
-		return fmt.Errorf("[%s]: %w", res.Status(), ErrWrongResponse)
+		return responseErr
 	}
 
-	errorType, _ := errorInfo["type"].(string)
-	reason, _ := errorInfo["reason"].(string)
+	responseErr.Type, _ = errorInfo["type"].(string)
+	responseErr.Reason, _ = errorInfo["reason"].(string)
 
-	return fmt.Errorf("[%s] %s: %s: %w", res.Status(), errorType, reason, ErrWrongResponse)
+	return responseErr
 }
@@ -0,0 +1,363 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/InsideGallery/core/db/retry"
+)
+
+// Bulk indexer defaults.
+const (
+	DefaultBulkBatchSize  = 500
+	DefaultBulkFlushBytes = 5 << 20
+	DefaultBulkMaxRetries = 3
+	DefaultBulkRetryDelay = 100 * time.Millisecond
+)
+
+// BulkAction is the action of a bulk item.
+type BulkAction string
+
+// Bulk actions.
+const (
+	// BulkIndex creates or replaces a document.
+	BulkIndex BulkAction = "index"
+	// BulkCreate creates a document and fails when the id exists.
+	BulkCreate BulkAction = "create"
+	// BulkUpdate merges Document into an existing document.
+	BulkUpdate BulkAction = "update"
+	// BulkDelete deletes a document; a missing document is not an error.
+	BulkDelete BulkAction = "delete"
+)
+
+// BulkItem is one document operation of a bulk request.
+type BulkItem struct {
+	Action BulkAction
+	// Index defaults to BulkOptions.Index.
+	Index string
+	// ID may be empty for BulkIndex and BulkCreate to let Elasticsearch generate it.
+	ID       string
+	Document any
+	// Upsert creates the document from Document when a BulkUpdate finds none.
+	Upsert bool
+}
+
+// BulkItemError is a bulk item Elasticsearch rejected.
+type BulkItemError struct {
+	Item   BulkItem
+	Status int
+	Type   string
+	Reason string
+}
+
+func (e BulkItemError) Error() string {
+	return fmt.Sprintf("%s %s/%s: [%d] %s: %s", e.Item.Action, e.Item.Index, e.Item.ID, e.Status, e.Type, e.Reason)
+}
+
+// BulkResult counts the items sent by a BulkIndexer.
+type BulkResult struct {
+	Succeeded int
+	Failed    []BulkItemError
+}
+
+// BulkOptions configures a BulkIndexer.
+type BulkOptions struct {
+	// Index is the index of items without one.
+	Index string
+	// BatchSize is the number of items sent per request; default DefaultBulkBatchSize.
+	BatchSize int
+	// FlushBytes sends a request once the pending body reaches it; default DefaultBulkFlushBytes.
+	FlushBytes int
+	// MaxRetries is how often items rejected with 429, 502, 503 or 504 statuses are retried;
+	// default DefaultBulkMaxRetries, negative disables retries.
+	MaxRetries int
+	// RetryDelay is multiplied by the attempt number between retries; default DefaultBulkRetryDelay.
+	RetryDelay time.Duration
+	// Refresh is the refresh parameter of the requests: "true", "false" or "wait_for".
+	Refresh string
+}
+
+type bulkEntry struct {
+	item  BulkItem
+	lines []byte
+}
+
+// BulkIndexer batches document operations into bulk requests. It is safe for concurrent
+// use; requests are sent one at a time.
+type BulkIndexer struct {
+	client  *SearchClient
+	options BulkOptions
+
+	mu      sync.Mutex
+	pending []bulkEntry
+	size    int
+	result  BulkResult
+	closed  bool
+}
+
+// NewBulkIndexer returns a BulkIndexer sending through c.
+func (c *SearchClient) NewBulkIndexer(options BulkOptions) *BulkIndexer {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBulkBatchSize
+	}
+
+	if options.FlushBytes <= 0 {
+		options.FlushBytes = DefaultBulkFlushBytes
+	}
+
+	if options.MaxRetries == 0 {
+		options.MaxRetries = DefaultBulkMaxRetries
+	}
+
+	if options.RetryDelay <= 0 {
+		options.RetryDelay = DefaultBulkRetryDelay
+	}
+
+	return &BulkIndexer{client: c, options: options}
+}
+
+// Add queues items and sends a request whenever BatchSize or FlushBytes is reached.
+// Rejected items are reported by Result; only request failures are returned.
+func (b *BulkIndexer) Add(ctx context.Context, items ...BulkItem) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBulkIndexerClosed
+	}
+
+	for _, item := range items {
+		if item.Index == "" {
+			item.Index = b.options.Index
+		}
+
+		lines, err := bulkLines(item)
+		if err != nil {
+			return err
+		}
+
+		b.pending = append(b.pending, bulkEntry{item: item, lines: lines})
+		b.size += len(lines)
+
+		if len(b.pending) >= b.options.BatchSize || b.size >= b.options.FlushBytes {
+			if err := b.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Flush sends the queued items.
+func (b *BulkIndexer) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flush(ctx)
+}
+
+// Result returns the counts of the items sent so far.
+func (b *BulkIndexer) Result() BulkResult {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := b.result
+	result.Failed = append([]BulkItemError(nil), b.result.Failed...)
+
+	return result
+}
+
+// Close sends the queued items, stops accepting new ones and returns the final result.
+func (b *BulkIndexer) Close(ctx context.Context) (BulkResult, error) {
+	b.mu.Lock()
+	err := b.flush(ctx)
+	b.closed = true
+	b.mu.Unlock()
+
+	return b.Result(), err
+}
+
+// errBulkRetry reports that a flush attempt left items to retry.
+var errBulkRetry = errors.New("bulk items to retry")
+
+// flush sends the pending items, retrying retriable ones with retry.Do; it must hold the
+// lock.
+func (b *BulkIndexer) flush(ctx context.Context) error {
+	pending := b.pending
+	b.pending, b.size = nil, 0
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	policy := retry.Policy{MaxRetries: b.options.MaxRetries, Delay: b.options.RetryDelay}
+
+	err := retry.Do(ctx, policy, func(err error) bool {
+		return errors.Is(err, errBulkRetry)
+	}, func(attempt int) error {
+		var err error
+
+		pending, err = b.send(ctx, pending, attempt >= policy.Retries())
+		if err != nil {
+			return err
+		}
+
+		if len(pending) > 0 {
+			return errBulkRetry
+		}
+
+		return nil
+	})
+	if errors.Is(err, errBulkRetry) {
+		// Only a context done during a pause leaves items unsent.
+		b.fail(pending, ctx.Err())
+
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// bulkResponse is the wire format of a bulk response.
+type bulkResponse struct {
+	Items []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// send sends entries once and returns the ones to retry. A failed request is retried as a
+// whole when it may succeed later; otherwise, and on the last attempt, every entry is
+// recorded as failed.
+func (b *BulkIndexer) send(ctx context.Context, entries []bulkEntry, last bool) ([]bulkEntry, error) {
+	var body bytes.Buffer
+	for _, entry := range entries {
+		body.Write(entry.lines)
+	}
+
+	var response bulkResponse
+
+	err := b.client.perform(ctx, "bulk", b.options.Index, esapi.BulkRequest{
+		Body:    &body,
+		Refresh: b.options.Refresh,
+	}, &response)
+	if err != nil {
+		if !last && ctx.Err() == nil && retriableRequest(err) {
+			return entries, nil
+		}
+
+		b.fail(entries, err)
+
+		return nil, err
+	}
+
+	var retry []bulkEntry
+
+	for i, entry := range entries {
+		if i >= len(response.Items) {
+			b.result.Failed = append(b.result.Failed, BulkItemError{Item: entry.item, Reason: "missing from response"})
+
+			continue
+		}
+
+		for _, item := range response.Items[i] {
+			switch {
+			case item.Error == nil:
+				b.result.Succeeded++
+			case !last && retriableStatus(item.Status):
+				retry = append(retry, entry)
+			default:
+				b.result.Failed = append(b.result.Failed, BulkItemError{
+					Item:   entry.item,
+					Status: item.Status,
+					Type:   item.Error.Type,
+					Reason: item.Error.Reason,
+				})
+			}
+		}
+	}
+
+	return retry, nil
+}
+
+// fail records every entry as failed with err.
+func (b *BulkIndexer) fail(entries []bulkEntry, err error) {
+	status := 0
+
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		status = responseErr.StatusCode
+	}
+
+	for _, entry := range entries {
+		b.result.Failed = append(b.result.Failed, BulkItemError{Item: entry.item, Status: status, Reason: err.Error()})
+	}
+}
+
+// bulkLines encodes the action line and, except for deletes, the document line of item.
+func bulkLines(item BulkItem) ([]byte, error) {
+	meta := map[string]any{"_index": item.Index}
+	if item.ID != "" {
+		meta["_id"] = item.ID
+	}
+
+	var document any
+
+	switch item.Action {
+	case BulkIndex, BulkCreate:
+		document = item.Document
+	case BulkUpdate:
+		document = map[string]any{"doc": item.Document, "doc_as_upsert": item.Upsert}
+	case BulkDelete:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBulkAction, item.Action)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(map[string]any{string(item.Action): meta}); err != nil {
+		return nil, err
+	}
+
+	if item.Action != BulkDelete {
+		if err := encoder.Encode(document); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func retriableRequest(err error) bool {
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return retriableStatus(responseErr.StatusCode)
+	}
+
+	// Transport errors, such as a refused connection, may succeed on another attempt.
+	return true
+}
+
+// retriableStatus reports whether status is transient. 500 is not: Elasticsearch also uses
+// it for failures a retry repeats, such as a script error.
+func retriableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
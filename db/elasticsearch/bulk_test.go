@@ -0,0 +1,198 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+)
+
+// bulkActions returns the action and id of every item of a bulk body.
+func bulkActions(t *testing.T, body string) [][2]string {
+	t.Helper()
+
+	var actions [][2]string
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var line map[string]map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("decode %q: %v", scanner.Text(), err)
+		}
+
+		for action, meta := range line {
+			switch BulkAction(action) {
+			case BulkIndex, BulkCreate, BulkUpdate, BulkDelete:
+				id, _ := meta["_id"].(string)
+				actions = append(actions, [2]string{action, id})
+
+				if BulkAction(action) != BulkDelete {
+					scanner.Scan()
+				}
+			}
+		}
+	}
+
+	return actions
+}
+
+func bulkItem(action, id string, status int, err map[string]any) map[string]any {
+	item := map[string]any{"_index": "products", "_id": id, "status": status}
+	if err != nil {
+		item["error"] = err
+	}
+
+	return map[string]any{action: item}
+}
+
+func TestBulkIndexerRetriesAndReportsItems(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	attempt := 0
+
+	cluster.handle("POST /_bulk", func(string) (int, any) {
+		attempt++
+
+		if attempt == 1 {
+			return http.StatusOK, map[string]any{"errors": true, "items": []any{
+				bulkItem("index", "1", http.StatusCreated, nil),
+				bulkItem("index", "2", http.StatusTooManyRequests, map[string]any{"type": "es_rejected_execution_exception"}),
+				bulkItem("create", "3", http.StatusConflict, map[string]any{
+					"type": "version_conflict_engine_exception", "reason": "document already exists",
+				}),
+			}}
+		}
+
+		return http.StatusOK, map[string]any{"errors": false, "items": []any{
+			bulkItem("index", "2", http.StatusCreated, nil),
+		}}
+	})
+
+	indexer := cluster.client().NewBulkIndexer(BulkOptions{Index: "products", RetryDelay: time.Millisecond})
+
+	err := indexer.Add(context.Background(),
+		BulkItem{Action: BulkIndex, ID: "1", Document: product{Name: "a"}},
+		BulkItem{Action: BulkIndex, ID: "2", Document: product{Name: "b"}},
+		BulkItem{Action: BulkCreate, ID: "3", Document: product{Name: "c"}},
+	)
+	testutils.Equal(t, err, nil)
+
+	result, err := indexer.Close(context.Background())
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, result.Succeeded, 2)
+	testutils.Equal(t, len(result.Failed), 1)
+	testutils.Equal(t, result.Failed[0].Item.ID, "3")
+	testutils.Equal(t, result.Failed[0].Status, http.StatusConflict)
+	testutils.Equal(t, result.Failed[0].Type, "version_conflict_engine_exception")
+
+	calls := cluster.requests()
+	testutils.Equal(t, len(calls), 2)
+	testutils.Equal(t, bulkActions(t, calls[0].Body), [][2]string{{"index", "1"}, {"index", "2"}, {"create", "3"}})
+	testutils.Equal(t, bulkActions(t, calls[1].Body), [][2]string{{"index", "2"}})
+
+	err = indexer.Add(context.Background(), BulkItem{Action: BulkDelete, ID: "1"})
+	testutils.Equal(t, errors.Is(err, ErrBulkIndexerClosed), true)
+}
+
+func TestBulkIndexerBatches(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("POST /_bulk", func(body string) (int, any) {
+		items := []any{}
+		for _, action := range bulkActions(t, body) {
+			items = append(items, bulkItem(action[0], action[1], http.StatusOK, nil))
+		}
+
+		return http.StatusOK, map[string]any{"items": items}
+	})
+
+	indexer := cluster.client().NewBulkIndexer(BulkOptions{Index: "products", BatchSize: 2, Refresh: "wait_for"})
+
+	for _, id := range []string{"1", "2", "3"} {
+		err := indexer.Add(context.Background(), BulkItem{Action: BulkUpdate, ID: id, Document: product{Price: 1}, Upsert: true})
+		testutils.Equal(t, err, nil)
+	}
+
+	testutils.Equal(t, len(cluster.requests()), 1)
+
+	result, err := indexer.Close(context.Background())
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, result.Succeeded, 3)
+
+	calls := cluster.requests()
+	testutils.Equal(t, len(calls), 2)
+	testutils.Equal(t, calls[0].Query, "refresh=wait_for")
+
+	lines := strings.Split(strings.TrimSpace(calls[1].Body), "\n")
+	testutils.Equal(t, decodeJSON(t, lines[0]), decodeJSON(t, `{"update": {"_index": "products", "_id": "3"}}`))
+	testutils.Equal(t, decodeJSON(t, lines[1]), decodeJSON(t, `{"doc": {"name": "", "price": 1}, "doc_as_upsert": true}`))
+}
+
+func TestBulkIndexerRequestFailures(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	attempts := 0
+
+	cluster.handle("POST /_bulk", func(string) (int, any) {
+		attempts++
+
+		return http.StatusServiceUnavailable, errorBody("cluster_block_exception", "blocked")
+	})
+
+	indexer := cluster.client().NewBulkIndexer(BulkOptions{MaxRetries: 2, RetryDelay: time.Millisecond})
+
+	err := indexer.Add(context.Background(), BulkItem{Action: BulkDelete, Index: "products", ID: "1"})
+	testutils.Equal(t, err, nil)
+
+	err = indexer.Flush(context.Background())
+	testutils.Equal(t, errors.Is(err, ErrWrongResponse), true)
+	testutils.Equal(t, attempts, 3)
+
+	result := indexer.Result()
+	testutils.Equal(t, result.Succeeded, 0)
+	testutils.Equal(t, len(result.Failed), 1)
+	testutils.Equal(t, result.Failed[0].Status, http.StatusServiceUnavailable)
+
+	err = indexer.Add(context.Background(), BulkItem{Action: "upsert", ID: "1"})
+	testutils.Equal(t, errors.Is(err, ErrUnknownBulkAction), true)
+}
+
+func TestBulkIndexerRetryStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+
+	cluster.handle("POST /_bulk", func(string) (int, any) {
+		attempts++
+
+		cancel()
+
+		return http.StatusOK, map[string]any{"errors": true, "items": []any{
+			bulkItem("index", "1", http.StatusTooManyRequests, map[string]any{"type": "es_rejected_execution_exception"}),
+		}}
+	})
+
+	indexer := cluster.client().NewBulkIndexer(BulkOptions{Index: "products", RetryDelay: time.Hour})
+
+	err := indexer.Add(context.Background(), BulkItem{Action: BulkIndex, ID: "1", Document: product{Name: "a"}})
+	testutils.Equal(t, err, nil)
+
+	err = indexer.Flush(ctx)
+	testutils.Equal(t, errors.Is(err, context.Canceled), true)
+	testutils.Equal(t, attempts, 1)
+
+	result := indexer.Result()
+	testutils.Equal(t, len(result.Failed), 1)
+	testutils.Equal(t, result.Failed[0].Reason, context.Canceled.Error())
+}
@@ -1,8 +1,62 @@
 package elasticsearch
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
 
 var (
 	ErrWrongResponse         = errors.New("wrong response")
 	ErrWrongCountOfArguments = errors.New("error count of arguments")
+	ErrSortIsNotSet          = errors.New("search_after pagination needs a sort")
+	ErrStop                  = errors.New("stop pagination")
+	ErrBulkIndexerClosed     = errors.New("bulk indexer is closed")
+	ErrUnknownBulkAction     = errors.New("unknown bulk action")
+	ErrReindexFailed         = errors.New("reindex has failed documents")
 )
+
+func init() {
+	coreerrors.RegisterClassifier(classifyError)
+}
+
+// ResponseError is an Elasticsearch error response. It matches ErrWrongResponse with errors.Is.
+type ResponseError struct {
+	StatusCode int
+	Status     string
+	Type       string
+	Reason     string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Type == "" && e.Reason == "" {
+		return fmt.Sprintf("[%s]: %s", e.Status, ErrWrongResponse)
+	}
+
+	return fmt.Sprintf("[%s] %s: %s: %s", e.Status, e.Type, e.Reason, ErrWrongResponse)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return ErrWrongResponse
+}
+
+// classifyError maps missing indexes or documents, version conflicts and throttling to core error categories.
+func classifyError(err error) coreerrors.Category {
+	var responseErr *ResponseError
+	if !errors.As(err, &responseErr) {
+		return coreerrors.CategoryUnknown
+	}
+
+	switch responseErr.StatusCode {
+	case http.StatusNotFound:
+		return coreerrors.CategoryNotFound
+	case http.StatusConflict:
+		return coreerrors.CategoryConflict
+	case http.StatusTooManyRequests:
+		return coreerrors.CategoryRateLimited
+	default:
+		return coreerrors.CategoryUnknown
+	}
+}
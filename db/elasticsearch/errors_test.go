@@ -0,0 +1,35 @@
+package elasticsearch
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestErrorCategory(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want coreerrors.Category
+	}{
+		{name: "missing index", err: &ResponseError{StatusCode: http.StatusNotFound}, want: coreerrors.CategoryNotFound},
+		{name: "version conflict", err: &ResponseError{StatusCode: http.StatusConflict}, want: coreerrors.CategoryConflict},
+		{name: "throttled", err: &ResponseError{StatusCode: http.StatusTooManyRequests}, want: coreerrors.CategoryRateLimited},
+		{name: "other response", err: &ResponseError{StatusCode: http.StatusBadRequest}, want: coreerrors.CategoryInternal},
+		{name: "other", err: errors.New("boom"), want: coreerrors.CategoryInternal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := coreerrors.CategoryOf(test.err); got != test.want {
+				t.Fatalf("CategoryOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
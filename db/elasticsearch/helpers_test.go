@@ -0,0 +1,103 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeTransport serves requests with an in-process handler.
+type fakeTransport struct {
+	handler http.Handler
+}
+
+func (f fakeTransport) Perform(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	f.handler.ServeHTTP(recorder, req)
+
+	return recorder.Result(), nil
+}
+
+// recorded is a request seen by a fake cluster.
+type recorded struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+// fakeCluster answers requests by "METHOD /path" and records them in order.
+type fakeCluster struct {
+	t      *testing.T
+	mu     sync.Mutex
+	routes map[string]func(body string) (int, any)
+	calls  []recorded
+}
+
+func newFakeCluster(t *testing.T) *fakeCluster {
+	t.Helper()
+
+	return &fakeCluster{t: t, routes: map[string]func(string) (int, any){}}
+}
+
+func (f *fakeCluster) handle(route string, fn func(body string) (int, any)) {
+	f.routes[route] = fn
+}
+
+func (f *fakeCluster) client() *SearchClient {
+	return newSearchClient(fakeTransport{handler: f})
+}
+
+func (f *fakeCluster) requests() []recorded {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]recorded(nil), f.calls...)
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, recorded{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Body: string(body)})
+	route, ok := f.routes[r.Method+" "+r.URL.Path]
+	f.mu.Unlock()
+
+	if !ok {
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	status, response := route(string(body))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if response != nil {
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
+
+func errorBody(kind, reason string) map[string]any {
+	return map[string]any{"error": map[string]any{"type": kind, "reason": reason}}
+}
+
+// decodeJSON decodes a recorded body for comparisons independent of key order.
+func decodeJSON(t *testing.T, body string) any {
+	t.Helper()
+
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		t.Fatalf("decode %q: %v", body, err)
+	}
+
+	return value
+}
@@ -0,0 +1,239 @@
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// IndexOptions configures a new index.
+type IndexOptions struct {
+	Settings map[string]any
+	Mappings map[string]any
+	// Aliases are added to the index when it is created.
+	Aliases []string
+}
+
+// ReindexOptions configures a reindex.
+type ReindexOptions struct {
+	// Source is the index or alias copied from; Dest is the index copied to.
+	Source string
+	Dest   string
+	// Query limits the copied documents; nil copies all of them.
+	Query Query
+}
+
+// ReindexFailure is a document a reindex could not copy.
+type ReindexFailure struct {
+	Index  string `json:"index"`
+	ID     string `json:"id"`
+	Status int    `json:"status"`
+	Cause  struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"cause"`
+}
+
+// ReindexResult reports a completed reindex.
+type ReindexResult struct {
+	Took     int64            `json:"took"`
+	Total    int64            `json:"total"`
+	Created  int64            `json:"created"`
+	Updated  int64            `json:"updated"`
+	Deleted  int64            `json:"deleted"`
+	Failures []ReindexFailure `json:"failures"`
+}
+
+// AliasReindexOptions configures ReindexAlias.
+type AliasReindexOptions struct {
+	// Alias is read from before and points to Index after.
+	Alias string
+	// Index is the new index, created with Create.
+	Index  string
+	Create IndexOptions
+	// Query limits the copied documents; nil copies all of them.
+	Query Query
+	// DeleteOld deletes the indexes the alias pointed to once it is swapped.
+	DeleteOld bool
+}
+
+// CreateIndex creates the index name with settings, mappings and aliases.
+func (c *SearchClient) CreateIndex(ctx context.Context, name string, options IndexOptions) error {
+	body := map[string]any{}
+
+	if options.Settings != nil {
+		body["settings"] = options.Settings
+	}
+
+	if options.Mappings != nil {
+		body["mappings"] = options.Mappings
+	}
+
+	if len(options.Aliases) > 0 {
+		aliases := make(map[string]any, len(options.Aliases))
+		for _, alias := range options.Aliases {
+			aliases[alias] = map[string]any{}
+		}
+
+		body["aliases"] = aliases
+	}
+
+	reader, err := jsonBody(body)
+	if err != nil {
+		return err
+	}
+
+	return c.perform(ctx, "create index", name, esapi.IndicesCreateRequest{Index: name, Body: reader}, nil)
+}
+
+// DeleteIndex deletes indexes.
+func (c *SearchClient) DeleteIndex(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	return c.perform(ctx, "delete index", strings.Join(names, ","), esapi.IndicesDeleteRequest{Index: names}, nil)
+}
+
+// IndexExists reports whether the index or alias name exists.
+func (c *SearchClient) IndexExists(ctx context.Context, name string) (bool, error) {
+	err := c.perform(ctx, "index exists", name, esapi.IndicesExistsRequest{Index: []string{name}}, nil)
+	if isStatus(err, http.StatusNotFound) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Refresh makes recent writes to indexes visible to searches.
+func (c *SearchClient) Refresh(ctx context.Context, names ...string) error {
+	return c.perform(ctx, "refresh", strings.Join(names, ","), esapi.IndicesRefreshRequest{Index: names}, nil)
+}
+
+// AliasIndexes returns the sorted indexes alias points to; a missing alias has none.
+func (c *SearchClient) AliasIndexes(ctx context.Context, alias string) ([]string, error) {
+	var response map[string]any
+
+	err := c.perform(ctx, "get alias", alias, esapi.IndicesGetAliasRequest{Name: []string{alias}}, &response)
+	if isStatus(err, http.StatusNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]string, 0, len(response))
+	for index := range response {
+		indexes = append(indexes, index)
+	}
+
+	sort.Strings(indexes)
+
+	return indexes, nil
+}
+
+// SwapAlias atomically points alias to index only and returns the indexes it pointed to before.
+func (c *SearchClient) SwapAlias(ctx context.Context, alias, index string) ([]string, error) {
+	previous, err := c.AliasIndexes(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]map[string]any, 0, len(previous)+1)
+
+	for _, old := range previous {
+		if old != index {
+			actions = append(actions, map[string]any{"remove": map[string]any{"index": old, "alias": alias}})
+		}
+	}
+
+	actions = append(actions, map[string]any{"add": map[string]any{"index": index, "alias": alias}})
+
+	reader, err := jsonBody(map[string]any{"actions": actions})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.perform(ctx, "update aliases", alias, esapi.IndicesUpdateAliasesRequest{Body: reader}, nil)
+
+	return previous, err
+}
+
+// Reindex copies documents from Source to Dest, waits for completion and refreshes Dest.
+func (c *SearchClient) Reindex(ctx context.Context, options ReindexOptions) (ReindexResult, error) {
+	source := map[string]any{"index": options.Source}
+	if options.Query != nil {
+		source["query"] = options.Query
+	}
+
+	reader, err := jsonBody(map[string]any{
+		"source": source,
+		"dest":   map[string]any{"index": options.Dest},
+	})
+	if err != nil {
+		return ReindexResult{}, err
+	}
+
+	wait, refresh := true, true
+
+	var result ReindexResult
+
+	err = c.perform(ctx, "reindex", options.Dest, esapi.ReindexRequest{
+		Body:              reader,
+		WaitForCompletion: &wait,
+		Refresh:           &refresh,
+	}, &result)
+
+	return result, err
+}
+
+// ReindexAlias moves alias to a new index without downtime: it creates Index, copies the
+// documents the alias points to, swaps the alias in one atomic update and optionally
+// deletes the old indexes. On reindex failures the alias is left unchanged and the new
+// index is kept for inspection. Writes to the old indexes during the copy are not carried
+// over, so writers should pause or write through the alias after the swap.
+func (c *SearchClient) ReindexAlias(ctx context.Context, options AliasReindexOptions) (ReindexResult, error) {
+	previous, err := c.AliasIndexes(ctx, options.Alias)
+	if err != nil {
+		return ReindexResult{}, err
+	}
+
+	if err := c.CreateIndex(ctx, options.Index, options.Create); err != nil {
+		return ReindexResult{}, err
+	}
+
+	var result ReindexResult
+
+	if len(previous) > 0 {
+		result, err = c.Reindex(ctx, ReindexOptions{Source: options.Alias, Dest: options.Index, Query: options.Query})
+		if err != nil {
+			return result, err
+		}
+
+		if len(result.Failures) > 0 {
+			return result, fmt.Errorf("%w: %d of %d", ErrReindexFailed, len(result.Failures), result.Total)
+		}
+	}
+
+	if _, err := c.SwapAlias(ctx, options.Alias, options.Index); err != nil {
+		return result, err
+	}
+
+	if options.DeleteOld {
+		return result, c.DeleteIndex(ctx, previous...)
+	}
+
+	return result, nil
+}
+
+func isStatus(err error, status int) bool {
+	var responseErr *ResponseError
+
+	return errors.As(err, &responseErr) && responseErr.StatusCode == status
+}
@@ -0,0 +1,164 @@
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+)
+
+func TestCreateIndex(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("PUT /products-v1", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"acknowledged": true}
+	})
+
+	err := cluster.client().CreateIndex(context.Background(), "products-v1", IndexOptions{
+		Settings: map[string]any{"number_of_shards": 1},
+		Mappings: map[string]any{"properties": map[string]any{"name": map[string]any{"type": "text"}}},
+		Aliases:  []string{"products"},
+	})
+	testutils.Equal(t, err, nil)
+
+	calls := cluster.requests()
+	testutils.Equal(t, len(calls), 1)
+	testutils.Equal(t, decodeJSON(t, calls[0].Body), decodeJSON(t, `{
+		"settings": {"number_of_shards": 1},
+		"mappings": {"properties": {"name": {"type": "text"}}},
+		"aliases": {"products": {}}
+	}`))
+}
+
+func TestIndexExists(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("HEAD /present", func(string) (int, any) { return http.StatusOK, nil })
+	cluster.handle("HEAD /missing", func(string) (int, any) { return http.StatusNotFound, nil })
+	cluster.handle("HEAD /broken", func(string) (int, any) { return http.StatusForbidden, nil })
+
+	client := cluster.client()
+
+	exists, err := client.IndexExists(context.Background(), "present")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, exists, true)
+
+	exists, err = client.IndexExists(context.Background(), "missing")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, exists, false)
+
+	_, err = client.IndexExists(context.Background(), "broken")
+	testutils.Equal(t, errors.Is(err, ErrWrongResponse), true)
+}
+
+func TestReindexAlias(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("GET /_alias/products", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"products-v1": map[string]any{"aliases": map[string]any{"products": map[string]any{}}}}
+	})
+	cluster.handle("PUT /products-v2", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"acknowledged": true}
+	})
+	cluster.handle("POST /_reindex", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"took": 12, "total": 3, "created": 3, "failures": []any{}}
+	})
+	cluster.handle("POST /_aliases", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"acknowledged": true}
+	})
+	cluster.handle("DELETE /products-v1", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"acknowledged": true}
+	})
+
+	result, err := cluster.client().ReindexAlias(context.Background(), AliasReindexOptions{
+		Alias:     "products",
+		Index:     "products-v2",
+		DeleteOld: true,
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, result.Created, int64(3))
+
+	var routes []string
+	for _, call := range cluster.requests() {
+		routes = append(routes, call.Method+" "+call.Path)
+	}
+
+	testutils.Equal(t, routes, []string{
+		"GET /_alias/products",
+		"PUT /products-v2",
+		"POST /_reindex",
+		"GET /_alias/products",
+		"POST /_aliases",
+		"DELETE /products-v1",
+	})
+
+	calls := cluster.requests()
+	testutils.Equal(t, decodeJSON(t, calls[2].Body), decodeJSON(t, `{
+		"source": {"index": "products"},
+		"dest": {"index": "products-v2"}
+	}`))
+	testutils.Equal(t, decodeJSON(t, calls[4].Body), decodeJSON(t, `{"actions": [
+		{"remove": {"index": "products-v1", "alias": "products"}},
+		{"add": {"index": "products-v2", "alias": "products"}}
+	]}`))
+}
+
+func TestReindexAliasFirstIndex(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("GET /_alias/products", func(string) (int, any) {
+		return http.StatusNotFound, errorBody("aliases_not_found_exception", "alias [products] missing")
+	})
+	cluster.handle("PUT /products-v1", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"acknowledged": true}
+	})
+	cluster.handle("POST /_aliases", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"acknowledged": true}
+	})
+
+	_, err := cluster.client().ReindexAlias(context.Background(), AliasReindexOptions{
+		Alias: "products",
+		Index: "products-v1",
+	})
+	testutils.Equal(t, err, nil)
+
+	calls := cluster.requests()
+	testutils.Equal(t, len(calls), 4)
+	testutils.Equal(t, decodeJSON(t, calls[3].Body), decodeJSON(t, `{"actions": [
+		{"add": {"index": "products-v1", "alias": "products"}}
+	]}`))
+}
+
+func TestReindexAliasKeepsAliasOnFailures(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("GET /_alias/products", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"products-v1": map[string]any{}}
+	})
+	cluster.handle("PUT /products-v2", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"acknowledged": true}
+	})
+	cluster.handle("POST /_reindex", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"total": 2, "created": 1, "failures": []any{
+			map[string]any{"index": "products-v2", "id": "2", "status": 400, "cause": map[string]any{
+				"type": "mapper_parsing_exception", "reason": "failed to parse field [price]",
+			}},
+		}}
+	})
+
+	result, err := cluster.client().ReindexAlias(context.Background(), AliasReindexOptions{
+		Alias: "products",
+		Index: "products-v2",
+	})
+	testutils.Equal(t, errors.Is(err, ErrReindexFailed), true)
+	testutils.Equal(t, len(result.Failures), 1)
+	testutils.Equal(t, result.Failures[0].Cause.Type, "mapper_parsing_exception")
+	testutils.Equal(t, len(cluster.requests()), 3)
+}
@@ -0,0 +1,249 @@
+package elasticsearch
+
+// Query is an Elasticsearch query clause, built with the query functions of this package
+// or written by hand.
+type Query map[string]any
+
+// RangeBounds bounds a Range query; nil bounds are left out.
+type RangeBounds struct {
+	GT, GTE, LT, LTE any
+	// Format parses date bounds, such as "yyyy-MM-dd".
+	Format string
+}
+
+// BoolQuery combines clauses. Filter and MustNot do not affect scores.
+type BoolQuery struct {
+	Must    []Query
+	Filter  []Query
+	Should  []Query
+	MustNot []Query
+	// MinimumShouldMatch is how many Should clauses must match; zero keeps the default.
+	MinimumShouldMatch int
+}
+
+// MatchAll matches every document.
+func MatchAll() Query {
+	return Query{"match_all": map[string]any{}}
+}
+
+// Match runs a full-text match of value on field.
+func Match(field string, value any) Query {
+	return Query{"match": map[string]any{field: map[string]any{"query": value}}}
+}
+
+// MatchPhrase matches the exact phrase on field.
+func MatchPhrase(field string, phrase string) Query {
+	return Query{"match_phrase": map[string]any{field: map[string]any{"query": phrase}}}
+}
+
+// MultiMatch runs a full-text match of text on fields, which may carry boosts such as "name^2".
+func MultiMatch(text string, fields ...string) Query {
+	return Query{"multi_match": map[string]any{"query": text, "fields": fields}}
+}
+
+// Term matches documents whose field equals value exactly.
+func Term(field string, value any) Query {
+	return Query{"term": map[string]any{field: map[string]any{"value": value}}}
+}
+
+// Terms matches documents whose field equals any of values.
+func Terms(field string, values ...any) Query {
+	return Query{"terms": map[string]any{field: values}}
+}
+
+// IDs matches documents by id.
+func IDs(ids ...string) Query {
+	return Query{"ids": map[string]any{"values": ids}}
+}
+
+// Exists matches documents with a value in field.
+func Exists(field string) Query {
+	return Query{"exists": map[string]any{"field": field}}
+}
+
+// Prefix matches documents whose field starts with prefix.
+func Prefix(field string, prefix string) Query {
+	return Query{"prefix": map[string]any{field: map[string]any{"value": prefix}}}
+}
+
+// Range matches documents whose field is within bounds.
+func Range(field string, bounds RangeBounds) Query {
+	conditions := map[string]any{}
+
+	for name, value := range map[string]any{"gt": bounds.GT, "gte": bounds.GTE, "lt": bounds.LT, "lte": bounds.LTE} {
+		if value != nil {
+			conditions[name] = value
+		}
+	}
+
+	if bounds.Format != "" {
+		conditions["format"] = bounds.Format
+	}
+
+	return Query{"range": map[string]any{field: conditions}}
+}
+
+// Bool combines clauses into a bool query.
+func Bool(query BoolQuery) Query {
+	clauses := map[string]any{}
+
+	for name, queries := range map[string][]Query{
+		"must":     query.Must,
+		"filter":   query.Filter,
+		"should":   query.Should,
+		"must_not": query.MustNot,
+	} {
+		if len(queries) > 0 {
+			clauses[name] = queries
+		}
+	}
+
+	if query.MinimumShouldMatch > 0 {
+		clauses["minimum_should_match"] = query.MinimumShouldMatch
+	}
+
+	return Query{"bool": clauses}
+}
+
+// Nested runs query on the nested objects at path.
+func Nested(path string, query Query) Query {
+	return Query{"nested": map[string]any{"path": path, "query": query}}
+}
+
+// Aggregation is an Elasticsearch aggregation, built with the aggregation functions of
+// this package or written by hand.
+type Aggregation map[string]any
+
+// Metric aggregation kinds for MetricAggregation.
+const (
+	MetricAvg         = "avg"
+	MetricSum         = "sum"
+	MetricMin         = "min"
+	MetricMax         = "max"
+	MetricCardinality = "cardinality"
+	MetricValueCount  = "value_count"
+)
+
+// TermsAggregation buckets documents by the values of field, keeping the size most frequent.
+func TermsAggregation(field string, size int) Aggregation {
+	terms := map[string]any{"field": field}
+	if size > 0 {
+		terms["size"] = size
+	}
+
+	return Aggregation{"terms": terms}
+}
+
+// HistogramAggregation buckets numeric values of field by interval.
+func HistogramAggregation(field string, interval float64) Aggregation {
+	return Aggregation{"histogram": map[string]any{"field": field, "interval": interval}}
+}
+
+// DateHistogramAggregation buckets dates of field by a calendar interval such as "day" or "month".
+func DateHistogramAggregation(field string, calendarInterval string) Aggregation {
+	return Aggregation{"date_histogram": map[string]any{"field": field, "calendar_interval": calendarInterval}}
+}
+
+// FilterAggregation keeps a single bucket of the documents matching query.
+func FilterAggregation(query Query) Aggregation {
+	return Aggregation{"filter": query}
+}
+
+// MetricAggregation computes a single value of field; kind is one of the Metric constants.
+func MetricAggregation(kind string, field string) Aggregation {
+	return Aggregation{kind: map[string]any{"field": field}}
+}
+
+// With returns a copy of a with the sub-aggregation name added to every bucket.
+func (a Aggregation) With(name string, sub Aggregation) Aggregation {
+	aggregation := make(Aggregation, len(a)+1)
+	for key, value := range a {
+		aggregation[key] = value
+	}
+
+	subs := map[string]Aggregation{}
+	if existing, ok := a["aggs"].(map[string]Aggregation); ok {
+		for key, value := range existing {
+			subs[key] = value
+		}
+	}
+
+	subs[name] = sub
+	aggregation["aggs"] = subs
+
+	return aggregation
+}
+
+// SortField orders search results by Field.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// SearchRequest is a typed search.
+type SearchRequest struct {
+	Indexes []string
+	// Query selects documents; nil matches all of them.
+	Query Query
+	Sort  []SortField
+	// Size is the number of hits; zero keeps the Elasticsearch default, or uses
+	// DefaultPageSize when paginating with SearchAfter.
+	Size int
+	From int
+	// Source limits the returned source fields; empty returns all of them.
+	Source       []string
+	Aggregations map[string]Aggregation
+	// SearchAfter continues after the sort values of the last hit of a previous page.
+	SearchAfter    []any
+	TrackTotalHits bool
+}
+
+// Body returns the JSON body of the search.
+func (r SearchRequest) Body() map[string]any {
+	body := map[string]any{}
+
+	if r.Query != nil {
+		body["query"] = r.Query
+	}
+
+	if len(r.Sort) > 0 {
+		sorts := make([]map[string]any, len(r.Sort))
+
+		for i, field := range r.Sort {
+			order := "asc"
+			if field.Descending {
+				order = "desc"
+			}
+
+			sorts[i] = map[string]any{field.Field: map[string]any{"order": order}}
+		}
+
+		body["sort"] = sorts
+	}
+
+	if r.Size > 0 {
+		body["size"] = r.Size
+	}
+
+	if r.From > 0 {
+		body["from"] = r.From
+	}
+
+	if len(r.Source) > 0 {
+		body["_source"] = r.Source
+	}
+
+	if len(r.Aggregations) > 0 {
+		body["aggs"] = r.Aggregations
+	}
+
+	if len(r.SearchAfter) > 0 {
+		body["search_after"] = r.SearchAfter
+	}
+
+	if r.TrackTotalHits {
+		body["track_total_hits"] = true
+	}
+
+	return body
+}
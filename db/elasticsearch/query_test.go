@@ -0,0 +1,101 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+)
+
+func TestSearchRequestBody(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		request SearchRequest
+		want    string
+	}{
+		{
+			name:    "empty",
+			request: SearchRequest{},
+			want:    `{}`,
+		},
+		{
+			name: "bool query with sort and paging",
+			request: SearchRequest{
+				Query: Bool(BoolQuery{
+					Must:    []Query{Match("name", "lamp")},
+					Filter:  []Query{Term("status", "active"), Range("price", RangeBounds{GTE: 10, LT: 100})},
+					MustNot: []Query{Exists("deleted_at")},
+				}),
+				Sort:           []SortField{{Field: "price", Descending: true}, {Field: "id"}},
+				Size:           20,
+				From:           40,
+				Source:         []string{"name", "price"},
+				TrackTotalHits: true,
+			},
+			want: `{
+				"query": {"bool": {
+					"must": [{"match": {"name": {"query": "lamp"}}}],
+					"filter": [
+						{"term": {"status": {"value": "active"}}},
+						{"range": {"price": {"gte": 10, "lt": 100}}}
+					],
+					"must_not": [{"exists": {"field": "deleted_at"}}]
+				}},
+				"sort": [{"price": {"order": "desc"}}, {"id": {"order": "asc"}}],
+				"size": 20,
+				"from": 40,
+				"_source": ["name", "price"],
+				"track_total_hits": true
+			}`,
+		},
+		{
+			name: "aggregations with sub aggregations",
+			request: SearchRequest{
+				Query: Nested("variants", Terms("variants.color", "red", "blue")),
+				Aggregations: map[string]Aggregation{
+					"brands": TermsAggregation("brand", 5).
+						With("avg_price", MetricAggregation(MetricAvg, "price")).
+						With("max_price", MetricAggregation(MetricMax, "price")),
+					"daily": DateHistogramAggregation("created_at", "day"),
+				},
+				SearchAfter: []any{10.5, "b"},
+			},
+			want: `{
+				"query": {"nested": {"path": "variants", "query": {"terms": {"variants.color": ["red", "blue"]}}}},
+				"aggs": {
+					"brands": {
+						"terms": {"field": "brand", "size": 5},
+						"aggs": {
+							"avg_price": {"avg": {"field": "price"}},
+							"max_price": {"max": {"field": "price"}}
+						}
+					},
+					"daily": {"date_histogram": {"field": "created_at", "calendar_interval": "day"}}
+				},
+				"search_after": [10.5, "b"]
+			}`,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			body, err := json.Marshal(test.request.Body())
+			testutils.Equal(t, err, nil)
+			testutils.Equal(t, decodeJSON(t, string(body)), decodeJSON(t, test.want))
+		})
+	}
+}
+
+func TestAggregationWithDoesNotModifyReceiver(t *testing.T) {
+	t.Parallel()
+
+	base := TermsAggregation("brand", 0)
+	_ = base.With("count", MetricAggregation(MetricValueCount, "id"))
+
+	_, ok := base["aggs"]
+	testutils.Equal(t, ok, false)
+}
@@ -0,0 +1,68 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/InsideGallery/core/db/instrument"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// perform runs req, reports it to the observer and decodes a successful JSON response
+// into out; a nil out discards the body.
+func (c *SearchClient) perform(ctx context.Context, operation, target string, req esapi.Request, out any) error {
+	done := instrument.Begin(ctx, c.observer, instrument.Event{
+		System:    instrument.SystemElasticsearch,
+		Operation: operation,
+		Target:    target,
+	})
+
+	err := c.do(ctx, operation, req, out)
+	done(0, err)
+
+	return err
+}
+
+func (c *SearchClient) do(ctx context.Context, operation string, req esapi.Request, out any) (err error) {
+	res, err := req.Do(ctx, c.transport)
+	if err != nil {
+		return coreerrors.WrapBoundary("elasticsearch", operation, err)
+	}
+
+	defer func() {
+		if closeErr := res.Body.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("error closing response: %w", closeErr)
+		}
+	}()
+
+	if res.IsError() {
+		return responseError(res)
+	}
+
+	if out == nil {
+		_, err = io.Copy(io.Discard, res.Body)
+
+		return err
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("error parsing the response body: %w", err)
+	}
+
+	return nil
+}
+
+// jsonBody encodes v as a request body.
+func jsonBody(v any) (io.Reader, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, fmt.Errorf("error encoding body: %w", err)
+	}
+
+	return &buf, nil
+}
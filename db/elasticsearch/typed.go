@@ -0,0 +1,290 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// DefaultPageSize is the page size of SearchAfter when the request has no Size.
+const DefaultPageSize = 100
+
+// shardDocField is the PIT tiebreaker sort used when a paginated request has no sort.
+const shardDocField = "_shard_doc"
+
+// Hit is a search hit with its source decoded into T.
+type Hit[T any] struct {
+	Index  string
+	ID     string
+	Score  float64
+	Source T
+	// Sort holds the sort values of the hit, used as SearchAfter of the next page.
+	Sort []any
+}
+
+// SearchResponse is a typed search response.
+type SearchResponse[T any] struct {
+	Took         int64
+	Total        int64
+	Hits         []Hit[T]
+	Aggregations Aggregations
+	// PITID is the point-in-time id to use for the next page, when searching a PIT.
+	PITID string
+}
+
+// Aggregations holds the raw results of the aggregations of a response or a bucket.
+type Aggregations map[string]json.RawMessage
+
+// Bucket is a bucket of a terms, histogram or date histogram aggregation.
+type Bucket struct {
+	Key          any
+	KeyAsString  string
+	DocCount     int64
+	Aggregations Aggregations
+}
+
+// UnmarshalJSON decodes the known bucket fields and keeps the others as sub-aggregations.
+func (b *Bucket) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	b.Aggregations = Aggregations{}
+
+	for name, value := range fields {
+		var err error
+
+		switch name {
+		case "key":
+			err = json.Unmarshal(value, &b.Key)
+		case "key_as_string":
+			err = json.Unmarshal(value, &b.KeyAsString)
+		case "doc_count":
+			err = json.Unmarshal(value, &b.DocCount)
+		default:
+			b.Aggregations[name] = value
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Buckets returns the buckets of the bucket aggregation name.
+func (a Aggregations) Buckets(name string) ([]Bucket, error) {
+	raw, ok := a[name]
+	if !ok {
+		return nil, nil
+	}
+
+	var aggregation struct {
+		Buckets []Bucket `json:"buckets"`
+	}
+
+	err := json.Unmarshal(raw, &aggregation)
+
+	return aggregation.Buckets, err
+}
+
+// Value returns the value of the metric aggregation name; a missing or null value is zero.
+func (a Aggregations) Value(name string) (float64, error) {
+	raw, ok := a[name]
+	if !ok {
+		return 0, nil
+	}
+
+	var aggregation struct {
+		Value *float64 `json:"value"`
+	}
+
+	if err := json.Unmarshal(raw, &aggregation); err != nil || aggregation.Value == nil {
+		return 0, err
+	}
+
+	return *aggregation.Value, nil
+}
+
+// searchResponse is the wire format of a search response.
+type searchResponse struct {
+	Took  int64  `json:"took"`
+	PITID string `json:"pit_id"`
+	Hits  struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Index  string          `json:"_index"`
+			ID     string          `json:"_id"`
+			Score  *float64        `json:"_score"`
+			Source json.RawMessage `json:"_source"`
+			Sort   []any           `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations Aggregations `json:"aggregations"`
+}
+
+// Search runs request and decodes the source of every hit into T.
+func Search[T any](ctx context.Context, client *SearchClient, request SearchRequest) (SearchResponse[T], error) {
+	return search[T](ctx, client, request.Indexes, request.Body())
+}
+
+// SearchAfter pages through every hit of request in sort order, calling fn per page until
+// the hits run out or fn returns an error; ErrStop stops without an error. A positive
+// keepAlive searches a point in time, so pages stay consistent while documents change,
+// and sorts by the PIT tiebreaker when the request has no sort. Without a point in time
+// the request needs a sort whose last field is unique.
+func SearchAfter[T any](
+	ctx context.Context,
+	client *SearchClient,
+	request SearchRequest,
+	keepAlive time.Duration,
+	fn func(hits []Hit[T]) error,
+) (err error) {
+	if request.Size <= 0 {
+		request.Size = DefaultPageSize
+	}
+
+	request.From = 0
+
+	indexes := request.Indexes
+
+	var pit map[string]any
+
+	switch {
+	case keepAlive > 0:
+		var id string
+
+		id, err = client.OpenPIT(ctx, indexes, keepAlive)
+		if err != nil {
+			return err
+		}
+
+		// The id may change between pages, so the last one returned is closed.
+		defer func() {
+			if closeErr := client.ClosePIT(context.WithoutCancel(ctx), pit["id"].(string)); err == nil {
+				err = closeErr
+			}
+		}()
+
+		pit = map[string]any{"id": id, "keep_alive": keepAliveParam(keepAlive)}
+		indexes = nil
+
+		if len(request.Sort) == 0 {
+			request.Sort = []SortField{{Field: shardDocField}}
+		}
+	case len(request.Sort) == 0:
+		return ErrSortIsNotSet
+	}
+
+	for {
+		body := request.Body()
+		if pit != nil {
+			body["pit"] = pit
+		}
+
+		page, err := search[T](ctx, client, indexes, body)
+		if err != nil {
+			return err
+		}
+
+		if len(page.Hits) == 0 {
+			return nil
+		}
+
+		if err := fn(page.Hits); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+
+			return err
+		}
+
+		if len(page.Hits) < request.Size {
+			return nil
+		}
+
+		request.SearchAfter = page.Hits[len(page.Hits)-1].Sort
+
+		if pit != nil && page.PITID != "" {
+			pit["id"] = page.PITID
+		}
+	}
+}
+
+// OpenPIT opens a point in time over indexes, kept alive for keepAlive between searches.
+func (c *SearchClient) OpenPIT(ctx context.Context, indexes []string, keepAlive time.Duration) (string, error) {
+	var response struct {
+		ID string `json:"id"`
+	}
+
+	err := c.perform(ctx, "open pit", strings.Join(indexes, ","), esapi.OpenPointInTimeRequest{
+		Index:     indexes,
+		KeepAlive: keepAliveParam(keepAlive),
+	}, &response)
+
+	return response.ID, err
+}
+
+// ClosePIT closes a point in time.
+func (c *SearchClient) ClosePIT(ctx context.Context, id string) error {
+	reader, err := jsonBody(map[string]any{"id": id})
+	if err != nil {
+		return err
+	}
+
+	return c.perform(ctx, "close pit", "", esapi.ClosePointInTimeRequest{Body: reader}, nil)
+}
+
+func search[T any](ctx context.Context, client *SearchClient, indexes []string, body map[string]any) (SearchResponse[T], error) {
+	reader, err := jsonBody(body)
+	if err != nil {
+		return SearchResponse[T]{}, err
+	}
+
+	var response searchResponse
+
+	err = client.perform(ctx, "search", strings.Join(indexes, ","), esapi.SearchRequest{
+		Index: indexes,
+		Body:  reader,
+	}, &response)
+	if err != nil {
+		return SearchResponse[T]{}, err
+	}
+
+	result := SearchResponse[T]{
+		Took:         response.Took,
+		Total:        response.Hits.Total.Value,
+		Hits:         make([]Hit[T], len(response.Hits.Hits)),
+		Aggregations: response.Aggregations,
+		PITID:        response.PITID,
+	}
+
+	for i, hit := range response.Hits.Hits {
+		result.Hits[i] = Hit[T]{Index: hit.Index, ID: hit.ID, Sort: hit.Sort}
+
+		if hit.Score != nil {
+			result.Hits[i].Score = *hit.Score
+		}
+
+		if len(hit.Source) > 0 {
+			if err := json.Unmarshal(hit.Source, &result.Hits[i].Source); err != nil {
+				return result, fmt.Errorf("error decoding hit %s: %w", hit.ID, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func keepAliveParam(keepAlive time.Duration) string {
+	return fmt.Sprintf("%dms", keepAlive.Milliseconds())
+}
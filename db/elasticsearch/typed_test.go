@@ -0,0 +1,212 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+)
+
+type product struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+func hit(id string, source product, sort ...any) map[string]any {
+	return map[string]any{"_index": "products", "_id": id, "_score": 1.5, "_source": source, "sort": sort}
+}
+
+func TestSearchDecodesHitsAndAggregations(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("POST /products/_search", func(string) (int, any) {
+		return http.StatusOK, map[string]any{
+			"took": 3,
+			"hits": map[string]any{
+				"total": map[string]any{"value": 2},
+				"hits":  []any{hit("1", product{Name: "lamp", Price: 20}), hit("2", product{Name: "desk", Price: 80})},
+			},
+			"aggregations": map[string]any{
+				"avg_price": map[string]any{"value": 50},
+				"empty":     map[string]any{"value": nil},
+				"brands": map[string]any{"buckets": []any{
+					map[string]any{"key": "acme", "doc_count": 2, "avg_price": map[string]any{"value": 50}},
+				}},
+			},
+		}
+	})
+
+	response, err := Search[product](context.Background(), cluster.client(), SearchRequest{
+		Indexes: []string{"products"},
+		Query:   MatchAll(),
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, response.Took, int64(3))
+	testutils.Equal(t, response.Total, int64(2))
+	testutils.Equal(t, len(response.Hits), 2)
+	testutils.Equal(t, response.Hits[1].ID, "2")
+	testutils.Equal(t, response.Hits[1].Score, 1.5)
+	testutils.Equal(t, response.Hits[1].Source, product{Name: "desk", Price: 80})
+
+	average, err := response.Aggregations.Value("avg_price")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, average, 50.0)
+
+	empty, err := response.Aggregations.Value("empty")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, empty, 0.0)
+
+	buckets, err := response.Aggregations.Buckets("brands")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(buckets), 1)
+	testutils.Equal(t, buckets[0].Key, any("acme"))
+	testutils.Equal(t, buckets[0].DocCount, int64(2))
+
+	bucketAverage, err := buckets[0].Aggregations.Value("avg_price")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, bucketAverage, 50.0)
+}
+
+func TestSearchReportsErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("POST /missing/_search", func(string) (int, any) {
+		return http.StatusNotFound, errorBody("index_not_found_exception", "no such index [missing]")
+	})
+
+	_, err := Search[product](context.Background(), cluster.client(), SearchRequest{Indexes: []string{"missing"}})
+
+	var responseErr *ResponseError
+	testutils.Equal(t, errors.As(err, &responseErr), true)
+	testutils.Equal(t, responseErr.StatusCode, http.StatusNotFound)
+	testutils.Equal(t, responseErr.Type, "index_not_found_exception")
+	testutils.Equal(t, errors.Is(err, ErrWrongResponse), true)
+}
+
+func TestSearchAfterWithPIT(t *testing.T) {
+	t.Parallel()
+
+	pages := [][]any{
+		{hit("1", product{Name: "a"}, 1), hit("2", product{Name: "b"}, 2)},
+		{hit("3", product{Name: "c"}, 3)},
+	}
+
+	cluster := newFakeCluster(t)
+	cluster.handle("POST /products/_pit", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"id": "pit-1"}
+	})
+	cluster.handle("DELETE /_pit", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"succeeded": true}
+	})
+
+	page := 0
+	cluster.handle("POST /_search", func(string) (int, any) {
+		hits := pages[page]
+		page++
+
+		return http.StatusOK, map[string]any{"pit_id": "pit-2", "hits": map[string]any{"hits": hits}}
+	})
+
+	var names []string
+
+	err := SearchAfter(context.Background(), cluster.client(), SearchRequest{
+		Indexes: []string{"products"},
+		Size:    2,
+	}, time.Minute, func(hits []Hit[product]) error {
+		for _, hit := range hits {
+			names = append(names, hit.Source.Name)
+		}
+
+		return nil
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, names, []string{"a", "b", "c"})
+
+	calls := cluster.requests()
+	testutils.Equal(t, len(calls), 4)
+	testutils.Equal(t, calls[0].Query, "keep_alive=60000ms")
+	testutils.Equal(t, decodeJSON(t, calls[1].Body), decodeJSON(t, `{
+		"pit": {"id": "pit-1", "keep_alive": "60000ms"},
+		"sort": [{"_shard_doc": {"order": "asc"}}],
+		"size": 2
+	}`))
+	testutils.Equal(t, decodeJSON(t, calls[2].Body), decodeJSON(t, `{
+		"pit": {"id": "pit-2", "keep_alive": "60000ms"},
+		"sort": [{"_shard_doc": {"order": "asc"}}],
+		"size": 2,
+		"search_after": [2]
+	}`))
+	testutils.Equal(t, decodeJSON(t, calls[3].Body), decodeJSON(t, `{"id": "pit-2"}`))
+}
+
+func TestSearchAfterReturnsClosePITError(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.handle("POST /products/_pit", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"id": "pit-1"}
+	})
+	cluster.handle("DELETE /_pit", func(string) (int, any) {
+		return http.StatusNotFound, errorBody("search_context_missing_exception", "no such pit")
+	})
+	cluster.handle("POST /_search", func(string) (int, any) {
+		return http.StatusOK, map[string]any{"pit_id": "pit-1", "hits": map[string]any{"hits": []any{}}}
+	})
+
+	err := SearchAfter(context.Background(), cluster.client(), SearchRequest{
+		Indexes: []string{"products"},
+	}, time.Minute, func([]Hit[product]) error {
+		return nil
+	})
+
+	var responseErr *ResponseError
+
+	testutils.Equal(t, errors.As(err, &responseErr), true)
+	testutils.Equal(t, responseErr.Type, "search_context_missing_exception")
+}
+
+func TestSearchAfterWithoutPIT(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+
+	var bodies []map[string]any
+
+	cluster.handle("POST /products/_search", func(body string) (int, any) {
+		var decoded map[string]any
+		_ = json.Unmarshal([]byte(body), &decoded)
+		bodies = append(bodies, decoded)
+
+		return http.StatusOK, map[string]any{"hits": map[string]any{"hits": []any{
+			hit("1", product{Name: "a"}, "a"),
+		}}}
+	})
+
+	client := cluster.client()
+	request := SearchRequest{Indexes: []string{"products"}, Size: 1}
+
+	err := SearchAfter(context.Background(), client, request, 0, func([]Hit[product]) error { return nil })
+	testutils.Equal(t, errors.Is(err, ErrSortIsNotSet), true)
+
+	request.Sort = []SortField{{Field: "name"}}
+	calls := 0
+
+	err = SearchAfter(context.Background(), client, request, 0, func([]Hit[product]) error {
+		calls++
+		if calls == 2 {
+			return ErrStop
+		}
+
+		return nil
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, calls, 2)
+	testutils.Equal(t, len(bodies), 2)
+	testutils.Equal(t, bodies[1]["search_after"], any([]any{"a"}))
+}
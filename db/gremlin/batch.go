@@ -0,0 +1,153 @@
+package gremlin
+
+import (
+	"fmt"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// Kinds of batch operations; a batch holds operations of one kind.
+const (
+	batchKindVertex = "vertex"
+	batchKindEdge   = "edge"
+)
+
+// batchOperation is an Operation that can be chained with others of its kind into one
+// traversal.
+type batchOperation interface {
+	Operation
+	batchKind() string
+	// appendTo adds the operation steps to traversal.
+	appendTo(traversal *gremlingo.GraphTraversal, cache *Cache, source *gremlingo.GraphTraversalSource) (
+		*gremlingo.GraphTraversal, error)
+	// complete records the element the operation steps returned.
+	complete(cache *Cache, result *gremlingo.Result) error
+}
+
+var (
+	_ batchOperation = (*UpsertVertexOp)(nil)
+	_ batchOperation = (*UpsertEdgeOp)(nil)
+)
+
+func (o *UpsertVertexOp) batchKind() string {
+	return batchKindVertex
+}
+
+func (o *UpsertVertexOp) appendTo(
+	traversal *gremlingo.GraphTraversal,
+	_ *Cache,
+	_ *gremlingo.GraphTraversalSource,
+) (*gremlingo.GraphTraversal, error) {
+	return MergeV(traversal, o.label, o.id, o.properties), nil
+}
+
+func (o *UpsertVertexOp) complete(cache *Cache, result *gremlingo.Result) error {
+	vertex, err := result.GetVertex()
+	if err != nil {
+		return fmt.Errorf("UpsertVertexOp failed for get vertex: %w", err)
+	}
+
+	err = cache.AddVertex(o.label, o.id, vertex)
+	if err != nil {
+		return fmt.Errorf("UpsertVertexOp failed to add vertex to cache: %w", err)
+	}
+
+	o.setResult([]*gremlingo.Result{result})
+
+	return nil
+}
+
+func (o *UpsertEdgeOp) batchKind() string {
+	return batchKindEdge
+}
+
+func (o *UpsertEdgeOp) appendTo(
+	traversal *gremlingo.GraphTraversal,
+	cache *Cache,
+	source *gremlingo.GraphTraversalSource,
+) (*gremlingo.GraphTraversal, error) {
+	_, vertex1, err := o.from.Get(cache, source)
+	if err != nil {
+		return nil, fmt.Errorf("UpsertEdgeOp failed for get vertexLabel (from): %w", err)
+	}
+
+	_, vertex2, err := o.to.Get(cache, source)
+	if err != nil {
+		return nil, fmt.Errorf("UpsertEdgeOp failed for get vertexLabel (to): %w", err)
+	}
+
+	return MergeE(traversal, o.edge, o.id, vertex1, vertex2, o.properties), nil
+}
+
+func (o *UpsertEdgeOp) complete(_ *Cache, result *gremlingo.Result) error {
+	_, err := result.GetEdge()
+	if err != nil {
+		return fmt.Errorf("UpsertEdgeOp failed for get edge: %w", err)
+	}
+
+	o.setResult([]*gremlingo.Result{result})
+
+	return nil
+}
+
+// batchTraversal chains ops into one traversal. With more than one operation it selects
+// the element of each by its step label, returned by batchStep.
+func batchTraversal(
+	cache *Cache,
+	source *gremlingo.GraphTraversalSource,
+	ops []batchOperation,
+) (*gremlingo.GraphTraversal, error) {
+	traversal := source.GetGraphTraversal()
+	steps := make([]any, 0, len(ops))
+
+	for i, op := range ops {
+		var err error
+
+		traversal, err = op.appendTo(traversal, cache, source)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ops) > 1 {
+			traversal = traversal.As(batchStep(i))
+			steps = append(steps, batchStep(i))
+		}
+	}
+
+	if len(steps) > 0 {
+		traversal = traversal.Select(steps...)
+	}
+
+	return traversal, nil
+}
+
+func batchStep(i int) string {
+	return fmt.Sprintf("op%d", i)
+}
+
+// completeBatch hands each operation of a batchTraversal its element.
+func completeBatch(cache *Cache, ops []batchOperation, results []*gremlingo.Result) error {
+	if len(results) != 1 {
+		return fmt.Errorf("%w: batch returned %d results", ErrCastType, len(results))
+	}
+
+	elements := map[any]any{batchStep(0): results[0].Data}
+
+	if len(ops) > 1 {
+		var ok bool
+
+		elements, ok = results[0].Data.(map[any]any)
+		if !ok {
+			return fmt.Errorf("%w: batch returned %T", ErrCastType, results[0].Data)
+		}
+	}
+
+	for i, op := range ops {
+		err := op.complete(cache, &gremlingo.Result{Data: elements[batchStep(i)]})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -9,7 +9,8 @@
 //
 // Prefer VertexStore or GraphStore with UpsertVertexOptions, UpsertEdgeOptions,
 // CountVerticesOptions, ListValuesOptions, and their result types instead of
-// exposing Gremlin SDK traversal values through application interfaces.
+// exposing Gremlin SDK traversal values through application interfaces, and
+// GraphTraverser for neighbors, paths, subgraphs, degrees and paginated listing.
 //
 // Compatibility: legacy traversal helpers and package-level syntax state remain
 // available for existing consumers. Prefer explicit options and SyntaxState in
@@ -22,6 +23,7 @@ import (
 
 	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
 
+	"github.com/InsideGallery/core/db/instrument"
 	coreerrors "github.com/InsideGallery/core/errors"
 )
 
@@ -136,8 +138,14 @@ func (c *Client) UpsertVertex(ctx context.Context, options UpsertVertexOptions)
 		return GraphResult{}, coreerrors.WrapBoundary("gremlin", "upsert vertex", err)
 	}
 
+	done := c.observe(ctx, "upsert vertex", options.Label)
+
 	op := NewUpsertVertexOp(options.Label, options.ID, propertiesKeyValues(options.Properties)...)
-	if err := c.Execute(NewCache(), op); err != nil {
+
+	err := c.Execute(NewCache(), op)
+	done(int64(len(op.Result())), err)
+
+	if err != nil {
 		return GraphResult{}, coreerrors.WrapBoundary("gremlin", "upsert vertex", err)
 	}
 
@@ -154,6 +162,8 @@ func (c *Client) UpsertEdge(ctx context.Context, options UpsertEdgeOptions) (Gra
 		return GraphResult{}, coreerrors.WrapBoundary("gremlin", "upsert edge", err)
 	}
 
+	done := c.observe(ctx, "upsert edge", options.Label)
+
 	op := NewUpsertEdgeOp(
 		options.Label,
 		options.ID,
@@ -161,7 +171,11 @@ func (c *Client) UpsertEdge(ctx context.Context, options UpsertEdgeOptions) (Gra
 		NewLabelVertexGetter(options.To.Label, options.To.ID),
 		propertiesKeyValues(options.Properties)...,
 	)
-	if err := c.Execute(NewCache(), op); err != nil {
+
+	err := c.Execute(NewCache(), op)
+	done(int64(len(op.Result())), err)
+
+	if err != nil {
 		return GraphResult{}, coreerrors.WrapBoundary("gremlin", "upsert edge", err)
 	}
 
@@ -178,7 +192,13 @@ func (c *Client) CountVertices(ctx context.Context, options CountVerticesOptions
 		return CountResult{}, coreerrors.WrapBoundary("gremlin", "count vertices", err)
 	}
 
-	count, err := WrapCount(applyTraversalOptions(c.S().V(), options.Label, options.ID, options.Filters))
+	done := c.observe(ctx, "count vertices", options.Label)
+
+	count, err := WrapCount(
+		applyTraversalOptions(c.S().V(), CurrentSyntaxState(), options.Label, options.ID, options.Filters),
+	)
+	done(0, err)
+
 	if err != nil {
 		return CountResult{}, coreerrors.WrapBoundary("gremlin", "count vertices", err)
 	}
@@ -196,10 +216,14 @@ func (c *Client) ListValues(ctx context.Context, options ListValuesOptions) (Val
 		return ValueListResult{}, coreerrors.WrapBoundary("gremlin", "list values", err)
 	}
 
+	done := c.observe(ctx, "list values", options.Label)
+
 	results, err := WrapValuesToList(
-		applyTraversalOptions(c.S().V(), options.Label, options.ID, options.Filters),
+		applyTraversalOptions(c.S().V(), CurrentSyntaxState(), options.Label, options.ID, options.Filters),
 		options.Property,
 	)
+	done(int64(len(results)), err)
+
 	if err != nil {
 		return ValueListResult{}, coreerrors.WrapBoundary("gremlin", "list values", err)
 	}
@@ -227,6 +251,20 @@ func (c *Client) CloseGraph(ctx context.Context) error {
 	return nil
 }
 
+// SetObserver sets the observer of the core-owned graph operations; nil uses
+// instrument.Default. Call it before the client is shared.
+func (c *Client) SetObserver(observer instrument.Observer) {
+	c.observer = observer
+}
+
+func (c *Client) observe(ctx context.Context, operation, label string) func(rows int64, err error) {
+	return instrument.Begin(ctx, c.observer, instrument.Event{
+		System:    instrument.SystemGremlin,
+		Operation: operation,
+		Target:    label,
+	})
+}
+
 func propertiesKeyValues(properties map[string]any) []any {
 	if len(properties) == 0 {
 		return nil
@@ -249,6 +287,7 @@ func propertiesKeyValues(properties map[string]any) []any {
 
 func applyTraversalOptions(
 	traversal *gremlingo.GraphTraversal,
+	state SyntaxState,
 	label string,
 	id string,
 	filters []PropertyFilter,
@@ -258,7 +297,7 @@ func applyTraversalOptions(
 	}
 
 	if id != "" {
-		traversal = WrapperHasID(traversal, id)
+		traversal = hasID(traversal, state, id)
 	}
 
 	for _, filter := range filters {
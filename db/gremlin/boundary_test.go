@@ -16,6 +16,7 @@ func TestVertexStoreContract(t *testing.T) {
 
 				var _ VertexStore = (*Client)(nil)
 				var _ GraphStore = (*Client)(nil)
+				var _ GraphTraverser = (*Client)(nil)
 			},
 		},
 		{
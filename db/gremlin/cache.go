@@ -1,6 +1,7 @@
 package gremlin
 
 import (
+	"container/list"
 	"errors"
 	"sync"
 
@@ -10,33 +11,112 @@ import (
 
 var ErrCastType = errors.New("error cast type")
 
+// CacheOptions configures a Cache.
+type CacheOptions struct {
+	// MaxEntries bounds the cached vertices, evicting the least recently used; zero means
+	// unbounded.
+	MaxEntries int
+}
+
+// CacheStats counts the vertex lookups and evictions of a Cache.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+// HitRatio returns the share of lookups that were hits, or zero before any lookup.
+func (s CacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type cacheKey struct {
+	label string
+	id    string
+}
+
+// Cache keeps the vertices operations upserted or looked up, so edges can reference them
+// without a query. The vertex methods keep it within CacheOptions.MaxEntries and count
+// Stats; writes through the embedded Registry bypass both.
 type Cache struct {
 	*registry.Registry[string, string, any]
 	mu sync.RWMutex
+
+	maxEntries int
+	// order lists cacheKey values, the most recently used first.
+	order    *list.List
+	elements map[cacheKey]*list.Element
+	stats    CacheStats
 }
 
+// NewCache returns an unbounded Cache.
 func NewCache() *Cache {
+	return NewCacheWithOptions(CacheOptions{})
+}
+
+// NewCacheWithOptions returns a Cache configured by options.
+func NewCacheWithOptions(options CacheOptions) *Cache {
 	return &Cache{
-		Registry: registry.NewRegistry[string, string, any](),
+		Registry:   registry.NewRegistry[string, string, any](),
+		maxEntries: max(options.MaxEntries, 0),
+		order:      list.New(),
+		elements:   map[cacheKey]*list.Element{},
 	}
 }
 
 func (c *Cache) AddVertex(label, id string, vertex *gremlingo.Vertex) error {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.Add(label, id, vertex)
+	if err != nil {
+		return err
+	}
+
+	c.initOrder()
+
+	key := cacheKey{label: label, id: id}
+	if element, ok := c.elements[key]; ok {
+		c.order.MoveToFront(element)
+
+		return nil
+	}
+
+	c.elements[key] = c.order.PushFront(key)
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest, _ := c.order.Remove(c.order.Back()).(cacheKey)
+		delete(c.elements, oldest)
 
-	return c.Add(label, id, vertex)
+		_ = c.Remove(oldest.label, oldest.id)
+		c.stats.Evictions++
+	}
+
+	return nil
 }
 
 func (c *Cache) GetVertex(label, id string) (*gremlingo.Vertex, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	rawVertex, err := c.Get(label, id)
 	if err != nil {
+		c.stats.Misses++
+
 		return nil, err
 	}
 
+	c.stats.Hits++
+
+	if element, ok := c.elements[cacheKey{label: label, id: id}]; ok {
+		c.order.MoveToFront(element)
+	}
+
 	vertex, ok := rawVertex.(*gremlingo.Vertex)
 	if !ok {
 		return nil, ErrCastType
@@ -46,17 +126,44 @@ func (c *Cache) GetVertex(label, id string) (*gremlingo.Vertex, error) {
 }
 
 func (c *Cache) DeleteVertex(label, id string) error {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cacheKey{label: label, id: id}
+	if element, ok := c.elements[key]; ok {
+		c.order.Remove(element)
+		delete(c.elements, key)
+	}
 
 	err := c.Remove(label, id)
 
 	return err
 }
 
+// Stats returns the lookup counts since the cache was created and its current size.
+func (c *Cache) Stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := c.stats
+	stats.Entries = len(c.elements)
+
+	return stats
+}
+
 func (c *Cache) Truncate() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.Registry = registry.NewRegistry[string, string, any]()
+	c.order, c.elements = nil, nil
+	c.initOrder()
+}
+
+// initOrder prepares the usage order of a Cache built without NewCacheWithOptions.
+func (c *Cache) initOrder() {
+	if c.order == nil {
+		c.order = list.New()
+		c.elements = map[cacheKey]*list.Element{}
+	}
 }
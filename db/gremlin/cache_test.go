@@ -0,0 +1,81 @@
+package gremlin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FrogoAI/memory/registry"
+	"github.com/FrogoAI/testutils"
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+func vertex(id string) *gremlingo.Vertex {
+	return &gremlingo.Vertex{Element: gremlingo.Element{Id: id, Label: "person"}}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCacheWithOptions(CacheOptions{MaxEntries: 2})
+
+	testutils.Equal(t, cache.AddVertex("person", "1", vertex("1")), nil)
+	testutils.Equal(t, cache.AddVertex("person", "2", vertex("2")), nil)
+
+	_, err := cache.GetVertex("person", "1")
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, cache.AddVertex("person", "3", vertex("3")), nil)
+
+	_, err = cache.GetVertex("person", "2")
+	testutils.Equal(t, errors.Is(err, registry.ErrNotFoundEntity), true)
+
+	for _, id := range []string{"1", "3"} {
+		got, err := cache.GetVertex("person", id)
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, got.Id, any(id))
+	}
+
+	stats := cache.Stats()
+	testutils.Equal(t, stats, CacheStats{Hits: 3, Misses: 1, Evictions: 1, Entries: 2})
+	testutils.Equal(t, stats.HitRatio(), 0.75)
+}
+
+func TestCacheReAddKeepsOneEntry(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCacheWithOptions(CacheOptions{MaxEntries: 2})
+
+	for range 3 {
+		testutils.Equal(t, cache.AddVertex("person", "1", vertex("1")), nil)
+	}
+
+	testutils.Equal(t, cache.AddVertex("person", "2", vertex("2")), nil)
+	testutils.Equal(t, cache.Stats().Entries, 2)
+	testutils.Equal(t, cache.Stats().Evictions, uint64(0))
+
+	testutils.Equal(t, cache.DeleteVertex("person", "1"), nil)
+	testutils.Equal(t, cache.Stats().Entries, 1)
+
+	cache.Truncate()
+	testutils.Equal(t, cache.Stats().Entries, 0)
+
+	_, err := cache.GetVertex("person", "2")
+	testutils.Equal(t, errors.Is(err, registry.ErrNotFoundEntity), true)
+}
+
+func TestCacheUnbounded(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache()
+
+	for _, id := range []string{"1", "2", "3"} {
+		testutils.Equal(t, cache.AddVertex("person", id, vertex(id)), nil)
+	}
+
+	testutils.Equal(t, cache.Stats(), CacheStats{Entries: 3})
+	testutils.Equal(t, CacheStats{}.HitRatio(), 0.0)
+
+	zero := &Cache{Registry: registry.NewRegistry[string, string, any]()}
+	testutils.Equal(t, zero.AddVertex("person", "1", vertex("1")), nil)
+	testutils.Equal(t, zero.Stats().Entries, 1)
+}
@@ -3,6 +3,7 @@ package gremlin
 import (
 	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
 
+	"github.com/InsideGallery/core/db/instrument"
 	"github.com/InsideGallery/core/errors"
 )
 
@@ -11,6 +12,11 @@ import (
 // Deprecated: use VertexStore and core-owned option/result types for new code.
 type Client struct {
 	Connection *gremlingo.DriverRemoteConnection
+	observer   instrument.Observer
+
+	// submit and transactions replace the remote connection in tests.
+	submit       func(traversal *gremlingo.GraphTraversal) ([]*gremlingo.Result, error)
+	transactions func() transaction
 }
 
 // GetConnection creates the legacy Gremlin remote connection.
@@ -0,0 +1,59 @@
+package gremlin
+
+import (
+	"errors"
+	"strings"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// All kind of errors for gremlin traversals
+var (
+	ErrIDIsNotSet           = errors.New("id is not set")
+	ErrPathIsNotFound       = errors.New("path is not found")
+	ErrUnsupportedDirection = errors.New("unsupported direction")
+)
+
+// concurrentModificationMarkers are lower-cased fragments of the server errors Neptune and
+// JanusGraph return when a write conflicts with a concurrent one. The driver reports server
+// errors as text only.
+var concurrentModificationMarkers = []string{
+	"concurrentmodificationexception",
+	"concurrent modification",
+	"temporarylockingexception",
+	"local lock contention",
+}
+
+func init() {
+	coreerrors.RegisterClassifier(classifyError)
+}
+
+// classifyError maps missing paths and concurrent modifications to core error categories.
+func classifyError(err error) coreerrors.Category {
+	switch {
+	case errors.Is(err, ErrPathIsNotFound):
+		return coreerrors.CategoryNotFound
+	case IsConcurrentModification(err):
+		return coreerrors.CategoryConflict
+	default:
+		return coreerrors.CategoryUnknown
+	}
+}
+
+// IsConcurrentModification reports whether err is a server error caused by a concurrent
+// write, which succeeds when retried.
+func IsConcurrentModification(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+
+	for _, marker := range concurrentModificationMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,316 @@
+package gremlin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+
+	"github.com/InsideGallery/core/db/retry"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Execute defaults.
+const (
+	DefaultExecuteMaxRetries = retry.DefaultMaxRetries
+	DefaultExecuteRetryDelay = retry.DefaultDelay
+)
+
+// ExecuteOptions configures Client.ExecuteWithOptions.
+type ExecuteOptions struct {
+	// BatchSize is how many consecutive vertex upserts, or edge upserts, are submitted as
+	// one traversal; zero or one submits every operation on its own.
+	BatchSize int
+	// Transaction runs all operations in one session transaction, committed after the
+	// last one and rolled back on the first failure. The provider must support sessions.
+	Transaction bool
+	// MaxRetries is how many times a traversal, or the whole transaction, is retried after
+	// a concurrent modification; zero uses DefaultExecuteMaxRetries and a negative value
+	// disables retries.
+	MaxRetries int
+	// RetryDelay is multiplied by the attempt number between retries; zero uses
+	// DefaultExecuteRetryDelay.
+	RetryDelay time.Duration
+}
+
+// OperationError is the failure of one operation passed to Client.ExecuteWithOptions.
+type OperationError struct {
+	// Index is the position of Operation in the executed operations.
+	Index     int
+	Operation Operation
+	Err       error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// ExecuteResult reports what Client.ExecuteWithOptions did.
+type ExecuteResult struct {
+	// Succeeded counts the applied operations; in a transaction, all or none.
+	Succeeded int
+	// Failed holds the failed operations in order.
+	Failed []*OperationError
+	// Traversals counts the submitted batches and single operations, including retries.
+	Traversals int
+	// Retries counts the retries after concurrent modifications.
+	Retries int
+}
+
+// transaction is the part of gremlingo.Transaction ExecuteWithOptions uses.
+type transaction interface {
+	Begin() (*gremlingo.GraphTraversalSource, error)
+	Commit() error
+	Rollback() error
+}
+
+// unit is the operations submitted together: a batch or a single operation.
+type unit struct {
+	start int
+	ops   []Operation
+}
+
+type pipeline struct {
+	client  *Client
+	cache   *Cache
+	options ExecuteOptions
+	result  ExecuteResult
+}
+
+// ExecuteWithOptions runs ops in order, batching and retrying them as options configure,
+// and reports the failure of each operation. Outside a transaction a failed operation does
+// not stop the others; when a batch fails, its operations are run one by one to find the
+// failed ones. A nil cache uses a new unbounded Cache.
+func (c *Client) ExecuteWithOptions(
+	ctx context.Context,
+	cache *Cache,
+	options ExecuteOptions,
+	ops ...Operation,
+) (ExecuteResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if cache == nil {
+		cache = NewCache()
+	}
+
+	p := &pipeline{client: c, cache: cache, options: options}
+	units := splitUnits(ops, options.BatchSize)
+
+	done := c.observe(ctx, "execute", "")
+
+	var err error
+	if options.Transaction {
+		err = p.transaction(ctx, ops, units)
+	} else {
+		err = p.run(ctx, c.S(), units, false)
+	}
+
+	done(int64(p.result.Succeeded), err)
+
+	if err != nil {
+		return p.result, coreerrors.WrapBoundary("gremlin", "execute", err)
+	}
+
+	return p.result, nil
+}
+
+// splitUnits groups consecutive batch operations of one kind into units of up to size.
+func splitUnits(ops []Operation, size int) []unit {
+	var units []unit
+
+	for i, op := range ops {
+		if len(units) > 0 && size > 1 {
+			last := &units[len(units)-1]
+			if len(last.ops) < size && sameBatchKind(last.ops[0], op) {
+				last.ops = append(last.ops, op)
+
+				continue
+			}
+		}
+
+		units = append(units, unit{start: i, ops: []Operation{op}})
+	}
+
+	return units
+}
+
+func sameBatchKind(a, b Operation) bool {
+	batchA, okA := a.(batchOperation)
+	batchB, okB := b.(batchOperation)
+
+	return okA && okB && batchA.batchKind() == batchB.batchKind()
+}
+
+// run submits units through source. In a transaction it stops at the first failure;
+// otherwise it runs every unit and returns the operation errors joined.
+func (p *pipeline) run(ctx context.Context, source *gremlingo.GraphTraversalSource, units []unit, inTx bool) error {
+	for n, unit := range units {
+		if err := ctx.Err(); err != nil {
+			for _, rest := range units[n:] {
+				p.fail(rest, err)
+			}
+
+			return errors.Join(p.errs()...)
+		}
+
+		var err error
+		if inTx {
+			err = p.submit(source, unit)
+		} else {
+			err = p.retry(ctx, func() error { return p.submit(source, unit) })
+		}
+
+		switch {
+		case err == nil:
+			p.result.Succeeded += len(unit.ops)
+		case inTx:
+			p.fail(unit, err)
+
+			return errors.Join(p.errs()...)
+		case len(unit.ops) > 1:
+			for i, op := range unit.ops {
+				single := unitOf(unit.start+i, op)
+
+				if err := p.retry(ctx, func() error { return p.submit(source, single) }); err != nil {
+					p.fail(single, err)
+				} else {
+					p.result.Succeeded++
+				}
+			}
+		default:
+			p.fail(unit, err)
+		}
+	}
+
+	return errors.Join(p.errs()...)
+}
+
+// transaction runs units in a session transaction, retrying it whole after a concurrent
+// modification.
+func (p *pipeline) transaction(ctx context.Context, ops []Operation, units []unit) error {
+	return p.retry(ctx, func() error {
+		p.result.Succeeded, p.result.Failed = 0, nil
+
+		tx := p.client.transaction()
+
+		source, err := tx.Begin()
+		if err != nil {
+			return coreerrors.WrapBoundary("gremlin", "begin", err)
+		}
+
+		if err := p.run(ctx, source, units, true); err != nil {
+			p.forget(ops)
+			p.result.Succeeded = 0
+
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return errors.Join(err, coreerrors.WrapBoundary("gremlin", "rollback", rollbackErr))
+			}
+
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			p.forget(ops)
+			p.result.Succeeded = 0
+			p.fail(unitOf(0, ops...), err)
+
+			return coreerrors.WrapBoundary("gremlin", "commit", err)
+		}
+
+		return nil
+	})
+}
+
+// submit runs one unit: batch operations as one traversal, others through Execute.
+func (p *pipeline) submit(source *gremlingo.GraphTraversalSource, unit unit) error {
+	p.result.Traversals++
+
+	batch := make([]batchOperation, 0, len(unit.ops))
+
+	for _, op := range unit.ops {
+		batchOp, ok := op.(batchOperation)
+		if !ok {
+			return op.Execute(p.cache, source)
+		}
+
+		batch = append(batch, batchOp)
+	}
+
+	traversal, err := batchTraversal(p.cache, source, batch)
+	if err != nil {
+		return err
+	}
+
+	results, err := p.client.submitTraversal(traversal)
+	if err != nil {
+		return fmt.Errorf("batch Execute failed: %w", err)
+	}
+
+	return completeBatch(p.cache, batch, results)
+}
+
+// retry runs fn until it succeeds, fails with another error than a concurrent
+// modification, or runs out of retries.
+func (p *pipeline) retry(ctx context.Context, fn func() error) error {
+	policy := retry.Policy{MaxRetries: p.options.MaxRetries, Delay: p.options.RetryDelay}
+
+	return retry.Do(ctx, policy, IsConcurrentModification, func(attempt int) error {
+		if attempt > 0 {
+			p.result.Retries++
+		}
+
+		return fn()
+	})
+}
+
+func (p *pipeline) fail(unit unit, err error) {
+	for i, op := range unit.ops {
+		p.result.Failed = append(p.result.Failed, &OperationError{Index: unit.start + i, Operation: op, Err: err})
+	}
+}
+
+func (p *pipeline) errs() []error {
+	errs := make([]error, 0, len(p.result.Failed))
+	for _, failed := range p.result.Failed {
+		errs = append(errs, failed)
+	}
+
+	return errs
+}
+
+// forget drops the vertices a rolled back transaction upserted from the cache.
+func (p *pipeline) forget(ops []Operation) {
+	for _, op := range ops {
+		if vertexOp, ok := op.(*UpsertVertexOp); ok {
+			_ = p.cache.DeleteVertex(vertexOp.label, vertexOp.id)
+		}
+	}
+}
+
+func unitOf(start int, ops ...Operation) unit {
+	return unit{start: start, ops: ops}
+}
+
+func (c *Client) transaction() transaction {
+	if c.transactions != nil {
+		return c.transactions()
+	}
+
+	return c.S().Tx()
+}
+
+func (c *Client) submitTraversal(traversal *gremlingo.GraphTraversal) ([]*gremlingo.Result, error) {
+	if c.submit != nil {
+		return c.submit(traversal)
+	}
+
+	return traversal.ToList()
+}
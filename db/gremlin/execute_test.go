@@ -0,0 +1,305 @@
+package gremlin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+var errConcurrent = errors.New("ConcurrentModificationException: conflict on vertex")
+
+// fakeServer answers batch traversals with one vertex or edge per operation. Scripts naming
+// the vertex 'bad' fail; scripts naming 'busy' fail with a concurrent modification busy times.
+type fakeServer struct {
+	scripts []string
+	busy    int
+}
+
+func (s *fakeServer) submit(traversal *gremlingo.GraphTraversal) ([]*gremlingo.Result, error) {
+	script, err := gremlingo.NewTranslator("g").Translate(traversal.Bytecode)
+	if err != nil {
+		return nil, err
+	}
+
+	s.scripts = append(s.scripts, script)
+
+	if strings.Contains(script, "'bad'") {
+		return nil, errors.New("vertex 'bad' is rejected")
+	}
+
+	if strings.Contains(script, "'busy'") && s.busy > 0 {
+		s.busy--
+
+		return nil, errConcurrent
+	}
+
+	element := func() any {
+		if strings.Contains(script, "mergeE") {
+			return &gremlingo.Edge{Element: gremlingo.Element{Label: "knows"}}
+		}
+
+		return &gremlingo.Vertex{Element: gremlingo.Element{Label: "person"}}
+	}
+
+	steps := strings.Count(script, "as('op")
+	if steps == 0 {
+		return []*gremlingo.Result{{Data: element()}}, nil
+	}
+
+	elements := map[any]any{}
+	for i := range steps {
+		elements[batchStep(i)] = element()
+	}
+
+	return []*gremlingo.Result{{Data: elements}}, nil
+}
+
+type fakeTransaction struct {
+	begins, commits, rollbacks int
+	commitErr                  error
+}
+
+func (t *fakeTransaction) Begin() (*gremlingo.GraphTraversalSource, error) {
+	t.begins++
+
+	return gremlingo.NewGraphTraversalSource(nil, nil, gremlingo.NewBytecode(nil)), nil
+}
+
+func (t *fakeTransaction) Commit() error {
+	t.commits++
+
+	return t.commitErr
+}
+
+func (t *fakeTransaction) Rollback() error {
+	t.rollbacks++
+
+	return nil
+}
+
+func newFakeClient(server *fakeServer, tx *fakeTransaction) *Client {
+	return &Client{
+		submit:       server.submit,
+		transactions: func() transaction { return tx },
+	}
+}
+
+func failedIndexes(result ExecuteResult) []int {
+	indexes := make([]int, 0, len(result.Failed))
+	for _, failed := range result.Failed {
+		indexes = append(indexes, failed.Index)
+	}
+
+	return indexes
+}
+
+func TestSplitUnits(t *testing.T) {
+	t.Parallel()
+
+	from := NewCommonVertexGetter(vertex("1"), "1")
+	callback := NewCallbackOp(func(*Cache, *gremlingo.GraphTraversalSource) ([]*gremlingo.Result, error) {
+		return nil, nil
+	})
+
+	ops := []Operation{
+		NewUpsertVertexOp("person", "1"),
+		NewUpsertVertexOp("person", "2"),
+		NewUpsertVertexOp("person", "3"),
+		NewUpsertEdgeOp("knows", "1-2", from, from),
+		callback,
+		NewUpsertVertexOp("person", "4"),
+	}
+
+	sizes := func(units []unit) [][2]int {
+		got := make([][2]int, 0, len(units))
+		for _, unit := range units {
+			got = append(got, [2]int{unit.start, len(unit.ops)})
+		}
+
+		return got
+	}
+
+	testutils.Equal(t, sizes(splitUnits(ops, 2)), [][2]int{{0, 2}, {2, 1}, {3, 1}, {4, 1}, {5, 1}})
+	testutils.Equal(t, sizes(splitUnits(ops, 10)), [][2]int{{0, 3}, {3, 1}, {4, 1}, {5, 1}})
+	testutils.Equal(t, len(splitUnits(ops, 0)), len(ops))
+}
+
+func TestExecuteBatchesUpserts(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeServer{}
+	cache := NewCache()
+	from := NewCommonVertexGetter(vertex("1"), "1")
+	vertexOp := NewUpsertVertexOp("person", "1")
+
+	result, err := newFakeClient(server, nil).ExecuteWithOptions(context.Background(), cache,
+		ExecuteOptions{BatchSize: 10},
+		vertexOp,
+		NewUpsertVertexOp("person", "2"),
+		NewUpsertEdgeOp("knows", "1-2", from, from),
+	)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, result, ExecuteResult{Succeeded: 3, Traversals: 2})
+	testutils.Equal(t, len(server.scripts), 2)
+	testutils.Equal(t, strings.Count(server.scripts[0], "mergeV("), 2)
+	testutils.Equal(t, strings.HasSuffix(server.scripts[0], ".select('op0','op1')"), true)
+	testutils.Equal(t, strings.Contains(server.scripts[1], "select("), false)
+	testutils.Equal(t, len(vertexOp.Result()), 1)
+	testutils.Equal(t, cache.Stats().Entries, 2)
+}
+
+func TestExecuteReportsFailedOperations(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeServer{}
+	errCallback := errors.New("callback failed")
+
+	ops := []Operation{
+		NewUpsertVertexOp("person", "1"),
+		NewUpsertVertexOp("person", "bad"),
+		NewUpsertVertexOp("person", "3"),
+		NewCallbackOp(func(*Cache, *gremlingo.GraphTraversalSource) ([]*gremlingo.Result, error) {
+			return nil, errCallback
+		}),
+	}
+
+	result, err := newFakeClient(server, nil).ExecuteWithOptions(context.Background(), nil,
+		ExecuteOptions{BatchSize: 3}, ops...)
+	testutils.Equal(t, errors.Is(err, errCallback), true)
+	testutils.Equal(t, result.Succeeded, 2)
+	testutils.Equal(t, failedIndexes(result), []int{1, 3})
+	testutils.Equal(t, result.Failed[0].Operation, ops[1])
+	testutils.Equal(t, result.Traversals, 5)
+
+	var opErr *OperationError
+	testutils.Equal(t, errors.As(err, &opErr), true)
+	testutils.Equal(t, opErr.Index, 1)
+}
+
+func TestExecuteRetriesConcurrentModification(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeServer{busy: 2}
+	options := ExecuteOptions{BatchSize: 2, RetryDelay: time.Microsecond}
+
+	result, err := newFakeClient(server, nil).ExecuteWithOptions(context.Background(), nil, options,
+		NewUpsertVertexOp("person", "1"),
+		NewUpsertVertexOp("person", "busy"),
+	)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, result, ExecuteResult{Succeeded: 2, Traversals: 3, Retries: 2})
+
+	server.busy = 10
+	options.MaxRetries = -1
+
+	result, err = newFakeClient(server, nil).ExecuteWithOptions(context.Background(), nil, options,
+		NewUpsertVertexOp("person", "busy"),
+	)
+	testutils.Equal(t, errors.Is(err, errConcurrent), true)
+	testutils.Equal(t, coreerrors.CategoryOf(err), coreerrors.CategoryConflict)
+	testutils.Equal(t, result.Retries, 0)
+	testutils.Equal(t, failedIndexes(result), []int{0})
+}
+
+func TestExecuteTransaction(t *testing.T) {
+	t.Parallel()
+
+	tx := &fakeTransaction{}
+	cache := NewCache()
+	options := ExecuteOptions{BatchSize: 2, Transaction: true}
+
+	result, err := newFakeClient(&fakeServer{}, tx).ExecuteWithOptions(context.Background(), cache, options,
+		NewUpsertVertexOp("person", "1"),
+		NewUpsertVertexOp("person", "2"),
+	)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, result.Succeeded, 2)
+	testutils.Equal(t, [3]int{tx.begins, tx.commits, tx.rollbacks}, [3]int{1, 1, 0})
+	testutils.Equal(t, cache.Stats().Entries, 2)
+
+	tx = &fakeTransaction{}
+	cache = NewCache()
+
+	result, err = newFakeClient(&fakeServer{}, tx).ExecuteWithOptions(context.Background(), cache, options,
+		NewUpsertVertexOp("person", "1"),
+		NewUpsertVertexOp("person", "2"),
+		NewUpsertVertexOp("person", "bad"),
+	)
+	testutils.Equal(t, err != nil, true)
+	testutils.Equal(t, result.Succeeded, 0)
+	testutils.Equal(t, failedIndexes(result), []int{2})
+	testutils.Equal(t, [3]int{tx.begins, tx.commits, tx.rollbacks}, [3]int{1, 0, 1})
+	testutils.Equal(t, cache.Stats().Entries, 0)
+}
+
+func TestExecuteTransactionRetriesWhole(t *testing.T) {
+	t.Parallel()
+
+	tx := &fakeTransaction{}
+	options := ExecuteOptions{Transaction: true, RetryDelay: time.Microsecond}
+
+	result, err := newFakeClient(&fakeServer{busy: 1}, tx).ExecuteWithOptions(context.Background(), nil, options,
+		NewUpsertVertexOp("person", "1"),
+		NewUpsertVertexOp("person", "busy"),
+	)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, result, ExecuteResult{Succeeded: 2, Traversals: 4, Retries: 1})
+	testutils.Equal(t, [3]int{tx.begins, tx.commits, tx.rollbacks}, [3]int{2, 1, 1})
+
+	tx = &fakeTransaction{commitErr: errors.New("commit refused")}
+	options.MaxRetries = -1
+
+	result, err = newFakeClient(&fakeServer{}, tx).ExecuteWithOptions(context.Background(), nil, options,
+		NewUpsertVertexOp("person", "1"),
+		NewUpsertVertexOp("person", "2"),
+	)
+	testutils.Equal(t, errors.Is(err, tx.commitErr), true)
+	testutils.Equal(t, result.Succeeded, 0)
+	testutils.Equal(t, failedIndexes(result), []int{0, 1})
+}
+
+func TestExecuteCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	server := &fakeServer{}
+
+	result, err := newFakeClient(server, nil).ExecuteWithOptions(ctx, nil, ExecuteOptions{},
+		NewUpsertVertexOp("person", "1"),
+		NewUpsertVertexOp("person", "2"),
+	)
+	testutils.Equal(t, errors.Is(err, context.Canceled), true)
+	testutils.Equal(t, failedIndexes(result), []int{0, 1})
+	testutils.Equal(t, len(server.scripts), 0)
+}
+
+func TestIsConcurrentModification(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"ConcurrentModificationException: Conflict":                          true,
+		"Failed to complete due to concurrent modification, please retry":    true,
+		"org.janusgraph.diskstorage.locking.TemporaryLockingException: lock": true,
+		"Local lock contention": true,
+		"vertex 'bad' rejected": false,
+		"":                      false,
+	}
+
+	for message, want := range cases {
+		testutils.Equal(t, IsConcurrentModification(errors.New(message)), want)
+	}
+
+	testutils.Equal(t, IsConcurrentModification(nil), false)
+	testutils.Equal(t, classifyError(ErrPathIsNotFound), coreerrors.CategoryNotFound)
+	testutils.Equal(t, classifyError(errConcurrent), coreerrors.CategoryConflict)
+	testutils.Equal(t, classifyError(errors.New("other")), coreerrors.CategoryUnknown)
+}
@@ -0,0 +1,356 @@
+// Package gremlintest provides conformance suites for gremlin.GraphStore and
+// gremlin.GraphTraverser implementations.
+//
+//	func TestGraphStore(t *testing.T) {
+//		gremlintest.RunGraphStore(t, func(t *testing.T) gremlin.GraphStore {
+//			return memgraph.New(memgraph.Options{})
+//		})
+//	}
+//
+// Every subtest writes vertices under labels unique to the run, so the suite can also run
+// against a shared Gremlin server; it does not delete what it writes.
+package gremlintest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/InsideGallery/core/db/gremlin"
+)
+
+// NewStore returns the store a subtest runs against. It may return the same store every
+// time; returned stores are not closed by the suite.
+type NewStore func(t *testing.T) gremlin.GraphStore
+
+// RunGraphStore runs the GraphStore conformance suite as subtests of t.
+func RunGraphStore(t *testing.T, newStore NewStore) {
+	t.Helper()
+
+	cases := []struct {
+		name string
+		run  func(t *testing.T, store gremlin.GraphStore)
+	}{
+		{name: "upsert vertex creates once", run: testUpsertVertexCreatesOnce},
+		{name: "upsert vertex merges properties", run: testUpsertVertexMergesProperties},
+		{name: "count by id", run: testCountByID},
+		{name: "comparisons", run: testComparisons},
+		{name: "string comparisons", run: testStringComparisons},
+		{name: "combined filters", run: testCombinedFilters},
+		{name: "list values", run: testListValues},
+		{name: "upsert edge", run: testUpsertEdge},
+		{name: "upsert edge needs vertices", run: testUpsertEdgeNeedsVertices},
+		{name: "canceled context", run: testCanceledContext},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t, newStore(t))
+		})
+	}
+}
+
+// label returns a vertex or edge label no other run uses.
+func label(name string) string {
+	return name + "_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+func upsertVertices(t *testing.T, store gremlin.GraphStore, vertices ...gremlin.UpsertVertexOptions) {
+	t.Helper()
+
+	for _, vertex := range vertices {
+		result, err := store.UpsertVertex(context.Background(), vertex)
+		if err != nil {
+			t.Fatalf("UpsertVertex(%s/%s) error = %v", vertex.Label, vertex.ID, err)
+		}
+
+		if result.Affected != 1 {
+			t.Fatalf("UpsertVertex(%s/%s).Affected = %d, want 1", vertex.Label, vertex.ID, result.Affected)
+		}
+	}
+}
+
+func count(t *testing.T, store gremlin.GraphStore, options gremlin.CountVerticesOptions) int64 {
+	t.Helper()
+
+	result, err := store.CountVertices(context.Background(), options)
+	if err != nil {
+		t.Fatalf("CountVertices() error = %v", err)
+	}
+
+	return result.Count
+}
+
+func values(t *testing.T, store gremlin.GraphStore, options gremlin.ListValuesOptions) []any {
+	t.Helper()
+
+	result, err := store.ListValues(context.Background(), options)
+	if err != nil {
+		t.Fatalf("ListValues() error = %v", err)
+	}
+
+	return result.Values
+}
+
+// assertValues compares values ignoring order and numeric types, since servers may
+// return a different integer width than the one written.
+func assertValues(t *testing.T, got []any, want ...any) {
+	t.Helper()
+
+	normalize := func(values []any) []string {
+		out := make([]string, len(values))
+
+		for i, value := range values {
+			v := reflect.ValueOf(value)
+
+			switch {
+			case v.CanInt():
+				out[i] = fmt.Sprintf("%g", float64(v.Int()))
+			case v.CanUint():
+				out[i] = fmt.Sprintf("%g", float64(v.Uint()))
+			case v.CanFloat():
+				out[i] = fmt.Sprintf("%g", v.Float())
+			default:
+				out[i] = fmt.Sprintf("%v", value)
+			}
+		}
+
+		sort.Strings(out)
+
+		return out
+	}
+
+	gotValues, wantValues := normalize(got), normalize(want)
+	if strings.Join(gotValues, "\x00") != strings.Join(wantValues, "\x00") {
+		t.Fatalf("values = %v, want %v", gotValues, wantValues)
+	}
+}
+
+func testUpsertVertexCreatesOnce(t *testing.T, store gremlin.GraphStore) {
+	person := label("person")
+
+	upsertVertices(t, store,
+		gremlin.UpsertVertexOptions{Label: person, ID: "1", Properties: map[string]any{"name": "Ada"}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "1", Properties: map[string]any{"name": "Ada"}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "2"},
+	)
+
+	if got := count(t, store, gremlin.CountVerticesOptions{Label: person}); got != 2 {
+		t.Fatalf("count = %d, want 2", got)
+	}
+
+	if got := count(t, store, gremlin.CountVerticesOptions{Label: label("missing")}); got != 0 {
+		t.Fatalf("count of missing label = %d, want 0", got)
+	}
+}
+
+func testUpsertVertexMergesProperties(t *testing.T, store gremlin.GraphStore) {
+	person := label("person")
+
+	upsertVertices(t, store,
+		gremlin.UpsertVertexOptions{Label: person, ID: "1", Properties: map[string]any{"name": "Ada", "age": 36}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "1", Properties: map[string]any{"age": 37, "city": "London"}},
+	)
+
+	assertValues(t, values(t, store, gremlin.ListValuesOptions{Label: person, Property: "name"}), "Ada")
+	assertValues(t, values(t, store, gremlin.ListValuesOptions{Label: person, Property: "age"}), 37)
+	assertValues(t, values(t, store, gremlin.ListValuesOptions{Label: person, Property: "city"}), "London")
+}
+
+func testCountByID(t *testing.T, store gremlin.GraphStore) {
+	person := label("person")
+
+	upsertVertices(t, store,
+		gremlin.UpsertVertexOptions{Label: person, ID: "1"},
+		gremlin.UpsertVertexOptions{Label: person, ID: "2"},
+	)
+
+	if got := count(t, store, gremlin.CountVerticesOptions{Label: person, ID: "2"}); got != 1 {
+		t.Fatalf("count by id = %d, want 1", got)
+	}
+
+	if got := count(t, store, gremlin.CountVerticesOptions{Label: person, ID: "3"}); got != 0 {
+		t.Fatalf("count by missing id = %d, want 0", got)
+	}
+}
+
+func testComparisons(t *testing.T, store gremlin.GraphStore) {
+	person := label("person")
+
+	upsertVertices(t, store,
+		gremlin.UpsertVertexOptions{Label: person, ID: "1", Properties: map[string]any{"age": 10}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "2", Properties: map[string]any{"age": 20}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "3", Properties: map[string]any{"age": 30}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "4"},
+	)
+
+	cases := []struct {
+		comparison gremlin.Comparison
+		value      any
+		want       int64
+	}{
+		{comparison: gremlin.ComparisonEqual, value: 20, want: 1},
+		{comparison: gremlin.ComparisonGreaterThan, value: 20, want: 1},
+		{comparison: gremlin.ComparisonGreaterThanOrEqual, value: 20, want: 2},
+		{comparison: gremlin.ComparisonLessThan, value: 20, want: 1},
+		{comparison: gremlin.ComparisonLessThanOrEqual, value: 20, want: 2},
+		{comparison: gremlin.ComparisonGreaterThan, value: 30, want: 0},
+		{comparison: gremlin.ComparisonLessThan, value: 10.5, want: 1},
+		{comparison: gremlin.ComparisonEqual, value: 25, want: 0},
+	}
+
+	for _, test := range cases {
+		got := count(t, store, gremlin.CountVerticesOptions{
+			Label:   person,
+			Filters: []gremlin.PropertyFilter{{Name: "age", Comparison: test.comparison, Value: test.value}},
+		})
+		if got != test.want {
+			t.Fatalf("count(age %s %v) = %d, want %d", test.comparison, test.value, got, test.want)
+		}
+	}
+}
+
+func testStringComparisons(t *testing.T, store gremlin.GraphStore) {
+	person := label("person")
+
+	upsertVertices(t, store,
+		gremlin.UpsertVertexOptions{Label: person, ID: "1", Properties: map[string]any{"name": "ada"}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "2", Properties: map[string]any{"name": "bob"}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "3", Properties: map[string]any{"name": "cid"}},
+	)
+
+	cases := []struct {
+		comparison gremlin.Comparison
+		value      string
+		want       int64
+	}{
+		{comparison: gremlin.ComparisonEqual, value: "bob", want: 1},
+		{comparison: gremlin.ComparisonGreaterThan, value: "b", want: 2},
+		{comparison: gremlin.ComparisonLessThanOrEqual, value: "bob", want: 2},
+	}
+
+	for _, test := range cases {
+		got := count(t, store, gremlin.CountVerticesOptions{
+			Label:   person,
+			Filters: []gremlin.PropertyFilter{{Name: "name", Comparison: test.comparison, Value: test.value}},
+		})
+		if got != test.want {
+			t.Fatalf("count(name %s %q) = %d, want %d", test.comparison, test.value, got, test.want)
+		}
+	}
+}
+
+func testCombinedFilters(t *testing.T, store gremlin.GraphStore) {
+	person := label("person")
+
+	upsertVertices(t, store,
+		gremlin.UpsertVertexOptions{Label: person, ID: "1", Properties: map[string]any{"age": 10, "team": "a"}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "2", Properties: map[string]any{"age": 20, "team": "a"}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "3", Properties: map[string]any{"age": 30, "team": "b"}},
+	)
+
+	got := count(t, store, gremlin.CountVerticesOptions{
+		Label: person,
+		Filters: []gremlin.PropertyFilter{
+			{Name: "age", Comparison: gremlin.ComparisonGreaterThanOrEqual, Value: 10},
+			{Name: "age", Comparison: gremlin.ComparisonLessThan, Value: 30},
+			{Name: "team", Comparison: gremlin.ComparisonEqual, Value: "a"},
+		},
+	})
+	if got != 2 {
+		t.Fatalf("count = %d, want 2", got)
+	}
+
+	got = count(t, store, gremlin.CountVerticesOptions{
+		Label:   person,
+		Filters: []gremlin.PropertyFilter{{Name: "missing", Comparison: gremlin.ComparisonEqual, Value: 1}},
+	})
+	if got != 0 {
+		t.Fatalf("count on missing property = %d, want 0", got)
+	}
+}
+
+func testListValues(t *testing.T, store gremlin.GraphStore) {
+	person := label("person")
+
+	upsertVertices(t, store,
+		gremlin.UpsertVertexOptions{Label: person, ID: "1", Properties: map[string]any{"name": "Ada", "age": 36}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "2", Properties: map[string]any{"name": "Bob", "age": 20}},
+		gremlin.UpsertVertexOptions{Label: person, ID: "3", Properties: map[string]any{"age": 50}},
+	)
+
+	assertValues(t, values(t, store, gremlin.ListValuesOptions{Label: person, Property: "name"}), "Ada", "Bob")
+	assertValues(t, values(t, store, gremlin.ListValuesOptions{
+		Label:    person,
+		Property: "name",
+		Filters:  []gremlin.PropertyFilter{{Name: "age", Comparison: gremlin.ComparisonGreaterThan, Value: 30}},
+	}), "Ada")
+	assertValues(t, values(t, store, gremlin.ListValuesOptions{Label: person, ID: "2", Property: "age"}), 20)
+	assertValues(t, values(t, store, gremlin.ListValuesOptions{Label: label("missing"), Property: "name"}))
+}
+
+func testUpsertEdge(t *testing.T, store gremlin.GraphStore) {
+	person, knows := label("person"), label("knows")
+
+	upsertVertices(t, store,
+		gremlin.UpsertVertexOptions{Label: person, ID: "1"},
+		gremlin.UpsertVertexOptions{Label: person, ID: "2"},
+	)
+
+	edge := gremlin.UpsertEdgeOptions{
+		Label:      knows,
+		ID:         "1-2",
+		From:       gremlin.VertexRef{Label: person, ID: "1"},
+		To:         gremlin.VertexRef{Label: person, ID: "2"},
+		Properties: map[string]any{"since": 2020},
+	}
+
+	for range 2 {
+		result, err := store.UpsertEdge(context.Background(), edge)
+		if err != nil {
+			t.Fatalf("UpsertEdge() error = %v", err)
+		}
+
+		if result.Affected != 1 {
+			t.Fatalf("UpsertEdge().Affected = %d, want 1", result.Affected)
+		}
+	}
+
+	if got := count(t, store, gremlin.CountVerticesOptions{Label: person}); got != 2 {
+		t.Fatalf("vertex count after edge upserts = %d, want 2", got)
+	}
+}
+
+func testUpsertEdgeNeedsVertices(t *testing.T, store gremlin.GraphStore) {
+	person := label("person")
+
+	upsertVertices(t, store, gremlin.UpsertVertexOptions{Label: person, ID: "1"})
+
+	_, err := store.UpsertEdge(context.Background(), gremlin.UpsertEdgeOptions{
+		Label: label("knows"),
+		ID:    "1-404",
+		From:  gremlin.VertexRef{Label: person, ID: "1"},
+		To:    gremlin.VertexRef{Label: person, ID: "404"},
+	})
+	if err == nil {
+		t.Fatal("UpsertEdge() to a missing vertex error = nil, want an error")
+	}
+}
+
+func testCanceledContext(t *testing.T, store gremlin.GraphStore) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := store.UpsertVertex(ctx, gremlin.UpsertVertexOptions{Label: label("person"), ID: "1"}); err == nil {
+		t.Fatal("UpsertVertex() with a canceled context error = nil, want an error")
+	}
+
+	if _, err := store.CountVertices(ctx, gremlin.CountVerticesOptions{}); err == nil {
+		t.Fatal("CountVertices() with a canceled context error = nil, want an error")
+	}
+}
@@ -0,0 +1,529 @@
+package gremlintest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/InsideGallery/core/db/gremlin"
+)
+
+// TraversableStore is a GraphStore that also implements gremlin.GraphTraverser.
+type TraversableStore interface {
+	gremlin.GraphStore
+	gremlin.GraphTraverser
+}
+
+// NewTraversableStore returns the store a traverser subtest runs against. It may return the
+// same store every time; returned stores are not closed by the suite.
+type NewTraversableStore func(t *testing.T) TraversableStore
+
+// RunGraphTraverser runs the GraphTraverser conformance suite as subtests of t. Every subtest
+// writes this graph under its own labels, with edges labeled knows unless noted:
+//
+//	1 -> 2 -> 3 -> 4
+//	1 -> 5 -> 4
+//	1 -likes-> 3
+//	6
+func RunGraphTraverser(t *testing.T, newStore NewTraversableStore) {
+	t.Helper()
+
+	cases := []struct {
+		name string
+		run  func(t *testing.T, graph *fixture)
+	}{
+		{name: "neighbors", run: testNeighbors},
+		{name: "neighbors page", run: testNeighborsPage},
+		{name: "shortest path", run: testShortestPath},
+		{name: "shortest path not found", run: testShortestPathNotFound},
+		{name: "all paths", run: testAllPaths},
+		{name: "subgraph", run: testSubgraph},
+		{name: "degree", run: testDegree},
+		{name: "list vertices", run: testListVertices},
+		{name: "invalid options", run: testInvalidTraversals},
+		{name: "canceled context", run: testCanceledTraversal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t, newFixture(t, newStore(t)))
+		})
+	}
+}
+
+// fixture is the suite graph written to a store.
+type fixture struct {
+	store TraversableStore
+	// person, knows and likes are the labels of this run.
+	person, knows, likes string
+}
+
+func newFixture(t *testing.T, store TraversableStore) *fixture {
+	t.Helper()
+
+	graph := &fixture{store: store, person: label("person"), knows: label("knows"), likes: label("likes")}
+
+	ages := map[string]any{"1": 30, "2": 20, "3": 40, "4": 50, "5": 20}
+	for _, n := range []string{"1", "2", "3", "4", "5", "6"} {
+		vertex := gremlin.UpsertVertexOptions{Label: graph.person, ID: graph.id(n)}
+		if age, ok := ages[n]; ok {
+			vertex.Properties = map[string]any{"age": age}
+		}
+
+		upsertVertices(t, store, vertex)
+	}
+
+	for _, edge := range [][3]string{
+		{graph.knows, "1", "2"},
+		{graph.knows, "2", "3"},
+		{graph.knows, "3", "4"},
+		{graph.knows, "1", "5"},
+		{graph.knows, "5", "4"},
+		{graph.likes, "1", "3"},
+	} {
+		_, err := store.UpsertEdge(context.Background(), gremlin.UpsertEdgeOptions{
+			Label: edge[0],
+			ID:    graph.id(edge[1] + "-" + edge[2]),
+			From:  graph.ref(edge[1]),
+			To:    graph.ref(edge[2]),
+		})
+		if err != nil {
+			t.Fatalf("UpsertEdge(%s %s-%s) error = %v", edge[0], edge[1], edge[2], err)
+		}
+	}
+
+	return graph
+}
+
+// id returns the id of vertex or edge n, unique to the run so that stores keying on the
+// id alone, like Neptune, can be shared.
+func (f *fixture) id(n string) string {
+	return f.person + ":" + n
+}
+
+func (f *fixture) ref(n string) gremlin.VertexRef {
+	return gremlin.VertexRef{Label: f.person, ID: f.id(n)}
+}
+
+// names returns the suite names of vertices, in order.
+func (f *fixture) names(vertices []gremlin.Vertex) []string {
+	names := make([]string, 0, len(vertices))
+	for _, vertex := range vertices {
+		names = append(names, strings.TrimPrefix(vertex.ID, f.person+":"))
+	}
+
+	return names
+}
+
+func (f *fixture) sortedNames(vertices []gremlin.Vertex) []string {
+	names := f.names(vertices)
+	slices.Sort(names)
+
+	return names
+}
+
+func (f *fixture) edgeNames(edges []gremlin.Edge) []string {
+	names := make([]string, 0, len(edges))
+	for _, edge := range edges {
+		names = append(names, strings.TrimPrefix(edge.ID, f.person+":"))
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+func assertNames(t *testing.T, what string, got []string, want ...string) {
+	t.Helper()
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("%s = %v, want %v", what, got, want)
+	}
+}
+
+func testNeighbors(t *testing.T, graph *fixture) {
+	cases := []struct {
+		name    string
+		options gremlin.NeighborsOptions
+		want    []string
+	}{
+		{
+			name:    "one hop",
+			options: gremlin.NeighborsOptions{From: graph.ref("1"), EdgeLabels: []string{graph.knows}},
+			want:    []string{"2", "5"},
+		},
+		{
+			name:    "two hops",
+			options: gremlin.NeighborsOptions{From: graph.ref("1"), EdgeLabels: []string{graph.knows}, Hops: 2},
+			want:    []string{"2", "3", "4", "5"},
+		},
+		{
+			name:    "every edge label",
+			options: gremlin.NeighborsOptions{From: graph.ref("1")},
+			want:    []string{"2", "3", "5"},
+		},
+		{
+			name:    "incoming",
+			options: gremlin.NeighborsOptions{From: graph.ref("4"), Direction: gremlin.DirectionIn},
+			want:    []string{"3", "5"},
+		},
+		{
+			name: "both directions",
+			options: gremlin.NeighborsOptions{
+				From: graph.ref("3"), Direction: gremlin.DirectionBoth, EdgeLabels: []string{graph.knows},
+			},
+			want: []string{"2", "4"},
+		},
+		{
+			name: "cycle back to start",
+			options: gremlin.NeighborsOptions{
+				From: graph.ref("2"), Direction: gremlin.DirectionBoth, EdgeLabels: []string{graph.knows}, Hops: 2,
+			},
+			want: []string{"1", "3", "4", "5"},
+		},
+		{
+			name: "filters",
+			options: gremlin.NeighborsOptions{
+				From:    graph.ref("1"),
+				Hops:    2,
+				Label:   graph.person,
+				Filters: []gremlin.PropertyFilter{{Name: "age", Comparison: gremlin.ComparisonGreaterThanOrEqual, Value: 40}},
+			},
+			want: []string{"3", "4"},
+		},
+		{
+			name:    "missing vertex",
+			options: gremlin.NeighborsOptions{From: graph.ref("404")},
+		},
+	}
+
+	for _, test := range cases {
+		result, err := graph.store.Neighbors(context.Background(), test.options)
+		if err != nil {
+			t.Fatalf("%s: Neighbors() error = %v", test.name, err)
+		}
+
+		assertNames(t, test.name+": neighbors", graph.names(result.Vertices), test.want...)
+	}
+
+	result, err := graph.store.Neighbors(context.Background(), gremlin.NeighborsOptions{From: graph.ref("5")})
+	if err != nil {
+		t.Fatalf("Neighbors() error = %v", err)
+	}
+
+	if len(result.Vertices) != 1 || result.Vertices[0].Label != graph.person {
+		t.Fatalf("Neighbors() = %+v, want vertex 4 labeled %s", result.Vertices, graph.person)
+	}
+
+	assertValues(t, []any{result.Vertices[0].Properties["age"]}, 50)
+}
+
+func testNeighborsPage(t *testing.T, graph *fixture) {
+	cases := []struct {
+		page gremlin.Page
+		want []string
+	}{
+		{page: gremlin.Page{Limit: 2}, want: []string{"2", "3"}},
+		{page: gremlin.Page{Offset: 1, Limit: 2}, want: []string{"3", "4"}},
+		{page: gremlin.Page{Offset: 3}, want: []string{"5"}},
+		{page: gremlin.Page{Offset: 4, Limit: 2}},
+	}
+
+	for _, test := range cases {
+		result, err := graph.store.Neighbors(context.Background(), gremlin.NeighborsOptions{
+			From: graph.ref("1"), EdgeLabels: []string{graph.knows}, Hops: 2, Page: test.page,
+		})
+		if err != nil {
+			t.Fatalf("Neighbors(%+v) error = %v", test.page, err)
+		}
+
+		assertNames(t, "page", graph.names(result.Vertices), test.want...)
+	}
+}
+
+func testShortestPath(t *testing.T, graph *fixture) {
+	result, err := graph.store.ShortestPath(context.Background(), gremlin.PathsOptions{
+		From: graph.ref("1"), To: graph.ref("4"), EdgeLabels: []string{graph.knows},
+	})
+	if err != nil {
+		t.Fatalf("ShortestPath() error = %v", err)
+	}
+
+	assertNames(t, "path", graph.names(result.Path.Vertices), "1", "5", "4")
+	assertNames(t, "path edges", graph.edgeNames(result.Path.Edges), "1-5", "5-4")
+
+	result, err = graph.store.ShortestPath(context.Background(), gremlin.PathsOptions{
+		From: graph.ref("1"), To: graph.ref("3"),
+	})
+	if err != nil {
+		t.Fatalf("ShortestPath() over every edge label error = %v", err)
+	}
+
+	assertNames(t, "path over every edge label", graph.names(result.Path.Vertices), "1", "3")
+
+	if edge := result.Path.Edges[0]; edge.Label != graph.likes {
+		t.Fatalf("edge label = %s, want %s", edge.Label, graph.likes)
+	}
+
+	result, err = graph.store.ShortestPath(context.Background(), gremlin.PathsOptions{
+		From: graph.ref("4"), To: graph.ref("1"), Direction: gremlin.DirectionIn, EdgeLabels: []string{graph.knows},
+	})
+	if err != nil {
+		t.Fatalf("ShortestPath() over incoming edges error = %v", err)
+	}
+
+	assertNames(t, "incoming path", graph.names(result.Path.Vertices), "4", "5", "1")
+
+	if edge := result.Path.Edges[0]; edge.From != graph.ref("5") || edge.To != graph.ref("4") {
+		t.Fatalf("incoming path edge = %+v, want the stored direction 5 -> 4", edge)
+	}
+}
+
+func testShortestPathNotFound(t *testing.T, graph *fixture) {
+	for _, options := range []gremlin.PathsOptions{
+		{From: graph.ref("4"), To: graph.ref("1")},
+		{From: graph.ref("1"), To: graph.ref("6"), Direction: gremlin.DirectionBoth},
+		{From: graph.ref("1"), To: graph.ref("4"), EdgeLabels: []string{graph.knows}, MaxDepth: 1},
+	} {
+		_, err := graph.store.ShortestPath(context.Background(), options)
+		if !errors.Is(err, gremlin.ErrPathIsNotFound) {
+			t.Fatalf("ShortestPath(%+v) error = %v, want %v", options, err, gremlin.ErrPathIsNotFound)
+		}
+	}
+}
+
+func testAllPaths(t *testing.T, graph *fixture) {
+	cases := []struct {
+		name    string
+		options gremlin.PathsOptions
+		want    [][]string
+	}{
+		{
+			name:    "every path",
+			options: gremlin.PathsOptions{From: graph.ref("1"), To: graph.ref("4"), EdgeLabels: []string{graph.knows}},
+			want:    [][]string{{"1", "5", "4"}, {"1", "2", "3", "4"}},
+		},
+		{
+			name: "max depth",
+			options: gremlin.PathsOptions{
+				From: graph.ref("1"), To: graph.ref("4"), EdgeLabels: []string{graph.knows}, MaxDepth: 2,
+			},
+			want: [][]string{{"1", "5", "4"}},
+		},
+		{
+			name: "limit",
+			options: gremlin.PathsOptions{
+				From: graph.ref("1"), To: graph.ref("4"), EdgeLabels: []string{graph.knows}, Limit: 1,
+			},
+			want: [][]string{{"1", "5", "4"}},
+		},
+		{
+			name:    "every edge label",
+			options: gremlin.PathsOptions{From: graph.ref("1"), To: graph.ref("4")},
+			want:    [][]string{{"1", "5", "4"}, {"1", "3", "4"}, {"1", "2", "3", "4"}},
+		},
+		{
+			name:    "no path",
+			options: gremlin.PathsOptions{From: graph.ref("4"), To: graph.ref("1")},
+		},
+	}
+
+	for _, test := range cases {
+		result, err := graph.store.AllPaths(context.Background(), test.options)
+		if err != nil {
+			t.Fatalf("%s: AllPaths() error = %v", test.name, err)
+		}
+
+		var got [][]string
+
+		for i, path := range result.Paths {
+			if len(path.Edges) != len(path.Vertices)-1 {
+				t.Fatalf("%s: path %d has %d vertices and %d edges", test.name, i, len(path.Vertices), len(path.Edges))
+			}
+
+			if i > 0 && len(path.Edges) < len(result.Paths[i-1].Edges) {
+				t.Fatalf("%s: path %d is shorter than the path before it", test.name, i)
+			}
+
+			got = append(got, graph.names(path.Vertices))
+		}
+
+		// Paths of the same length may come in any order.
+		sortPaths := func(paths [][]string) {
+			slices.SortStableFunc(paths, func(a, b []string) int {
+				if len(a) != len(b) {
+					return len(a) - len(b)
+				}
+
+				return strings.Compare(strings.Join(a, ","), strings.Join(b, ","))
+			})
+		}
+
+		sortPaths(got)
+		sortPaths(test.want)
+
+		if !slices.EqualFunc(got, test.want, slices.Equal[[]string]) {
+			t.Fatalf("%s: paths = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func testSubgraph(t *testing.T, graph *fixture) {
+	cases := []struct {
+		name      string
+		options   gremlin.SubgraphOptions
+		vertices  []string
+		edgeNames []string
+	}{
+		{
+			name:      "one hop",
+			options:   gremlin.SubgraphOptions{From: graph.ref("1"), EdgeLabels: []string{graph.knows}},
+			vertices:  []string{"1", "2", "5"},
+			edgeNames: []string{"1-2", "1-5"},
+		},
+		{
+			name:      "edges between reached vertices",
+			options:   gremlin.SubgraphOptions{From: graph.ref("1")},
+			vertices:  []string{"1", "2", "3", "5"},
+			edgeNames: []string{"1-2", "1-3", "1-5", "2-3"},
+		},
+		{
+			name: "incoming",
+			options: gremlin.SubgraphOptions{
+				From: graph.ref("4"), Direction: gremlin.DirectionIn, EdgeLabels: []string{graph.knows}, Hops: 2,
+			},
+			vertices:  []string{"1", "2", "3", "4", "5"},
+			edgeNames: []string{"1-2", "1-5", "2-3", "3-4", "5-4"},
+		},
+		{
+			name:     "isolated vertex",
+			options:  gremlin.SubgraphOptions{From: graph.ref("6"), Direction: gremlin.DirectionBoth},
+			vertices: []string{"6"},
+		},
+	}
+
+	for _, test := range cases {
+		result, err := graph.store.Subgraph(context.Background(), test.options)
+		if err != nil {
+			t.Fatalf("%s: Subgraph() error = %v", test.name, err)
+		}
+
+		assertNames(t, test.name+": vertices", graph.sortedNames(result.Vertices), test.vertices...)
+		assertNames(t, test.name+": edges", graph.edgeNames(result.Edges), test.edgeNames...)
+	}
+}
+
+func testDegree(t *testing.T, graph *fixture) {
+	cases := []struct {
+		name    string
+		options gremlin.DegreeOptions
+		want    int64
+	}{
+		{name: "out", options: gremlin.DegreeOptions{Vertex: graph.ref("1")}, want: 3},
+		{
+			name:    "out by label",
+			options: gremlin.DegreeOptions{Vertex: graph.ref("1"), EdgeLabels: []string{graph.knows}},
+			want:    2,
+		},
+		{name: "in", options: gremlin.DegreeOptions{Vertex: graph.ref("4"), Direction: gremlin.DirectionIn}, want: 2},
+		{name: "both", options: gremlin.DegreeOptions{Vertex: graph.ref("3"), Direction: gremlin.DirectionBoth}, want: 3},
+		{name: "isolated", options: gremlin.DegreeOptions{Vertex: graph.ref("6"), Direction: gremlin.DirectionBoth}},
+		{name: "missing", options: gremlin.DegreeOptions{Vertex: graph.ref("404")}},
+	}
+
+	for _, test := range cases {
+		result, err := graph.store.Degree(context.Background(), test.options)
+		if err != nil {
+			t.Fatalf("%s: Degree() error = %v", test.name, err)
+		}
+
+		if result.Count != test.want {
+			t.Fatalf("%s: Degree() = %d, want %d", test.name, result.Count, test.want)
+		}
+	}
+}
+
+func testListVertices(t *testing.T, graph *fixture) {
+	cases := []struct {
+		name    string
+		options gremlin.ListVerticesOptions
+		want    []string
+	}{
+		{
+			name:    "ordered by id",
+			options: gremlin.ListVerticesOptions{Label: graph.person},
+			want:    []string{"1", "2", "3", "4", "5", "6"},
+		},
+		{
+			name:    "ordered by property",
+			options: gremlin.ListVerticesOptions{Label: graph.person, OrderBy: "age", Descending: true},
+			want:    []string{"4", "3", "1", "2", "5"},
+		},
+		{
+			name: "page",
+			options: gremlin.ListVerticesOptions{
+				Label: graph.person, OrderBy: "age", Page: gremlin.Page{Offset: 1, Limit: 3},
+			},
+			want: []string{"5", "1", "3"},
+		},
+		{
+			name: "filters",
+			options: gremlin.ListVerticesOptions{
+				Label:   graph.person,
+				Filters: []gremlin.PropertyFilter{{Name: "age", Comparison: gremlin.ComparisonLessThan, Value: 25}},
+			},
+			want: []string{"2", "5"},
+		},
+		{
+			name:    "by id",
+			options: gremlin.ListVerticesOptions{Label: graph.person, ID: graph.id("3")},
+			want:    []string{"3"},
+		},
+	}
+
+	for _, test := range cases {
+		result, err := graph.store.ListVertices(context.Background(), test.options)
+		if err != nil {
+			t.Fatalf("%s: ListVertices() error = %v", test.name, err)
+		}
+
+		assertNames(t, test.name, graph.names(result.Vertices), test.want...)
+	}
+}
+
+func testInvalidTraversals(t *testing.T, graph *fixture) {
+	ctx := context.Background()
+
+	if _, err := graph.store.Neighbors(ctx, gremlin.NeighborsOptions{}); err == nil {
+		t.Fatal("Neighbors() without a start vertex error = nil, want an error")
+	}
+
+	if _, err := graph.store.AllPaths(ctx, gremlin.PathsOptions{From: graph.ref("1")}); err == nil {
+		t.Fatal("AllPaths() without an end vertex error = nil, want an error")
+	}
+
+	_, err := graph.store.Degree(ctx, gremlin.DegreeOptions{Vertex: graph.ref("1"), Direction: "sideways"})
+	if !errors.Is(err, gremlin.ErrUnsupportedDirection) {
+		t.Fatalf("Degree() error = %v, want %v", err, gremlin.ErrUnsupportedDirection)
+	}
+
+	_, err = graph.store.Subgraph(ctx, gremlin.SubgraphOptions{From: graph.ref("1"), Direction: "sideways"})
+	if !errors.Is(err, gremlin.ErrUnsupportedDirection) {
+		t.Fatalf("Subgraph() error = %v, want %v", err, gremlin.ErrUnsupportedDirection)
+	}
+}
+
+func testCanceledTraversal(t *testing.T, graph *fixture) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := graph.store.Neighbors(ctx, gremlin.NeighborsOptions{From: graph.ref("1")}); err == nil {
+		t.Fatal("Neighbors() with a canceled context error = nil, want an error")
+	}
+
+	if _, err := graph.store.ListVertices(ctx, gremlin.ListVerticesOptions{}); err == nil {
+		t.Fatal("ListVertices() with a canceled context error = nil, want an error")
+	}
+}
@@ -0,0 +1,102 @@
+package memgraph
+
+import (
+	"cmp"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/InsideGallery/core/db/gremlin"
+)
+
+// Compare reports whether value satisfies comparison against operand the way Gremlin
+// predicates do: numbers compare by value across types, strings lexicographically and
+// times chronologically. Ordering comparisons of other or mixed types never match, and an
+// unknown comparison is treated as gremlin.ComparisonEqual, like Client.CountVertices.
+func Compare(comparison gremlin.Comparison, value, operand any) bool {
+	switch comparison {
+	case gremlin.ComparisonGreaterThan:
+		order, ok := compare(value, operand)
+
+		return ok && order > 0
+	case gremlin.ComparisonGreaterThanOrEqual:
+		order, ok := compare(value, operand)
+
+		return ok && order >= 0
+	case gremlin.ComparisonLessThan:
+		order, ok := compare(value, operand)
+
+		return ok && order < 0
+	case gremlin.ComparisonLessThanOrEqual:
+		order, ok := compare(value, operand)
+
+		return ok && order <= 0
+	default:
+		if order, ok := compare(value, operand); ok {
+			return order == 0
+		}
+
+		return reflect.DeepEqual(value, operand)
+	}
+}
+
+// compare orders two values of comparable kinds; ok is false otherwise.
+func compare(a, b any) (order int, ok bool) {
+	if x, isNumber := number(a); isNumber {
+		y, isNumber := number(b)
+		if !isNumber {
+			return 0, false
+		}
+
+		if x.integer && y.integer {
+			return cmp.Compare(x.i, y.i), true
+		}
+
+		return cmp.Compare(x.f, y.f), true
+	}
+
+	switch x := a.(type) {
+	case string:
+		y, isString := b.(string)
+		if !isString {
+			return 0, false
+		}
+
+		return strings.Compare(x, y), true
+	case time.Time:
+		y, isTime := b.(time.Time)
+		if !isTime {
+			return 0, false
+		}
+
+		return x.Compare(y), true
+	default:
+		return 0, false
+	}
+}
+
+type numeric struct {
+	integer bool
+	i       int64
+	f       float64
+}
+
+func number(value any) (numeric, bool) {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return numeric{integer: true, i: v.Int(), f: float64(v.Int())}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := v.Uint()
+		if u > 1<<63-1 {
+			return numeric{f: float64(u)}, true
+		}
+
+		return numeric{integer: true, i: int64(u), f: float64(u)}, true
+	case reflect.Float32, reflect.Float64:
+		return numeric{f: v.Float()}, true
+	default:
+		return numeric{}, false
+	}
+}
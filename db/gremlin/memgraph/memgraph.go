@@ -0,0 +1,300 @@
+// Package memgraph is an in-process graph implementing gremlin.GraphStore and
+// gremlin.GraphTraverser, for unit tests and local development without a Gremlin server.
+//
+//	import "github.com/InsideGallery/core/db/gremlin/memgraph"
+//
+//	var store gremlin.GraphStore = memgraph.New(memgraph.Options{})
+//
+// Upserts follow the semantics of gremlin.MergeV and gremlin.MergeE for the configured
+// syntax, and property filters honor every gremlin.Comparison. The graph is safe for
+// concurrent use.
+package memgraph
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+
+	"github.com/InsideGallery/core/db/gremlin"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Graph errors.
+var (
+	ErrVertexIsNotFound = errors.New("vertex is not found")
+	ErrLabelIsNotSet    = errors.New("label is not set")
+	ErrIDIsNotSet       = gremlin.ErrIDIsNotSet
+	ErrGraphIsClosed    = errors.New("graph is closed")
+)
+
+func init() {
+	coreerrors.RegisterClassifier(coreerrors.CategoryFor(ErrVertexIsNotFound, coreerrors.CategoryNotFound))
+}
+
+// Options configures a Graph.
+type Options struct {
+	// Syntax selects the upsert semantics, gremlin.SyntaxAerospike (default) or
+	// gremlin.SyntaxNeptun. Aerospike merges edges on label and endpoints and stores the
+	// edge id as a property; Neptune also merges on the edge id.
+	Syntax string
+}
+
+// Vertex is a vertex of a Graph. Properties include gremlin.DefaultPropertyID.
+type Vertex struct {
+	Label      string
+	ID         string
+	Properties map[string]any
+}
+
+// Edge is an edge of a Graph.
+type Edge struct {
+	Label      string
+	ID         string
+	From       gremlin.VertexRef
+	To         gremlin.VertexRef
+	Properties map[string]any
+}
+
+// Graph is an in-process graph store.
+type Graph struct {
+	syntax string
+
+	mu       sync.RWMutex
+	vertices []*Vertex
+	byRef    map[gremlin.VertexRef]*Vertex
+	edges    []*Edge
+	closed   bool
+}
+
+var _ gremlin.GraphStore = (*Graph)(nil)
+
+// New returns an empty Graph.
+func New(options Options) *Graph {
+	return &Graph{
+		syntax: gremlin.NewSyntaxState(options.Syntax).Syntax,
+		byRef:  map[gremlin.VertexRef]*Vertex{},
+	}
+}
+
+// UpsertVertex creates the vertex with options.Label and options.ID, or sets the given
+// properties on it when it exists; other properties are kept.
+func (g *Graph) UpsertVertex(ctx context.Context, options gremlin.UpsertVertexOptions) (gremlin.GraphResult, error) {
+	if err := g.check(ctx, "upsert vertex"); err != nil {
+		return gremlin.GraphResult{}, err
+	}
+
+	if options.Label == "" {
+		return gremlin.GraphResult{}, ErrLabelIsNotSet
+	}
+
+	if options.ID == "" {
+		return gremlin.GraphResult{}, ErrIDIsNotSet
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ref := gremlin.VertexRef{Label: options.Label, ID: options.ID}
+
+	vertex, ok := g.byRef[ref]
+	if !ok {
+		vertex = &Vertex{Label: options.Label, ID: options.ID, Properties: map[string]any{}}
+		g.vertices = append(g.vertices, vertex)
+		g.byRef[ref] = vertex
+	}
+
+	maps.Copy(vertex.Properties, options.Properties)
+	vertex.Properties[gremlin.DefaultPropertyID] = options.ID
+
+	return gremlin.GraphResult{Affected: 1}, nil
+}
+
+// UpsertEdge creates the edge between two existing vertices, or sets the given properties
+// on the edge it merges with; other properties are kept. Missing vertices return
+// ErrVertexIsNotFound.
+func (g *Graph) UpsertEdge(ctx context.Context, options gremlin.UpsertEdgeOptions) (gremlin.GraphResult, error) {
+	if err := g.check(ctx, "upsert edge"); err != nil {
+		return gremlin.GraphResult{}, err
+	}
+
+	if options.Label == "" {
+		return gremlin.GraphResult{}, ErrLabelIsNotSet
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, ref := range []gremlin.VertexRef{options.From, options.To} {
+		if _, ok := g.byRef[ref]; !ok {
+			return gremlin.GraphResult{}, coreerrors.WrapBoundary("memgraph", "upsert edge", ErrVertexIsNotFound)
+		}
+	}
+
+	var edge *Edge
+
+	for _, candidate := range g.edges {
+		if candidate.Label == options.Label && candidate.From == options.From && candidate.To == options.To &&
+			(g.syntax != gremlin.SyntaxNeptun || candidate.ID == options.ID) {
+			edge = candidate
+
+			break
+		}
+	}
+
+	if edge == nil {
+		edge = &Edge{Label: options.Label, From: options.From, To: options.To, Properties: map[string]any{}}
+		g.edges = append(g.edges, edge)
+	}
+
+	edge.ID = options.ID
+	maps.Copy(edge.Properties, options.Properties)
+
+	if g.syntax == gremlin.SyntaxAerospike {
+		edge.Properties[gremlin.DefaultPropertyID] = options.ID
+	}
+
+	return gremlin.GraphResult{Affected: 1}, nil
+}
+
+// CountVertices counts the vertices matching options.
+func (g *Graph) CountVertices(ctx context.Context, options gremlin.CountVerticesOptions) (gremlin.CountResult, error) {
+	if err := g.check(ctx, "count vertices"); err != nil {
+		return gremlin.CountResult{}, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var count int64
+
+	for _, vertex := range g.vertices {
+		if matches(vertex, options.Label, options.ID, options.Filters) {
+			count++
+		}
+	}
+
+	return gremlin.CountResult{Count: count}, nil
+}
+
+// ListValues returns options.Property of the matching vertices in insertion order;
+// vertices without the property are skipped.
+func (g *Graph) ListValues(ctx context.Context, options gremlin.ListValuesOptions) (gremlin.ValueListResult, error) {
+	if err := g.check(ctx, "list values"); err != nil {
+		return gremlin.ValueListResult{}, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	values := []any{}
+
+	for _, vertex := range g.vertices {
+		if !matches(vertex, options.Label, options.ID, options.Filters) {
+			continue
+		}
+
+		if value, ok := vertex.Properties[options.Property]; ok {
+			values = append(values, value)
+		}
+	}
+
+	return gremlin.ValueListResult{Values: values}, nil
+}
+
+// CloseGraph closes the graph; later operations return ErrGraphIsClosed.
+func (g *Graph) CloseGraph(ctx context.Context) error {
+	if err := g.check(ctx, "close"); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	return nil
+}
+
+// Vertices returns copies of the vertices with label in insertion order; an empty label
+// returns every vertex.
+func (g *Graph) Vertices(label string) []Vertex {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var vertices []Vertex
+
+	for _, vertex := range g.vertices {
+		if label == "" || vertex.Label == label {
+			vertices = append(vertices, Vertex{Label: vertex.Label, ID: vertex.ID, Properties: maps.Clone(vertex.Properties)})
+		}
+	}
+
+	return vertices
+}
+
+// Edges returns copies of the edges with label in insertion order; an empty label returns
+// every edge.
+func (g *Graph) Edges(label string) []Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var edges []Edge
+
+	for _, edge := range g.edges {
+		if label == "" || edge.Label == label {
+			copied := *edge
+			copied.Properties = maps.Clone(edge.Properties)
+			edges = append(edges, copied)
+		}
+	}
+
+	return edges
+}
+
+// Reset removes every vertex and edge.
+func (g *Graph) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.vertices, g.edges = nil, nil
+	g.byRef = map[gremlin.VertexRef]*Vertex{}
+}
+
+func (g *Graph) check(ctx context.Context, operation string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return coreerrors.WrapBoundary("memgraph", operation, err)
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.closed {
+		return ErrGraphIsClosed
+	}
+
+	return nil
+}
+
+// matches reports whether vertex has label and id, when set, and passes every filter.
+func matches(vertex *Vertex, label, id string, filters []gremlin.PropertyFilter) bool {
+	if label != "" && vertex.Label != label {
+		return false
+	}
+
+	if id != "" && vertex.ID != id {
+		return false
+	}
+
+	for _, filter := range filters {
+		value, ok := vertex.Properties[filter.Name]
+		if !ok || !Compare(filter.Comparison, value, filter.Value) {
+			return false
+		}
+	}
+
+	return true
+}
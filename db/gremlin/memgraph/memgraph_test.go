@@ -0,0 +1,246 @@
+package memgraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/gremlin"
+	"github.com/InsideGallery/core/db/gremlin/gremlintest"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestGraphStoreConformance(t *testing.T) {
+	t.Parallel()
+
+	for _, syntax := range []string{gremlin.SyntaxAerospike, gremlin.SyntaxNeptun} {
+		t.Run(syntax, func(t *testing.T) {
+			t.Parallel()
+
+			gremlintest.RunGraphStore(t, func(*testing.T) gremlin.GraphStore {
+				return New(Options{Syntax: syntax})
+			})
+		})
+	}
+
+	t.Run("shared store", func(t *testing.T) {
+		t.Parallel()
+
+		store := New(Options{})
+
+		gremlintest.RunGraphStore(t, func(*testing.T) gremlin.GraphStore { return store })
+	})
+}
+
+func TestGraphTraverserConformance(t *testing.T) {
+	t.Parallel()
+
+	for _, syntax := range []string{gremlin.SyntaxAerospike, gremlin.SyntaxNeptun} {
+		t.Run(syntax, func(t *testing.T) {
+			t.Parallel()
+
+			gremlintest.RunGraphTraverser(t, func(*testing.T) gremlintest.TraversableStore {
+				return New(Options{Syntax: syntax})
+			})
+		})
+	}
+}
+
+func seed(t *testing.T, graph *Graph) {
+	t.Helper()
+
+	for _, id := range []string{"1", "2"} {
+		_, err := graph.UpsertVertex(context.Background(), gremlin.UpsertVertexOptions{Label: "person", ID: id})
+		testutils.Equal(t, err, nil)
+	}
+}
+
+func knows(id string, properties map[string]any) gremlin.UpsertEdgeOptions {
+	return gremlin.UpsertEdgeOptions{
+		Label:      "knows",
+		ID:         id,
+		From:       gremlin.VertexRef{Label: "person", ID: "1"},
+		To:         gremlin.VertexRef{Label: "person", ID: "2"},
+		Properties: properties,
+	}
+}
+
+func TestUpsertEdgeAerospikeMergesOnEndpoints(t *testing.T) {
+	t.Parallel()
+
+	graph := New(Options{Syntax: gremlin.SyntaxAerospike})
+	seed(t, graph)
+
+	_, err := graph.UpsertEdge(context.Background(), knows("a", map[string]any{"since": 2020, "weight": 1}))
+	testutils.Equal(t, err, nil)
+
+	_, err = graph.UpsertEdge(context.Background(), knows("b", map[string]any{"weight": 2}))
+	testutils.Equal(t, err, nil)
+
+	edges := graph.Edges("knows")
+	testutils.Equal(t, len(edges), 1)
+	testutils.Equal(t, edges[0].ID, "b")
+	testutils.Equal(t, edges[0].Properties, map[string]any{"since": 2020, "weight": 2, "id": "b"})
+}
+
+func TestUpsertEdgeNeptuneMergesOnID(t *testing.T) {
+	t.Parallel()
+
+	graph := New(Options{Syntax: gremlin.SyntaxNeptun})
+	seed(t, graph)
+
+	for _, edge := range []gremlin.UpsertEdgeOptions{
+		knows("a", map[string]any{"since": 2020}),
+		knows("b", nil),
+		knows("a", map[string]any{"weight": 3}),
+	} {
+		_, err := graph.UpsertEdge(context.Background(), edge)
+		testutils.Equal(t, err, nil)
+	}
+
+	edges := graph.Edges("")
+	testutils.Equal(t, len(edges), 2)
+	testutils.Equal(t, edges[0].ID, "a")
+	testutils.Equal(t, edges[0].Properties, map[string]any{"since": 2020, "weight": 3})
+	testutils.Equal(t, edges[1].ID, "b")
+}
+
+func TestDegreeCountsSelfLoopTwice(t *testing.T) {
+	t.Parallel()
+
+	graph := New(Options{})
+	seed(t, graph)
+
+	loop := knows("loop", nil)
+	loop.To = loop.From
+
+	_, err := graph.UpsertEdge(context.Background(), loop)
+	testutils.Equal(t, err, nil)
+
+	for direction, want := range map[gremlin.Direction]int64{
+		gremlin.DirectionOut:  1,
+		gremlin.DirectionIn:   1,
+		gremlin.DirectionBoth: 2,
+	} {
+		result, err := graph.Degree(context.Background(), gremlin.DegreeOptions{
+			Vertex: gremlin.VertexRef{Label: "person", ID: "1"}, Direction: direction,
+		})
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, result.Count, want)
+	}
+
+	neighbors, err := graph.Neighbors(context.Background(), gremlin.NeighborsOptions{
+		From: gremlin.VertexRef{Label: "person", ID: "1"}, Direction: gremlin.DirectionBoth,
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(neighbors.Vertices), 0)
+}
+
+func TestUpsertVertexKeepsProperties(t *testing.T) {
+	t.Parallel()
+
+	graph := New(Options{})
+
+	_, err := graph.UpsertVertex(context.Background(), gremlin.UpsertVertexOptions{
+		Label: "person", ID: "1", Properties: map[string]any{"name": "Ada", "id": "ignored"},
+	})
+	testutils.Equal(t, err, nil)
+
+	_, err = graph.UpsertVertex(context.Background(), gremlin.UpsertVertexOptions{
+		Label: "person", ID: "1", Properties: map[string]any{"age": 37},
+	})
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, graph.Vertices("person"), []Vertex{{
+		Label: "person", ID: "1", Properties: map[string]any{"name": "Ada", "age": 37, "id": "1"},
+	}})
+
+	graph.Vertices("person")[0].Properties["name"] = "changed"
+	testutils.Equal(t, graph.Vertices("")[0].Properties["name"], any("Ada"))
+}
+
+func TestGraphErrors(t *testing.T) {
+	t.Parallel()
+
+	graph := New(Options{})
+
+	_, err := graph.UpsertVertex(context.Background(), gremlin.UpsertVertexOptions{ID: "1"})
+	testutils.Equal(t, errors.Is(err, ErrLabelIsNotSet), true)
+
+	_, err = graph.UpsertVertex(context.Background(), gremlin.UpsertVertexOptions{Label: "person"})
+	testutils.Equal(t, errors.Is(err, ErrIDIsNotSet), true)
+
+	_, err = graph.UpsertEdge(context.Background(), knows("a", nil))
+	testutils.Equal(t, errors.Is(err, ErrVertexIsNotFound), true)
+	testutils.Equal(t, coreerrors.CategoryOf(err), coreerrors.CategoryNotFound)
+
+	testutils.Equal(t, graph.CloseGraph(context.Background()), nil)
+
+	_, err = graph.CountVertices(context.Background(), gremlin.CountVerticesOptions{})
+	testutils.Equal(t, errors.Is(err, ErrGraphIsClosed), true)
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	cases := []struct {
+		name       string
+		comparison gremlin.Comparison
+		value      any
+		operand    any
+		want       bool
+	}{
+		{name: "int equals float", comparison: gremlin.ComparisonEqual, value: int32(2), operand: 2.0, want: true},
+		{name: "uint greater than int", comparison: gremlin.ComparisonGreaterThan, value: uint8(3), operand: -1, want: true},
+		{name: "large ints", comparison: gremlin.ComparisonLessThan, value: int64(1<<62 + 1), operand: int64(1<<62 + 2), want: true},
+		{name: "string order", comparison: gremlin.ComparisonLessThanOrEqual, value: "a", operand: "b", want: true},
+		{name: "time order", comparison: gremlin.ComparisonGreaterThanOrEqual, value: now, operand: now.Add(-time.Second), want: true},
+		{name: "mixed types", comparison: gremlin.ComparisonGreaterThan, value: "10", operand: 1, want: false},
+		{name: "mixed types equal", comparison: gremlin.ComparisonEqual, value: "1", operand: 1, want: false},
+		{name: "bool equal", comparison: gremlin.ComparisonEqual, value: true, operand: true, want: true},
+		{name: "bool order", comparison: gremlin.ComparisonGreaterThan, value: true, operand: false, want: false},
+		{name: "unknown comparison is equality", comparison: "contains", value: "x", operand: "x", want: true},
+		{name: "slices equal", comparison: gremlin.ComparisonEqual, value: []any{"a"}, operand: []any{"a"}, want: true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			testutils.Equal(t, Compare(test.comparison, test.value, test.operand), test.want)
+		})
+	}
+}
+
+func TestConcurrentUpserts(t *testing.T) {
+	t.Parallel()
+
+	graph := New(Options{})
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 50 {
+				_, _ = graph.UpsertVertex(context.Background(), gremlin.UpsertVertexOptions{Label: "person", ID: "1"})
+				_, _ = graph.CountVertices(context.Background(), gremlin.CountVerticesOptions{Label: "person"})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	result, err := graph.CountVertices(context.Background(), gremlin.CountVerticesOptions{Label: "person"})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, result.Count, int64(1))
+}
@@ -0,0 +1,436 @@
+package memgraph
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/InsideGallery/core/db/gremlin"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+var _ gremlin.GraphTraverser = (*Graph)(nil)
+
+// hop is one step from a vertex over an edge.
+type hop struct {
+	edge   *Edge
+	vertex *Vertex
+}
+
+// Neighbors returns the vertices within options.Hops of options.From, excluding it,
+// ordered by id.
+func (g *Graph) Neighbors(ctx context.Context, options gremlin.NeighborsOptions) (gremlin.VertexListResult, error) {
+	if err := g.check(ctx, "neighbors"); err != nil {
+		return gremlin.VertexListResult{}, err
+	}
+
+	if options.From.ID == "" {
+		return gremlin.VertexListResult{}, ErrIDIsNotSet
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	depth := orDefault(options.Hops, gremlin.DefaultHops)
+
+	reached, err := g.reach(options.From, options.Direction, options.EdgeLabels, depth)
+	if err != nil {
+		return gremlin.VertexListResult{}, err
+	}
+
+	var vertices []*Vertex
+
+	for _, vertex := range reached {
+		if !isRef(vertex, options.From) && matches(vertex, options.Label, "", options.Filters) {
+			vertices = append(vertices, vertex)
+		}
+	}
+
+	sortVertices(vertices, "", false)
+
+	return gremlin.VertexListResult{Vertices: copyVertices(paginate(vertices, options.Page))}, nil
+}
+
+// ShortestPath returns a path with the fewest edges, or gremlin.ErrPathIsNotFound.
+func (g *Graph) ShortestPath(ctx context.Context, options gremlin.PathsOptions) (gremlin.PathResult, error) {
+	if err := g.check(ctx, "shortest path"); err != nil {
+		return gremlin.PathResult{}, err
+	}
+
+	options.Limit = 1
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	paths, err := g.paths(options)
+	if err != nil {
+		return gremlin.PathResult{}, err
+	}
+
+	if len(paths) == 0 {
+		return gremlin.PathResult{}, coreerrors.WrapBoundary("memgraph", "shortest path", gremlin.ErrPathIsNotFound)
+	}
+
+	return gremlin.PathResult{Path: paths[0]}, nil
+}
+
+// AllPaths returns the simple paths from options.From to options.To, shortest first and
+// in edge insertion order within a length.
+func (g *Graph) AllPaths(ctx context.Context, options gremlin.PathsOptions) (gremlin.PathListResult, error) {
+	if err := g.check(ctx, "all paths"); err != nil {
+		return gremlin.PathListResult{}, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	paths, err := g.paths(options)
+	if err != nil {
+		return gremlin.PathListResult{}, err
+	}
+
+	return gremlin.PathListResult{Paths: paths}, nil
+}
+
+// Subgraph returns the vertices within options.Hops of options.From, including it, and the
+// edges with options.EdgeLabels between them, in insertion order.
+func (g *Graph) Subgraph(ctx context.Context, options gremlin.SubgraphOptions) (gremlin.SubgraphResult, error) {
+	if err := g.check(ctx, "subgraph"); err != nil {
+		return gremlin.SubgraphResult{}, err
+	}
+
+	if options.From.ID == "" {
+		return gremlin.SubgraphResult{}, ErrIDIsNotSet
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	depth := orDefault(options.Hops, gremlin.DefaultHops)
+
+	reached, err := g.reach(options.From, options.Direction, options.EdgeLabels, depth)
+	if err != nil {
+		return gremlin.SubgraphResult{}, err
+	}
+
+	inside := map[gremlin.VertexRef]bool{}
+	for _, vertex := range reached {
+		inside[vertex.Ref()] = true
+	}
+
+	var result gremlin.SubgraphResult
+
+	for _, vertex := range g.vertices {
+		if inside[vertex.Ref()] {
+			result.Vertices = append(result.Vertices, copyVertex(vertex))
+		}
+	}
+
+	for _, edge := range g.edges {
+		if hasLabel(edge, options.EdgeLabels) && inside[edge.From] && inside[edge.To] {
+			result.Edges = append(result.Edges, copyEdge(edge))
+		}
+	}
+
+	return result, nil
+}
+
+// Degree counts the edges of options.Vertex; a self-loop counts twice with
+// gremlin.DirectionBoth.
+func (g *Graph) Degree(ctx context.Context, options gremlin.DegreeOptions) (gremlin.CountResult, error) {
+	if err := g.check(ctx, "degree"); err != nil {
+		return gremlin.CountResult{}, err
+	}
+
+	if options.Vertex.ID == "" {
+		return gremlin.CountResult{}, ErrIDIsNotSet
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var count int64
+
+	for _, vertex := range g.find(options.Vertex) {
+		hops, err := g.hops(vertex, options.Direction, options.EdgeLabels)
+		if err != nil {
+			return gremlin.CountResult{}, err
+		}
+
+		count += int64(len(hops))
+	}
+
+	return gremlin.CountResult{Count: count}, nil
+}
+
+// ListVertices returns a page of the vertices matching options, ordered by options.OrderBy
+// and then by id.
+func (g *Graph) ListVertices(
+	ctx context.Context,
+	options gremlin.ListVerticesOptions,
+) (gremlin.VertexListResult, error) {
+	if err := g.check(ctx, "list vertices"); err != nil {
+		return gremlin.VertexListResult{}, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var vertices []*Vertex
+
+	for _, vertex := range g.vertices {
+		if !matches(vertex, options.Label, options.ID, options.Filters) {
+			continue
+		}
+
+		if _, ok := vertex.Properties[options.OrderBy]; options.OrderBy != "" && !ok {
+			continue
+		}
+
+		vertices = append(vertices, vertex)
+	}
+
+	sortVertices(vertices, options.OrderBy, options.Descending)
+
+	return gremlin.VertexListResult{Vertices: copyVertices(paginate(vertices, options.Page))}, nil
+}
+
+// Ref returns the reference to the vertex.
+func (v Vertex) Ref() gremlin.VertexRef {
+	return gremlin.VertexRef{Label: v.Label, ID: v.ID}
+}
+
+// find returns the vertices ref matches; a ref without a label matches the id in any label.
+func (g *Graph) find(ref gremlin.VertexRef) []*Vertex {
+	if ref.Label != "" {
+		if vertex, ok := g.byRef[ref]; ok {
+			return []*Vertex{vertex}
+		}
+
+		return nil
+	}
+
+	var vertices []*Vertex
+
+	for _, vertex := range g.vertices {
+		if vertex.ID == ref.ID {
+			vertices = append(vertices, vertex)
+		}
+	}
+
+	return vertices
+}
+
+// hops returns the steps from vertex over the edges with labels in direction, in edge
+// insertion order.
+func (g *Graph) hops(vertex *Vertex, direction gremlin.Direction, labels []string) ([]hop, error) {
+	var out, in bool
+
+	switch direction {
+	case "", gremlin.DirectionOut:
+		out = true
+	case gremlin.DirectionIn:
+		in = true
+	case gremlin.DirectionBoth:
+		out, in = true, true
+	default:
+		return nil, fmt.Errorf("%w: %q", gremlin.ErrUnsupportedDirection, direction)
+	}
+
+	ref := vertex.Ref()
+
+	var hops []hop
+
+	for _, edge := range g.edges {
+		if !hasLabel(edge, labels) {
+			continue
+		}
+
+		if out && edge.From == ref {
+			hops = append(hops, hop{edge: edge, vertex: g.byRef[edge.To]})
+		}
+
+		if in && edge.To == ref {
+			hops = append(hops, hop{edge: edge, vertex: g.byRef[edge.From]})
+		}
+	}
+
+	return hops, nil
+}
+
+// reach returns the distinct vertices within depth hops of from, including it, breadth first.
+func (g *Graph) reach(
+	from gremlin.VertexRef,
+	direction gremlin.Direction,
+	labels []string,
+	depth int,
+) ([]*Vertex, error) {
+	level := g.find(from)
+	seen := map[*Vertex]bool{}
+
+	var reached []*Vertex
+
+	for _, vertex := range level {
+		seen[vertex] = true
+		reached = append(reached, vertex)
+	}
+
+	for range depth {
+		var next []*Vertex
+
+		for _, vertex := range level {
+			hops, err := g.hops(vertex, direction, labels)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, hop := range hops {
+				if !seen[hop.vertex] {
+					seen[hop.vertex] = true
+					reached = append(reached, hop.vertex)
+					next = append(next, hop.vertex)
+				}
+			}
+		}
+
+		level = next
+	}
+
+	return reached, nil
+}
+
+// paths returns the simple paths from options.From that end at the first visit of
+// options.To, breadth first.
+func (g *Graph) paths(options gremlin.PathsOptions) ([]gremlin.Path, error) {
+	if options.From.ID == "" || options.To.ID == "" {
+		return nil, ErrIDIsNotSet
+	}
+
+	type walk struct {
+		vertices []*Vertex
+		edges    []*Edge
+	}
+
+	var queue []walk
+
+	for _, vertex := range g.find(options.From) {
+		queue = append(queue, walk{vertices: []*Vertex{vertex}})
+	}
+
+	maxDepth := orDefault(options.MaxDepth, gremlin.DefaultMaxDepth)
+
+	var paths []gremlin.Path
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		hops, err := g.hops(current.vertices[len(current.vertices)-1], options.Direction, options.EdgeLabels)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hop := range hops {
+			if slices.Contains(current.vertices, hop.vertex) {
+				continue
+			}
+
+			next := walk{
+				vertices: append(slices.Clip(current.vertices), hop.vertex),
+				edges:    append(slices.Clip(current.edges), hop.edge),
+			}
+
+			if isRef(hop.vertex, options.To) {
+				paths = append(paths, copyPath(next.vertices, next.edges))
+
+				if options.Limit > 0 && int64(len(paths)) == options.Limit {
+					return paths, nil
+				}
+
+				continue
+			}
+
+			if len(next.edges) < maxDepth {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+func isRef(vertex *Vertex, ref gremlin.VertexRef) bool {
+	return vertex.ID == ref.ID && (ref.Label == "" || vertex.Label == ref.Label)
+}
+
+func hasLabel(edge *Edge, labels []string) bool {
+	return len(labels) == 0 || slices.Contains(labels, edge.Label)
+}
+
+func orDefault(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+// sortVertices orders vertices by property, if set, and then by id, the way the Gremlin
+// client orders them.
+func sortVertices(vertices []*Vertex, property string, descending bool) {
+	slices.SortStableFunc(vertices, func(a, b *Vertex) int {
+		if property != "" {
+			order, _ := compare(a.Properties[property], b.Properties[property])
+			if descending {
+				order = -order
+			}
+
+			if order != 0 {
+				return order
+			}
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+func paginate(vertices []*Vertex, page gremlin.Page) []*Vertex {
+	offset := min(max(page.Offset, 0), int64(len(vertices)))
+	vertices = vertices[offset:]
+
+	if page.Limit > 0 && page.Limit < int64(len(vertices)) {
+		vertices = vertices[:page.Limit]
+	}
+
+	return vertices
+}
+
+func copyVertex(vertex *Vertex) gremlin.Vertex {
+	return gremlin.Vertex{Label: vertex.Label, ID: vertex.ID, Properties: maps.Clone(vertex.Properties)}
+}
+
+func copyVertices(vertices []*Vertex) []gremlin.Vertex {
+	copied := make([]gremlin.Vertex, 0, len(vertices))
+	for _, vertex := range vertices {
+		copied = append(copied, copyVertex(vertex))
+	}
+
+	return copied
+}
+
+func copyEdge(edge *Edge) gremlin.Edge {
+	return gremlin.Edge{
+		Label: edge.Label, ID: edge.ID, From: edge.From, To: edge.To, Properties: maps.Clone(edge.Properties),
+	}
+}
+
+func copyPath(vertices []*Vertex, edges []*Edge) gremlin.Path {
+	path := gremlin.Path{Vertices: copyVertices(vertices), Edges: make([]gremlin.Edge, 0, len(edges))}
+	for _, edge := range edges {
+		path.Edges = append(path.Edges, copyEdge(edge))
+	}
+
+	return path
+}
@@ -0,0 +1,406 @@
+package gremlin
+
+import (
+	"fmt"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// Keys of the projections traversals return.
+const (
+	projectionLabel      = "label"
+	projectionID         = "id"
+	projectionProperties = "properties"
+	projectionFrom       = "from"
+	projectionTo         = "to"
+
+	startStepLabel        = "start"
+	subgraphSideKey       = "subgraph"
+	rangeToEnd      int64 = -1
+)
+
+// anonymous returns an empty traversal for child steps such as Until and By.
+func anonymous() *gremlingo.GraphTraversal {
+	return gremlingo.NewGraphTraversal(nil, gremlingo.NewBytecode(nil), nil)
+}
+
+// hasID filters on the id a vertex or edge was upserted with: the id property for
+// SyntaxAerospike, the element id for SyntaxNeptun.
+func hasID(traversal *gremlingo.GraphTraversal, state SyntaxState, id string) *gremlingo.GraphTraversal {
+	if state.Syntax == SyntaxNeptun {
+		return traversal.HasId(id)
+	}
+
+	return traversal.Has(state.PropertyID, id)
+}
+
+func hasRef(traversal *gremlingo.GraphTraversal, state SyntaxState, ref VertexRef) *gremlingo.GraphTraversal {
+	traversal = hasID(traversal, state, ref.ID)
+	if ref.Label != "" {
+		traversal = traversal.HasLabel(ref.Label)
+	}
+
+	return traversal
+}
+
+// idKey is the By argument that yields the id a vertex or edge was upserted with.
+func idKey(state SyntaxState) any {
+	if state.Syntax == SyntaxNeptun {
+		return gremlingo.T.Id
+	}
+
+	return state.PropertyID
+}
+
+func vertexProjection(traversal *gremlingo.GraphTraversal, state SyntaxState) *gremlingo.GraphTraversal {
+	return traversal.Project(projectionLabel, projectionID, projectionProperties).
+		By(gremlingo.T.Label).
+		By(idKey(state)).
+		By(gremlingo.T__.ValueMap().By(gremlingo.T__.Unfold()))
+}
+
+func edgeProjection(traversal *gremlingo.GraphTraversal, state SyntaxState) *gremlingo.GraphTraversal {
+	ref := func(traversal *gremlingo.GraphTraversal) *gremlingo.GraphTraversal {
+		return traversal.Project(projectionLabel, projectionID).By(gremlingo.T.Label).By(idKey(state))
+	}
+
+	return traversal.Project(projectionLabel, projectionID, projectionProperties, projectionFrom, projectionTo).
+		By(gremlingo.T.Label).
+		By(idKey(state)).
+		By(gremlingo.T__.ValueMap()).
+		By(ref(gremlingo.T__.OutV())).
+		By(ref(gremlingo.T__.InV()))
+}
+
+func edgeLabels(labels []string) []any {
+	values := make([]any, 0, len(labels))
+	for _, label := range labels {
+		values = append(values, label)
+	}
+
+	return values
+}
+
+// vertexStep moves to the adjacent vertices.
+func vertexStep(direction Direction, labels []string) (*gremlingo.GraphTraversal, error) {
+	switch direction {
+	case "", DirectionOut:
+		return gremlingo.T__.Out(edgeLabels(labels)...), nil
+	case DirectionIn:
+		return gremlingo.T__.In(edgeLabels(labels)...), nil
+	case DirectionBoth:
+		return gremlingo.T__.Both(edgeLabels(labels)...), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedDirection, direction)
+	}
+}
+
+// edgeStep moves to the adjacent vertices through the edges, keeping them in the path.
+func edgeStep(direction Direction, labels []string) (*gremlingo.GraphTraversal, error) {
+	switch direction {
+	case "", DirectionOut:
+		return gremlingo.T__.OutE(edgeLabels(labels)...).InV(), nil
+	case DirectionIn:
+		return gremlingo.T__.InE(edgeLabels(labels)...).OutV(), nil
+	case DirectionBoth:
+		return gremlingo.T__.BothE(edgeLabels(labels)...).OtherV(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedDirection, direction)
+	}
+}
+
+func orDefault(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+func orderVertices(
+	traversal *gremlingo.GraphTraversal,
+	state SyntaxState,
+	property string,
+	descending bool,
+) *gremlingo.GraphTraversal {
+	traversal = traversal.Order()
+
+	if property != "" {
+		order := gremlingo.Order.Asc
+		if descending {
+			order = gremlingo.Order.Desc
+		}
+
+		traversal = traversal.By(property, order)
+	}
+
+	return traversal.By(idKey(state), gremlingo.Order.Asc)
+}
+
+func paginate(traversal *gremlingo.GraphTraversal, page Page) *gremlingo.GraphTraversal {
+	switch {
+	case page.Limit > 0:
+		return traversal.Range(page.Offset, page.Offset+page.Limit)
+	case page.Offset > 0:
+		return traversal.Range(page.Offset, rangeToEnd)
+	default:
+		return traversal
+	}
+}
+
+func neighborsTraversal(
+	source *gremlingo.GraphTraversalSource,
+	state SyntaxState,
+	options NeighborsOptions,
+) (*gremlingo.GraphTraversal, error) {
+	if options.From.ID == "" {
+		return nil, ErrIDIsNotSet
+	}
+
+	step, err := vertexStep(options.Direction, options.EdgeLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	traversal := hasRef(source.V(), state, options.From).As(startStepLabel).
+		Repeat(step).Emit().Times(orDefault(options.Hops, DefaultHops)).
+		Dedup().
+		Where(gremlingo.P.Neq(startStepLabel))
+	traversal = applyTraversalOptions(traversal, state, options.Label, "", options.Filters)
+	traversal = paginate(orderVertices(traversal, state, "", false), options.Page)
+
+	return vertexProjection(traversal, state), nil
+}
+
+func pathsTraversal(
+	source *gremlingo.GraphTraversalSource,
+	state SyntaxState,
+	options PathsOptions,
+) (*gremlingo.GraphTraversal, error) {
+	if options.From.ID == "" || options.To.ID == "" {
+		return nil, ErrIDIsNotSet
+	}
+
+	step, err := edgeStep(options.Direction, options.EdgeLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeat walks breadth first, so shorter paths come first.
+	traversal := hasRef(source.V(), state, options.From).
+		Repeat(step.SimplePath()).
+		Until(gremlingo.T__.Or(
+			hasRef(anonymous(), state, options.To),
+			gremlingo.T__.Loops().Is(gremlingo.P.Gte(orDefault(options.MaxDepth, DefaultMaxDepth))),
+		))
+	traversal = hasRef(traversal, state, options.To)
+
+	if options.Limit > 0 {
+		traversal = traversal.Limit(options.Limit)
+	}
+
+	// Path objects alternate vertex, edge, vertex, so the By modulators apply in turn.
+	return traversal.Path().
+		By(vertexProjection(anonymous(), state)).
+		By(edgeProjection(anonymous(), state)), nil
+}
+
+// subgraphVertices reaches the vertices within options.Hops of options.From, including it.
+func subgraphVertices(
+	source *gremlingo.GraphTraversalSource,
+	state SyntaxState,
+	options SubgraphOptions,
+) (*gremlingo.GraphTraversal, error) {
+	if options.From.ID == "" {
+		return nil, ErrIDIsNotSet
+	}
+
+	step, err := vertexStep(options.Direction, options.EdgeLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	return hasRef(source.V(), state, options.From).
+		Emit().Repeat(step).Times(orDefault(options.Hops, DefaultHops)).
+		Dedup(), nil
+}
+
+func subgraphVerticesTraversal(
+	source *gremlingo.GraphTraversalSource,
+	state SyntaxState,
+	options SubgraphOptions,
+) (*gremlingo.GraphTraversal, error) {
+	traversal, err := subgraphVertices(source, state, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return vertexProjection(traversal, state), nil
+}
+
+func subgraphEdgesTraversal(
+	source *gremlingo.GraphTraversalSource,
+	state SyntaxState,
+	options SubgraphOptions,
+) (*gremlingo.GraphTraversal, error) {
+	traversal, err := subgraphVertices(source, state, options)
+	if err != nil {
+		return nil, err
+	}
+
+	traversal = traversal.Aggregate(subgraphSideKey).
+		BothE(edgeLabels(options.EdgeLabels)...).
+		Dedup().
+		Where(gremlingo.T__.OtherV().Where(gremlingo.P.Within(subgraphSideKey)))
+
+	return edgeProjection(traversal, state), nil
+}
+
+func degreeTraversal(
+	source *gremlingo.GraphTraversalSource,
+	state SyntaxState,
+	options DegreeOptions,
+) (*gremlingo.GraphTraversal, error) {
+	if options.Vertex.ID == "" {
+		return nil, ErrIDIsNotSet
+	}
+
+	traversal := hasRef(source.V(), state, options.Vertex)
+	labels := edgeLabels(options.EdgeLabels)
+
+	switch options.Direction {
+	case "", DirectionOut:
+		traversal = traversal.OutE(labels...)
+	case DirectionIn:
+		traversal = traversal.InE(labels...)
+	case DirectionBoth:
+		traversal = traversal.BothE(labels...)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedDirection, options.Direction)
+	}
+
+	return traversal.Count(), nil
+}
+
+func listVerticesTraversal(
+	source *gremlingo.GraphTraversalSource,
+	state SyntaxState,
+	options ListVerticesOptions,
+) *gremlingo.GraphTraversal {
+	traversal := applyTraversalOptions(source.V(), state, options.Label, options.ID, options.Filters)
+	traversal = orderVertices(traversal, state, options.OrderBy, options.Descending)
+
+	return vertexProjection(paginate(traversal, options.Page), state)
+}
+
+func decodeVertices(values []any) ([]Vertex, error) {
+	vertices := make([]Vertex, 0, len(values))
+
+	for _, value := range values {
+		vertex, err := decodeVertex(value)
+		if err != nil {
+			return nil, err
+		}
+
+		vertices = append(vertices, vertex)
+	}
+
+	return vertices, nil
+}
+
+func decodeVertex(value any) (Vertex, error) {
+	projection, ok := value.(map[any]any)
+	if !ok {
+		return Vertex{}, fmt.Errorf("%w: vertex projection is %T", ErrCastType, value)
+	}
+
+	return Vertex{
+		Label:      decodeString(projection[projectionLabel]),
+		ID:         decodeString(projection[projectionID]),
+		Properties: decodeProperties(projection[projectionProperties]),
+	}, nil
+}
+
+func decodeEdge(value any) (Edge, error) {
+	projection, ok := value.(map[any]any)
+	if !ok {
+		return Edge{}, fmt.Errorf("%w: edge projection is %T", ErrCastType, value)
+	}
+
+	from, err := decodeVertex(projection[projectionFrom])
+	if err != nil {
+		return Edge{}, err
+	}
+
+	to, err := decodeVertex(projection[projectionTo])
+	if err != nil {
+		return Edge{}, err
+	}
+
+	return Edge{
+		Label:      decodeString(projection[projectionLabel]),
+		ID:         decodeString(projection[projectionID]),
+		From:       from.Ref(),
+		To:         to.Ref(),
+		Properties: decodeProperties(projection[projectionProperties]),
+	}, nil
+}
+
+func decodePath(value any) (Path, error) {
+	var objects []any
+
+	switch path := value.(type) {
+	case *gremlingo.Path:
+		objects = path.Objects
+	case gremlingo.Path:
+		objects = path.Objects
+	default:
+		return Path{}, fmt.Errorf("%w: path is %T", ErrCastType, value)
+	}
+
+	var path Path
+
+	for i, object := range objects {
+		if i%2 == 0 {
+			vertex, err := decodeVertex(object)
+			if err != nil {
+				return Path{}, err
+			}
+
+			path.Vertices = append(path.Vertices, vertex)
+
+			continue
+		}
+
+		edge, err := decodeEdge(object)
+		if err != nil {
+			return Path{}, err
+		}
+
+		path.Edges = append(path.Edges, edge)
+	}
+
+	return path, nil
+}
+
+func decodeString(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func decodeProperties(value any) map[string]any {
+	properties := map[string]any{}
+
+	values, _ := value.(map[any]any)
+	for key, value := range values {
+		properties[decodeString(key)] = value
+	}
+
+	return properties
+}
@@ -0,0 +1,181 @@
+package gremlin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// script translates traversal to Gremlin, starting at the V step.
+func script(t *testing.T, traversal *gremlingo.GraphTraversal) string {
+	t.Helper()
+
+	translated, err := gremlingo.NewTranslator("g").Translate(traversal.Bytecode)
+	testutils.Equal(t, err, nil)
+
+	return translated[strings.Index(translated, "V()"):]
+}
+
+func TestTraversalScripts(t *testing.T) {
+	t.Parallel()
+
+	source := gremlingo.NewGraphTraversalSource(nil, nil, gremlingo.NewBytecode(nil))
+	person := VertexRef{Label: "person", ID: "1"}
+
+	const (
+		aerospikeVertex = "project('label','id','properties').by(label).by('id').by(valueMap().by(unfold()))"
+		neptunVertex    = "project('label','id','properties').by(label).by(id).by(valueMap().by(unfold()))"
+		aerospikeEdge   = "project('label','id','properties','from','to').by(label).by('id').by(valueMap())" +
+			".by(outV().project('label','id').by(label).by('id')).by(inV().project('label','id').by(label).by('id'))"
+	)
+
+	cases := []struct {
+		name   string
+		syntax string
+		build  func(state SyntaxState) (*gremlingo.GraphTraversal, error)
+		want   string
+	}{
+		{
+			name:   "neighbors",
+			syntax: SyntaxAerospike,
+			build: func(state SyntaxState) (*gremlingo.GraphTraversal, error) {
+				return neighborsTraversal(source, state, NeighborsOptions{
+					From:       person,
+					EdgeLabels: []string{"knows"},
+					Hops:       2,
+					Filters:    []PropertyFilter{{Name: "age", Comparison: ComparisonGreaterThan, Value: 3}},
+					Page:       Page{Offset: 1, Limit: 2},
+				})
+			},
+			want: "V().has('id','1').hasLabel('person').as('start').repeat(out('knows')).emit().times(2).dedup()" +
+				".where(neq('start')).has('age',is(gt(3))).order().by('id',asc).range(1,3)." + aerospikeVertex,
+		},
+		{
+			name:   "neighbors with neptun ids",
+			syntax: SyntaxNeptun,
+			build: func(state SyntaxState) (*gremlingo.GraphTraversal, error) {
+				return neighborsTraversal(source, state, NeighborsOptions{
+					From: VertexRef{ID: "1"}, Direction: DirectionBoth,
+				})
+			},
+			want: "V().hasId('1').as('start').repeat(both()).emit().times(1).dedup()" +
+				".where(neq('start')).order().by(id,asc)." + neptunVertex,
+		},
+		{
+			name:   "paths",
+			syntax: SyntaxAerospike,
+			build: func(state SyntaxState) (*gremlingo.GraphTraversal, error) {
+				return pathsTraversal(source, state, PathsOptions{
+					From: person, To: VertexRef{ID: "2"}, Direction: DirectionIn, MaxDepth: 3, Limit: 1,
+				})
+			},
+			want: "V().has('id','1').hasLabel('person').repeat(inE().outV().simplePath())" +
+				".until(or(has('id','2'),loops().is(gte(3)))).has('id','2').limit(1)" +
+				".path().by(" + aerospikeVertex + ").by(" + aerospikeEdge + ")",
+		},
+		{
+			name:   "subgraph edges",
+			syntax: SyntaxAerospike,
+			build: func(state SyntaxState) (*gremlingo.GraphTraversal, error) {
+				return subgraphEdgesTraversal(source, state, SubgraphOptions{From: person, EdgeLabels: []string{"knows"}})
+			},
+			want: "V().has('id','1').hasLabel('person').emit().repeat(out('knows')).times(1).dedup()" +
+				".aggregate('subgraph').bothE('knows').dedup().where(otherV().where(within('subgraph')))." + aerospikeEdge,
+		},
+		{
+			name:   "degree",
+			syntax: SyntaxNeptun,
+			build: func(state SyntaxState) (*gremlingo.GraphTraversal, error) {
+				return degreeTraversal(source, state, DegreeOptions{Vertex: person, Direction: DirectionIn})
+			},
+			want: "V().hasId('1').hasLabel('person').inE().count()",
+		},
+		{
+			name:   "list vertices",
+			syntax: SyntaxNeptun,
+			build: func(state SyntaxState) (*gremlingo.GraphTraversal, error) {
+				return listVerticesTraversal(source, state, ListVerticesOptions{
+					Label: "person", OrderBy: "age", Descending: true, Page: Page{Offset: 3},
+				}), nil
+			},
+			want: "V().hasLabel('person').order().by('age',desc).by(id,asc).range(3,-1)." + neptunVertex,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			traversal, err := test.build(NewSyntaxState(test.syntax))
+			testutils.Equal(t, err, nil)
+			testutils.Equal(t, script(t, traversal), test.want)
+		})
+	}
+}
+
+func TestTraversalOptionErrors(t *testing.T) {
+	t.Parallel()
+
+	source := gremlingo.NewGraphTraversalSource(nil, nil, gremlingo.NewBytecode(nil))
+	state := NewSyntaxState(SyntaxAerospike)
+
+	_, err := neighborsTraversal(source, state, NeighborsOptions{})
+	testutils.Equal(t, errors.Is(err, ErrIDIsNotSet), true)
+
+	_, err = pathsTraversal(source, state, PathsOptions{From: VertexRef{ID: "1"}})
+	testutils.Equal(t, errors.Is(err, ErrIDIsNotSet), true)
+
+	_, err = subgraphVerticesTraversal(source, state, SubgraphOptions{From: VertexRef{ID: "1"}, Direction: "up"})
+	testutils.Equal(t, errors.Is(err, ErrUnsupportedDirection), true)
+
+	_, err = degreeTraversal(source, state, DegreeOptions{Vertex: VertexRef{ID: "1"}, Direction: "up"})
+	testutils.Equal(t, errors.Is(err, ErrUnsupportedDirection), true)
+
+	testutils.Equal(t, coreerrors.CategoryOf(coreerrors.WrapBoundary("gremlin", "shortest path", ErrPathIsNotFound)),
+		coreerrors.CategoryNotFound)
+}
+
+func TestDecodePath(t *testing.T) {
+	t.Parallel()
+
+	vertex := func(id string) map[any]any {
+		return map[any]any{"label": "person", "id": id, "properties": map[any]any{"id": id, "age": int64(3)}}
+	}
+
+	path, err := decodePath(&gremlingo.Path{Objects: []any{
+		vertex("2"),
+		map[any]any{
+			"label":      "knows",
+			"id":         int64(7),
+			"properties": map[any]any{},
+			"from":       map[any]any{"label": "person", "id": "1"},
+			"to":         map[any]any{"label": "person", "id": "2"},
+		},
+		vertex("1"),
+	}})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, path, Path{
+		Vertices: []Vertex{
+			{Label: "person", ID: "2", Properties: map[string]any{"id": "2", "age": int64(3)}},
+			{Label: "person", ID: "1", Properties: map[string]any{"id": "1", "age": int64(3)}},
+		},
+		Edges: []Edge{{
+			Label:      "knows",
+			ID:         "7",
+			From:       VertexRef{Label: "person", ID: "1"},
+			To:         VertexRef{Label: "person", ID: "2"},
+			Properties: map[string]any{},
+		}},
+	})
+
+	_, err = decodePath([]any{"v"})
+	testutils.Equal(t, errors.Is(err, ErrCastType), true)
+
+	_, err = decodePath(gremlingo.Path{Objects: []any{"v"}})
+	testutils.Equal(t, errors.Is(err, ErrCastType), true)
+}
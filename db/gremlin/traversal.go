@@ -0,0 +1,335 @@
+package gremlin
+
+import (
+	"context"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Traversal defaults.
+const (
+	DefaultHops     = 1
+	DefaultMaxDepth = 5
+)
+
+// Direction selects which edges a traversal follows from a vertex.
+type Direction string
+
+const (
+	// DirectionOut follows outgoing edges. It is the default.
+	DirectionOut Direction = "out"
+	// DirectionIn follows incoming edges.
+	DirectionIn Direction = "in"
+	// DirectionBoth follows edges in either direction.
+	DirectionBoth Direction = "both"
+)
+
+// Vertex is a core-owned vertex returned by traversals. ID is the id the vertex was
+// upserted with, whatever the syntax stores it as.
+type Vertex struct {
+	Label      string
+	ID         string
+	Properties map[string]any
+}
+
+// Ref returns the reference to the vertex.
+func (v Vertex) Ref() VertexRef {
+	return VertexRef{Label: v.Label, ID: v.ID}
+}
+
+// Edge is a core-owned edge returned by traversals.
+type Edge struct {
+	Label      string
+	ID         string
+	From       VertexRef
+	To         VertexRef
+	Properties map[string]any
+}
+
+// Path is a walk through the graph. Edges[i] connects Vertices[i] and Vertices[i+1]; its
+// From and To keep the stored edge direction, which differs from the walk direction when
+// the path follows an edge backwards.
+type Path struct {
+	Vertices []Vertex
+	Edges    []Edge
+}
+
+// Page selects a range of an ordered result. A zero Limit returns everything from Offset.
+type Page struct {
+	Offset int64
+	Limit  int64
+}
+
+// NeighborsOptions is the core-owned input for k-hop neighbor queries.
+type NeighborsOptions struct {
+	From       VertexRef
+	Direction  Direction
+	EdgeLabels []string
+	// Hops is the maximum distance from From; zero means DefaultHops.
+	Hops int
+	// Label and Filters select among the reached vertices.
+	Label   string
+	Filters []PropertyFilter
+	Page    Page
+}
+
+// PathsOptions is the core-owned input for path queries.
+type PathsOptions struct {
+	From       VertexRef
+	To         VertexRef
+	Direction  Direction
+	EdgeLabels []string
+	// MaxDepth is the maximum number of edges in a path; zero means DefaultMaxDepth.
+	MaxDepth int
+	// Limit caps the number of paths AllPaths returns; zero returns every path.
+	Limit int64
+}
+
+// SubgraphOptions is the core-owned input for subgraph extraction.
+type SubgraphOptions struct {
+	From       VertexRef
+	Direction  Direction
+	EdgeLabels []string
+	// Hops is the maximum distance from From; zero means DefaultHops.
+	Hops int
+}
+
+// DegreeOptions is the core-owned input for degree counts.
+type DegreeOptions struct {
+	Vertex     VertexRef
+	Direction  Direction
+	EdgeLabels []string
+}
+
+// ListVerticesOptions is the core-owned input for listing vertices.
+type ListVerticesOptions struct {
+	Label   string
+	ID      string
+	Filters []PropertyFilter
+	// OrderBy sorts by a property, skipping vertices without it; vertices are ordered by id
+	// otherwise and on ties.
+	OrderBy    string
+	Descending bool
+	Page       Page
+}
+
+// VertexListResult is the core-owned result for vertex queries.
+type VertexListResult struct {
+	Vertices []Vertex
+}
+
+// PathResult is the core-owned result for single path queries.
+type PathResult struct {
+	Path Path
+}
+
+// PathListResult is the core-owned result for path queries.
+type PathListResult struct {
+	Paths []Path
+}
+
+// SubgraphResult is the core-owned result for subgraph extraction.
+type SubgraphResult struct {
+	Vertices []Vertex
+	Edges    []Edge
+}
+
+// GraphTraverser is the core-owned Gremlin contract for read-only traversals.
+//
+// Neighbors returns the distinct vertices within options.Hops of options.From, excluding
+// it, ordered by id. ShortestPath returns a path with the fewest edges, or
+// ErrPathIsNotFound. AllPaths returns the simple paths, shortest first; paths end at the
+// first visit of options.To and have at least one edge. Subgraph returns the vertices within
+// options.Hops of options.From, including it, and the edges with options.EdgeLabels between
+// them in either direction. Degree counts the edges of a vertex, a self-loop twice with
+// DirectionBoth. ListVertices returns a page of matching vertices.
+type GraphTraverser interface {
+	Neighbors(ctx context.Context, options NeighborsOptions) (VertexListResult, error)
+	ShortestPath(ctx context.Context, options PathsOptions) (PathResult, error)
+	AllPaths(ctx context.Context, options PathsOptions) (PathListResult, error)
+	Subgraph(ctx context.Context, options SubgraphOptions) (SubgraphResult, error)
+	Degree(ctx context.Context, options DegreeOptions) (CountResult, error)
+	ListVertices(ctx context.Context, options ListVerticesOptions) (VertexListResult, error)
+}
+
+// Neighbors returns the vertices within options.Hops of options.From.
+func (c *Client) Neighbors(ctx context.Context, options NeighborsOptions) (VertexListResult, error) {
+	values, err := c.toList(ctx, "neighbors", options.From.Label,
+		func(source *gremlingo.GraphTraversalSource, state SyntaxState) (*gremlingo.GraphTraversal, error) {
+			return neighborsTraversal(source, state, options)
+		})
+	if err != nil {
+		return VertexListResult{}, err
+	}
+
+	vertices, err := decodeVertices(values)
+	if err != nil {
+		return VertexListResult{}, coreerrors.WrapBoundary("gremlin", "neighbors", err)
+	}
+
+	return VertexListResult{Vertices: vertices}, nil
+}
+
+// ShortestPath returns a path with the fewest edges from options.From to options.To.
+func (c *Client) ShortestPath(ctx context.Context, options PathsOptions) (PathResult, error) {
+	options.Limit = 1
+
+	paths, err := c.paths(ctx, "shortest path", options)
+	if err != nil {
+		return PathResult{}, err
+	}
+
+	if len(paths) == 0 {
+		return PathResult{}, coreerrors.WrapBoundary("gremlin", "shortest path", ErrPathIsNotFound)
+	}
+
+	return PathResult{Path: paths[0]}, nil
+}
+
+// AllPaths returns the simple paths from options.From to options.To, shortest first.
+func (c *Client) AllPaths(ctx context.Context, options PathsOptions) (PathListResult, error) {
+	paths, err := c.paths(ctx, "all paths", options)
+	if err != nil {
+		return PathListResult{}, err
+	}
+
+	return PathListResult{Paths: paths}, nil
+}
+
+// Subgraph returns the vertices within options.Hops of options.From and the edges between them.
+func (c *Client) Subgraph(ctx context.Context, options SubgraphOptions) (SubgraphResult, error) {
+	values, err := c.toList(ctx, "subgraph", options.From.Label,
+		func(source *gremlingo.GraphTraversalSource, state SyntaxState) (*gremlingo.GraphTraversal, error) {
+			return subgraphVerticesTraversal(source, state, options)
+		})
+	if err != nil {
+		return SubgraphResult{}, err
+	}
+
+	vertices, err := decodeVertices(values)
+	if err != nil {
+		return SubgraphResult{}, coreerrors.WrapBoundary("gremlin", "subgraph", err)
+	}
+
+	values, err = c.toList(ctx, "subgraph", options.From.Label,
+		func(source *gremlingo.GraphTraversalSource, state SyntaxState) (*gremlingo.GraphTraversal, error) {
+			return subgraphEdgesTraversal(source, state, options)
+		})
+	if err != nil {
+		return SubgraphResult{}, err
+	}
+
+	edges := make([]Edge, 0, len(values))
+
+	for _, value := range values {
+		edge, err := decodeEdge(value)
+		if err != nil {
+			return SubgraphResult{}, coreerrors.WrapBoundary("gremlin", "subgraph", err)
+		}
+
+		edges = append(edges, edge)
+	}
+
+	return SubgraphResult{Vertices: vertices, Edges: edges}, nil
+}
+
+// Degree counts the edges of options.Vertex.
+func (c *Client) Degree(ctx context.Context, options DegreeOptions) (CountResult, error) {
+	values, err := c.toList(ctx, "degree", options.Vertex.Label,
+		func(source *gremlingo.GraphTraversalSource, state SyntaxState) (*gremlingo.GraphTraversal, error) {
+			return degreeTraversal(source, state, options)
+		})
+	if err != nil {
+		return CountResult{}, err
+	}
+
+	if len(values) != 1 {
+		return CountResult{}, coreerrors.WrapBoundary("gremlin", "degree", ErrCastType)
+	}
+
+	count, ok := values[0].(int64)
+	if !ok {
+		return CountResult{}, coreerrors.WrapBoundary("gremlin", "degree", ErrCastType)
+	}
+
+	return CountResult{Count: count}, nil
+}
+
+// ListVertices returns a page of the vertices matching options.
+func (c *Client) ListVertices(ctx context.Context, options ListVerticesOptions) (VertexListResult, error) {
+	values, err := c.toList(ctx, "list vertices", options.Label,
+		func(source *gremlingo.GraphTraversalSource, state SyntaxState) (*gremlingo.GraphTraversal, error) {
+			return listVerticesTraversal(source, state, options), nil
+		})
+	if err != nil {
+		return VertexListResult{}, err
+	}
+
+	vertices, err := decodeVertices(values)
+	if err != nil {
+		return VertexListResult{}, coreerrors.WrapBoundary("gremlin", "list vertices", err)
+	}
+
+	return VertexListResult{Vertices: vertices}, nil
+}
+
+func (c *Client) paths(ctx context.Context, operation string, options PathsOptions) ([]Path, error) {
+	values, err := c.toList(ctx, operation, options.From.Label,
+		func(source *gremlingo.GraphTraversalSource, state SyntaxState) (*gremlingo.GraphTraversal, error) {
+			return pathsTraversal(source, state, options)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]Path, 0, len(values))
+
+	for _, value := range values {
+		path, err := decodePath(value)
+		if err != nil {
+			return nil, coreerrors.WrapBoundary("gremlin", operation, err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// toList builds a traversal for the current syntax, runs it and returns the raw results.
+func (c *Client) toList(
+	ctx context.Context,
+	operation, label string,
+	build func(source *gremlingo.GraphTraversalSource, state SyntaxState) (*gremlingo.GraphTraversal, error),
+) ([]any, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, coreerrors.WrapBoundary("gremlin", operation, err)
+	}
+
+	traversal, err := build(c.S(), CurrentSyntaxState())
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("gremlin", operation, err)
+	}
+
+	done := c.observe(ctx, operation, label)
+
+	results, err := traversal.ToList()
+	done(int64(len(results)), err)
+
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("gremlin", operation, err)
+	}
+
+	values := make([]any, 0, len(results))
+	for _, result := range results {
+		values = append(values, result.GetInterface())
+	}
+
+	return values, nil
+}
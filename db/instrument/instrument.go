@@ -0,0 +1,112 @@
+// Package instrument observes database operations at the boundary methods of
+// the db adapters and fans them out to tracing, metrics and logging observers.
+package instrument
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Database systems reported in Event.System.
+const (
+	SystemPostgres      = "postgresql"
+	SystemMongoDB       = "mongodb"
+	SystemRedis         = "redis"
+	SystemAerospike     = "aerospike"
+	SystemElasticsearch = "elasticsearch"
+	SystemGremlin       = "gremlin"
+)
+
+// Event describes one finished database operation.
+type Event struct {
+	// System is one of the System constants.
+	System string
+	// Operation is the adapter operation, such as query or find documents.
+	Operation string
+	// Target is the table, collection, set, index or label the operation works on; it may be empty.
+	Target string
+	// Statement is the query text without arguments, when the adapter has one.
+	Statement string
+	Start     time.Time
+	Duration  time.Duration
+	// Rows is the number of rows or documents read or written; zero when unknown.
+	Rows int64
+	Err  error
+}
+
+// Observer receives every observed operation. Implementations must be safe for
+// concurrent use and should not block.
+type Observer interface {
+	Observe(ctx context.Context, event Event)
+}
+
+// ObserverFunc adapts a function to Observer.
+type ObserverFunc func(ctx context.Context, event Event)
+
+// Observe calls f.
+func (f ObserverFunc) Observe(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+// Observers fans an event out to every observer in order.
+type Observers []Observer
+
+// Observe calls every non-nil observer.
+func (o Observers) Observe(ctx context.Context, event Event) {
+	for _, observer := range o {
+		if observer != nil {
+			observer.Observe(ctx, event)
+		}
+	}
+}
+
+type observerHolder struct {
+	observer Observer
+}
+
+var defaultObserver atomic.Pointer[observerHolder] //nolint:gochecknoglobals // process-wide default like metrics.SetDefault
+
+// SetDefault sets the observer used by adapters that have none of their own; nil disables it.
+func SetDefault(observer Observer) {
+	defaultObserver.Store(&observerHolder{observer: observer})
+}
+
+// Default returns the observer set by SetDefault, or nil.
+//
+//nolint:ireturn // observers are configured through the interface
+func Default() Observer {
+	holder := defaultObserver.Load()
+	if holder == nil {
+		return nil
+	}
+
+	return holder.observer
+}
+
+// Begin starts timing event and returns the function that completes it with the
+// rows and error of the operation. observer nil falls back to Default; when both
+// are nil Begin does nothing.
+//
+//	done := instrument.Begin(ctx, c.observer, instrument.Event{System: instrument.SystemRedis, Operation: "get"})
+//	value, err := c.Get(ctx, key)
+//	done(1, err)
+func Begin(ctx context.Context, observer Observer, event Event) func(rows int64, err error) {
+	if observer == nil {
+		observer = Default()
+	}
+
+	if observer == nil {
+		return func(int64, error) {}
+	}
+
+	event.Start = time.Now()
+
+	return func(rows int64, err error) {
+		event.Duration = time.Since(event.Start)
+		event.Rows = rows
+		event.Err = err
+
+		observer.Observe(ctx, event)
+	}
+}
@@ -0,0 +1,137 @@
+package instrument
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/InsideGallery/core/metrics"
+)
+
+type recordingProcessor struct {
+	mu      sync.Mutex
+	counts  map[string]int64
+	samples map[string][]string
+}
+
+func (p *recordingProcessor) Close() error { return nil }
+
+func (p *recordingProcessor) Count(name string, value int64, _ []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.counts[name] += value
+
+	return nil
+}
+
+func (p *recordingProcessor) Gauge(string, float64, []string) error { return nil }
+
+func (p *recordingProcessor) Distribution(name string, _ float64, tags []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.samples[name] = tags
+
+	return nil
+}
+
+func TestBegin(t *testing.T) {
+	ctx := context.Background()
+	errBoom := errors.New("boom")
+
+	var events []Event
+
+	record := ObserverFunc(func(_ context.Context, event Event) {
+		events = append(events, event)
+	})
+
+	Begin(ctx, nil, Event{Operation: "ignored"})(1, nil)
+	testutils.Equal(t, len(events), 0)
+
+	Begin(ctx, Observers{nil, record, record}, Event{System: SystemRedis, Operation: "get"})(1, errBoom)
+	testutils.Equal(t, len(events), 2)
+	testutils.Equal(t, events[0].Rows, int64(1))
+	testutils.Equal(t, events[0].Err, errBoom)
+	testutils.Equal(t, events[0].Start.IsZero(), false)
+
+	SetDefault(record)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	Begin(ctx, nil, Event{Operation: "default"})(0, nil)
+	testutils.Equal(t, events[2].Operation, "default")
+}
+
+func TestSlowQueryObserver(t *testing.T) {
+	var buf bytes.Buffer
+
+	observer := NewSlowQueryObserver(slog.New(slog.NewTextHandler(&buf, nil)), time.Second)
+
+	observer.Observe(context.Background(), Event{Operation: "fast", Duration: time.Millisecond})
+	testutils.Equal(t, buf.Len(), 0)
+
+	observer.Observe(context.Background(), Event{
+		System:    SystemPostgres,
+		Operation: "query",
+		Target:    "users",
+		Statement: "select * from users",
+		Duration:  2 * time.Second,
+	})
+	testutils.Equal(t, strings.Contains(buf.String(), `msg="slow db operation"`), true)
+	testutils.Equal(t, strings.Contains(buf.String(), `statement="select * from users"`), true)
+}
+
+func TestMetricsObserver(t *testing.T) {
+	processor := &recordingProcessor{counts: make(map[string]int64), samples: make(map[string][]string)}
+	kind := "instrument-" + t.Name()
+
+	metrics.Register(kind, func(metrics.Config, string) (metrics.Processor, error) {
+		return processor, nil
+	})
+
+	client, err := metrics.New(metrics.Config{Processors: []string{kind}}, "test")
+	testutils.Equal(t, err, nil)
+
+	observer := NewMetricsObserver(client)
+	observer.Observe(context.Background(), Event{System: SystemMongoDB, Operation: "find", Target: "users"})
+	observer.Observe(context.Background(), Event{System: SystemMongoDB, Operation: "find", Target: "users", Err: errors.New("boom")})
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+
+	testutils.Equal(t, processor.counts[MetricOperationErrors], int64(1))
+	testutils.Equal(t, processor.samples[MetricOperationDuration],
+		[]string{"system:mongodb", "operation:find", "target:users", "status:error"})
+}
+
+func TestSpanObserver(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	observer := NewSpanObserver(provider.Tracer(TracerName))
+
+	start := time.Now().Add(-time.Second)
+	observer.Observe(context.Background(), Event{
+		System:    SystemPostgres,
+		Operation: "exec",
+		Target:    "users",
+		Start:     start,
+		Duration:  250 * time.Millisecond,
+		Err:       errors.New("boom"),
+	})
+
+	spans := recorder.Ended()
+	testutils.Equal(t, len(spans), 1)
+	testutils.Equal(t, spans[0].Name(), "exec users")
+	testutils.Equal(t, spans[0].EndTime().Sub(spans[0].StartTime()), 250*time.Millisecond)
+	testutils.Equal(t, spans[0].Status().Code, codes.Error)
+}
@@ -0,0 +1,57 @@
+package instrument
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/InsideGallery/core/metrics"
+)
+
+// Metric names recorded by MetricsObserver, tagged with system, operation, target and status.
+const (
+	MetricOperationDuration = "db.operation.duration_ms"
+	MetricOperationErrors   = "db.operation.errors"
+	MetricOperationRows     = "db.operation.rows"
+
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+// MetricsObserver records latency, rows and errors of every event.
+type MetricsObserver struct {
+	client *metrics.Client
+}
+
+// NewMetricsObserver returns a MetricsObserver sending to client.
+func NewMetricsObserver(client *metrics.Client) *MetricsObserver {
+	return &MetricsObserver{client: client}
+}
+
+// Observe records event.
+func (o *MetricsObserver) Observe(_ context.Context, event Event) {
+	status := statusOK
+	if event.Err != nil {
+		status = statusError
+	}
+
+	tags := []string{
+		"system:" + event.System,
+		"operation:" + event.Operation,
+		"target:" + event.Target,
+		"status:" + status,
+	}
+
+	o.record(MetricOperationDuration, o.client.Distribution(
+		MetricOperationDuration, float64(event.Duration.Microseconds())/1000, tags))
+	o.record(MetricOperationRows, o.client.Distribution(MetricOperationRows, float64(event.Rows), tags))
+
+	if event.Err != nil {
+		o.record(MetricOperationErrors, o.client.Count(MetricOperationErrors, 1, tags))
+	}
+}
+
+func (o *MetricsObserver) record(name string, err error) {
+	if err != nil {
+		slog.Default().Warn("record db metric failed", "metric", name, "error", err)
+	}
+}
@@ -0,0 +1,56 @@
+package instrument
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultSlowThreshold is the SlowQueryObserver threshold used when none is set.
+const DefaultSlowThreshold = 200 * time.Millisecond
+
+// SlowQueryObserver logs operations that take at least the threshold.
+type SlowQueryObserver struct {
+	logger    *slog.Logger
+	threshold time.Duration
+}
+
+// NewSlowQueryObserver returns a SlowQueryObserver; nil logger uses slog.Default and
+// zero threshold uses DefaultSlowThreshold.
+func NewSlowQueryObserver(logger *slog.Logger, threshold time.Duration) *SlowQueryObserver {
+	if threshold <= 0 {
+		threshold = DefaultSlowThreshold
+	}
+
+	return &SlowQueryObserver{logger: logger, threshold: threshold}
+}
+
+// Observe logs event at warn level when it is slow.
+func (o *SlowQueryObserver) Observe(ctx context.Context, event Event) {
+	if event.Duration < o.threshold {
+		return
+	}
+
+	logger := o.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	attrs := []slog.Attr{
+		slog.String("system", event.System),
+		slog.String("operation", event.Operation),
+		slog.String("target", event.Target),
+		slog.Duration("duration", event.Duration),
+		slog.Int64("rows", event.Rows),
+	}
+
+	if event.Statement != "" {
+		attrs = append(attrs, slog.String("statement", event.Statement))
+	}
+
+	if event.Err != nil {
+		attrs = append(attrs, slog.Any("err", event.Err))
+	}
+
+	logger.LogAttrs(ctx, slog.LevelWarn, "slow db operation", attrs...)
+}
@@ -0,0 +1,59 @@
+package instrument
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of spans created by SpanObserver.
+const TracerName = "github.com/InsideGallery/core/db"
+
+// SpanObserver records every event as a client span, named "<operation> <target>",
+// that starts and ends at the event times and is parented by the span in ctx.
+type SpanObserver struct {
+	tracer trace.Tracer
+}
+
+// NewSpanObserver returns a SpanObserver; nil tracer uses the global provider.
+func NewSpanObserver(tracer trace.Tracer) *SpanObserver {
+	if tracer == nil {
+		tracer = otel.Tracer(TracerName)
+	}
+
+	return &SpanObserver{tracer: tracer}
+}
+
+// Observe records event as a span.
+func (o *SpanObserver) Observe(ctx context.Context, event Event) {
+	attributes := []attribute.KeyValue{
+		attribute.String("db.system", event.System),
+		attribute.String("db.operation.name", event.Operation),
+		attribute.Int64("db.response.returned_rows", event.Rows),
+	}
+
+	if event.Target != "" {
+		attributes = append(attributes, attribute.String("db.collection.name", event.Target))
+	}
+
+	if event.Statement != "" {
+		attributes = append(attributes, attribute.String("db.query.text", event.Statement))
+	}
+
+	_, span := o.tracer.Start(ctx, strings.TrimSpace(event.Operation+" "+event.Target),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(event.Start),
+		trace.WithAttributes(attributes...),
+	)
+
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+
+	span.End(trace.WithTimestamp(event.Start.Add(event.Duration)))
+}
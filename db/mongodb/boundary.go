@@ -24,6 +24,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/InsideGallery/core/db/instrument"
 	coreerrors "github.com/InsideGallery/core/errors"
 )
 
@@ -90,6 +91,8 @@ type WriteResult struct {
 	UpsertedCount int64
 	InsertedID    any
 	UpsertedID    any
+	// OperationErrors lists the failed operations of BulkWriteDocuments.
+	OperationErrors []OperationError
 }
 
 // DocumentStore is the core-owned MongoDB contract for new consumers.
@@ -103,12 +106,15 @@ type DocumentStore interface {
 }
 
 // FindOneDocument reads one document with core-owned options.
-func (m *MongoClient) FindOneDocument(ctx context.Context, options FindOptions) (DocumentResult, error) {
+func (m *MongoClient) FindOneDocument(ctx context.Context, options FindOptions) (result DocumentResult, err error) {
 	if options.Target == nil {
 		return DocumentResult{}, ErrDocumentTargetIsNotSet
 	}
 
-	err := m.FindOne(ctx, options.Collection, options.Target, options.Filter, options.findOneOptions())
+	done := m.observe(ctx, "find one document", options.Collection)
+	defer func() { done(documentRows(result), err) }()
+
+	err = m.FindOne(ctx, options.Collection, options.Target, options.Filter, options.findOneOptions())
 	if err != nil {
 		return DocumentResult{}, coreerrors.WrapBoundary("mongodb", "find one document", err)
 	}
@@ -117,11 +123,14 @@ func (m *MongoClient) FindOneDocument(ctx context.Context, options FindOptions)
 }
 
 // FindDocuments reads documents with core-owned options.
-func (m *MongoClient) FindDocuments(ctx context.Context, options FindOptions) (DocumentResult, error) {
+func (m *MongoClient) FindDocuments(ctx context.Context, options FindOptions) (result DocumentResult, err error) {
 	if options.Target == nil {
 		return DocumentResult{}, ErrDocumentTargetIsNotSet
 	}
 
+	done := m.observe(ctx, "find documents", options.Collection)
+	defer func() { done(documentRows(result), err) }()
+
 	documents, err := m.Find(ctx, options.Collection, options.Target, options.Filter, options.findOptions())
 	if err != nil {
 		return DocumentResult{}, coreerrors.WrapBoundary("mongodb", "find documents", err)
@@ -135,7 +144,10 @@ func (m *MongoClient) FindDocuments(ctx context.Context, options FindOptions) (D
 }
 
 // Count counts matching documents with core-owned options.
-func (m *MongoClient) Count(ctx context.Context, options CountOptions) (DocumentResult, error) {
+func (m *MongoClient) Count(ctx context.Context, options CountOptions) (result DocumentResult, err error) {
+	done := m.observe(ctx, "count documents", options.Collection)
+	defer func() { done(documentRows(result), err) }()
+
 	count, err := m.CountDocuments(ctx, options.Collection, options.Filter)
 	if err != nil {
 		return DocumentResult{}, coreerrors.WrapBoundary("mongodb", "count documents", err)
@@ -145,21 +157,27 @@ func (m *MongoClient) Count(ctx context.Context, options CountOptions) (Document
 }
 
 // InsertDocument inserts one document with core-owned options.
-func (m *MongoClient) InsertDocument(ctx context.Context, options InsertOptions) (WriteResult, error) {
-	result, err := m.Collection(options.Collection).InsertOne(ctx, options.Document)
+func (m *MongoClient) InsertDocument(ctx context.Context, options InsertOptions) (result WriteResult, err error) {
+	done := m.observe(ctx, "insert document", options.Collection)
+	defer func() { done(writeRows(result), err) }()
+
+	inserted, err := m.Collection(options.Collection).InsertOne(ctx, options.Document)
 	if err != nil {
 		return WriteResult{}, coreerrors.WrapBoundary("mongodb", "insert document", err)
 	}
 
-	return WriteResult{InsertedCount: 1, InsertedID: result.InsertedID}, nil
+	return WriteResult{InsertedCount: 1, InsertedID: inserted.InsertedID}, nil
 }
 
 // UpdateDocuments updates one or many documents with core-owned options.
-func (m *MongoClient) UpdateDocuments(ctx context.Context, options UpdateOptions) (WriteResult, error) {
+func (m *MongoClient) UpdateDocuments(ctx context.Context, options UpdateOptions) (result WriteResult, err error) {
+	done := m.observe(ctx, "update documents", options.Collection)
+	defer func() { done(writeRows(result), err) }()
+
 	updateOptions := mongooptions.Update().SetUpsert(options.Upsert)
 
 	if options.Many {
-		result, err := m.Collection(options.Collection).UpdateMany(
+		updated, err := m.Collection(options.Collection).UpdateMany(
 			ctx,
 			options.Filter,
 			normalizeUpdate(options.Update),
@@ -170,14 +188,14 @@ func (m *MongoClient) UpdateDocuments(ctx context.Context, options UpdateOptions
 		}
 
 		return WriteResult{
-			MatchedCount:  result.MatchedCount,
-			ModifiedCount: result.ModifiedCount,
-			UpsertedCount: result.UpsertedCount,
-			UpsertedID:    result.UpsertedID,
+			MatchedCount:  updated.MatchedCount,
+			ModifiedCount: updated.ModifiedCount,
+			UpsertedCount: updated.UpsertedCount,
+			UpsertedID:    updated.UpsertedID,
 		}, nil
 	}
 
-	result, err := m.Collection(options.Collection).UpdateOne(
+	updated, err := m.Collection(options.Collection).UpdateOne(
 		ctx,
 		options.Filter,
 		normalizeUpdate(options.Update),
@@ -188,30 +206,59 @@ func (m *MongoClient) UpdateDocuments(ctx context.Context, options UpdateOptions
 	}
 
 	return WriteResult{
-		MatchedCount:  result.MatchedCount,
-		ModifiedCount: result.ModifiedCount,
-		UpsertedCount: result.UpsertedCount,
-		UpsertedID:    result.UpsertedID,
+		MatchedCount:  updated.MatchedCount,
+		ModifiedCount: updated.ModifiedCount,
+		UpsertedCount: updated.UpsertedCount,
+		UpsertedID:    updated.UpsertedID,
 	}, nil
 }
 
 // DeleteDocuments deletes one or many documents with core-owned options.
-func (m *MongoClient) DeleteDocuments(ctx context.Context, options DeleteOptions) (WriteResult, error) {
+func (m *MongoClient) DeleteDocuments(ctx context.Context, options DeleteOptions) (result WriteResult, err error) {
+	done := m.observe(ctx, "delete documents", options.Collection)
+	defer func() { done(writeRows(result), err) }()
+
 	if options.Many {
-		result, err := m.Collection(options.Collection).DeleteMany(ctx, options.Filter)
+		deleted, err := m.Collection(options.Collection).DeleteMany(ctx, options.Filter)
 		if err != nil {
 			return WriteResult{}, coreerrors.WrapBoundary("mongodb", "delete documents", err)
 		}
 
-		return WriteResult{DeletedCount: result.DeletedCount}, nil
+		return WriteResult{DeletedCount: deleted.DeletedCount}, nil
 	}
 
-	result, err := m.Collection(options.Collection).DeleteOne(ctx, options.Filter)
+	deleted, err := m.Collection(options.Collection).DeleteOne(ctx, options.Filter)
 	if err != nil {
 		return WriteResult{}, coreerrors.WrapBoundary("mongodb", "delete document", err)
 	}
 
-	return WriteResult{DeletedCount: result.DeletedCount}, nil
+	return WriteResult{DeletedCount: deleted.DeletedCount}, nil
+}
+
+// SetObserver sets the observer of the core-owned document operations; nil uses
+// instrument.Default. Call it before the client is shared.
+func (m *MongoClient) SetObserver(observer instrument.Observer) {
+	m.observer = observer
+}
+
+func (m *MongoClient) observe(ctx context.Context, operation, collection string) func(rows int64, err error) {
+	return instrument.Begin(ctx, m.observer, instrument.Event{
+		System:    instrument.SystemMongoDB,
+		Operation: operation,
+		Target:    collection,
+	})
+}
+
+func documentRows(result DocumentResult) int64 {
+	if result.Document != nil {
+		return 1
+	}
+
+	return int64(len(result.Documents))
+}
+
+func writeRows(result WriteResult) int64 {
+	return result.InsertedCount + result.ModifiedCount + result.UpsertedCount + result.DeletedCount
 }
 
 func (o FindOptions) findOptions() *mongooptions.FindOptions {
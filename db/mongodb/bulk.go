@@ -0,0 +1,153 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// ErrUnknownBulkOperation reports a BulkOperation with an unsupported kind.
+var ErrUnknownBulkOperation = errors.New("unknown bulk operation")
+
+// BulkKind selects the write performed by a BulkOperation.
+type BulkKind int
+
+// Supported bulk operation kinds.
+const (
+	BulkInsert BulkKind = iota
+	BulkUpdate
+	BulkReplace
+	BulkDelete
+)
+
+// BulkOperation is one write of a bulk request. Document is the inserted or replacement
+// document for BulkInsert and BulkReplace and the update for BulkUpdate, wrapped in $set
+// like UpdateDocuments. Many applies BulkUpdate and BulkDelete to every matching document.
+type BulkOperation struct {
+	Kind     BulkKind
+	Filter   any
+	Document any
+	Upsert   bool
+	Many     bool
+}
+
+// BulkOptions is the core-owned input for MongoDB bulk writes. Ordered stops at the first
+// failing operation; unordered attempts every operation and reports each failure.
+type BulkOptions struct {
+	Collection string
+	Operations []BulkOperation
+	Ordered    bool
+}
+
+// OperationError is the failure of one operation of a bulk write.
+type OperationError struct {
+	// Index is the position of the operation in BulkOptions.Operations.
+	Index   int
+	Code    int
+	Message string
+}
+
+// BulkWriteDocuments runs every operation in one bulk request. When some operations fail it
+// returns the counts of the applied ones with one OperationError per failure in
+// WriteResult.OperationErrors, together with the wrapped driver error.
+func (m *MongoClient) BulkWriteDocuments(ctx context.Context, options BulkOptions) (result WriteResult, err error) {
+	if len(options.Operations) == 0 {
+		return WriteResult{}, nil
+	}
+
+	models, err := options.models()
+	if err != nil {
+		return WriteResult{}, err
+	}
+
+	done := m.observe(ctx, "bulk write documents", options.Collection)
+	defer func() { done(writeRows(result), err) }()
+
+	written, err := m.Collection(options.Collection).BulkWrite(
+		ctx,
+		models,
+		mongooptions.BulkWrite().SetOrdered(options.Ordered),
+	)
+
+	return bulkResult(written, err)
+}
+
+func (o BulkOptions) models() ([]mongo.WriteModel, error) {
+	models := make([]mongo.WriteModel, 0, len(o.Operations))
+
+	for _, operation := range o.Operations {
+		model, err := operation.model()
+		if err != nil {
+			return nil, err
+		}
+
+		models = append(models, model)
+	}
+
+	return models, nil
+}
+
+//nolint:ireturn // every kind maps to a different driver model
+func (o BulkOperation) model() (mongo.WriteModel, error) {
+	switch o.Kind {
+	case BulkInsert:
+		return mongo.NewInsertOneModel().SetDocument(o.Document), nil
+	case BulkUpdate:
+		if o.Many {
+			return mongo.NewUpdateManyModel().
+				SetFilter(o.Filter).
+				SetUpdate(normalizeUpdate(o.Document)).
+				SetUpsert(o.Upsert), nil
+		}
+
+		return mongo.NewUpdateOneModel().
+			SetFilter(o.Filter).
+			SetUpdate(normalizeUpdate(o.Document)).
+			SetUpsert(o.Upsert), nil
+	case BulkReplace:
+		return mongo.NewReplaceOneModel().SetFilter(o.Filter).SetReplacement(o.Document).SetUpsert(o.Upsert), nil
+	case BulkDelete:
+		if o.Many {
+			return mongo.NewDeleteManyModel().SetFilter(o.Filter), nil
+		}
+
+		return mongo.NewDeleteOneModel().SetFilter(o.Filter), nil
+	default:
+		return nil, ErrUnknownBulkOperation
+	}
+}
+
+func bulkResult(written *mongo.BulkWriteResult, err error) (WriteResult, error) {
+	var result WriteResult
+
+	if written != nil {
+		result = WriteResult{
+			InsertedCount: written.InsertedCount,
+			MatchedCount:  written.MatchedCount,
+			ModifiedCount: written.ModifiedCount,
+			DeletedCount:  written.DeletedCount,
+			UpsertedCount: written.UpsertedCount,
+		}
+	}
+
+	if err == nil {
+		return result, nil
+	}
+
+	var exception mongo.BulkWriteException
+	if errors.As(err, &exception) {
+		for _, writeError := range exception.WriteErrors {
+			result.OperationErrors = append(result.OperationErrors, OperationError{
+				Index:   writeError.Index,
+				Code:    writeError.Code,
+				Message: writeError.Message,
+			})
+		}
+	}
+
+	return result, coreerrors.WrapBoundary("mongodb", "bulk write documents", err)
+}
@@ -0,0 +1,52 @@
+package mongodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestBulkModels(t *testing.T) {
+	models, err := BulkOptions{Operations: []BulkOperation{
+		{Kind: BulkInsert, Document: Document{"name": "Ada"}},
+		{Kind: BulkUpdate, Filter: Filter{"_id": 1}, Document: Document{"name": "Bob"}, Upsert: true},
+		{Kind: BulkUpdate, Filter: Filter{}, Document: Document{"seen": true}, Many: true},
+		{Kind: BulkReplace, Filter: Filter{"_id": 2}, Document: Document{"name": "Cid"}},
+		{Kind: BulkDelete, Filter: Filter{"_id": 3}},
+		{Kind: BulkDelete, Filter: Filter{}, Many: true},
+	}}.models()
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(models), 6)
+
+	_, isUpdateMany := models[2].(*mongo.UpdateManyModel)
+	_, isDeleteMany := models[5].(*mongo.DeleteManyModel)
+	testutils.Equal(t, isUpdateMany, true)
+	testutils.Equal(t, isDeleteMany, true)
+
+	_, err = BulkOptions{Operations: []BulkOperation{{Kind: BulkKind(42)}}}.models()
+	testutils.Equal(t, errors.Is(err, ErrUnknownBulkOperation), true)
+}
+
+func TestBulkResult(t *testing.T) {
+	written := &mongo.BulkWriteResult{InsertedCount: 2}
+	exception := mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{
+		{WriteError: mongo.WriteError{Index: 1, Code: 11000, Message: "duplicate key"}},
+		{WriteError: mongo.WriteError{Index: 3, Code: 11000, Message: "duplicate key"}},
+	}}
+
+	result, err := bulkResult(written, exception)
+	testutils.Equal(t, result.InsertedCount, int64(2))
+	testutils.Equal(t, result.OperationErrors, []OperationError{
+		{Index: 1, Code: 11000, Message: "duplicate key"},
+		{Index: 3, Code: 11000, Message: "duplicate key"},
+	})
+	testutils.Equal(t, coreerrors.CategoryOf(err), coreerrors.CategoryConflict)
+
+	result, err = bulkResult(written, nil)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(result.OperationErrors), 0)
+}
@@ -0,0 +1,302 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// ErrWatchNameIsNotSet reports a watch with a resume token store but no name to store under.
+var ErrWatchNameIsNotSet = errors.New("watch name is not set")
+
+// DefaultResumeTokenCollection is the collection used by NewCollectionTokenStore when none is given.
+const DefaultResumeTokenCollection = "change_stream_tokens"
+
+// ChangeEvent is the core-owned form of one change stream event.
+type ChangeEvent struct {
+	Operation     string
+	Database      string
+	Collection    string
+	DocumentKey   Document
+	UpdatedFields Document
+	RemovedFields []string
+	ClusterTime   time.Time
+	ResumeToken   []byte
+	fullDocument  bson.Raw
+}
+
+// HasFullDocument reports whether the event carries the changed document.
+func (e ChangeEvent) HasFullDocument() bool {
+	return len(e.fullDocument) > 0
+}
+
+// DecodeFullDocument decodes the changed document into target. Updates only carry it
+// when WatchOptions.FullDocument is set.
+func (e ChangeEvent) DecodeFullDocument(target any) error {
+	if !e.HasFullDocument() {
+		return coreerrors.WrapBoundary("mongodb", "decode change", mongo.ErrNoDocuments)
+	}
+
+	return bson.Unmarshal(e.fullDocument, target)
+}
+
+// ChangeHandler handles one change event. An error stops the watch before the event's
+// resume token is stored, so the event is delivered again by the next watch.
+type ChangeHandler func(ctx context.Context, event ChangeEvent) error
+
+// ResumeTokenStore persists the resume token of a named watch.
+type ResumeTokenStore interface {
+	LoadResumeToken(ctx context.Context, name string) ([]byte, error)
+	SaveResumeToken(ctx context.Context, name string, token []byte) error
+}
+
+// WatchOptions is the core-owned input for WatchChanges.
+type WatchOptions struct {
+	// Name identifies the watch in Tokens.
+	Name string
+	// Collection limits the watch to one collection; empty watches the client database.
+	Collection string
+	// Pipeline is an optional aggregation pipeline applied to the events.
+	Pipeline any
+	// FullDocument looks up the current document for update events.
+	FullDocument bool
+	BatchSize    int32
+	MaxAwaitTime time.Duration
+	// Tokens resumes the watch after the last handled event; nil starts from now.
+	Tokens ResumeTokenStore
+}
+
+// changeStream is the part of mongo.ChangeStream a watch consumes.
+type changeStream interface {
+	Next(ctx context.Context) bool
+	Decode(value any) error
+	ResumeToken() bson.Raw
+	Err() error
+	Close(ctx context.Context) error
+}
+
+// WatchChanges opens a change stream and calls handler for every event until ctx is done,
+// which returns nil, or the handler or stream fails. With Tokens set, the resume token of
+// each handled event is saved and the next WatchChanges with the same Name starts after it.
+// Change streams require a replica set or sharded cluster.
+func (m *MongoClient) WatchChanges(ctx context.Context, options WatchOptions, handler ChangeHandler) error {
+	if options.Tokens != nil && options.Name == "" {
+		return ErrWatchNameIsNotSet
+	}
+
+	streamOptions := mongooptions.ChangeStream()
+
+	if options.FullDocument {
+		streamOptions.SetFullDocument(mongooptions.UpdateLookup)
+	}
+
+	if options.BatchSize > 0 {
+		streamOptions.SetBatchSize(options.BatchSize)
+	}
+
+	if options.MaxAwaitTime > 0 {
+		streamOptions.SetMaxAwaitTime(options.MaxAwaitTime)
+	}
+
+	if options.Tokens != nil {
+		token, err := options.Tokens.LoadResumeToken(ctx, options.Name)
+		if err != nil {
+			return err
+		}
+
+		if len(token) > 0 {
+			streamOptions.SetStartAfter(bson.Raw(token))
+		}
+	}
+
+	pipeline := options.Pipeline
+	if pipeline == nil {
+		pipeline = mongo.Pipeline{}
+	}
+
+	var (
+		stream *mongo.ChangeStream
+		err    error
+	)
+
+	if options.Collection != "" {
+		stream, err = m.Collection(options.Collection).Watch(ctx, pipeline, streamOptions)
+	} else {
+		stream, err = m.Database(m.database).Watch(ctx, pipeline, streamOptions)
+	}
+
+	if err != nil {
+		return coreerrors.WrapBoundary("mongodb", "watch changes", err)
+	}
+
+	return consumeChanges(ctx, stream, options, handler)
+}
+
+func consumeChanges(ctx context.Context, stream changeStream, options WatchOptions, handler ChangeHandler) error {
+	defer func() {
+		_ = stream.Close(context.WithoutCancel(ctx))
+	}()
+
+	for stream.Next(ctx) {
+		var raw changeDocument
+		if err := stream.Decode(&raw); err != nil {
+			return coreerrors.WrapBoundary("mongodb", "decode change", err)
+		}
+
+		event, err := raw.event()
+		if err != nil {
+			return coreerrors.WrapBoundary("mongodb", "decode change", err)
+		}
+
+		event.ResumeToken = append([]byte(nil), stream.ResumeToken()...)
+
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+
+		if options.Tokens != nil {
+			if err := options.Tokens.SaveResumeToken(ctx, options.Name, event.ResumeToken); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := stream.Err(); err != nil && ctx.Err() == nil {
+		return coreerrors.WrapBoundary("mongodb", "watch changes", err)
+	}
+
+	return nil
+}
+
+type changeDocument struct {
+	OperationType string `bson:"operationType"`
+	Namespace     struct {
+		Database   string `bson:"db"`
+		Collection string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey       bson.Raw            `bson:"documentKey"`
+	FullDocument      bson.Raw            `bson:"fullDocument"`
+	ClusterTime       primitive.Timestamp `bson:"clusterTime"`
+	UpdateDescription struct {
+		UpdatedFields bson.Raw `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+func (d changeDocument) event() (ChangeEvent, error) {
+	event := ChangeEvent{
+		Operation:     d.OperationType,
+		Database:      d.Namespace.Database,
+		Collection:    d.Namespace.Collection,
+		RemovedFields: d.UpdateDescription.RemovedFields,
+		fullDocument:  d.FullDocument,
+	}
+
+	if d.ClusterTime.T > 0 {
+		event.ClusterTime = time.Unix(int64(d.ClusterTime.T), 0).UTC()
+	}
+
+	if len(d.DocumentKey) > 0 {
+		if err := bson.Unmarshal(d.DocumentKey, &event.DocumentKey); err != nil {
+			return ChangeEvent{}, err
+		}
+	}
+
+	if len(d.UpdateDescription.UpdatedFields) > 0 {
+		if err := bson.Unmarshal(d.UpdateDescription.UpdatedFields, &event.UpdatedFields); err != nil {
+			return ChangeEvent{}, err
+		}
+	}
+
+	return event, nil
+}
+
+// MemoryTokenStore keeps resume tokens in memory, for tests and single-process watches.
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string][]byte
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: map[string][]byte{}}
+}
+
+// LoadResumeToken returns the token saved under name, or nil.
+func (s *MemoryTokenStore) LoadResumeToken(_ context.Context, name string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tokens[name], nil
+}
+
+// SaveResumeToken saves token under name.
+func (s *MemoryTokenStore) SaveResumeToken(_ context.Context, name string, token []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[name] = append([]byte(nil), token...)
+
+	return nil
+}
+
+// CollectionTokenStore keeps resume tokens in a MongoDB collection, one document per watch name.
+type CollectionTokenStore struct {
+	store      DocumentStore
+	collection string
+}
+
+// NewCollectionTokenStore returns a CollectionTokenStore on collection; empty uses
+// DefaultResumeTokenCollection.
+func NewCollectionTokenStore(store DocumentStore, collection string) *CollectionTokenStore {
+	if collection == "" {
+		collection = DefaultResumeTokenCollection
+	}
+
+	return &CollectionTokenStore{store: store, collection: collection}
+}
+
+type resumeTokenDocument struct {
+	Name  string `bson:"_id"`
+	Token []byte `bson:"token"`
+}
+
+// LoadResumeToken returns the token saved under name, or nil.
+func (s *CollectionTokenStore) LoadResumeToken(ctx context.Context, name string) ([]byte, error) {
+	var document resumeTokenDocument
+
+	_, err := s.store.FindOneDocument(ctx, FindOptions{
+		Collection: s.collection,
+		Filter:     Filter{mongoIDKey: name},
+		Target:     &document,
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return document.Token, nil
+}
+
+// SaveResumeToken saves token under name.
+func (s *CollectionTokenStore) SaveResumeToken(ctx context.Context, name string, token []byte) error {
+	_, err := s.store.UpdateDocuments(ctx, UpdateOptions{
+		Collection: s.collection,
+		Filter:     Filter{mongoIDKey: name},
+		Update:     Document{"token": token, "updated_at": time.Now().UTC()},
+		Upsert:     true,
+	})
+
+	return err
+}
@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type fakeChangeStream struct {
+	events  []bson.Raw
+	current int
+	closed  bool
+}
+
+func (s *fakeChangeStream) Next(context.Context) bool {
+	s.current++
+
+	return s.current <= len(s.events)
+}
+
+func (s *fakeChangeStream) Decode(value any) error {
+	return bson.Unmarshal(s.events[s.current-1], value)
+}
+
+func (s *fakeChangeStream) ResumeToken() bson.Raw {
+	token, _ := bson.Marshal(bson.D{{Key: "_data", Value: s.current}})
+
+	return token
+}
+
+func (s *fakeChangeStream) Err() error { return nil }
+
+func (s *fakeChangeStream) Close(context.Context) error {
+	s.closed = true
+
+	return nil
+}
+
+func newFakeChangeStream(t *testing.T, documents ...bson.D) *fakeChangeStream {
+	t.Helper()
+
+	stream := &fakeChangeStream{}
+
+	for _, document := range documents {
+		raw, err := bson.Marshal(document)
+		testutils.Equal(t, err, nil)
+
+		stream.events = append(stream.events, raw)
+	}
+
+	return stream
+}
+
+func TestConsumeChanges(t *testing.T) {
+	ctx := context.Background()
+	insert := bson.D{
+		{Key: "operationType", Value: "insert"},
+		{Key: "ns", Value: bson.D{{Key: "db", Value: "app"}, {Key: "coll", Value: "accounts"}}},
+		{Key: "documentKey", Value: bson.D{{Key: "_id", Value: "a"}}},
+		{Key: "fullDocument", Value: bson.D{{Key: "_id", Value: "a"}, {Key: "name", Value: "Ada"}}},
+	}
+	update := bson.D{
+		{Key: "operationType", Value: "update"},
+		{Key: "documentKey", Value: bson.D{{Key: "_id", Value: "a"}}},
+		{Key: "updateDescription", Value: bson.D{
+			{Key: "updatedFields", Value: bson.D{{Key: "name", Value: "Bob"}}},
+			{Key: "removedFields", Value: bson.A{"age"}},
+		}},
+	}
+
+	t.Run("delivers events and saves tokens", func(t *testing.T) {
+		stream := newFakeChangeStream(t, insert, update)
+		tokens := NewMemoryTokenStore()
+
+		var events []ChangeEvent
+
+		err := consumeChanges(ctx, stream, WatchOptions{Name: "accounts", Tokens: tokens}, func(_ context.Context, event ChangeEvent) error {
+			events = append(events, event)
+
+			return nil
+		})
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, stream.closed, true)
+		testutils.Equal(t, len(events), 2)
+		testutils.Equal(t, events[0].Collection, "accounts")
+		testutils.Equal(t, events[0].DocumentKey, Document{"_id": "a"})
+		testutils.Equal(t, events[1].UpdatedFields, Document{"name": "Bob"})
+		testutils.Equal(t, events[1].RemovedFields, []string{"age"})
+		testutils.Equal(t, events[1].HasFullDocument(), false)
+
+		var account testAccount
+		testutils.Equal(t, events[0].DecodeFullDocument(&account), nil)
+		testutils.Equal(t, account.Name, "Ada")
+
+		token, err := tokens.LoadResumeToken(ctx, "accounts")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, token, events[1].ResumeToken)
+	})
+
+	t.Run("handler error keeps previous token", func(t *testing.T) {
+		stream := newFakeChangeStream(t, insert, update)
+		tokens := NewMemoryTokenStore()
+		errBoom := errors.New("boom")
+
+		err := consumeChanges(ctx, stream, WatchOptions{Name: "accounts", Tokens: tokens}, func(_ context.Context, event ChangeEvent) error {
+			if event.Operation == "update" {
+				return errBoom
+			}
+
+			return nil
+		})
+		testutils.Equal(t, errors.Is(err, errBoom), true)
+
+		token, _ := tokens.LoadResumeToken(ctx, "accounts")
+		failed := stream.ResumeToken()
+		testutils.Equal(t, len(token) > 0, true)
+		testutils.Equal(t, bson.Raw(token).String() != failed.String(), true)
+	})
+}
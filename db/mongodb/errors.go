@@ -1,8 +1,30 @@
 package mongodb
 
-import "errors"
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
 
 // All kind of errors for mongo
 var (
 	ErrConnectionIsNotSet = errors.New("connection is not set")
 )
+
+func init() {
+	coreerrors.RegisterClassifier(classifyError)
+}
+
+// classifyError maps missing documents, duplicate keys and version conflicts to core error categories.
+func classifyError(err error) coreerrors.Category {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return coreerrors.CategoryNotFound
+	case mongo.IsDuplicateKeyError(err), errors.Is(err, ErrVersionConflict):
+		return coreerrors.CategoryConflict
+	default:
+		return coreerrors.CategoryUnknown
+	}
+}
@@ -0,0 +1,45 @@
+package mongodb
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestErrorCategory(t *testing.T) {
+	t.Parallel()
+
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "duplicate key"}}}
+
+	cases := []struct {
+		name string
+		err  error
+		want coreerrors.Category
+	}{
+		{
+			name: "missing document",
+			err:  coreerrors.WrapBoundary("mongodb", "find one", mongo.ErrNoDocuments),
+			want: coreerrors.CategoryNotFound,
+		},
+		{
+			name: "duplicate key",
+			err:  coreerrors.WrapBoundary("mongodb", "insert one", duplicate),
+			want: coreerrors.CategoryConflict,
+		},
+		{name: "version conflict", err: ErrVersionConflict, want: coreerrors.CategoryConflict},
+		{name: "other", err: errors.New("boom"), want: coreerrors.CategoryInternal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := coreerrors.CategoryOf(test.err); got != test.want {
+				t.Fatalf("CategoryOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
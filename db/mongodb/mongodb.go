@@ -8,12 +8,15 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"github.com/InsideGallery/core/db/instrument"
 )
 
 // MongoClient client for mongo db
 type MongoClient struct {
 	*mongo.Client
 	database string
+	observer instrument.Observer
 }
 
 // NewMongoClient return client from config
@@ -57,6 +60,7 @@ func (m *MongoClient) WithDB(name string) Client {
 	return &MongoClient{
 		database: name,
 		Client:   m.Client,
+		observer: m.observer,
 	}
 }
 
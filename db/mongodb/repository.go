@@ -0,0 +1,639 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/InsideGallery/core/ecs"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Repository errors.
+var (
+	ErrVersionConflict     = errors.New("document version conflict")
+	ErrDocumentIDIsNotSet  = errors.New("document _id is not set")
+	ErrInvalidCursor       = errors.New("invalid page cursor")
+	ErrIndexKeysAreNotSet  = errors.New("index keys are not set")
+	ErrCollectionIsNotSet  = errors.New("collection is not set")
+	ErrSortFieldIsNotFound = errors.New("sort field is not found in document")
+)
+
+const (
+	defaultVersionField   = "version"
+	defaultCreatedAtField = "created_at"
+	defaultUpdatedAtField = "updated_at"
+	defaultDeletedAtField = "deleted_at"
+	defaultPageLimit      = 20
+
+	mongoUnsetKey = "$unset"
+	mongoIncKey   = "$inc"
+	mongoOrKey    = "$or"
+	mongoAndKey   = "$and"
+	mongoGtKey    = "$gt"
+	mongoLtKey    = "$lt"
+)
+
+// Index declares a collection index ensured by EnsureIndexes.
+type Index struct {
+	Name          string
+	Keys          []SortField
+	Unique        bool
+	Sparse        bool
+	ExpireAfter   time.Duration
+	PartialFilter any
+}
+
+// IndexManager creates missing collection indexes.
+type IndexManager interface {
+	EnsureIndexes(ctx context.Context, collection string, indexes ...Index) error
+}
+
+// RepositoryStore is the store a Repository works on. MongoClient implements it.
+type RepositoryStore interface {
+	DocumentStore
+	IndexManager
+}
+
+// RepositoryOptions configures a Repository.
+type RepositoryOptions struct {
+	Collection string
+	// VersionField stores the ecs.Versionable version when *T implements it. Default "version".
+	VersionField string
+	// Timestamps sets CreatedAtField on insert and UpdatedAtField on every write.
+	Timestamps     bool
+	CreatedAtField string
+	UpdatedAtField string
+	// SoftDelete makes Delete set DeletedAtField and hides deleted documents from reads.
+	SoftDelete     bool
+	DeletedAtField string
+	Indexes        []Index
+	// Now returns the timestamp written by the repository. Default time.Now in UTC.
+	Now func() time.Time
+}
+
+// Pagination mirrors webserver.Pagination field for field, so
+// webserver.Pagination(page.Pagination) converts it without copying.
+type Pagination struct {
+	Total   int `json:"total"`
+	Page    int `json:"page"`
+	Pages   int `json:"pages"`
+	PerPage int `json:"per_page"`
+}
+
+// PageRequest selects one keyset page. Cursor is the NextCursor of the previous page.
+type PageRequest struct {
+	Filter     Filter
+	SortBy     string
+	Descending bool
+	Limit      int64
+	Cursor     string
+}
+
+// Page is one keyset page of typed documents.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	HasMore    bool
+	Pagination Pagination
+}
+
+// Repository is a typed collection on top of a RepositoryStore with optimistic
+// concurrency, soft delete, timestamps and keyset pagination. T must be a struct
+// with bson tags and an `_id` field.
+type Repository[T any] struct {
+	store   RepositoryStore
+	options RepositoryOptions
+}
+
+// NewRepository returns a Repository of T in options.Collection.
+func NewRepository[T any](store RepositoryStore, options RepositoryOptions) *Repository[T] {
+	if options.VersionField == "" {
+		options.VersionField = defaultVersionField
+	}
+
+	if options.CreatedAtField == "" {
+		options.CreatedAtField = defaultCreatedAtField
+	}
+
+	if options.UpdatedAtField == "" {
+		options.UpdatedAtField = defaultUpdatedAtField
+	}
+
+	if options.DeletedAtField == "" {
+		options.DeletedAtField = defaultDeletedAtField
+	}
+
+	if options.Now == nil {
+		options.Now = func() time.Time { return time.Now().UTC() }
+	}
+
+	return &Repository[T]{store: store, options: options}
+}
+
+// EnsureIndexes creates the declared indexes. Call it once at startup.
+func (r *Repository[T]) EnsureIndexes(ctx context.Context) error {
+	return r.store.EnsureIndexes(ctx, r.options.Collection, r.options.Indexes...)
+}
+
+// Insert inserts item, setting timestamps when enabled.
+func (r *Repository[T]) Insert(ctx context.Context, item *T) (WriteResult, error) {
+	document, err := toDocument(item)
+	if err != nil {
+		return WriteResult{}, err
+	}
+
+	if r.options.Timestamps {
+		now := r.options.Now()
+		document = setField(document, r.options.CreatedAtField, now)
+		document = setField(document, r.options.UpdatedAtField, now)
+	}
+
+	return r.store.InsertDocument(ctx, InsertOptions{Collection: r.options.Collection, Document: document})
+}
+
+// FindByID returns the document with the given _id. A missing document returns an
+// error wrapping mongo.ErrNoDocuments, classified as not found.
+func (r *Repository[T]) FindByID(ctx context.Context, id any) (T, error) {
+	return r.FindOne(ctx, Filter{mongoIDKey: id})
+}
+
+// FindOne returns the first document matching filter.
+func (r *Repository[T]) FindOne(ctx context.Context, filter Filter) (T, error) {
+	var item T
+
+	_, err := r.store.FindOneDocument(ctx, FindOptions{
+		Collection: r.options.Collection,
+		Filter:     r.scope(filter),
+		Target:     &item,
+	})
+
+	return item, err
+}
+
+// Find returns every document matching filter in sort order.
+func (r *Repository[T]) Find(ctx context.Context, filter Filter, sort ...SortField) ([]T, error) {
+	options := FindOptions{Collection: r.options.Collection, Filter: r.scope(filter)}
+	if len(sort) > 0 {
+		options.Sort = NewSort(sort...)
+	}
+
+	return r.find(ctx, options)
+}
+
+// Count counts documents matching filter.
+func (r *Repository[T]) Count(ctx context.Context, filter Filter) (int64, error) {
+	result, err := r.store.Count(ctx, CountOptions{Collection: r.options.Collection, Filter: r.scope(filter)})
+
+	return result.Count, err
+}
+
+// Update replaces the fields of item by its _id. When *T implements ecs.Versionable the
+// write only applies if the stored version still equals item's version, which is
+// incremented once the write applied; a concurrent change returns ErrVersionConflict and
+// the caller must reload item before retrying. A failed write leaves item unchanged.
+func (r *Repository[T]) Update(ctx context.Context, item *T) error {
+	document, err := toDocument(item)
+	if err != nil {
+		return err
+	}
+
+	id, ok := getField(document, mongoIDKey)
+	if !ok {
+		return ErrDocumentIDIsNotSet
+	}
+
+	filter := Filter{mongoIDKey: id}
+
+	versionable, versioned := any(item).(ecs.Versionable)
+	if versioned {
+		filter[r.options.VersionField] = versionable.GetVersion()
+
+		// Encode the next version from a copy, so item only changes once the write applied.
+		next := *item
+		if nextVersionable, ok := any(&next).(ecs.Versionable); ok {
+			nextVersionable.UpVersion()
+		}
+
+		if document, err = toDocument(&next); err != nil {
+			return err
+		}
+	}
+
+	document = removeField(document, mongoIDKey)
+	if r.options.Timestamps {
+		document = removeField(document, r.options.CreatedAtField)
+		document = setField(document, r.options.UpdatedAtField, r.options.Now())
+	}
+
+	result, err := r.store.UpdateDocuments(ctx, UpdateOptions{
+		Collection: r.options.Collection,
+		Filter:     r.scope(filter),
+		Update:     bson.D{{Key: mongoSetKey, Value: document}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount > 0 {
+		if versioned {
+			versionable.UpVersion()
+		}
+
+		return nil
+	}
+
+	return r.missing(ctx, id, versioned)
+}
+
+// UpdateByID sets fields on the document with the given _id. It increments the version
+// of versioned documents so that concurrent Update calls detect the change.
+func (r *Repository[T]) UpdateByID(ctx context.Context, id any, fields Document) error {
+	set := make(bson.D, 0, len(fields)+1)
+	for key, value := range fields {
+		set = append(set, bson.E{Key: key, Value: value})
+	}
+
+	if r.options.Timestamps {
+		set = setField(set, r.options.UpdatedAtField, r.options.Now())
+	}
+
+	update := bson.D{{Key: mongoSetKey, Value: set}}
+	if r.versioned() {
+		update = append(update, bson.E{Key: mongoIncKey, Value: bson.D{{Key: r.options.VersionField, Value: 1}}})
+	}
+
+	result, err := r.store.UpdateDocuments(ctx, UpdateOptions{
+		Collection: r.options.Collection,
+		Filter:     r.scope(Filter{mongoIDKey: id}),
+		Update:     update,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	return r.missing(ctx, id, false)
+}
+
+// Delete removes the document with the given _id, or marks it deleted when soft delete is enabled.
+func (r *Repository[T]) Delete(ctx context.Context, id any) error {
+	if !r.options.SoftDelete {
+		return r.Purge(ctx, id)
+	}
+
+	now := r.options.Now()
+	set := bson.D{{Key: r.options.DeletedAtField, Value: now}}
+
+	if r.options.Timestamps {
+		set = setField(set, r.options.UpdatedAtField, now)
+	}
+
+	result, err := r.store.UpdateDocuments(ctx, UpdateOptions{
+		Collection: r.options.Collection,
+		Filter:     r.scope(Filter{mongoIDKey: id}),
+		Update:     bson.D{{Key: mongoSetKey, Value: set}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return r.missing(ctx, id, false)
+	}
+
+	return nil
+}
+
+// Restore clears the soft delete mark of the document with the given _id.
+func (r *Repository[T]) Restore(ctx context.Context, id any) error {
+	update := bson.D{{Key: mongoUnsetKey, Value: bson.D{{Key: r.options.DeletedAtField, Value: ""}}}}
+	if r.options.Timestamps {
+		update = append(update, bson.E{Key: mongoSetKey, Value: bson.D{{Key: r.options.UpdatedAtField, Value: r.options.Now()}}})
+	}
+
+	_, err := r.store.UpdateDocuments(ctx, UpdateOptions{
+		Collection: r.options.Collection,
+		Filter:     Filter{mongoIDKey: id},
+		Update:     update,
+	})
+
+	return err
+}
+
+// Purge removes the document with the given _id regardless of soft delete.
+func (r *Repository[T]) Purge(ctx context.Context, id any) error {
+	_, err := r.store.DeleteDocuments(ctx, DeleteOptions{
+		Collection: r.options.Collection,
+		Filter:     Filter{mongoIDKey: id},
+	})
+
+	return err
+}
+
+// Page returns the page after request.Cursor ordered by request.SortBy and then _id.
+// The sort field must be present in every document.
+func (r *Repository[T]) Page(ctx context.Context, request PageRequest) (Page[T], error) {
+	if request.SortBy == "" {
+		request.SortBy = mongoIDKey
+	}
+
+	if request.Limit <= 0 {
+		request.Limit = defaultPageLimit
+	}
+
+	state := pageCursor{Key: request.SortBy, Page: 1}
+	if request.Cursor != "" {
+		decoded, err := decodeCursor(request.Cursor)
+		if err != nil || decoded.Key != request.SortBy {
+			return Page[T]{}, ErrInvalidCursor
+		}
+
+		state = decoded
+	}
+
+	total, err := r.Count(ctx, request.Filter)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	filter := r.scope(request.Filter)
+	if request.Cursor != "" {
+		filter = bson.D{{Key: mongoAndKey, Value: bson.A{filter, keysetFilter(request, state)}}}
+	}
+
+	items, err := r.find(ctx, FindOptions{
+		Collection: r.options.Collection,
+		Filter:     filter,
+		Sort:       keysetSort(request),
+		Limit:      request.Limit + 1,
+	})
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	page := Page[T]{
+		Items:   items,
+		HasMore: int64(len(items)) > request.Limit,
+		Pagination: Pagination{
+			Total:   int(total),
+			Page:    state.Page,
+			Pages:   int((total + request.Limit - 1) / request.Limit),
+			PerPage: int(request.Limit),
+		},
+	}
+
+	if page.HasMore {
+		page.Items = items[:request.Limit]
+
+		page.NextCursor, err = nextCursor(page.Items[len(page.Items)-1], request.SortBy, state.Page+1)
+		if err != nil {
+			return Page[T]{}, err
+		}
+	}
+
+	return page, nil
+}
+
+func (r *Repository[T]) find(ctx context.Context, options FindOptions) ([]T, error) {
+	options.Target = new(T)
+
+	result, err := r.store.FindDocuments(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, len(result.Documents))
+	for _, document := range result.Documents {
+		switch item := document.(type) {
+		case T:
+			items = append(items, item)
+		case *T:
+			items = append(items, *item)
+		}
+	}
+
+	return items, nil
+}
+
+// missing tells a lost optimistic lock apart from an absent document after a write matched nothing.
+func (r *Repository[T]) missing(ctx context.Context, id any, versioned bool) error {
+	if versioned {
+		count, err := r.Count(ctx, Filter{mongoIDKey: id})
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return ErrVersionConflict
+		}
+	}
+
+	return coreerrors.WrapBoundary("mongodb", "update document", mongo.ErrNoDocuments)
+}
+
+func (r *Repository[T]) versioned() bool {
+	_, ok := any(new(T)).(ecs.Versionable)
+
+	return ok
+}
+
+// scope hides soft-deleted documents from filter.
+func (r *Repository[T]) scope(filter Filter) any {
+	if !r.options.SoftDelete {
+		if filter == nil {
+			return Filter{}
+		}
+
+		return filter
+	}
+
+	notDeleted := Filter{r.options.DeletedAtField: nil}
+	if len(filter) == 0 {
+		return notDeleted
+	}
+
+	return bson.D{{Key: mongoAndKey, Value: bson.A{filter, notDeleted}}}
+}
+
+type pageCursor struct {
+	Key   string        `bson:"k"`
+	Value bson.RawValue `bson:"v"`
+	ID    bson.RawValue `bson:"id"`
+	Page  int           `bson:"p"`
+}
+
+func nextCursor(item any, sortBy string, page int) (string, error) {
+	raw, err := bson.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := bson.Raw(raw).LookupErr(mongoIDKey)
+	if err != nil {
+		return "", ErrDocumentIDIsNotSet
+	}
+
+	value, err := bson.Raw(raw).LookupErr(strings.Split(sortBy, ".")...)
+	if err != nil {
+		return "", ErrSortFieldIsNotFound
+	}
+
+	encoded, err := bson.Marshal(pageCursor{Key: sortBy, Value: value, ID: id, Page: page})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+func decodeCursor(cursor string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pageCursor{}, err
+	}
+
+	var state pageCursor
+	if err := bson.Unmarshal(raw, &state); err != nil {
+		return pageCursor{}, err
+	}
+
+	return state, nil
+}
+
+func keysetSort(request PageRequest) any {
+	sort := []SortField{{Name: request.SortBy, Descending: request.Descending}}
+	if request.SortBy != mongoIDKey {
+		sort = append(sort, SortField{Name: mongoIDKey, Descending: request.Descending})
+	}
+
+	return NewSort(sort...)
+}
+
+func keysetFilter(request PageRequest, state pageCursor) bson.D {
+	operator := mongoGtKey
+	if request.Descending {
+		operator = mongoLtKey
+	}
+
+	if request.SortBy == mongoIDKey {
+		return bson.D{{Key: mongoIDKey, Value: bson.D{{Key: operator, Value: state.ID}}}}
+	}
+
+	return bson.D{{Key: mongoOrKey, Value: bson.A{
+		bson.D{{Key: request.SortBy, Value: bson.D{{Key: operator, Value: state.Value}}}},
+		bson.D{
+			{Key: request.SortBy, Value: state.Value},
+			{Key: mongoIDKey, Value: bson.D{{Key: operator, Value: state.ID}}},
+		},
+	}}}
+}
+
+// EnsureIndexes creates indexes on collection; existing identical indexes are left as they are.
+func (m *MongoClient) EnsureIndexes(ctx context.Context, collection string, indexes ...Index) (err error) {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	if collection == "" {
+		return ErrCollectionIsNotSet
+	}
+
+	models := make([]mongo.IndexModel, 0, len(indexes))
+
+	for _, index := range indexes {
+		if len(index.Keys) == 0 {
+			return ErrIndexKeysAreNotSet
+		}
+
+		models = append(models, mongo.IndexModel{Keys: NewSort(index.Keys...), Options: index.options()})
+	}
+
+	done := m.observe(ctx, "ensure indexes", collection)
+	defer func() { done(int64(len(models)), err) }()
+
+	_, err = m.Collection(collection).Indexes().CreateMany(ctx, models)
+
+	return coreerrors.WrapBoundary("mongodb", "ensure indexes", err)
+}
+
+func (i Index) options() *mongooptions.IndexOptions {
+	options := mongooptions.Index()
+
+	if i.Name != "" {
+		options.SetName(i.Name)
+	}
+
+	if i.Unique {
+		options.SetUnique(true)
+	}
+
+	if i.Sparse {
+		options.SetSparse(true)
+	}
+
+	if i.ExpireAfter > 0 {
+		options.SetExpireAfterSeconds(int32(i.ExpireAfter / time.Second))
+	}
+
+	if i.PartialFilter != nil {
+		options.SetPartialFilterExpression(i.PartialFilter)
+	}
+
+	return options
+}
+
+func toDocument(value any) (bson.D, error) {
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var document bson.D
+	if err := bson.Unmarshal(raw, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func getField(document bson.D, key string) (any, bool) {
+	for _, element := range document {
+		if element.Key == key {
+			return element.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func setField(document bson.D, key string, value any) bson.D {
+	for i, element := range document {
+		if element.Key == key {
+			document[i].Value = value
+
+			return document
+		}
+	}
+
+	return append(document, bson.E{Key: key, Value: value})
+}
+
+func removeField(document bson.D, key string) bson.D {
+	for i, element := range document {
+		if element.Key == key {
+			return append(document[:i], document[i+1:]...)
+		}
+	}
+
+	return document
+}
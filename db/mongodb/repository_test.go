@@ -0,0 +1,233 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/InsideGallery/core/server/webserver"
+)
+
+type testAccount struct {
+	ID      string `bson:"_id"`
+	Name    string `bson:"name"`
+	Version uint64 `bson:"version"`
+}
+
+func (a *testAccount) GetVersion() uint64 { return a.Version }
+
+func (a *testAccount) UpVersion() { a.Version++ }
+
+type fakeRepositoryStore struct {
+	documents []any
+	matched   int64
+	count     int64
+	finds     []FindOptions
+	updates   []UpdateOptions
+	inserts   []InsertOptions
+	deletes   []DeleteOptions
+	indexes   []Index
+	err       error
+}
+
+func (s *fakeRepositoryStore) FindOneDocument(_ context.Context, options FindOptions) (DocumentResult, error) {
+	s.finds = append(s.finds, options)
+	if len(s.documents) == 0 {
+		return DocumentResult{}, mongo.ErrNoDocuments
+	}
+
+	*options.Target.(*testAccount) = s.documents[0].(testAccount)
+
+	return DocumentResult{Found: true, Document: options.Target}, nil
+}
+
+func (s *fakeRepositoryStore) FindDocuments(_ context.Context, options FindOptions) (DocumentResult, error) {
+	s.finds = append(s.finds, options)
+
+	documents := s.documents
+	if options.Limit > 0 && int64(len(documents)) > options.Limit {
+		documents = documents[:options.Limit]
+	}
+
+	return DocumentResult{Found: len(documents) > 0, Documents: documents, Count: int64(len(documents))}, nil
+}
+
+func (s *fakeRepositoryStore) Count(context.Context, CountOptions) (DocumentResult, error) {
+	return DocumentResult{Found: s.count > 0, Count: s.count}, nil
+}
+
+func (s *fakeRepositoryStore) InsertDocument(_ context.Context, options InsertOptions) (WriteResult, error) {
+	s.inserts = append(s.inserts, options)
+
+	return WriteResult{InsertedCount: 1}, nil
+}
+
+func (s *fakeRepositoryStore) UpdateDocuments(_ context.Context, options UpdateOptions) (WriteResult, error) {
+	s.updates = append(s.updates, options)
+	if s.err != nil {
+		return WriteResult{}, s.err
+	}
+
+	return WriteResult{MatchedCount: s.matched, ModifiedCount: s.matched}, nil
+}
+
+func (s *fakeRepositoryStore) DeleteDocuments(_ context.Context, options DeleteOptions) (WriteResult, error) {
+	s.deletes = append(s.deletes, options)
+
+	return WriteResult{DeletedCount: 1}, nil
+}
+
+func (s *fakeRepositoryStore) EnsureIndexes(_ context.Context, _ string, indexes ...Index) error {
+	s.indexes = append(s.indexes, indexes...)
+
+	return nil
+}
+
+func newTestRepository(store *fakeRepositoryStore, softDelete bool) *Repository[testAccount] {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	return NewRepository[testAccount](store, RepositoryOptions{
+		Collection: "accounts",
+		Timestamps: true,
+		SoftDelete: softDelete,
+		Indexes:    []Index{{Keys: []SortField{{Name: "name"}}, Unique: true}},
+		Now:        func() time.Time { return now },
+	})
+}
+
+func TestRepositoryWrites(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("insert sets timestamps", func(t *testing.T) {
+		store := &fakeRepositoryStore{}
+		repository := newTestRepository(store, false)
+
+		_, err := repository.Insert(ctx, &testAccount{ID: "a", Name: "Ada"})
+		testutils.Equal(t, err, nil)
+
+		document := store.inserts[0].Document.(bson.D)
+		created, _ := getField(document, "created_at")
+		updated, _ := getField(document, "updated_at")
+		testutils.Equal(t, created, any(now))
+		testutils.Equal(t, updated, any(now))
+	})
+
+	t.Run("update checks and bumps version", func(t *testing.T) {
+		store := &fakeRepositoryStore{matched: 1}
+		repository := newTestRepository(store, false)
+		account := &testAccount{ID: "a", Name: "Ada", Version: 3}
+
+		testutils.Equal(t, repository.Update(ctx, account), nil)
+		testutils.Equal(t, account.Version, uint64(4))
+
+		update := store.updates[0]
+		testutils.Equal(t, update.Filter.(Filter)["version"], any(uint64(3)))
+
+		set := update.Update.(bson.D)[0].Value.(bson.D)
+		version, _ := getField(set, "version")
+		_, hasID := getField(set, "_id")
+		_, hasCreated := getField(set, "created_at")
+		testutils.Equal(t, version, any(int64(4)))
+		testutils.Equal(t, hasID, false)
+		testutils.Equal(t, hasCreated, false)
+	})
+
+	t.Run("update reports conflict when document exists", func(t *testing.T) {
+		store := &fakeRepositoryStore{count: 1}
+		repository := newTestRepository(store, false)
+
+		account := &testAccount{ID: "a", Version: 1}
+
+		err := repository.Update(ctx, account)
+		testutils.Equal(t, errors.Is(err, ErrVersionConflict), true)
+		testutils.Equal(t, account.Version, uint64(1))
+	})
+
+	t.Run("failed update keeps version", func(t *testing.T) {
+		store := &fakeRepositoryStore{err: errors.New("network")}
+		repository := newTestRepository(store, false)
+		account := &testAccount{ID: "a", Version: 1}
+
+		testutils.Equal(t, repository.Update(ctx, account) != nil, true)
+		testutils.Equal(t, account.Version, uint64(1))
+	})
+
+	t.Run("update reports missing document", func(t *testing.T) {
+		store := &fakeRepositoryStore{}
+		repository := newTestRepository(store, false)
+
+		err := repository.Update(ctx, &testAccount{ID: "a", Version: 1})
+		testutils.Equal(t, errors.Is(err, mongo.ErrNoDocuments), true)
+	})
+
+	t.Run("soft delete marks and scopes reads", func(t *testing.T) {
+		store := &fakeRepositoryStore{matched: 1}
+		repository := newTestRepository(store, true)
+
+		testutils.Equal(t, repository.Delete(ctx, "a"), nil)
+		testutils.Equal(t, len(store.deletes), 0)
+
+		set := store.updates[0].Update.(bson.D)[0].Value.(bson.D)
+		deleted, _ := getField(set, "deleted_at")
+		testutils.Equal(t, deleted, any(now))
+
+		_, err := repository.Find(ctx, nil)
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, store.finds[0].Filter, any(Filter{"deleted_at": nil}))
+
+		testutils.Equal(t, repository.Purge(ctx, "a"), nil)
+		testutils.Equal(t, len(store.deletes), 1)
+	})
+
+	t.Run("ensure indexes forwards declarations", func(t *testing.T) {
+		store := &fakeRepositoryStore{}
+		repository := newTestRepository(store, false)
+
+		testutils.Equal(t, repository.EnsureIndexes(ctx), nil)
+		testutils.Equal(t, len(store.indexes), 1)
+		testutils.Equal(t, store.indexes[0].Unique, true)
+	})
+}
+
+func TestRepositoryPage(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeRepositoryStore{
+		count: 3,
+		documents: []any{
+			testAccount{ID: "a", Name: "Ada"},
+			testAccount{ID: "b", Name: "Bob"},
+			testAccount{ID: "c", Name: "Cid"},
+		},
+	}
+	repository := newTestRepository(store, false)
+
+	first, err := repository.Page(ctx, PageRequest{SortBy: "name", Limit: 2})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(first.Items), 2)
+	testutils.Equal(t, first.HasMore, true)
+	testutils.Equal(t, first.NextCursor != "", true)
+	testutils.Equal(t, webserver.Pagination(first.Pagination), webserver.Pagination{
+		Total: 3, Page: 1, Pages: 2, PerPage: 2,
+	})
+	testutils.Equal(t, store.finds[0].Limit, int64(3))
+
+	store.documents = store.documents[2:]
+
+	second, err := repository.Page(ctx, PageRequest{SortBy: "name", Limit: 2, Cursor: first.NextCursor})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(second.Items), 1)
+	testutils.Equal(t, second.HasMore, false)
+	testutils.Equal(t, second.Pagination.Page, 2)
+
+	and := store.finds[1].Filter.(bson.D)[0]
+	testutils.Equal(t, and.Key, "$and")
+
+	_, err = repository.Page(ctx, PageRequest{SortBy: "_id", Cursor: first.NextCursor})
+	testutils.Equal(t, errors.Is(err, ErrInvalidCursor), true)
+}
@@ -0,0 +1,132 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/InsideGallery/core/db/retry"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Transaction defaults.
+const (
+	DefaultTxMaxRetries = retry.DefaultMaxRetries
+	DefaultTxRetryDelay = retry.DefaultDelay
+)
+
+// MongoDB error labels that make a transaction or its commit safe to retry.
+const (
+	labelTransientTransaction     = "TransientTransactionError"
+	labelUnknownTransactionCommit = "UnknownTransactionCommitResult"
+)
+
+// TxOptions is the core-owned input for WithTransaction.
+type TxOptions struct {
+	// MaxRetries is how many times the whole transaction is retried after a transient
+	// transaction error; zero uses DefaultTxMaxRetries and a negative value disables retries.
+	// A commit with an unknown result is retried the same way.
+	MaxRetries int
+	// RetryDelay is multiplied by the attempt number between retries; zero uses DefaultTxRetryDelay.
+	RetryDelay time.Duration
+}
+
+// txSession is the part of mongo.Session a transaction attempt drives.
+type txSession interface {
+	StartTransaction(opts ...*mongooptions.TransactionOptions) error
+	AbortTransaction(ctx context.Context) error
+	CommitTransaction(ctx context.Context) error
+}
+
+// WithTransaction runs fn in a multi-document transaction carried by the context passed
+// to fn, so every MongoClient and Repository call using that context joins it. fn
+// returning an error aborts; otherwise the transaction commits.
+//
+// Errors labelled TransientTransactionError re-run fn from the start, and a commit
+// labelled UnknownTransactionCommitResult is retried, so fn must not have side effects
+// outside the database. A WithTransaction call inside fn joins the outer transaction.
+// Transactions require a replica set or sharded cluster.
+func (m *MongoClient) WithTransaction(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	if InTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	session, err := m.StartSession()
+	if err != nil {
+		return coreerrors.WrapBoundary("mongodb", "start session", err)
+	}
+	defer session.EndSession(ctx)
+
+	return runTransaction(ctx, session, mongo.NewSessionContext(ctx, session), opts, fn)
+}
+
+// InTransaction reports whether ctx carries a MongoDB session with an open transaction scope.
+func InTransaction(ctx context.Context) bool {
+	return mongo.SessionFromContext(ctx) != nil
+}
+
+// IsTransient reports whether err carries the TransientTransactionError label.
+func IsTransient(err error) bool {
+	return hasErrorLabel(err, labelTransientTransaction)
+}
+
+func runTransaction(
+	ctx context.Context,
+	session txSession,
+	sessionCtx context.Context,
+	opts TxOptions,
+	fn func(ctx context.Context) error,
+) error {
+	policy := retry.Policy{MaxRetries: opts.MaxRetries, Delay: opts.RetryDelay}
+
+	return retry.Do(ctx, policy, IsTransient, func(int) error {
+		return runTransactionAttempt(sessionCtx, session, policy, fn)
+	})
+}
+
+func runTransactionAttempt(
+	ctx context.Context,
+	session txSession,
+	policy retry.Policy,
+	fn func(ctx context.Context) error,
+) error {
+	if err := session.StartTransaction(); err != nil {
+		return coreerrors.WrapBoundary("mongodb", "start transaction", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_ = session.AbortTransaction(context.WithoutCancel(ctx))
+
+			panic(recovered)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		if abortErr := session.AbortTransaction(context.WithoutCancel(ctx)); abortErr != nil {
+			return errors.Join(err, coreerrors.WrapBoundary("mongodb", "abort transaction", abortErr))
+		}
+
+		return err
+	}
+
+	err := retry.Do(ctx, policy, isUnknownCommitResult, func(int) error {
+		return session.CommitTransaction(ctx)
+	})
+
+	return coreerrors.WrapBoundary("mongodb", "commit transaction", err)
+}
+
+// isUnknownCommitResult reports whether err carries the UnknownTransactionCommitResult label.
+func isUnknownCommitResult(err error) bool {
+	return hasErrorLabel(err, labelUnknownTransactionCommit)
+}
+
+func hasErrorLabel(err error, label string) bool {
+	var labeled mongo.LabeledError
+
+	return errors.As(err, &labeled) && labeled.HasErrorLabel(label)
+}
@@ -0,0 +1,149 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type fakeSession struct {
+	commitErrs []error
+	starts     int
+	aborts     int
+	commits    int
+}
+
+func (s *fakeSession) StartTransaction(...*mongooptions.TransactionOptions) error {
+	s.starts++
+
+	return nil
+}
+
+func (s *fakeSession) AbortTransaction(context.Context) error {
+	s.aborts++
+
+	return nil
+}
+
+func (s *fakeSession) CommitTransaction(context.Context) error {
+	s.commits++
+
+	if len(s.commitErrs) == 0 {
+		return nil
+	}
+
+	err := s.commitErrs[0]
+	s.commitErrs = s.commitErrs[1:]
+
+	return err
+}
+
+func TestRunTransaction(t *testing.T) {
+	ctx := context.Background()
+	errBoom := errors.New("boom")
+	transient := mongo.CommandError{Name: "WriteConflict", Labels: []string{labelTransientTransaction}}
+	unknownCommit := mongo.CommandError{Name: "NetworkError", Labels: []string{labelUnknownTransactionCommit}}
+
+	cases := []struct {
+		name          string
+		session       *fakeSession
+		fnErrs        []error
+		opts          TxOptions
+		wantErr       error
+		wantTransient bool
+		wantStarts    int
+		wantAborts    int
+		wantCommits   int
+	}{
+		{name: "commits", session: &fakeSession{}, wantStarts: 1, wantCommits: 1},
+		{
+			name:       "aborts on error",
+			session:    &fakeSession{},
+			fnErrs:     []error{errBoom},
+			wantErr:    errBoom,
+			wantStarts: 1,
+			wantAborts: 1,
+		},
+		{
+			name:        "retries transient error",
+			session:     &fakeSession{},
+			fnErrs:      []error{transient},
+			wantStarts:  2,
+			wantAborts:  1,
+			wantCommits: 1,
+		},
+		{
+			name:          "negative retries disable retry",
+			session:       &fakeSession{},
+			fnErrs:        []error{transient},
+			opts:          TxOptions{MaxRetries: -1},
+			wantTransient: true,
+			wantStarts:    1,
+			wantAborts:    1,
+		},
+		{
+			name:        "retries unknown commit result",
+			session:     &fakeSession{commitErrs: []error{unknownCommit}},
+			wantStarts:  1,
+			wantCommits: 2,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			fnErrs := test.fnErrs
+			fn := func(context.Context) error {
+				if len(fnErrs) == 0 {
+					return nil
+				}
+
+				err := fnErrs[0]
+				fnErrs = fnErrs[1:]
+
+				return err
+			}
+
+			err := runTransaction(ctx, test.session, ctx, test.opts, fn)
+			if test.wantErr != nil {
+				testutils.Equal(t, errors.Is(err, test.wantErr), true)
+			}
+
+			testutils.Equal(t, err != nil, test.wantErr != nil || test.wantTransient)
+			testutils.Equal(t, IsTransient(err), test.wantTransient)
+			testutils.Equal(t, test.session.starts, test.wantStarts)
+			testutils.Equal(t, test.session.aborts, test.wantAborts)
+			testutils.Equal(t, test.session.commits, test.wantCommits)
+		})
+	}
+}
+
+func TestRunTransactionCommitRetryLimit(t *testing.T) {
+	ctx := context.Background()
+	unknownCommit := mongo.CommandError{Name: "NetworkError", Labels: []string{labelUnknownTransactionCommit}}
+	session := &fakeSession{commitErrs: []error{unknownCommit, unknownCommit, unknownCommit}}
+
+	err := runTransaction(ctx, session, ctx, TxOptions{MaxRetries: 1}, func(context.Context) error {
+		return nil
+	})
+	testutils.Equal(t, isUnknownCommitResult(err), true)
+	testutils.Equal(t, session.commits, 2)
+}
+
+func TestRunTransactionCommitRetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	unknownCommit := mongo.CommandError{Name: "NetworkError", Labels: []string{labelUnknownTransactionCommit}}
+	session := &fakeSession{commitErrs: []error{unknownCommit, unknownCommit}}
+
+	err := runTransaction(ctx, session, ctx, TxOptions{RetryDelay: time.Hour}, func(context.Context) error {
+		cancel()
+
+		return nil
+	})
+	testutils.Equal(t, errors.Is(err, context.Canceled), true)
+	testutils.Equal(t, session.commits, 1)
+}
@@ -7,7 +7,10 @@
 //	client, err := neo4j.NewGraphClient(ctx, neo4j.Options{Host: "neo4j://127.0.0.1:7687"})
 //
 // Prefer Graph, Options, and Result in application-facing code so the Neo4j SDK
-// driver type stays inside this adapter package.
+// driver type stays inside this adapter package. Read and Write run Cypher in
+// retried transactions; Query decodes records into structs, UpsertNodes and
+// UpsertRelationships merge batches with UNWIND, and Session chains transactions
+// with bookmarks.
 //
 // Compatibility: Client and GetConnection remain available for existing
 // SDK-shaped callers. Prefer NewGraphClient for new integrations.
@@ -47,6 +50,8 @@ type Graph interface {
 // GraphClient wraps the Neo4j driver behind core-owned operation inputs and results.
 type GraphClient struct {
 	driver neo4j.DriverWithContext
+	// sessions replaces driver.NewSession in tests.
+	sessions func(ctx context.Context, config neo4j.SessionConfig) session
 }
 
 // NewGraphClient creates a Neo4j graph client from core-owned options.
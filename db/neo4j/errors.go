@@ -0,0 +1,63 @@
+package neo4j
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// All kind of errors for neo4j
+var (
+	ErrRecordIsNotFound = errors.New("record is not found")
+	ErrLabelIsNotSet    = errors.New("label is not set")
+	ErrIDIsNotSet       = errors.New("id is not set")
+	ErrUnsupportedValue = errors.New("unsupported value")
+)
+
+// Neo4j status codes mapped to core error categories.
+const (
+	codeConstraintValidationFailed = "Neo.ClientError.Schema.ConstraintValidationFailed"
+	codeSecurityPrefix             = "Neo.ClientError.Security."
+	codeForbiddenSuffix            = "Forbidden"
+)
+
+func init() {
+	coreerrors.RegisterClassifier(classifyError)
+}
+
+// classifyError maps missing records, constraint violations and security errors to core error categories.
+func classifyError(err error) coreerrors.Category {
+	if errors.Is(err, ErrRecordIsNotFound) {
+		return coreerrors.CategoryNotFound
+	}
+
+	var neo4jErr *neo4j.Neo4jError
+	if !errors.As(err, &neo4jErr) {
+		return coreerrors.CategoryUnknown
+	}
+
+	switch {
+	case neo4jErr.Code == codeConstraintValidationFailed:
+		return coreerrors.CategoryConflict
+	case strings.HasPrefix(neo4jErr.Code, codeSecurityPrefix) && strings.HasSuffix(neo4jErr.Code, codeForbiddenSuffix):
+		return coreerrors.CategoryForbidden
+	case strings.HasPrefix(neo4jErr.Code, codeSecurityPrefix):
+		return coreerrors.CategoryUnauthorized
+	default:
+		return coreerrors.CategoryUnknown
+	}
+}
+
+// IsRetryable reports whether err is a transient, cluster or connectivity error after
+// which the whole transaction may succeed.
+func IsRetryable(err error) bool {
+	var connectivityErr *neo4j.ConnectivityError
+	if errors.As(err, &connectivityErr) {
+		return neo4j.IsRetryable(connectivityErr)
+	}
+
+	return neo4j.IsRetryable(err)
+}
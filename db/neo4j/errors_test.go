@@ -0,0 +1,80 @@
+package neo4j
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestErrorCategory(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want coreerrors.Category
+	}{
+		{name: "missing record", err: ErrRecordIsNotFound, want: coreerrors.CategoryNotFound},
+		{
+			name: "constraint violation",
+			err: coreerrors.WrapBoundary("neo4j", "commit",
+				&neo4j.Neo4jError{Code: "Neo.ClientError.Schema.ConstraintValidationFailed"}),
+			want: coreerrors.CategoryConflict,
+		},
+		{
+			name: "unauthorized",
+			err:  &neo4j.Neo4jError{Code: "Neo.ClientError.Security.Unauthorized"},
+			want: coreerrors.CategoryUnauthorized,
+		},
+		{
+			name: "forbidden",
+			err:  &neo4j.Neo4jError{Code: "Neo.ClientError.Security.Forbidden"},
+			want: coreerrors.CategoryForbidden,
+		},
+		{name: "other", err: errors.New("boom"), want: coreerrors.CategoryInternal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := coreerrors.CategoryOf(test.err); got != test.want {
+				t.Fatalf("CategoryOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "transient", err: &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"}, want: true},
+		{name: "not a leader", err: &neo4j.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader"}, want: true},
+		{name: "syntax", err: &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}, want: false},
+		{
+			name: "wrapped connectivity",
+			err:  coreerrors.WrapBoundary("neo4j", "run", &neo4j.ConnectivityError{Inner: errors.New("reset")}),
+			want: true,
+		},
+		{name: "other", err: errors.New("boom"), want: false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsRetryable(test.err); got != test.want {
+				t.Fatalf("IsRetryable() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package neo4j
+
+import (
+	"context"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// The fakes embed the driver interfaces for their unexported methods and implement only
+// what the client calls.
+
+type fakeCounters struct {
+	neo4j.Counters
+	nodesCreated, relationshipsCreated, propertiesSet int
+}
+
+func (c fakeCounters) NodesCreated() int         { return c.nodesCreated }
+func (c fakeCounters) NodesDeleted() int         { return 0 }
+func (c fakeCounters) RelationshipsCreated() int { return c.relationshipsCreated }
+func (c fakeCounters) RelationshipsDeleted() int { return 0 }
+func (c fakeCounters) PropertiesSet() int        { return c.propertiesSet }
+func (c fakeCounters) LabelsAdded() int          { return 0 }
+func (c fakeCounters) LabelsRemoved() int        { return 0 }
+
+type fakeSummary struct {
+	neo4j.ResultSummary
+	counters fakeCounters
+}
+
+func (s fakeSummary) Counters() neo4j.Counters { return s.counters }
+
+type fakeResult struct {
+	neo4j.ResultWithContext
+	records  []*neo4j.Record
+	counters fakeCounters
+}
+
+func (r *fakeResult) Collect(context.Context) ([]*neo4j.Record, error) { return r.records, nil }
+
+func (r *fakeResult) Consume(context.Context) (neo4j.ResultSummary, error) {
+	return fakeSummary{counters: r.counters}, nil
+}
+
+// record builds a driver record from alternating keys and values.
+func record(pairs ...any) *neo4j.Record {
+	result := &neo4j.Record{}
+
+	for i := 0; i < len(pairs); i += 2 {
+		result.Keys = append(result.Keys, pairs[i].(string))
+		result.Values = append(result.Values, pairs[i+1])
+	}
+
+	return result
+}
+
+type statement struct {
+	cypher string
+	params map[string]any
+}
+
+type fakeTx struct {
+	neo4j.ExplicitTransaction
+	db         *fakeDB
+	committed  bool
+	rolledBack bool
+}
+
+func (t *fakeTx) Run(_ context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error) {
+	t.db.mu.Lock()
+	t.db.statements = append(t.db.statements, statement{cypher: cypher, params: params})
+	t.db.mu.Unlock()
+
+	return t.db.run(cypher, params)
+}
+
+func (t *fakeTx) Commit(context.Context) error {
+	t.committed = true
+
+	return t.db.commitErr
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	t.rolledBack = true
+
+	return nil
+}
+
+type fakeSession struct {
+	db     *fakeDB
+	config neo4j.SessionConfig
+	closed bool
+}
+
+func (s *fakeSession) BeginTransaction(
+	_ context.Context,
+	configurers ...func(*neo4j.TransactionConfig),
+) (neo4j.ExplicitTransaction, error) {
+	config := neo4j.TransactionConfig{}
+	for _, configure := range configurers {
+		configure(&config)
+	}
+
+	tx := &fakeTx{db: s.db}
+
+	s.db.mu.Lock()
+	s.db.txConfigs = append(s.db.txConfigs, config)
+	s.db.txs = append(s.db.txs, tx)
+	s.db.mu.Unlock()
+
+	return tx, nil
+}
+
+func (s *fakeSession) LastBookmarks() neo4j.Bookmarks {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if len(s.db.txs) == 0 || !s.db.txs[len(s.db.txs)-1].committed {
+		return s.config.Bookmarks
+	}
+
+	return neo4j.Bookmarks{"bookmark-" + string(rune('0'+len(s.db.txs)))}
+}
+
+func (s *fakeSession) Close(context.Context) error {
+	s.closed = true
+
+	return nil
+}
+
+// fakeDB records the sessions, transactions and statements of a GraphClient.
+type fakeDB struct {
+	mu         sync.Mutex
+	run        func(cypher string, params map[string]any) (neo4j.ResultWithContext, error)
+	commitErr  error
+	sessions   []*fakeSession
+	txs        []*fakeTx
+	txConfigs  []neo4j.TransactionConfig
+	statements []statement
+}
+
+func newFakeClient(run func(cypher string, params map[string]any) (neo4j.ResultWithContext, error)) (*GraphClient, *fakeDB) {
+	db := &fakeDB{run: run}
+	if db.run == nil {
+		db.run = func(string, map[string]any) (neo4j.ResultWithContext, error) { return &fakeResult{}, nil }
+	}
+
+	client := &GraphClient{sessions: func(_ context.Context, config neo4j.SessionConfig) session {
+		sess := &fakeSession{db: db, config: config}
+
+		db.mu.Lock()
+		db.sessions = append(db.sessions, sess)
+		db.mu.Unlock()
+
+		return sess
+	}}
+
+	return client, db
+}
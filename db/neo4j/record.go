@@ -0,0 +1,454 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// tagName is the struct tag naming the record key or property a field is decoded from.
+const tagName = "neo4j"
+
+// runner is the part of a Neo4j transaction Tx runs Cypher on.
+type runner interface {
+	Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error)
+}
+
+// Tx runs Cypher inside a transaction started by Read or Write.
+type Tx struct {
+	tx runner
+}
+
+// Record is a result row keyed by the names of the RETURN clause. Graph values are Node,
+// Relationship and Path; temporal values are time.Time.
+type Record map[string]any
+
+// Node is a graph node.
+type Node struct {
+	// ElementID is the server-assigned id; it is stable only within a transaction.
+	ElementID  string
+	Labels     []string
+	Properties map[string]any
+}
+
+// Relationship is a graph relationship.
+type Relationship struct {
+	ElementID      string
+	Type           string
+	StartElementID string
+	EndElementID   string
+	Properties     map[string]any
+}
+
+// Path is an alternating sequence of nodes and the relationships between them.
+type Path struct {
+	Nodes         []Node
+	Relationships []Relationship
+}
+
+// Counters reports the changes made by a statement.
+type Counters struct {
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+}
+
+// Add returns the sum of c and other.
+func (c Counters) Add(other Counters) Counters {
+	return Counters{
+		NodesCreated:         c.NodesCreated + other.NodesCreated,
+		NodesDeleted:         c.NodesDeleted + other.NodesDeleted,
+		RelationshipsCreated: c.RelationshipsCreated + other.RelationshipsCreated,
+		RelationshipsDeleted: c.RelationshipsDeleted + other.RelationshipsDeleted,
+		PropertiesSet:        c.PropertiesSet + other.PropertiesSet,
+		LabelsAdded:          c.LabelsAdded + other.LabelsAdded,
+		LabelsRemoved:        c.LabelsRemoved + other.LabelsRemoved,
+	}
+}
+
+// Run runs a parameterized statement and returns every record.
+func (t *Tx) Run(ctx context.Context, cypher string, params map[string]any) ([]Record, error) {
+	result, err := t.tx.Run(ctx, cypher, params)
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("neo4j", "run", err)
+	}
+
+	records, err := result.Collect(ctx)
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("neo4j", "collect", err)
+	}
+
+	rows := make([]Record, len(records))
+	for i, record := range records {
+		rows[i] = make(Record, len(record.Keys))
+		for j, key := range record.Keys {
+			rows[i][key] = convertValue(record.Values[j])
+		}
+	}
+
+	return rows, nil
+}
+
+// Exec runs a parameterized statement, discards its records and returns its counters.
+func (t *Tx) Exec(ctx context.Context, cypher string, params map[string]any) (Counters, error) {
+	result, err := t.tx.Run(ctx, cypher, params)
+	if err != nil {
+		return Counters{}, coreerrors.WrapBoundary("neo4j", "run", err)
+	}
+
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return Counters{}, coreerrors.WrapBoundary("neo4j", "consume", err)
+	}
+
+	counters := summary.Counters()
+
+	return Counters{
+		NodesCreated:         counters.NodesCreated(),
+		NodesDeleted:         counters.NodesDeleted(),
+		RelationshipsCreated: counters.RelationshipsCreated(),
+		RelationshipsDeleted: counters.RelationshipsDeleted(),
+		PropertiesSet:        counters.PropertiesSet(),
+		LabelsAdded:          counters.LabelsAdded(),
+		LabelsRemoved:        counters.LabelsRemoved(),
+	}, nil
+}
+
+// Query runs a parameterized statement and decodes every record into a T.
+//
+// A struct T takes each field from the record key named by its `neo4j` tag, or by its
+// name compared case-insensitively. A record with a single key is decoded as a whole into
+// a non-struct T, and into a struct T when it holds a node, relationship or map none of
+// the fields is named after, so RETURN p fills a struct from the properties of p. Nodes
+// and relationships decode into Node, Relationship or their properties into a struct or
+// map, and paths into Path.
+func Query[T any](ctx context.Context, tx *Tx, cypher string, params map[string]any) ([]T, error) {
+	records, err := tx.Run(ctx, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]T, len(records))
+	for i, record := range records {
+		if err := Decode(record, &rows[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return rows, nil
+}
+
+// QueryOne runs a parameterized statement and decodes its first record into a T. No
+// records returns ErrRecordIsNotFound, classified as not found.
+func QueryOne[T any](ctx context.Context, tx *Tx, cypher string, params map[string]any) (T, error) {
+	var row T
+
+	records, err := tx.Run(ctx, cypher, params)
+	if err != nil {
+		return row, err
+	}
+
+	if len(records) == 0 {
+		return row, ErrRecordIsNotFound
+	}
+
+	return row, Decode(records[0], &row)
+}
+
+// Decode decodes a record into the value out points to, with the rules of Query.
+func Decode(record Record, out any) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("%w: decode target %T is not a pointer", ErrUnsupportedValue, out)
+	}
+
+	target = target.Elem()
+
+	if target.Kind() == reflect.Struct && !isGraphType(target.Type()) && !singleEntity(record, target.Type()) {
+		return decodeStruct(record, target)
+	}
+
+	if len(record) != 1 {
+		return fmt.Errorf("%w: %d record keys for %s", ErrUnsupportedValue, len(record), target.Type())
+	}
+
+	for _, value := range record {
+		return decodeValue(value, target)
+	}
+
+	return nil
+}
+
+// singleEntity reports whether record holds one node, relationship or map that no field of
+// the struct t is named after, so the entity itself is decoded into t.
+func singleEntity(record Record, t reflect.Type) bool {
+	if len(record) != 1 {
+		return false
+	}
+
+	for key, value := range record {
+		switch value.(type) {
+		case Node, Relationship, map[string]any:
+		default:
+			return false
+		}
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			name, tagged := field.Tag.Lookup(tagName)
+			if (tagged && name == key) || (!tagged && strings.EqualFold(field.Name, key)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// convertValue replaces driver graph and temporal values with core-owned ones.
+func convertValue(value any) any {
+	switch v := value.(type) {
+	case dbtype.Node:
+		return convertNode(v)
+	case dbtype.Relationship:
+		return convertRelationship(v)
+	case dbtype.Path:
+		path := Path{
+			Nodes:         make([]Node, len(v.Nodes)),
+			Relationships: make([]Relationship, len(v.Relationships)),
+		}
+
+		for i, node := range v.Nodes {
+			path.Nodes[i] = convertNode(node)
+		}
+
+		for i, relationship := range v.Relationships {
+			path.Relationships[i] = convertRelationship(relationship)
+		}
+
+		return path
+	case dbtype.Date:
+		return v.Time()
+	case dbtype.LocalDateTime:
+		return v.Time()
+	case dbtype.LocalTime:
+		return v.Time()
+	case dbtype.Time:
+		return v.Time()
+	case []any:
+		values := make([]any, len(v))
+		for i, item := range v {
+			values[i] = convertValue(item)
+		}
+
+		return values
+	case map[string]any:
+		values := make(map[string]any, len(v))
+		for key, item := range v {
+			values[key] = convertValue(item)
+		}
+
+		return values
+	default:
+		return value
+	}
+}
+
+func convertNode(node dbtype.Node) Node {
+	return Node{ElementID: node.ElementId, Labels: node.Labels, Properties: convertValue(node.Props).(map[string]any)}
+}
+
+func convertRelationship(relationship dbtype.Relationship) Relationship {
+	return Relationship{
+		ElementID:      relationship.ElementId,
+		Type:           relationship.Type,
+		StartElementID: relationship.StartElementId,
+		EndElementID:   relationship.EndElementId,
+		Properties:     convertValue(relationship.Props).(map[string]any),
+	}
+}
+
+var (
+	nodeType         = reflect.TypeOf(Node{})
+	relationshipType = reflect.TypeOf(Relationship{})
+	pathType         = reflect.TypeOf(Path{})
+	timeType         = reflect.TypeOf(time.Time{})
+)
+
+// isGraphType reports whether t is decoded as a whole rather than field by field.
+func isGraphType(t reflect.Type) bool {
+	return t == nodeType || t == relationshipType || t == pathType || t == timeType
+}
+
+// decodeValue stores value in target, converting graph values, lists, maps and numbers.
+func decodeValue(value any, target reflect.Value) error {
+	if value == nil {
+		target.SetZero()
+
+		return nil
+	}
+
+	if target.Kind() == reflect.Pointer {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+
+		return decodeValue(value, target.Elem())
+	}
+
+	source := reflect.ValueOf(value)
+	if source.Type().AssignableTo(target.Type()) {
+		target.Set(source)
+
+		return nil
+	}
+
+	switch v := value.(type) {
+	case Node:
+		return decodeValue(v.Properties, target)
+	case Relationship:
+		return decodeValue(v.Properties, target)
+	case map[string]any:
+		return decodeMap(v, target)
+	case []any:
+		if target.Kind() != reflect.Slice {
+			break
+		}
+
+		slice := reflect.MakeSlice(target.Type(), len(v), len(v))
+		for i, item := range v {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		target.Set(slice)
+
+		return nil
+	}
+
+	if isNumber(source.Kind()) && isNumber(target.Kind()) {
+		target.Set(source.Convert(target.Type()))
+
+		return nil
+	}
+
+	if target.Kind() == reflect.Interface && source.Type().Implements(target.Type()) {
+		target.Set(source)
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: cannot decode %T into %s", ErrUnsupportedValue, value, target.Type())
+}
+
+func decodeMap(values map[string]any, target reflect.Value) error {
+	switch target.Kind() {
+	case reflect.Struct:
+		if isGraphType(target.Type()) {
+			break
+		}
+
+		return decodeStruct(values, target)
+	case reflect.Map:
+		if target.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		m := reflect.MakeMapWithSize(target.Type(), len(values))
+
+		for key, value := range values {
+			item := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeValue(value, item); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), item)
+		}
+
+		target.Set(m)
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: cannot decode map into %s", ErrUnsupportedValue, target.Type())
+}
+
+// decodeStruct fills the exported fields of target from values by tag or case-insensitive name.
+func decodeStruct(values map[string]any, target reflect.Value) error {
+	fields := target.Type()
+
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, tagged := field.Tag.Lookup(tagName)
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			if err := decodeStruct(values, target.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		value, ok := lookup(values, field.Name, name)
+		if !ok {
+			continue
+		}
+
+		if err := decodeValue(value, target.Field(i)); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func lookup(values map[string]any, fieldName, tag string) (any, bool) {
+	if tag != "" {
+		value, ok := values[tag]
+
+		return value, ok
+	}
+
+	if value, ok := values[fieldName]; ok {
+		return value, true
+	}
+
+	for key, value := range values {
+		if strings.EqualFold(key, fieldName) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,174 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+type person struct {
+	ID      string `neo4j:"id"`
+	Name    string
+	Age     int
+	Tags    []string
+	Born    time.Time `neo4j:"born"`
+	Ignored string    `neo4j:"-"`
+}
+
+var (
+	alice = dbtype.Node{ElementId: "4:a", Labels: []string{"Person"}, Props: map[string]any{
+		"id": "1", "name": "Alice", "age": int64(42), "tags": []any{"admin", "dev"}, "Ignored": "x",
+		"born": dbtype.Date(time.Date(1984, 3, 2, 0, 0, 0, 0, time.UTC)),
+	}}
+	bob   = dbtype.Node{ElementId: "4:b", Labels: []string{"Person"}, Props: map[string]any{"id": "2", "name": "Bob"}}
+	knows = dbtype.Relationship{
+		ElementId: "5:k", StartElementId: "4:a", EndElementId: "4:b", Type: "KNOWS",
+		Props: map[string]any{"since": int64(2020)},
+	}
+)
+
+func queryClient(records ...*neo4j.Record) *GraphClient {
+	client, _ := newFakeClient(func(string, map[string]any) (neo4j.ResultWithContext, error) {
+		return &fakeResult{records: records}, nil
+	})
+
+	return client
+}
+
+func TestQueryDecodesNodeIntoStruct(t *testing.T) {
+	t.Parallel()
+
+	client := queryClient(record("p", alice))
+
+	var people []person
+
+	_, err := client.Read(context.Background(), TxOptions{}, func(ctx context.Context, tx *Tx) (err error) {
+		people, err = Query[person](ctx, tx, "MATCH (p:Person) RETURN p", nil)
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, people, []person{{
+		ID:   "1",
+		Name: "Alice",
+		Age:  42,
+		Tags: []string{"admin", "dev"},
+		Born: time.Date(1984, 3, 2, 0, 0, 0, 0, time.UTC),
+	}})
+}
+
+func TestQueryDecodesColumnsAndGraphValues(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Person   person       `neo4j:"p"`
+		Node     Node         `neo4j:"p"`
+		Knows    Relationship `neo4j:"r"`
+		Path     Path         `neo4j:"path"`
+		Friends  *int64       `neo4j:"friends"`
+		Score    float64      `neo4j:"score"`
+		Settings map[string]string
+	}
+
+	client := queryClient(record(
+		"p", alice,
+		"r", knows,
+		"path", dbtype.Path{Nodes: []dbtype.Node{alice, bob}, Relationships: []dbtype.Relationship{knows}},
+		"friends", int64(1),
+		"score", int64(3),
+		"settings", map[string]any{"theme": "dark"},
+	))
+
+	var got row
+
+	_, err := client.Read(context.Background(), TxOptions{}, func(ctx context.Context, tx *Tx) (err error) {
+		got, err = QueryOne[row](ctx, tx, "MATCH path = (p)-[r:KNOWS]->() RETURN p, r, path", nil)
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, got.Person.Name, "Alice")
+	testutils.Equal(t, got.Node.ElementID, "4:a")
+	testutils.Equal(t, got.Node.Labels, []string{"Person"})
+	testutils.Equal(t, got.Knows, Relationship{
+		ElementID: "5:k", Type: "KNOWS", StartElementID: "4:a", EndElementID: "4:b",
+		Properties: map[string]any{"since": int64(2020)},
+	})
+	testutils.Equal(t, len(got.Path.Nodes), 2)
+	testutils.Equal(t, got.Path.Nodes[1].Properties["name"], any("Bob"))
+	testutils.Equal(t, got.Path.Relationships[0].Type, "KNOWS")
+	testutils.Equal(t, *got.Friends, int64(1))
+	testutils.Equal(t, got.Score, 3.0)
+	testutils.Equal(t, got.Settings, map[string]string{"theme": "dark"})
+}
+
+func TestQueryDecodesScalars(t *testing.T) {
+	t.Parallel()
+
+	client := queryClient(record("name", "Alice"), record("name", "Bob"))
+
+	var names []string
+
+	_, err := client.Read(context.Background(), TxOptions{}, func(ctx context.Context, tx *Tx) (err error) {
+		names, err = Query[string](ctx, tx, "MATCH (p:Person) RETURN p.name AS name", nil)
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, names, []string{"Alice", "Bob"})
+}
+
+func TestQueryErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		records []*neo4j.Record
+		query   func(ctx context.Context, tx *Tx) error
+		want    error
+	}{
+		{
+			name: "no record",
+			query: func(ctx context.Context, tx *Tx) error {
+				_, err := QueryOne[person](ctx, tx, "MATCH (p:Person {id: $id}) RETURN p", map[string]any{"id": "9"})
+
+				return err
+			},
+			want: ErrRecordIsNotFound,
+		},
+		{
+			name:    "wrong type",
+			records: []*neo4j.Record{record("age", "old")},
+			query: func(ctx context.Context, tx *Tx) error {
+				_, err := Query[int](ctx, tx, "RETURN 'old' AS age", nil)
+
+				return err
+			},
+			want: ErrUnsupportedValue,
+		},
+		{
+			name:    "several keys into scalar",
+			records: []*neo4j.Record{record("a", int64(1), "b", int64(2))},
+			query: func(ctx context.Context, tx *Tx) error {
+				_, err := Query[int](ctx, tx, "RETURN 1 AS a, 2 AS b", nil)
+
+				return err
+			},
+			want: ErrUnsupportedValue,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := queryClient(test.records...).Read(context.Background(), TxOptions{}, test.query)
+			testutils.Equal(t, errors.Is(err, test.want), true)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/InsideGallery/core/db/retry"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Transaction defaults.
+const (
+	DefaultTxMaxRetries = retry.DefaultMaxRetries
+	DefaultTxRetryDelay = retry.DefaultDelay
+)
+
+// Bookmarks mark the transactions a later transaction must observe for causal consistency.
+type Bookmarks []string
+
+// TxOptions is the core-owned input for Read and Write.
+type TxOptions struct {
+	// Database is the database name; empty uses the server default.
+	Database string
+	// Bookmarks make the transaction wait until the server has applied the bookmarked writes.
+	Bookmarks Bookmarks
+	// Timeout aborts the transaction on the server after it runs that long; zero uses the server default.
+	Timeout time.Duration
+	// Metadata is attached to the transaction and shown by the server in query logs.
+	Metadata map[string]any
+	// MaxRetries is how many times the whole transaction is retried after a retryable error;
+	// zero uses DefaultTxMaxRetries and a negative value disables retries.
+	MaxRetries int
+	// RetryDelay is multiplied by the attempt number between retries; zero uses DefaultTxRetryDelay.
+	RetryDelay time.Duration
+}
+
+// TxFunc is the work of a transaction.
+type TxFunc func(ctx context.Context, tx *Tx) error
+
+// session is the part of neo4j.SessionWithContext a transaction drives.
+type session interface {
+	BeginTransaction(ctx context.Context, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ExplicitTransaction, error)
+	LastBookmarks() neo4j.Bookmarks
+	Close(ctx context.Context) error
+}
+
+// Read runs fn in a read transaction routed to a reader and returns the bookmarks of the
+// transaction. fn returning an error rolls back. Retryable errors re-run fn from the
+// start, so fn must not have side effects outside the database.
+func (c *GraphClient) Read(ctx context.Context, opts TxOptions, fn TxFunc) (Bookmarks, error) {
+	return c.execute(ctx, neo4j.AccessModeRead, opts, fn)
+}
+
+// Write runs fn in a write transaction and returns the bookmarks to pass to later
+// transactions that must observe its writes. fn returning an error rolls back; otherwise
+// the transaction commits. Retryable errors re-run fn from the start, so fn must not
+// have side effects outside the database.
+func (c *GraphClient) Write(ctx context.Context, opts TxOptions, fn TxFunc) (Bookmarks, error) {
+	return c.execute(ctx, neo4j.AccessModeWrite, opts, fn)
+}
+
+// Session chains transactions causally: every transaction observes the writes of the
+// transactions run before it through the same Session. It is safe for concurrent use.
+type Session struct {
+	client   *GraphClient
+	database string
+
+	mu        sync.Mutex
+	bookmarks Bookmarks
+}
+
+// NewSession returns a Session on database starting after bookmarks; an empty database
+// uses the server default.
+func (c *GraphClient) NewSession(database string, bookmarks ...string) *Session {
+	return &Session{client: c, database: database, bookmarks: bookmarks}
+}
+
+// Read runs fn in a read transaction that observes the previous transactions of s.
+// The Database and Bookmarks of opts are replaced by those of s.
+func (s *Session) Read(ctx context.Context, opts TxOptions, fn TxFunc) error {
+	return s.execute(ctx, neo4j.AccessModeRead, opts, fn)
+}
+
+// Write runs fn in a write transaction that observes the previous transactions of s.
+// The Database and Bookmarks of opts are replaced by those of s.
+func (s *Session) Write(ctx context.Context, opts TxOptions, fn TxFunc) error {
+	return s.execute(ctx, neo4j.AccessModeWrite, opts, fn)
+}
+
+// Bookmarks returns the bookmarks of the last transaction of s, to continue the causal
+// chain in another session or process.
+func (s *Session) Bookmarks() Bookmarks {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append(Bookmarks(nil), s.bookmarks...)
+}
+
+func (s *Session) execute(ctx context.Context, mode neo4j.AccessMode, opts TxOptions, fn TxFunc) error {
+	opts.Database = s.database
+	opts.Bookmarks = s.Bookmarks()
+
+	bookmarks, err := s.client.execute(ctx, mode, opts, fn)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.bookmarks = bookmarks
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (c *GraphClient) execute(ctx context.Context, mode neo4j.AccessMode, opts TxOptions, fn TxFunc) (_ Bookmarks, err error) {
+	sess := c.session(ctx, neo4j.SessionConfig{
+		AccessMode:   mode,
+		Bookmarks:    neo4j.BookmarksFromRawValues(opts.Bookmarks...),
+		DatabaseName: opts.Database,
+	})
+
+	defer func() {
+		if closeErr := sess.Close(context.WithoutCancel(ctx)); closeErr != nil && err == nil {
+			err = coreerrors.WrapBoundary("neo4j", "close session", closeErr)
+		}
+	}()
+
+	policy := retry.Policy{MaxRetries: opts.MaxRetries, Delay: opts.RetryDelay}
+
+	err = retry.Do(ctx, policy, IsRetryable, func(int) error {
+		return runTransaction(ctx, sess, opts, fn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return Bookmarks(neo4j.BookmarksToRawValues(sess.LastBookmarks())), nil
+}
+
+func (c *GraphClient) session(ctx context.Context, config neo4j.SessionConfig) session {
+	if c.sessions != nil {
+		return c.sessions(ctx, config)
+	}
+
+	return c.driver.NewSession(ctx, config)
+}
+
+func runTransaction(ctx context.Context, sess session, opts TxOptions, fn TxFunc) error {
+	var configurers []func(*neo4j.TransactionConfig)
+
+	if opts.Timeout > 0 {
+		configurers = append(configurers, neo4j.WithTxTimeout(opts.Timeout))
+	}
+
+	if len(opts.Metadata) > 0 {
+		configurers = append(configurers, neo4j.WithTxMetadata(opts.Metadata))
+	}
+
+	tx, err := sess.BeginTransaction(ctx, configurers...)
+	if err != nil {
+		return coreerrors.WrapBoundary("neo4j", "begin transaction", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+
+			panic(recovered)
+		}
+	}()
+
+	if err := fn(ctx, &Tx{tx: tx}); err != nil {
+		if rollbackErr := tx.Rollback(context.WithoutCancel(ctx)); rollbackErr != nil {
+			return errors.Join(err, coreerrors.WrapBoundary("neo4j", "rollback", rollbackErr))
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return coreerrors.WrapBoundary("neo4j", "commit", err)
+	}
+
+	return nil
+}
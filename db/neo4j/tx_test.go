@@ -0,0 +1,141 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+var errBoom = errors.New("boom")
+
+func TestWriteCommitsAndReturnsBookmarks(t *testing.T) {
+	t.Parallel()
+
+	client, db := newFakeClient(nil)
+
+	bookmarks, err := client.Write(context.Background(), TxOptions{
+		Database:  "graph",
+		Bookmarks: Bookmarks{"start"},
+		Timeout:   time.Second,
+		Metadata:  map[string]any{"app": "test"},
+	}, func(ctx context.Context, tx *Tx) error {
+		_, err := tx.Exec(ctx, "CREATE (:Person {id: $id})", map[string]any{"id": "1"})
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, bookmarks, Bookmarks{"bookmark-1"})
+
+	testutils.Equal(t, len(db.sessions), 1)
+	testutils.Equal(t, db.sessions[0].config.AccessMode, neo4j.AccessModeWrite)
+	testutils.Equal(t, db.sessions[0].config.DatabaseName, "graph")
+	testutils.Equal(t, db.sessions[0].config.Bookmarks, neo4j.Bookmarks{"start"})
+	testutils.Equal(t, db.sessions[0].closed, true)
+	testutils.Equal(t, db.txConfigs[0].Timeout, time.Second)
+	testutils.Equal(t, db.txConfigs[0].Metadata, map[string]any{"app": "test"})
+	testutils.Equal(t, db.txs[0].committed, true)
+	testutils.Equal(t, db.statements[0].params, map[string]any{"id": "1"})
+}
+
+func TestReadRollsBackOnError(t *testing.T) {
+	t.Parallel()
+
+	client, db := newFakeClient(nil)
+
+	_, err := client.Read(context.Background(), TxOptions{}, func(context.Context, *Tx) error {
+		return errBoom
+	})
+	testutils.Equal(t, errors.Is(err, errBoom), true)
+	testutils.Equal(t, db.sessions[0].config.AccessMode, neo4j.AccessModeRead)
+	testutils.Equal(t, len(db.txs), 1)
+	testutils.Equal(t, db.txs[0].rolledBack, true)
+	testutils.Equal(t, db.txs[0].committed, false)
+}
+
+func TestWriteRetriesRetryableErrors(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	client, db := newFakeClient(func(string, map[string]any) (neo4j.ResultWithContext, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"}
+		}
+
+		return &fakeResult{}, nil
+	})
+
+	calls := 0
+
+	_, err := client.Write(context.Background(), TxOptions{RetryDelay: time.Millisecond}, func(ctx context.Context, tx *Tx) error {
+		calls++
+		_, err := tx.Exec(ctx, "MERGE (:Person {id: '1'})", nil)
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, calls, 3)
+	testutils.Equal(t, len(db.sessions), 1)
+	testutils.Equal(t, db.txs[0].rolledBack, true)
+	testutils.Equal(t, db.txs[2].committed, true)
+}
+
+func TestWriteStopsOnNonRetryableErrors(t *testing.T) {
+	t.Parallel()
+
+	client, db := newFakeClient(func(string, map[string]any) (neo4j.ResultWithContext, error) {
+		return nil, &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}
+	})
+
+	_, err := client.Write(context.Background(), TxOptions{}, func(ctx context.Context, tx *Tx) error {
+		_, err := tx.Exec(ctx, "MERG", nil)
+
+		return err
+	})
+
+	var neo4jErr *neo4j.Neo4jError
+	testutils.Equal(t, errors.As(err, &neo4jErr), true)
+	testutils.Equal(t, len(db.txs), 1)
+}
+
+func TestWriteRetriesAreBounded(t *testing.T) {
+	t.Parallel()
+
+	client, db := newFakeClient(nil)
+	db.commitErr = &neo4j.Neo4jError{Code: "Neo.TransientError.General.DatabaseUnavailable"}
+
+	_, err := client.Write(context.Background(), TxOptions{MaxRetries: 1, RetryDelay: time.Millisecond},
+		func(context.Context, *Tx) error { return nil })
+	testutils.Equal(t, IsRetryable(err), true)
+	testutils.Equal(t, len(db.txs), 2)
+
+	_, err = client.Write(context.Background(), TxOptions{MaxRetries: -1},
+		func(context.Context, *Tx) error { return nil })
+	testutils.Equal(t, IsRetryable(err), true)
+	testutils.Equal(t, len(db.txs), 3)
+}
+
+func TestSessionChainsBookmarks(t *testing.T) {
+	t.Parallel()
+
+	client, db := newFakeClient(nil)
+	session := client.NewSession("graph", "initial")
+
+	noop := func(context.Context, *Tx) error { return nil }
+
+	testutils.Equal(t, session.Write(context.Background(), TxOptions{}, noop), nil)
+	testutils.Equal(t, session.Read(context.Background(), TxOptions{Database: "other"}, noop), nil)
+
+	testutils.Equal(t, db.sessions[0].config.Bookmarks, neo4j.Bookmarks{"initial"})
+	testutils.Equal(t, db.sessions[1].config.Bookmarks, neo4j.Bookmarks{"bookmark-1"})
+	testutils.Equal(t, db.sessions[1].config.DatabaseName, "graph")
+	testutils.Equal(t, session.Bookmarks(), Bookmarks{"bookmark-2"})
+
+	err := session.Write(context.Background(), TxOptions{}, func(context.Context, *Tx) error { return errBoom })
+	testutils.Equal(t, errors.Is(err, errBoom), true)
+	testutils.Equal(t, session.Bookmarks(), Bookmarks{"bookmark-2"})
+}
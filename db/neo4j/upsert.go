@@ -0,0 +1,180 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// IDProperty is the property upserts match nodes and relationships by.
+const IDProperty = "id"
+
+// DefaultUpsertBatchSize is the number of rows sent per UNWIND statement.
+const DefaultUpsertBatchSize = 1000
+
+// UpsertNodeOptions is the core-owned input for a node upsert; it mirrors
+// gremlin.UpsertVertexOptions.
+type UpsertNodeOptions struct {
+	Label      string
+	ID         string
+	Properties map[string]any
+}
+
+// NodeRef identifies a node by label and IDProperty; it mirrors gremlin.VertexRef.
+type NodeRef struct {
+	Label string
+	ID    string
+}
+
+// UpsertRelationshipOptions is the core-owned input for a relationship upsert; it mirrors
+// gremlin.UpsertEdgeOptions. Label is the relationship type. An empty ID merges on the
+// type and endpoints alone, keeping at most one such relationship between two nodes.
+type UpsertRelationshipOptions struct {
+	Label      string
+	ID         string
+	From       NodeRef
+	To         NodeRef
+	Properties map[string]any
+}
+
+// UpsertNodes merges nodes by label and IDProperty and adds their properties, sending one
+// UNWIND statement per label and batch of DefaultUpsertBatchSize rows.
+func (t *Tx) UpsertNodes(ctx context.Context, nodes ...UpsertNodeOptions) (Counters, error) {
+	groups := map[string][]map[string]any{}
+
+	var labels []string
+
+	for _, node := range nodes {
+		if node.Label == "" {
+			return Counters{}, ErrLabelIsNotSet
+		}
+
+		if node.ID == "" {
+			return Counters{}, ErrIDIsNotSet
+		}
+
+		if _, ok := groups[node.Label]; !ok {
+			labels = append(labels, node.Label)
+		}
+
+		groups[node.Label] = append(groups[node.Label], map[string]any{
+			"id":         node.ID,
+			"properties": properties(node.Properties),
+		})
+	}
+
+	var counters Counters
+
+	for _, label := range labels {
+		cypher := fmt.Sprintf(
+			"UNWIND $rows AS row MERGE (n:%s {%s: row.id}) SET n += row.properties",
+			quote(label), quote(IDProperty),
+		)
+
+		result, err := t.unwind(ctx, cypher, groups[label])
+		counters = counters.Add(result)
+
+		if err != nil {
+			return counters, err
+		}
+	}
+
+	return counters, nil
+}
+
+// UpsertRelationships merges relationships between existing nodes and adds their
+// properties, sending one UNWIND statement per type, endpoint labels and batch of
+// DefaultUpsertBatchSize rows. Rows whose endpoints do not exist are skipped.
+func (t *Tx) UpsertRelationships(ctx context.Context, relationships ...UpsertRelationshipOptions) (Counters, error) {
+	type group struct {
+		label, from, to string
+		withID          bool
+	}
+
+	groups := map[group][]map[string]any{}
+
+	var order []group
+
+	for _, relationship := range relationships {
+		if relationship.Label == "" || relationship.From.Label == "" || relationship.To.Label == "" {
+			return Counters{}, ErrLabelIsNotSet
+		}
+
+		if relationship.From.ID == "" || relationship.To.ID == "" {
+			return Counters{}, ErrIDIsNotSet
+		}
+
+		key := group{
+			label:  relationship.Label,
+			from:   relationship.From.Label,
+			to:     relationship.To.Label,
+			withID: relationship.ID != "",
+		}
+
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+
+		groups[key] = append(groups[key], map[string]any{
+			"id":         relationship.ID,
+			"from":       relationship.From.ID,
+			"to":         relationship.To.ID,
+			"properties": properties(relationship.Properties),
+		})
+	}
+
+	var counters Counters
+
+	for _, key := range order {
+		match := ""
+		if key.withID {
+			match = fmt.Sprintf(" {%s: row.id}", quote(IDProperty))
+		}
+
+		cypher := fmt.Sprintf(
+			"UNWIND $rows AS row MATCH (a:%[1]s {%[4]s: row.from}) MATCH (b:%[2]s {%[4]s: row.to}) "+
+				"MERGE (a)-[r:%[3]s%[5]s]->(b) SET r += row.properties",
+			quote(key.from), quote(key.to), quote(key.label), quote(IDProperty), match,
+		)
+
+		result, err := t.unwind(ctx, cypher, groups[key])
+		counters = counters.Add(result)
+
+		if err != nil {
+			return counters, err
+		}
+	}
+
+	return counters, nil
+}
+
+// unwind runs cypher once per batch of rows bound to $rows.
+func (t *Tx) unwind(ctx context.Context, cypher string, rows []map[string]any) (Counters, error) {
+	var counters Counters
+
+	for start := 0; start < len(rows); start += DefaultUpsertBatchSize {
+		end := min(start+DefaultUpsertBatchSize, len(rows))
+
+		result, err := t.Exec(ctx, cypher, map[string]any{"rows": rows[start:end]})
+		if err != nil {
+			return counters, err
+		}
+
+		counters = counters.Add(result)
+	}
+
+	return counters, nil
+}
+
+// quote escapes a label, type or property name; they cannot be passed as parameters.
+func quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func properties(values map[string]any) map[string]any {
+	if values == nil {
+		return map[string]any{}
+	}
+
+	return values
+}
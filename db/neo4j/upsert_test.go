@@ -0,0 +1,144 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func upsertClient() (*GraphClient, *fakeDB) {
+	return newFakeClient(func(_ string, params map[string]any) (neo4j.ResultWithContext, error) {
+		rows := len(params["rows"].([]map[string]any))
+
+		return &fakeResult{counters: fakeCounters{nodesCreated: rows, relationshipsCreated: rows}}, nil
+	})
+}
+
+func TestUpsertNodes(t *testing.T) {
+	t.Parallel()
+
+	client, db := upsertClient()
+
+	nodes := make([]UpsertNodeOptions, 0, DefaultUpsertBatchSize+2)
+	for i := 0; i <= DefaultUpsertBatchSize; i++ {
+		nodes = append(nodes, UpsertNodeOptions{Label: "Person", ID: strconv.Itoa(i), Properties: map[string]any{"n": i}})
+	}
+
+	nodes = append(nodes, UpsertNodeOptions{Label: "Team`s", ID: "t"})
+
+	var counters Counters
+
+	_, err := client.Write(context.Background(), TxOptions{}, func(ctx context.Context, tx *Tx) (err error) {
+		counters, err = tx.UpsertNodes(ctx, nodes...)
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, counters.NodesCreated, DefaultUpsertBatchSize+2)
+	testutils.Equal(t, len(db.statements), 3)
+	testutils.Equal(t, db.statements[0].cypher, "UNWIND $rows AS row MERGE (n:`Person` {`id`: row.id}) SET n += row.properties")
+	testutils.Equal(t, len(db.statements[0].params["rows"].([]map[string]any)), DefaultUpsertBatchSize)
+	testutils.Equal(t, db.statements[1].params["rows"], any([]map[string]any{
+		{"id": strconv.Itoa(DefaultUpsertBatchSize), "properties": map[string]any{"n": DefaultUpsertBatchSize}},
+	}))
+	testutils.Equal(t, db.statements[2].cypher, "UNWIND $rows AS row MERGE (n:`Team``s` {`id`: row.id}) SET n += row.properties")
+	testutils.Equal(t, db.statements[2].params["rows"], any([]map[string]any{{"id": "t", "properties": map[string]any{}}}))
+}
+
+func TestUpsertRelationships(t *testing.T) {
+	t.Parallel()
+
+	client, db := upsertClient()
+
+	_, err := client.Write(context.Background(), TxOptions{}, func(ctx context.Context, tx *Tx) error {
+		_, err := tx.UpsertRelationships(ctx,
+			UpsertRelationshipOptions{
+				Label: "KNOWS", ID: "k1",
+				From: NodeRef{Label: "Person", ID: "1"}, To: NodeRef{Label: "Person", ID: "2"},
+				Properties: map[string]any{"since": 2020},
+			},
+			UpsertRelationshipOptions{
+				Label: "MEMBER_OF",
+				From:  NodeRef{Label: "Person", ID: "1"}, To: NodeRef{Label: "Team", ID: "t"},
+			},
+		)
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(db.statements), 2)
+	testutils.Equal(t, db.statements[0].cypher, "UNWIND $rows AS row MATCH (a:`Person` {`id`: row.from}) "+
+		"MATCH (b:`Person` {`id`: row.to}) MERGE (a)-[r:`KNOWS` {`id`: row.id}]->(b) SET r += row.properties")
+	testutils.Equal(t, db.statements[0].params["rows"], any([]map[string]any{
+		{"id": "k1", "from": "1", "to": "2", "properties": map[string]any{"since": 2020}},
+	}))
+	testutils.Equal(t, db.statements[1].cypher, "UNWIND $rows AS row MATCH (a:`Person` {`id`: row.from}) "+
+		"MATCH (b:`Team` {`id`: row.to}) MERGE (a)-[r:`MEMBER_OF`]->(b) SET r += row.properties")
+}
+
+func TestUpsertValidation(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		run  func(ctx context.Context, tx *Tx) error
+		want error
+	}{
+		{
+			name: "node label",
+			run: func(ctx context.Context, tx *Tx) error {
+				_, err := tx.UpsertNodes(ctx, UpsertNodeOptions{ID: "1"})
+
+				return err
+			},
+			want: ErrLabelIsNotSet,
+		},
+		{
+			name: "node id",
+			run: func(ctx context.Context, tx *Tx) error {
+				_, err := tx.UpsertNodes(ctx, UpsertNodeOptions{Label: "Person"})
+
+				return err
+			},
+			want: ErrIDIsNotSet,
+		},
+		{
+			name: "relationship endpoint label",
+			run: func(ctx context.Context, tx *Tx) error {
+				_, err := tx.UpsertRelationships(ctx, UpsertRelationshipOptions{
+					Label: "KNOWS", From: NodeRef{ID: "1"}, To: NodeRef{Label: "Person", ID: "2"},
+				})
+
+				return err
+			},
+			want: ErrLabelIsNotSet,
+		},
+		{
+			name: "relationship endpoint id",
+			run: func(ctx context.Context, tx *Tx) error {
+				_, err := tx.UpsertRelationships(ctx, UpsertRelationshipOptions{
+					Label: "KNOWS", From: NodeRef{Label: "Person", ID: "1"}, To: NodeRef{Label: "Person"},
+				})
+
+				return err
+			},
+			want: ErrIDIsNotSet,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, db := upsertClient()
+
+			_, err := client.Write(context.Background(), TxOptions{}, test.run)
+			testutils.Equal(t, errors.Is(err, test.want), true)
+			testutils.Equal(t, len(db.statements), 0)
+		})
+	}
+}
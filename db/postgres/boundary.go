@@ -20,11 +20,14 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/InsideGallery/core/db/instrument"
 	coreerrors "github.com/InsideGallery/core/errors"
 )
 
@@ -44,6 +47,15 @@ type DatabaseOptions struct {
 	ApplicationName string
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
+	MaxIdleConns    int
+	ConnMaxIdleTime time.Duration
+	ConnectTimeout  time.Duration
+	SSLMode         string
+	SSLRootCert     string
+	SSLCert         string
+	SSLKey          string
+	// Replicas lists read replicas as host or host:port.
+	Replicas []string
 }
 
 // CommandResult is the core-owned result for SQL commands.
@@ -57,22 +69,44 @@ type Database interface {
 	Exec(ctx context.Context, statement Statement) (CommandResult, error)
 	Query(ctx context.Context, statement Statement) (*sql.Rows, error)
 	QueryRow(ctx context.Context, statement Statement) *sql.Row
+	WithTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error
 	Close() error
 }
 
 // DatabaseClient wraps sqlx behind core-owned operation inputs and results.
 type DatabaseClient struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	replicas []*sqlx.DB
+	next     *atomic.Uint64
+	observer instrument.Observer
+	// borrowed marks a Reader view, which shares the pools of its client.
+	borrowed bool
 }
 
-// NewDatabase creates a Postgres database client from core-owned config.
+// NewDatabase creates a Postgres database client from core-owned config, with a
+// pool for the primary and one for every configured replica.
 func NewDatabase(config *ConnectionConfig) (*DatabaseClient, error) {
 	db, err := NewClient(config)
 	if err != nil {
 		return nil, fmt.Errorf("postgres open: %w", err)
 	}
 
-	return &DatabaseClient{db: db}, nil
+	replicas := make([]*sqlx.DB, 0, len(config.Replicas))
+
+	for _, replicaConfig := range config.ReplicaConfigs() {
+		replica, err := NewClient(replicaConfig)
+		if err != nil {
+			for _, opened := range append(replicas, db) {
+				_ = opened.Close()
+			}
+
+			return nil, fmt.Errorf("postgres open replica %s: %w", replicaConfig.Host, err)
+		}
+
+		replicas = append(replicas, replica)
+	}
+
+	return WrapDatabase(db, replicas...), nil
 }
 
 // NewDatabaseFromOptions creates a Postgres database client from core-owned construction options.
@@ -90,9 +124,33 @@ func DefaultDatabase() (*DatabaseClient, error) {
 	return WrapDatabase(db), nil
 }
 
-// WrapDatabase adapts an existing sqlx DB to DatabaseClient.
-func WrapDatabase(db *sqlx.DB) *DatabaseClient {
-	return &DatabaseClient{db: db}
+// WrapDatabase adapts an existing sqlx DB and optional read replicas to DatabaseClient.
+func WrapDatabase(db *sqlx.DB, replicas ...*sqlx.DB) *DatabaseClient {
+	return &DatabaseClient{db: db, replicas: replicas, next: new(atomic.Uint64)}
+}
+
+// Reader returns a client for read-only statements: the next replica in round-robin
+// order, or the primary when there are no replicas or ctx carries a transaction, so
+// reads inside WithTx see its writes. The reader shares the pools of d, and its Close
+// does nothing; close d instead.
+func (d *DatabaseClient) Reader(ctx context.Context) *DatabaseClient {
+	db := d.db
+	if len(d.replicas) > 0 && !d.InTx(ctx) {
+		db = d.replicas[(d.next.Add(1)-1)%uint64(len(d.replicas))]
+	}
+
+	return &DatabaseClient{db: db, observer: d.observer, borrowed: true}
+}
+
+// SetObserver sets the observer of every operation; nil uses instrument.Default.
+// Call it before the client is shared.
+func (d *DatabaseClient) SetObserver(observer instrument.Observer) {
+	d.observer = observer
+}
+
+// Replicas returns the number of read replicas.
+func (d *DatabaseClient) Replicas() int {
+	return len(d.replicas)
 }
 
 // SQLDB returns the standard-library database handle.
@@ -109,14 +167,17 @@ func (d *DatabaseClient) Ping(ctx context.Context) error {
 	return nil
 }
 
-// Exec runs a command with core-owned options.
-func (d *DatabaseClient) Exec(ctx context.Context, statement Statement) (CommandResult, error) {
-	result, err := d.db.ExecContext(ctx, statement.Query, statement.Args...)
+// Exec runs a command with core-owned options, inside the transaction carried by ctx if any.
+func (d *DatabaseClient) Exec(ctx context.Context, statement Statement) (result CommandResult, err error) {
+	done := d.observeStatement(ctx, "exec", statement.Query)
+	defer func() { done(result.RowsAffected, err) }()
+
+	sqlResult, err := d.executor(ctx).ExecContext(ctx, statement.Query, statement.Args...)
 	if err != nil {
 		return CommandResult{}, coreerrors.WrapBoundary("postgres", "exec", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rowsAffected, err := sqlResult.RowsAffected()
 	if err != nil {
 		return CommandResult{}, coreerrors.WrapBoundary("postgres", "rows affected", err)
 	}
@@ -124,9 +185,13 @@ func (d *DatabaseClient) Exec(ctx context.Context, statement Statement) (Command
 	return CommandResult{RowsAffected: rowsAffected}, nil
 }
 
-// Query runs a query with core-owned options.
+// Query runs a query with core-owned options, inside the transaction carried by ctx if any.
 func (d *DatabaseClient) Query(ctx context.Context, statement Statement) (*sql.Rows, error) {
-	rows, err := d.db.QueryContext(ctx, statement.Query, statement.Args...)
+	done := d.observeStatement(ctx, "query", statement.Query)
+
+	rows, err := d.executor(ctx).QueryContext(ctx, statement.Query, statement.Args...)
+	done(0, err)
+
 	if err != nil {
 		return nil, coreerrors.WrapBoundary("postgres", "query", err)
 	}
@@ -134,18 +199,31 @@ func (d *DatabaseClient) Query(ctx context.Context, statement Statement) (*sql.R
 	return rows, nil
 }
 
-// QueryRow runs a single-row query with core-owned options.
+// QueryRow runs a single-row query with core-owned options, inside the transaction carried by ctx if any.
 func (d *DatabaseClient) QueryRow(ctx context.Context, statement Statement) *sql.Row {
-	return d.db.QueryRowContext(ctx, statement.Query, statement.Args...)
+	done := d.observeStatement(ctx, "query row", statement.Query)
+
+	row := d.executor(ctx).QueryRowContext(ctx, statement.Query, statement.Args...)
+	done(0, row.Err())
+
+	return row
 }
 
-// Close closes the database client.
+// Close closes the primary and replica pools. It does nothing on a Reader.
 func (d *DatabaseClient) Close() error {
-	if err := d.db.Close(); err != nil {
-		return coreerrors.WrapBoundary("postgres", "close", err)
+	if d.borrowed {
+		return nil
 	}
 
-	return nil
+	var errs []error
+
+	for _, db := range append([]*sqlx.DB{d.db}, d.replicas...) {
+		if err := db.Close(); err != nil {
+			errs = append(errs, coreerrors.WrapBoundary("postgres", "close", err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 func (o DatabaseOptions) config() *ConnectionConfig {
@@ -158,5 +236,13 @@ func (o DatabaseOptions) config() *ConnectionConfig {
 		ApplicationName: o.ApplicationName,
 		MaxOpenConns:    o.MaxOpenConns,
 		ConnMaxLifetime: int64(o.ConnMaxLifetime),
+		MaxIdleConns:    o.MaxIdleConns,
+		ConnMaxIdleTime: o.ConnMaxIdleTime,
+		ConnectTimeout:  o.ConnectTimeout,
+		SSLMode:         o.SSLMode,
+		SSLRootCert:     o.SSLRootCert,
+		SSLCert:         o.SSLCert,
+		SSLKey:          o.SSLKey,
+		Replicas:        o.Replicas,
 	}
 }
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// maxBindParameters is the Postgres limit of parameters in one statement.
+const maxBindParameters = 65535
+
+// Bulk insert errors.
+var (
+	ErrBulkRowWidth = errors.New("bulk rows must match a non-empty column list")
+
+	errCopyUnsupported = errors.New("driver does not support copy")
+)
+
+// BulkInsert writes rows into table with COPY FROM when the driver is pgx, and with
+// batched multi-row INSERT statements otherwise. table may be schema-qualified. It
+// joins the transaction carried by ctx if any and returns the number of rows written.
+func (d *DatabaseClient) BulkInsert(ctx context.Context, table string, columns []string, rows [][]any) (written int64, err error) {
+	if len(columns) == 0 {
+		return 0, ErrBulkRowWidth
+	}
+
+	for _, row := range rows {
+		if len(row) != len(columns) {
+			return 0, ErrBulkRowWidth
+		}
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	done := d.observe(ctx, "bulk insert", table, "")
+	defer func() { done(written, err) }()
+
+	copyRows := func(driverConn any) error {
+		conn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errCopyUnsupported
+		}
+
+		count, err := conn.Conn().CopyFrom(ctx, identifier(table), columns, pgx.CopyFromRows(rows))
+		written = count
+
+		return err
+	}
+
+	if state := d.txState(ctx); state != nil {
+		err = state.conn.Raw(copyRows)
+	} else {
+		err = d.rawConn(ctx, copyRows)
+	}
+
+	if errors.Is(err, errCopyUnsupported) {
+		return d.insertBatches(ctx, table, columns, rows)
+	}
+
+	if err != nil {
+		return 0, coreerrors.WrapBoundary("postgres", "copy", err)
+	}
+
+	return written, nil
+}
+
+func (d *DatabaseClient) rawConn(ctx context.Context, fn func(driverConn any) error) error {
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func(conn *sql.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	return conn.Raw(fn)
+}
+
+func (d *DatabaseClient) insertBatches(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, pgx.Identifier{column}.Sanitize())
+	}
+
+	prefix := "INSERT INTO " + identifier(table).Sanitize() + " (" + strings.Join(quoted, ", ") + ") VALUES "
+	batchSize := max(1, maxBindParameters/len(columns))
+
+	var written int64
+
+	for start := 0; start < len(rows); start += batchSize {
+		batch := rows[start:min(start+batchSize, len(rows))]
+
+		var query strings.Builder
+
+		query.WriteString(prefix)
+
+		args := make([]any, 0, len(batch)*len(columns))
+
+		for i, row := range batch {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+
+			query.WriteString("(")
+
+			for j, value := range row {
+				if j > 0 {
+					query.WriteString(", ")
+				}
+
+				args = append(args, value)
+				query.WriteString("$" + strconv.Itoa(len(args)))
+			}
+
+			query.WriteString(")")
+		}
+
+		result, err := d.executor(ctx).ExecContext(ctx, query.String(), args...)
+		if err != nil {
+			return written, coreerrors.WrapBoundary("postgres", "bulk insert", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return written, coreerrors.WrapBoundary("postgres", "rows affected", err)
+		}
+
+		written += affected
+	}
+
+	return written, nil
+}
+
+func identifier(table string) pgx.Identifier {
+	return pgx.Identifier(strings.Split(table, "."))
+}
@@ -84,6 +84,10 @@ func (s *ClientStore) Close() error {
 
 // NewClient creates a legacy sqlx Postgres client from explicit config.
 func NewClient(config *ConnectionConfig) (*sqlx.DB, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	db, err := sqlx.Open("pgx", config.GetDSN())
 	if err != nil {
 		return nil, err
@@ -91,6 +95,11 @@ func NewClient(config *ConnectionConfig) (*sqlx.DB, error) {
 
 	db.SetMaxOpenConns(config.MaxOpenConns)
 	db.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetime))
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	if config.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(config.MaxIdleConns)
+	}
 
 	return db, nil
 }
@@ -2,7 +2,11 @@ package postgres
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v10"
 )
@@ -10,6 +14,16 @@ import (
 // EnvPrefix environment prefix for mongodb config
 const EnvPrefix = "POSTGRES"
 
+// SSL modes accepted by ConnectionConfig.SSLMode.
+const (
+	SSLModeDisable    = "disable"
+	SSLModeAllow      = "allow"
+	SSLModePrefer     = "prefer"
+	SSLModeRequire    = "require"
+	SSLModeVerifyCA   = "verify-ca"
+	SSLModeVerifyFull = "verify-full"
+)
+
 // ConnectionConfig contains required data for gremlin
 type ConnectionConfig struct {
 	Host            string `env:"_HOST" envDefault:"localhost"`
@@ -20,6 +34,18 @@ type ConnectionConfig struct {
 	ApplicationName string `env:"_APPLICATIONNAME" envDefault:""`
 	MaxOpenConns    int    `env:"_MAXOPENCONNS" envDefault:"500"`
 	ConnMaxLifetime int64  `env:"_CONNMAXLIFETIME" envDefault:"-1"`
+	// MaxIdleConns keeps the database/sql default of 2 when zero.
+	MaxIdleConns    int           `env:"_MAXIDLECONNS"`
+	ConnMaxIdleTime time.Duration `env:"_CONNMAXIDLETIME"`
+	ConnectTimeout  time.Duration `env:"_CONNECTTIMEOUT"`
+	// SSLMode is one of the SSLMode constants; empty means SSLModeDisable.
+	SSLMode string `env:"_SSLMODE"`
+	// SSLRootCert, SSLCert and SSLKey are file paths of the CA and client certificates.
+	SSLRootCert string `env:"_SSLROOTCERT"`
+	SSLCert     string `env:"_SSLCERT"`
+	SSLKey      string `env:"_SSLKEY"`
+	// Replicas lists read replicas as host or host:port; the port defaults to Port.
+	Replicas []string `env:"_REPLICAS" envSeparator:","`
 }
 
 // GetConnectionConfigFromEnv return aerospike configs bases on environment variables
@@ -36,16 +62,133 @@ func GetConnectionConfigFromEnv() (*ConnectionConfig, error) {
 	return c, nil
 }
 
+// Validate checks the SSL settings.
+func (c *ConnectionConfig) Validate() error {
+	switch c.sslMode() {
+	case SSLModeDisable, SSLModeAllow, SSLModePrefer, SSLModeRequire:
+	case SSLModeVerifyCA, SSLModeVerifyFull:
+		if c.SSLRootCert == "" {
+			return fmt.Errorf("%w: %s needs a root certificate", ErrInvalidSSLConfig, c.SSLMode)
+		}
+	default:
+		return fmt.Errorf("%w: unknown mode %q", ErrInvalidSSLConfig, c.SSLMode)
+	}
+
+	if (c.SSLCert == "") != (c.SSLKey == "") {
+		return fmt.Errorf("%w: client certificate and key must be set together", ErrInvalidSSLConfig)
+	}
+
+	return nil
+}
+
+// GetDSN returns a key/value DSN with values quoted and escaped as libpq expects.
 func (c *ConnectionConfig) GetDSN() string {
-	if c.ApplicationName == "" {
-		return fmt.Sprintf(
-			"port=%s dbname=%s user=%s password=%s host=%s sslmode=disable",
-			c.Port, c.DB, c.User, c.Password, c.Host,
-		)
+	var builder strings.Builder
+
+	for _, param := range c.params() {
+		if builder.Len() > 0 {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString(param[0] + "=" + quoteDSNValue(param[1]))
+	}
+
+	return builder.String()
+}
+
+// GetURL returns the DSN in postgres:// URL form.
+func (c *ConnectionConfig) GetURL() string {
+	query := url.Values{}
+
+	for _, param := range c.params() {
+		switch param[0] {
+		case "host", "port", "dbname", "user", "password":
+		default:
+			query.Set(param[0], param[1])
+		}
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(c.Host, c.Port),
+		Path:     "/" + c.DB,
+		RawQuery: query.Encode(),
+	}
+
+	return dsn.String()
+}
+
+// ReplicaConfigs returns a copy of the config for every replica, without replicas of its own.
+func (c *ConnectionConfig) ReplicaConfigs() []*ConnectionConfig {
+	configs := make([]*ConnectionConfig, 0, len(c.Replicas))
+
+	for _, replica := range c.Replicas {
+		replica = strings.TrimSpace(replica)
+		if replica == "" {
+			continue
+		}
+
+		config := *c
+		config.Replicas = nil
+		config.Host = replica
+
+		if host, port, err := net.SplitHostPort(replica); err == nil {
+			config.Host, config.Port = host, port
+		}
+
+		configs = append(configs, &config)
+	}
+
+	return configs
+}
+
+func (c *ConnectionConfig) sslMode() string {
+	if c.SSLMode == "" {
+		return SSLModeDisable
+	}
+
+	return c.SSLMode
+}
+
+func (c *ConnectionConfig) params() [][2]string {
+	params := [][2]string{
+		{"port", c.Port},
+		{"dbname", c.DB},
+		{"user", c.User},
+		{"password", c.Password},
+		{"host", c.Host},
+	}
+
+	if c.ApplicationName != "" {
+		params = append(params, [2]string{"fallback_application_name", c.ApplicationName})
+	}
+
+	params = append(params, [2]string{"sslmode", c.sslMode()})
+
+	for _, param := range [][2]string{
+		{"sslrootcert", c.SSLRootCert},
+		{"sslcert", c.SSLCert},
+		{"sslkey", c.SSLKey},
+	} {
+		if param[1] != "" {
+			params = append(params, param)
+		}
+	}
+
+	if c.ConnectTimeout > 0 {
+		seconds := max(int64(c.ConnectTimeout/time.Second), 1)
+		params = append(params, [2]string{"connect_timeout", strconv.FormatInt(seconds, 10)})
+	}
+
+	return params
+}
+
+// quoteDSNValue quotes values with spaces, quotes or backslashes, and empty values.
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
 	}
 
-	return fmt.Sprintf(
-		"port=%s dbname=%s user=%s password=%s host=%s fallback_application_name=%s sslmode=disable",
-		c.Port, c.DB, c.User, c.Password, c.Host, c.ApplicationName,
-	)
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
 }
@@ -1,5 +1,41 @@
 package postgres
 
-import "errors"
+import (
+	"errors"
 
-var ErrConnectionIsNotSet = errors.New("connection is not set")
+	"github.com/jackc/pgx/v5/pgconn"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// All kind of errors
+var (
+	ErrConnectionIsNotSet = errors.New("connection is not set")
+	ErrInvalidSSLConfig   = errors.New("invalid postgres ssl config")
+)
+
+// Postgres SQLSTATE codes mapped to core error categories.
+const (
+	sqlStateUniqueViolation    = "23505"
+	sqlStateExclusionViolation = "23P01"
+)
+
+func init() {
+	coreerrors.RegisterClassifier(classifyError)
+}
+
+// classifyError maps unique and exclusion violations to conflicts; sql.ErrNoRows
+// is classified as not found by the core errors package.
+func classifyError(err error) coreerrors.Category {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return coreerrors.CategoryUnknown
+	}
+
+	switch pgErr.Code {
+	case sqlStateUniqueViolation, sqlStateExclusionViolation:
+		return coreerrors.CategoryConflict
+	default:
+		return coreerrors.CategoryUnknown
+	}
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestErrorCategory(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want coreerrors.Category
+	}{
+		{
+			name: "no rows",
+			err:  coreerrors.WrapBoundary("postgres", "query row", sql.ErrNoRows),
+			want: coreerrors.CategoryNotFound,
+		},
+		{
+			name: "unique violation",
+			err:  coreerrors.WrapBoundary("postgres", "exec", &pgconn.PgError{Code: sqlStateUniqueViolation}),
+			want: coreerrors.CategoryConflict,
+		},
+		{
+			name: "exclusion violation",
+			err:  &pgconn.PgError{Code: sqlStateExclusionViolation},
+			want: coreerrors.CategoryConflict,
+		},
+		{
+			name: "other postgres error",
+			err:  coreerrors.WrapBoundary("postgres", "exec", &pgconn.PgError{Code: "42P01"}),
+			want: coreerrors.CategoryUpstream,
+		},
+		{name: "other", err: errors.New("boom"), want: coreerrors.CategoryInternal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := coreerrors.CategoryOf(test.err); got != test.want {
+				t.Fatalf("CategoryOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log/slog"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Migration defaults.
+const (
+	DefaultMigrationsTable = "schema_migrations"
+
+	migrationUpSuffix   = ".up.sql"
+	migrationDownSuffix = ".down.sql"
+)
+
+// Migration errors.
+var (
+	ErrMigrationName     = errors.New("migration file name must look like 0001_name.up.sql or 0001_name.down.sql")
+	ErrMigrationDup      = errors.New("duplicate migration version")
+	ErrMigrationNoUp     = errors.New("migration has no up file")
+	ErrMigrationNoDown   = errors.New("migration has no down file")
+	ErrMigrationChecksum = errors.New("applied migration differs from its source file")
+	ErrMigrationUnknown  = errors.New("applied migration has no source file")
+	ErrMigrationFS       = errors.New("migration fs is not set")
+)
+
+// Direction is the way a migration step is applied.
+type Direction string
+
+// Migration directions.
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+// Migration is one versioned pair of up and down SQL scripts. Checksum is the
+// hex SHA-256 of Up and is recorded when the migration is applied.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationStep is a migration planned or applied in one direction.
+type MigrationStep struct {
+	Migration Migration
+	Direction Direction
+}
+
+// MigrationStatus reports whether a source migration is applied.
+type MigrationStatus struct {
+	Migration Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// MigrationConfig configures a Migrator.
+type MigrationConfig struct {
+	// FS holds the migration files, typically an embed.FS.
+	FS fs.FS
+	// Dir is the directory inside FS; empty uses the root.
+	Dir string
+	// Table records applied versions and may be schema-qualified; defaults to DefaultMigrationsTable.
+	Table string
+	// LockID is the advisory lock key; zero derives it from Table so only one replica migrates a schema.
+	LockID int64
+	// DryRun plans and logs the steps without executing them or creating Table.
+	DryRun bool
+}
+
+// Migrator applies versioned SQL migrations from an fs.FS.
+type Migrator struct {
+	db         *DatabaseClient
+	cfg        MigrationConfig
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations of cfg.FS. Files are named
+// <version>_<name>.up.sql and <version>_<name>.down.sql; the down file is optional.
+func (d *DatabaseClient) NewMigrator(cfg MigrationConfig) (*Migrator, error) {
+	if cfg.FS == nil {
+		return nil, ErrMigrationFS
+	}
+
+	if cfg.Dir == "" {
+		cfg.Dir = "."
+	}
+
+	if cfg.Table == "" {
+		cfg.Table = DefaultMigrationsTable
+	}
+
+	if cfg.LockID == 0 {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(cfg.Table))
+		cfg.LockID = int64(hash.Sum64()) //nolint:gosec // any 64-bit key works for an advisory lock
+	}
+
+	migrations, err := LoadMigrations(cfg.FS, cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: d, cfg: cfg, migrations: migrations}, nil
+}
+
+// Migrate applies every pending migration of cfg; it suits an app init callback
+// that must finish before routes are registered.
+func (d *DatabaseClient) Migrate(ctx context.Context, cfg MigrationConfig) ([]MigrationStep, error) {
+	migrator, err := d.NewMigrator(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrator.Up(ctx)
+}
+
+// LoadMigrations reads the migrations in dir of fsys, sorted by version.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		version, name, direction, err := parseMigrationName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != name {
+			return nil, fmt.Errorf("%w: %d", ErrMigrationDup, version)
+		}
+
+		script := &migration.Up
+		if direction == DirectionDown {
+			script = &migration.Down
+		}
+
+		if *script != "" {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationDup, entry.Name())
+		}
+
+		*script = string(content)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMigrationNoUp, migration.Version, migration.Name)
+		}
+
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Migrations returns the loaded migrations sorted by version.
+func (m *Migrator) Migrations() []Migration {
+	return slices.Clone(m.migrations)
+}
+
+// Up applies every pending migration. Versions applied by a newer release and
+// missing from the source are left alone, so older replicas can still start.
+func (m *Migrator) Up(ctx context.Context) ([]MigrationStep, error) {
+	return m.To(ctx, math.MaxInt64)
+}
+
+// To migrates to version: pending migrations up to version are applied in order and
+// applied migrations above it are rolled back in reverse order. Each step runs in its
+// own transaction while the advisory lock is held. The steps are returned even in
+// dry-run mode; on failure the steps completed before it are returned with the error.
+func (m *Migrator) To(ctx context.Context, version int64) (steps []MigrationStep, err error) {
+	err = m.locked(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		plan, err := m.plan(applied, version)
+		if err != nil {
+			return err
+		}
+
+		for _, step := range plan {
+			slog.Default().Info("postgres migration",
+				"version", step.Migration.Version, "name", step.Migration.Name,
+				"direction", step.Direction, "dry_run", m.cfg.DryRun)
+
+			if !m.cfg.DryRun {
+				if err := m.apply(ctx, conn, step); err != nil {
+					return err
+				}
+			}
+
+			steps = append(steps, step)
+		}
+
+		return nil
+	})
+
+	return steps, err
+}
+
+// Status lists the source migrations with their applied state.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var status []MigrationStatus
+
+	err := m.locked(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			record, ok := applied[migration.Version]
+			status = append(status, MigrationStatus{Migration: migration, Applied: ok, AppliedAt: record.AppliedAt})
+		}
+
+		return nil
+	})
+
+	return status, err
+}
+
+type appliedMigration struct {
+	Version   int64     `db:"version"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+func (m *Migrator) plan(applied map[int64]appliedMigration, target int64) ([]MigrationStep, error) {
+	var plan []MigrationStep
+
+	known := make(map[int64]struct{}, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		known[migration.Version] = struct{}{}
+
+		record, ok := applied[migration.Version]
+		if ok && record.Checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMigrationChecksum, migration.Version, migration.Name)
+		}
+
+		if !ok && migration.Version <= target {
+			plan = append(plan, MigrationStep{Migration: migration, Direction: DirectionUp})
+		}
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok || migration.Version <= target {
+			continue
+		}
+
+		if migration.Down == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMigrationNoDown, migration.Version, migration.Name)
+		}
+
+		plan = append(plan, MigrationStep{Migration: migration, Direction: DirectionDown})
+	}
+
+	for version := range applied {
+		if _, ok := known[version]; !ok && version > target {
+			return nil, fmt.Errorf("%w: %d", ErrMigrationUnknown, version)
+		}
+	}
+
+	return plan, nil
+}
+
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sqlx.Conn) error) (err error) {
+	conn, err := m.db.db.Connx(ctx)
+	if err != nil {
+		return coreerrors.WrapBoundary("postgres", "migrate", err)
+	}
+
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil && err == nil {
+			err = coreerrors.WrapBoundary("postgres", "release connection", closeErr)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", m.cfg.LockID); err != nil {
+		return coreerrors.WrapBoundary("postgres", "migration lock", err)
+	}
+
+	defer func() {
+		// The lock is session-scoped, so unlock with a fresh context even if ctx is done.
+		if _, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", m.cfg.LockID); unlockErr != nil && err == nil {
+			err = coreerrors.WrapBoundary("postgres", "migration unlock", unlockErr)
+		}
+	}()
+
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sqlx.Conn) (map[int64]appliedMigration, error) {
+	table := identifier(m.cfg.Table).Sanitize()
+
+	if m.cfg.DryRun {
+		var exists bool
+		if err := conn.GetContext(ctx, &exists, "SELECT to_regclass($1) IS NOT NULL", table); err != nil {
+			return nil, coreerrors.WrapBoundary("postgres", "migration table", err)
+		}
+
+		if !exists {
+			return map[int64]appliedMigration{}, nil
+		}
+	} else {
+		if _, err := conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+table+` (
+	version BIGINT PRIMARY KEY,
+	name TEXT NOT NULL,
+	checksum TEXT NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`); err != nil {
+			return nil, coreerrors.WrapBoundary("postgres", "migration table", err)
+		}
+	}
+
+	var records []appliedMigration
+	if err := conn.SelectContext(ctx, &records, "SELECT version, checksum, applied_at FROM "+table+" ORDER BY version"); err != nil {
+		return nil, coreerrors.WrapBoundary("postgres", "migration versions", err)
+	}
+
+	applied := make(map[int64]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, step MigrationStep) error {
+	table := identifier(m.cfg.Table).Sanitize()
+	op := "migrate " + string(step.Direction) + " " + strconv.FormatInt(step.Migration.Version, 10)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return coreerrors.WrapBoundary("postgres", op, err)
+	}
+
+	script := step.Migration.Up
+	record := "INSERT INTO " + table + " (version, name, checksum) VALUES ($1, $2, $3)"
+	args := []any{step.Migration.Version, step.Migration.Name, step.Migration.Checksum}
+
+	if step.Direction == DirectionDown {
+		script = step.Migration.Down
+		record = "DELETE FROM " + table + " WHERE version = $1"
+		args = args[:1]
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		_ = tx.Rollback()
+
+		return coreerrors.WrapBoundary("postgres", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		_ = tx.Rollback()
+
+		return coreerrors.WrapBoundary("postgres", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return coreerrors.WrapBoundary("postgres", op, err)
+	}
+
+	return nil
+}
+
+func parseMigrationName(file string) (int64, string, Direction, error) {
+	direction := DirectionUp
+
+	base, ok := strings.CutSuffix(file, migrationUpSuffix)
+	if !ok {
+		direction = DirectionDown
+
+		if base, ok = strings.CutSuffix(file, migrationDownSuffix); !ok {
+			return 0, "", "", fmt.Errorf("%w: %s", ErrMigrationName, file)
+		}
+	}
+
+	rawVersion, name, _ := strings.Cut(base, "_")
+
+	version, err := strconv.ParseInt(rawVersion, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("%w: %s", ErrMigrationName, file)
+	}
+
+	return version, name, direction, nil
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/FrogoAI/testutils"
+)
+
+var testMigrations = fstest.MapFS{
+	"migrations/0001_users.up.sql":   {Data: []byte("create table users (id bigint)")},
+	"migrations/0001_users.down.sql": {Data: []byte("drop table users")},
+	"migrations/0002_email.up.sql":   {Data: []byte("alter table users add email text")},
+	"migrations/0002_email.down.sql": {Data: []byte("alter table users drop email")},
+	"migrations/README.md":           {Data: []byte("ignored")},
+}
+
+func TestLoadMigrations(t *testing.T) {
+	cases := []struct {
+		name     string
+		fsys     fstest.MapFS
+		versions []int64
+		err      error
+	}{
+		{name: "sorted with checksums", fsys: testMigrations, versions: []int64{1, 2}},
+		{
+			name: "bad name",
+			fsys: fstest.MapFS{"migrations/users.up.sql": {Data: []byte("select 1")}},
+			err:  ErrMigrationName,
+		},
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"migrations/0001_users.up.sql":  {Data: []byte("select 1")},
+				"migrations/0001_orders.up.sql": {Data: []byte("select 2")},
+			},
+			err: ErrMigrationDup,
+		},
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{"migrations/0001_users.down.sql": {Data: []byte("select 1")}},
+			err:  ErrMigrationNoUp,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			migrations, err := LoadMigrations(test.fsys, "migrations")
+			testutils.Equal(t, errors.Is(err, test.err), true)
+
+			var versions []int64
+
+			for _, migration := range migrations {
+				testutils.Equal(t, len(migration.Checksum), 64)
+				versions = append(versions, migration.Version)
+			}
+
+			testutils.Equal(t, versions, test.versions)
+		})
+	}
+}
+
+func expectMigrationLock(mock sqlmock.Sqlmock, applied ...any) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS "schema_migrations"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "checksum", "applied_at"})
+	for i := 0; i < len(applied); i += 2 {
+		rows.AddRow(applied[i], applied[i+1], time.Now())
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, checksum, applied_at FROM "schema_migrations"`)).WillReturnRows(rows)
+}
+
+func TestMigratorTo(t *testing.T) {
+	ctx := context.Background()
+	migrations, err := LoadMigrations(testMigrations, "migrations")
+	testutils.Equal(t, err, nil)
+
+	first, second := migrations[0], migrations[1]
+
+	t.Run("up applies pending migrations", func(t *testing.T) {
+		database, mock := newMockDatabase(t)
+		expectMigrationLock(mock, int64(1), first.Checksum)
+		mock.ExpectBegin()
+		mock.ExpectExec("alter table users add email").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "schema_migrations"`)).
+			WithArgs(int64(2), "email", second.Checksum).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		steps, err := database.Migrate(ctx, MigrationConfig{FS: testMigrations, Dir: "migrations"})
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, steps, []MigrationStep{{Migration: second, Direction: DirectionUp}})
+		testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+	})
+
+	t.Run("target rolls back in reverse order", func(t *testing.T) {
+		database, mock := newMockDatabase(t)
+		expectMigrationLock(mock, int64(1), first.Checksum, int64(2), second.Checksum)
+
+		for _, script := range []string{"alter table users drop email", "drop table users"} {
+			mock.ExpectBegin()
+			mock.ExpectExec(script).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "schema_migrations" WHERE version = $1`)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		migrator, err := database.NewMigrator(MigrationConfig{FS: testMigrations, Dir: "migrations"})
+		testutils.Equal(t, err, nil)
+
+		steps, err := migrator.To(ctx, 0)
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, steps, []MigrationStep{
+			{Migration: second, Direction: DirectionDown},
+			{Migration: first, Direction: DirectionDown},
+		})
+		testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+	})
+
+	t.Run("dry run only plans", func(t *testing.T) {
+		database, mock := newMockDatabase(t)
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT to_regclass($1) IS NOT NULL")).
+			WithArgs(`"schema_migrations"`).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		steps, err := database.Migrate(ctx, MigrationConfig{FS: testMigrations, Dir: "migrations", DryRun: true})
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, len(steps), 2)
+		testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+	})
+
+	t.Run("changed checksum is rejected", func(t *testing.T) {
+		database, mock := newMockDatabase(t)
+		expectMigrationLock(mock, int64(1), "edited")
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := database.Migrate(ctx, MigrationConfig{FS: testMigrations, Dir: "migrations"})
+		testutils.Equal(t, errors.Is(err, ErrMigrationChecksum), true)
+		testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+	})
+
+	t.Run("unknown version blocks rollback only", func(t *testing.T) {
+		database, mock := newMockDatabase(t)
+		expectMigrationLock(mock, int64(1), first.Checksum, int64(2), second.Checksum, int64(3), "newer")
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		steps, err := database.Migrate(ctx, MigrationConfig{FS: testMigrations, Dir: "migrations"})
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, len(steps), 0)
+
+		migrator, err := database.NewMigrator(MigrationConfig{FS: testMigrations, Dir: "migrations"})
+		testutils.Equal(t, err, nil)
+
+		expectMigrationLock(mock, int64(1), first.Checksum, int64(3), "newer")
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err = migrator.To(ctx, 1)
+		testutils.Equal(t, errors.Is(err, ErrMigrationUnknown), true)
+		testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+	})
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/InsideGallery/core/db/instrument"
+)
+
+// statementTable matches the first table a statement reads or writes.
+var statementTable = regexp.MustCompile(`(?i)\b(?:from|into|update|join|table)\s+((?:"[^"]+"|[\w$]+)(?:\.(?:"[^"]+"|[\w$]+))?)`)
+
+func (d *DatabaseClient) observe(ctx context.Context, operation, target, statement string) func(rows int64, err error) {
+	return instrument.Begin(ctx, d.observer, instrument.Event{
+		System:    instrument.SystemPostgres,
+		Operation: operation,
+		Target:    target,
+		Statement: statement,
+	})
+}
+
+func (d *DatabaseClient) observeStatement(ctx context.Context, operation, query string) func(rows int64, err error) {
+	return d.observe(ctx, operation, statementTarget(query), query)
+}
+
+// statementTarget returns the unquoted first table of query, or an empty string.
+func statementTarget(query string) string {
+	match := statementTable.FindStringSubmatch(query)
+	if match == nil {
+		return ""
+	}
+
+	return strings.ReplaceAll(match[1], `"`, "")
+}
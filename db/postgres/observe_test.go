@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/instrument"
+)
+
+func TestStatementTarget(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{query: "select id from users where id = $1", want: "users"},
+		{query: `INSERT INTO "public"."events" (name) VALUES ($1)`, want: "public.events"},
+		{query: "update accounts set balance = 0", want: "accounts"},
+		{query: "select 1", want: ""},
+	}
+
+	for _, test := range cases {
+		t.Run(test.query, func(t *testing.T) {
+			testutils.Equal(t, statementTarget(test.query), test.want)
+		})
+	}
+}
+
+func TestDatabaseClientObserver(t *testing.T) {
+	database, mock := newMockDatabase(t)
+
+	var events []instrument.Event
+
+	database.SetObserver(instrument.ObserverFunc(func(_ context.Context, event instrument.Event) {
+		events = append(events, event)
+	}))
+
+	mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery("select id from users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	_, err := database.Exec(context.Background(), Statement{Query: "update users set name = 'x'"})
+	testutils.Equal(t, err, nil)
+
+	_, err = Select[int](context.Background(), database, Statement{Query: "select id from users"})
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, len(events), 2)
+	testutils.Equal(t, events[0].System, instrument.SystemPostgres)
+	testutils.Equal(t, events[0].Operation, "exec")
+	testutils.Equal(t, events[0].Target, "users")
+	testutils.Equal(t, events[0].Rows, int64(3))
+	testutils.Equal(t, events[1].Operation, "select")
+	testutils.Equal(t, events[1].Rows, int64(2))
+}
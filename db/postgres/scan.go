@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Select scans every row of statement into a T, using sqlx `db` tags for structs.
+// It joins the transaction carried by ctx if any.
+func Select[T any](ctx context.Context, d *DatabaseClient, statement Statement) ([]T, error) {
+	done := d.observeStatement(ctx, "select", statement.Query)
+
+	var rows []T
+
+	err := sqlx.SelectContext(ctx, d.executor(ctx), &rows, statement.Query, statement.Args...)
+	done(int64(len(rows)), err)
+
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("postgres", "select", err)
+	}
+
+	return rows, nil
+}
+
+// SelectOne scans the first row of statement into a T. No rows returns an error wrapping
+// sql.ErrNoRows, classified as not found. It joins the transaction carried by ctx if any.
+// It is the generic counterpart of sqlx Get; the name Get is taken by the legacy client accessor.
+func SelectOne[T any](ctx context.Context, d *DatabaseClient, statement Statement) (T, error) {
+	done := d.observeStatement(ctx, "select one", statement.Query)
+
+	var row T
+
+	err := sqlx.GetContext(ctx, d.executor(ctx), &row, statement.Query, statement.Args...)
+	if err != nil {
+		done(0, err)
+
+		return row, coreerrors.WrapBoundary("postgres", "get", err)
+	}
+
+	done(1, nil)
+
+	return row, nil
+}
+
+// Named binds :name parameters from a struct with `db` tags or a map[string]any and
+// returns the positional Statement for Exec, Query, Select or Get.
+//
+//	statement, err := postgres.Named("update users set name = :name where id = :id", user)
+func Named(query string, arg any) (Statement, error) {
+	bound, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return Statement{}, coreerrors.WrapBoundary("postgres", "bind named", err)
+	}
+
+	return Statement{Query: sqlx.Rebind(sqlx.DOLLAR, bound), Args: args}, nil
+}
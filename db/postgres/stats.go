@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+	"github.com/InsideGallery/core/metrics"
+)
+
+// Pool metric names reported by ReportStats, tagged with role:primary or role:replica.
+const (
+	MetricPoolOpen         = "postgres.pool.open"
+	MetricPoolInUse        = "postgres.pool.in_use"
+	MetricPoolIdle         = "postgres.pool.idle"
+	MetricPoolWaitCount    = "postgres.pool.wait_count"
+	MetricPoolWaitDuration = "postgres.pool.wait_duration_ms"
+
+	DefaultStatsInterval      = 10 * time.Second
+	DefaultHealthCheckTimeout = 2 * time.Second
+
+	rolePrimary = "primary"
+	roleReplica = "replica"
+)
+
+// PoolStats is the connection pool state of the primary or one replica.
+type PoolStats struct {
+	Role    string
+	Replica int
+	sql.DBStats
+}
+
+// Stats returns the pool statistics of the primary followed by every replica.
+func (d *DatabaseClient) Stats() []PoolStats {
+	stats := []PoolStats{{Role: rolePrimary, DBStats: d.db.Stats()}}
+
+	for i, replica := range d.replicas {
+		stats = append(stats, PoolStats{Role: roleReplica, Replica: i, DBStats: replica.Stats()})
+	}
+
+	return stats
+}
+
+// ReportStats sends pool gauges to client every interval until ctx is done; zero
+// interval uses DefaultStatsInterval. Run it in its own goroutine.
+func (d *DatabaseClient) ReportStats(ctx context.Context, client *metrics.Client, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultStatsInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		d.reportStats(client)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// HealthCheck returns a check for profiler.AddHealthCheck that pings the primary
+// and every replica; zero timeout uses DefaultHealthCheckTimeout.
+func (d *DatabaseClient) HealthCheck(timeout time.Duration) func() error {
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var errs []error
+
+		for i, db := range append([]*sqlx.DB{d.db}, d.replicas...) {
+			if err := db.PingContext(ctx); err != nil {
+				op := "ping primary"
+				if i > 0 {
+					op = "ping replica " + strconv.Itoa(i-1)
+				}
+
+				errs = append(errs, coreerrors.WrapBoundary("postgres", op, err))
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+func (d *DatabaseClient) reportStats(client *metrics.Client) {
+	for _, stats := range d.Stats() {
+		tags := []string{"role:" + stats.Role}
+		if stats.Role == roleReplica {
+			tags = append(tags, "replica:"+strconv.Itoa(stats.Replica))
+		}
+
+		for name, value := range map[string]float64{
+			MetricPoolOpen:         float64(stats.OpenConnections),
+			MetricPoolInUse:        float64(stats.InUse),
+			MetricPoolIdle:         float64(stats.Idle),
+			MetricPoolWaitCount:    float64(stats.WaitCount),
+			MetricPoolWaitDuration: float64(stats.WaitDuration.Milliseconds()),
+		} {
+			if err := client.Gauge(name, value, tags); err != nil {
+				slog.Default().Warn("record postgres metric failed", "metric", name, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/InsideGallery/core/db/retry"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Transaction defaults.
+const (
+	DefaultTxMaxRetries = retry.DefaultMaxRetries
+	DefaultTxRetryDelay = retry.DefaultDelay
+)
+
+// Postgres SQLSTATE codes that make a transaction safe to retry.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// TxOptions is the core-owned input for WithTx.
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// MaxRetries is how many times the whole transaction is retried after a serialization
+	// failure or deadlock; zero uses DefaultTxMaxRetries and a negative value disables retries.
+	MaxRetries int
+	// RetryDelay is multiplied by the attempt number between retries; zero uses DefaultTxRetryDelay.
+	RetryDelay time.Duration
+}
+
+// txState is the transaction carried by the context. The connection is pinned so
+// COPY runs inside the same transaction.
+type txState struct {
+	db         *sqlx.DB
+	conn       *sqlx.Conn
+	tx         *sqlx.Tx
+	savepoints int
+}
+
+type txContextKey struct{}
+
+// execer is implemented by *sqlx.DB and *sqlx.Tx.
+type execer interface {
+	sqlx.ExtContext
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// WithTx runs fn in a transaction carried by the context passed to fn, so every
+// DatabaseClient call and helper using that context joins it. fn returning an error
+// or panicking rolls back; otherwise the transaction commits.
+//
+// A WithTx call inside fn opens a savepoint instead, and rolls back only to that
+// savepoint on error. Only the outermost call retries, re-running fn from the start
+// after a serialization failure or deadlock, so fn must not have side effects
+// outside the database.
+func (d *DatabaseClient) WithTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	if state := d.txState(ctx); state != nil {
+		return d.withSavepoint(ctx, state, fn)
+	}
+
+	policy := retry.Policy{MaxRetries: opts.MaxRetries, Delay: opts.RetryDelay}
+
+	return retry.Do(ctx, policy, IsRetryable, func(int) error {
+		return d.runTx(ctx, opts, fn)
+	})
+}
+
+// InTx reports whether ctx carries a transaction of this client.
+func (d *DatabaseClient) InTx(ctx context.Context) bool {
+	return d.txState(ctx) != nil
+}
+
+// IsRetryable reports whether err is a serialization failure or deadlock.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
+
+func (d *DatabaseClient) runTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) (err error) {
+	conn, err := d.db.Connx(ctx)
+	if err != nil {
+		return coreerrors.WrapBoundary("postgres", "begin", err)
+	}
+
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil && err == nil {
+			err = coreerrors.WrapBoundary("postgres", "release connection", closeErr)
+		}
+	}()
+
+	tx, err := conn.BeginTxx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return coreerrors.WrapBoundary("postgres", "begin", err)
+	}
+
+	state := &txState{db: d.db, conn: conn, tx: tx}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_ = tx.Rollback()
+
+			panic(recovered)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, state)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, coreerrors.WrapBoundary("postgres", "rollback", rollbackErr))
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return coreerrors.WrapBoundary("postgres", "commit", err)
+	}
+
+	return nil
+}
+
+func (d *DatabaseClient) withSavepoint(ctx context.Context, state *txState, fn func(ctx context.Context) error) (err error) {
+	state.savepoints++
+	name := "sp_" + strconv.Itoa(state.savepoints)
+
+	if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return coreerrors.WrapBoundary("postgres", "savepoint", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_, _ = state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+
+			panic(recovered)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		if _, rollbackErr := state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rollbackErr != nil {
+			return errors.Join(err, coreerrors.WrapBoundary("postgres", "rollback savepoint", rollbackErr))
+		}
+
+		return err
+	}
+
+	if _, err := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return coreerrors.WrapBoundary("postgres", "release savepoint", err)
+	}
+
+	return nil
+}
+
+func (d *DatabaseClient) txState(ctx context.Context) *txState {
+	state, ok := ctx.Value(txContextKey{}).(*txState)
+	if !ok || state.db != d.db {
+		return nil
+	}
+
+	return state
+}
+
+// executor returns the transaction carried by ctx or the pool.
+//
+//nolint:ireturn // the pool and the transaction share the sqlx interfaces
+func (d *DatabaseClient) executor(ctx context.Context) execer {
+	if state := d.txState(ctx); state != nil {
+		return state.tx
+	}
+
+	return d.db
+}
@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/FrogoAI/testutils"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+type testUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func newMockDatabase(t *testing.T) (*DatabaseClient, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	testutils.Equal(t, err, nil)
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return WrapDatabase(sqlx.NewDb(db, "pgx")), mock
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	errBoom := errors.New("boom")
+
+	cases := []struct {
+		name    string
+		expect  func(mock sqlmock.Sqlmock)
+		fn      func(d *DatabaseClient) func(ctx context.Context) error
+		wantErr error
+	}{
+		{
+			name: "commits and propagates tx",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(d *DatabaseClient) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if !d.InTx(ctx) {
+						return errBoom
+					}
+
+					_, err := d.Exec(ctx, Statement{Query: "insert into users (name) values ($1)", Args: []any{"ada"}})
+
+					return err
+				}
+			},
+		},
+		{
+			name: "rolls back on error",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(*DatabaseClient) func(ctx context.Context) error {
+				return func(context.Context) error { return errBoom }
+			},
+			wantErr: errBoom,
+		},
+		{
+			name: "nested call uses a savepoint",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(d *DatabaseClient) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					nestedErr := d.WithTx(ctx, TxOptions{}, func(context.Context) error { return errBoom })
+					if !errors.Is(nestedErr, errBoom) {
+						return nestedErr
+					}
+
+					return d.WithTx(ctx, TxOptions{}, func(context.Context) error { return nil })
+				}
+			},
+		},
+		{
+			name: "retries serialization failures",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("update").WillReturnError(&pgconn.PgError{Code: sqlStateSerializationFailure})
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec("update").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(d *DatabaseClient) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					_, err := d.Exec(ctx, Statement{Query: "update users set name = 'x'"})
+
+					return err
+				}
+			},
+		},
+		{
+			name: "stops after max retries",
+			expect: func(mock sqlmock.Sqlmock) {
+				for range 2 {
+					mock.ExpectBegin()
+					mock.ExpectExec("update").WillReturnError(&pgconn.PgError{Code: sqlStateDeadlockDetected})
+					mock.ExpectRollback()
+				}
+			},
+			fn: func(d *DatabaseClient) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					_, err := d.Exec(ctx, Statement{Query: "update users set name = 'x'"})
+
+					return err
+				}
+			},
+			wantErr: coreerrors.BoundaryError{},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			database, mock := newMockDatabase(t)
+			test.expect(mock)
+
+			err := database.WithTx(ctx, TxOptions{MaxRetries: 1, RetryDelay: time.Millisecond}, test.fn(database))
+
+			switch {
+			case test.wantErr == nil:
+				testutils.Equal(t, err, nil)
+			case errors.As(test.wantErr, new(coreerrors.BoundaryError)):
+				testutils.Equal(t, IsRetryable(err), true)
+			default:
+				testutils.Equal(t, errors.Is(err, test.wantErr), true)
+			}
+
+			testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+		})
+	}
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	database, mock := newMockDatabase(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	defer func() {
+		testutils.Equal(t, recover(), any("boom"))
+		testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+	}()
+
+	_ = database.WithTx(context.Background(), TxOptions{}, func(context.Context) error {
+		panic("boom")
+	})
+}
+
+func TestSelectHelpers(t *testing.T) {
+	ctx := context.Background()
+	database, mock := newMockDatabase(t)
+
+	mock.ExpectQuery("select id, name from users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "ada").AddRow(2, "lin"))
+	mock.ExpectQuery("select id, name from users where id").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	users, err := Select[testUser](ctx, database, Statement{Query: "select id, name from users"})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, users, []testUser{{ID: 1, Name: "ada"}, {ID: 2, Name: "lin"}})
+
+	_, err = SelectOne[testUser](ctx, database, Statement{Query: "select id, name from users where id = $1", Args: []any{3}})
+	testutils.Equal(t, errors.Is(err, sql.ErrNoRows), true)
+	testutils.Equal(t, coreerrors.CategoryOf(err), coreerrors.CategoryNotFound)
+	testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+}
+
+func TestNamed(t *testing.T) {
+	statement, err := Named("update users set name = :name where id = :id", testUser{ID: 7, Name: "ada"})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, statement.Query, "update users set name = $1 where id = $2")
+	testutils.Equal(t, statement.Args, []any{"ada", int64(7)})
+
+	_, err = Named("select :missing", map[string]any{})
+	testutils.Equal(t, err != nil, true)
+}
+
+func TestBulkInsertFallsBackToInsert(t *testing.T) {
+	ctx := context.Background()
+	database, mock := newMockDatabase(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "public"."users" ("id", "name") VALUES ($1, $2), ($3, $4)`)).
+		WithArgs(1, "ada", 2, "lin").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := database.WithTx(ctx, TxOptions{}, func(ctx context.Context) error {
+		written, err := database.BulkInsert(ctx, "public.users", []string{"id", "name"}, [][]any{{1, "ada"}, {2, "lin"}})
+		testutils.Equal(t, written, int64(2))
+
+		return err
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, mock.ExpectationsWereMet(), nil)
+
+	_, err = database.BulkInsert(ctx, "users", []string{"id"}, [][]any{{1, "extra"}})
+	testutils.Equal(t, errors.Is(err, ErrBulkRowWidth), true)
+}
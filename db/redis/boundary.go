@@ -5,14 +5,14 @@
 //
 //	import "github.com/InsideGallery/core/db/redis"
 //
-//	store := redis.NewConnectionStore(redis.NewRedisClient(config))
-//	conn, err := store.Get()
+//	store := redis.NewConnectionStore(nil)
+//	conn, err := store.GetOrCreate(config)
 //
 // Prefer KeyValueStore with GetOptions, SetOptions, StringResult, and
 // CommandResult for application-facing code.
 //
 // Compatibility: package-level Set, Get, and Default remain available for
-// existing consumers. Prefer NewRedisClient or ConnectionStore.GetOrCreate with
+// existing consumers. Prefer NewConnection or ConnectionStore.GetOrCreate with
 // explicit configuration in new code.
 package redis
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/InsideGallery/core/db/instrument"
 	coreerrors "github.com/InsideGallery/core/errors"
 )
 
@@ -56,7 +57,11 @@ type KeyValueStore interface {
 
 // GetValue reads a Redis strings value with core-owned options.
 func (c Connection) GetValue(ctx context.Context, options GetOptions) (StringResult, error) {
+	done := c.observe(ctx, "get value")
+
 	value, present, err := c.Get(ctx, options.Key)
+	done(boolRows(present), err)
+
 	if err != nil {
 		return StringResult{}, coreerrors.WrapBoundary("redis", "get value", err)
 	}
@@ -70,9 +75,36 @@ func (c Connection) GetValue(ctx context.Context, options GetOptions) (StringRes
 
 // SetValue writes a Redis strings value with core-owned options.
 func (c Connection) SetValue(ctx context.Context, options SetOptions) (CommandResult, error) {
-	if err := c.Set(ctx, options.Key, options.Value, options.TTL); err != nil {
+	done := c.observe(ctx, "set value")
+
+	err := c.Set(ctx, options.Key, options.Value, options.TTL)
+	done(boolRows(err == nil), err)
+
+	if err != nil {
 		return CommandResult{}, coreerrors.WrapBoundary("redis", "set value", err)
 	}
 
 	return CommandResult{Key: options.Key}, nil
 }
+
+// SetObserver sets the observer of the core-owned key-value operations; nil uses
+// instrument.Default. Call it before the connection is shared.
+func (c *Connection) SetObserver(observer instrument.Observer) {
+	c.observer = observer
+}
+
+// observe reports no target: keys are unbounded and would explode metric cardinality.
+func (c Connection) observe(ctx context.Context, operation string) func(rows int64, err error) {
+	return instrument.Begin(ctx, c.observer, instrument.Event{
+		System:    instrument.SystemRedis,
+		Operation: operation,
+	})
+}
+
+func boolRows(ok bool) int64 {
+	if ok {
+		return 1
+	}
+
+	return 0
+}
@@ -52,7 +52,11 @@ func (s *ConnectionStore) GetOrCreate(config *ConnectionConfig) (*Connection, er
 		return nil, err
 	}
 
-	client = NewRedisClient(config)
+	client, err = NewConnection(config)
+	if err != nil {
+		return nil, err
+	}
+
 	s.Set(client)
 
 	return client, nil
@@ -89,7 +93,7 @@ func Get() (*Connection, error) {
 
 // Default return default client
 //
-// Deprecated: use NewRedisClient or ConnectionStore.GetOrCreate with explicit config.
+// Deprecated: use NewConnection or ConnectionStore.GetOrCreate with explicit config.
 func Default() (*Connection, error) {
 	c, err := Get()
 	if err != nil {
@@ -1,19 +1,73 @@
 package redis
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v10"
+	"github.com/redis/go-redis/v9"
 )
 
 const EnvPrefix = "REDIS"
 
+// Connection modes accepted by ConnectionConfig.Mode.
+const (
+	ModeSingle   = "single"
+	ModeSentinel = "sentinel"
+	ModeCluster  = "cluster"
+)
+
+// Read routings accepted by ConnectionConfig.ReadRouting for Sentinel and Cluster modes.
+const (
+	// RoutePrimary sends every command to the primary.
+	RoutePrimary = "primary"
+	// RouteReplica sends read-only commands to replicas.
+	RouteReplica = "replica"
+	// RouteLatency sends read-only commands to the closest node, primary or replica.
+	RouteLatency = "latency"
+	// RouteRandom sends read-only commands to a random node, primary or replica.
+	RouteRandom = "random"
+)
+
 type ConnectionConfig struct {
 	Host     string `env:"_HOST" envDefault:"localhost"`
 	Port     string `env:"_PORT" envDefault:"6379"`
 	User     string `env:"_USER" envDefault:""`
 	Pass     string `env:"_PASS" envDefault:""`
 	Database int    `env:"_DATABASE" envDefault:"0"`
+	// Mode is one of the Mode constants; empty means ModeSingle.
+	Mode string `env:"_MODE"`
+	// MasterName and SentinelAddrs locate the primary in ModeSentinel.
+	MasterName    string   `env:"_MASTERNAME"`
+	SentinelAddrs []string `env:"_SENTINELADDRS" envSeparator:","`
+	SentinelUser  string   `env:"_SENTINELUSER"`
+	SentinelPass  string   `env:"_SENTINELPASS"`
+	// ClusterAddrs is the seed list of ModeCluster; empty uses Host:Port.
+	ClusterAddrs []string `env:"_CLUSTERADDRS" envSeparator:","`
+	// ReadRouting is one of the Route constants; empty means RoutePrimary.
+	ReadRouting string `env:"_READROUTING"`
+	// TLS enables TLS; TLSCACert, TLSCert and TLSKey are PEM file paths.
+	TLS                   bool   `env:"_TLS"`
+	TLSCACert             string `env:"_TLSCACERT"`
+	TLSCert               string `env:"_TLSCERT"`
+	TLSKey                string `env:"_TLSKEY"`
+	TLSServerName         string `env:"_TLSSERVERNAME"`
+	TLSInsecureSkipVerify bool   `env:"_TLSINSECURESKIPVERIFY"`
+	// Timeouts and pool sizes keep the go-redis defaults when zero.
+	DialTimeout     time.Duration `env:"_DIALTIMEOUT"`
+	ReadTimeout     time.Duration `env:"_READTIMEOUT"`
+	WriteTimeout    time.Duration `env:"_WRITETIMEOUT"`
+	PoolSize        int           `env:"_POOLSIZE"`
+	MinIdleConns    int           `env:"_MINIDLECONNS"`
+	MaxIdleConns    int           `env:"_MAXIDLECONNS"`
+	PoolTimeout     time.Duration `env:"_POOLTIMEOUT"`
+	ConnMaxIdleTime time.Duration `env:"_CONNMAXIDLETIME"`
+	ConnMaxLifetime time.Duration `env:"_CONNMAXLIFETIME"`
 }
 
 func GetConnectionConfigFromEnv() (*ConnectionConfig, error) {
@@ -28,3 +82,138 @@ func GetConnectionConfigFromEnv() (*ConnectionConfig, error) {
 
 	return c, nil
 }
+
+// Validate checks the mode, read routing and TLS settings.
+func (c *ConnectionConfig) Validate() error {
+	switch c.mode() {
+	case ModeSingle, ModeCluster:
+	case ModeSentinel:
+		if c.MasterName == "" || len(c.SentinelAddrs) == 0 {
+			return fmt.Errorf("%w: sentinel mode needs a master name and sentinel addresses", ErrInvalidConfig)
+		}
+	default:
+		return fmt.Errorf("%w: unknown mode %q", ErrInvalidConfig, c.Mode)
+	}
+
+	switch c.readRouting() {
+	case RoutePrimary, RouteReplica, RouteLatency, RouteRandom:
+	default:
+		return fmt.Errorf("%w: unknown read routing %q", ErrInvalidConfig, c.ReadRouting)
+	}
+
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return fmt.Errorf("%w: client certificate and key must be set together", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// GetAddr returns Host:Port.
+func (c *ConnectionConfig) GetAddr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
+
+// UniversalOptions returns the go-redis options of the config, loading TLS files.
+func (c *ConnectionConfig) UniversalOptions() (*redis.UniversalOptions, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	options := &redis.UniversalOptions{
+		Addrs:            []string{c.GetAddr()},
+		Username:         c.User,
+		Password:         c.Pass,
+		DB:               c.Database,
+		SentinelUsername: c.SentinelUser,
+		SentinelPassword: c.SentinelPass,
+		DialTimeout:      c.DialTimeout,
+		ReadTimeout:      c.ReadTimeout,
+		WriteTimeout:     c.WriteTimeout,
+		PoolSize:         c.PoolSize,
+		MinIdleConns:     c.MinIdleConns,
+		MaxIdleConns:     c.MaxIdleConns,
+		PoolTimeout:      c.PoolTimeout,
+		ConnMaxIdleTime:  c.ConnMaxIdleTime,
+		ConnMaxLifetime:  c.ConnMaxLifetime,
+		TLSConfig:        tlsConfig,
+	}
+
+	switch c.mode() {
+	case ModeSentinel:
+		options.MasterName = c.MasterName
+		options.Addrs = c.SentinelAddrs
+	case ModeCluster:
+		if len(c.ClusterAddrs) > 0 {
+			options.Addrs = c.ClusterAddrs
+		}
+	}
+
+	switch c.readRouting() {
+	case RouteReplica:
+		options.ReadOnly = true
+	case RouteLatency:
+		options.RouteByLatency = true
+	case RouteRandom:
+		options.RouteRandomly = true
+	}
+
+	return options, nil
+}
+
+func (c *ConnectionConfig) mode() string {
+	if c.Mode == "" {
+		return ModeSingle
+	}
+
+	return c.Mode
+}
+
+func (c *ConnectionConfig) readRouting() string {
+	if c.ReadRouting == "" {
+		return RoutePrimary
+	}
+
+	return c.ReadRouting
+}
+
+func (c *ConnectionConfig) tlsConfig() (*tls.Config, error) {
+	if !c.TLS {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.TLSServerName,
+		InsecureSkipVerify: c.TLSInsecureSkipVerify, //nolint:gosec // explicit opt-in for test environments
+	}
+
+	if c.TLSCACert != "" {
+		pem, err := os.ReadFile(c.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("%w: read CA certificate: %w", ErrInvalidConfig, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates in %s", ErrInvalidConfig, c.TLSCACert)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if c.TLSCert != "" {
+		certificate, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: load client certificate: %w", ErrInvalidConfig, err)
+		}
+
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+)
+
+func TestConnectionConfigValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  ConnectionConfig
+		wantErr bool
+	}{
+		{name: "single by default", config: ConnectionConfig{}},
+		{
+			name:   "sentinel",
+			config: ConnectionConfig{Mode: ModeSentinel, MasterName: "main", SentinelAddrs: []string{"s1:26379"}},
+		},
+		{name: "sentinel without master", config: ConnectionConfig{Mode: ModeSentinel}, wantErr: true},
+		{name: "unknown mode", config: ConnectionConfig{Mode: "ring"}, wantErr: true},
+		{name: "unknown routing", config: ConnectionConfig{ReadRouting: "nearest"}, wantErr: true},
+		{name: "certificate without key", config: ConnectionConfig{TLS: true, TLSCert: "cert.pem"}, wantErr: true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			testutils.Equal(t, errors.Is(err, ErrInvalidConfig), test.wantErr)
+		})
+	}
+}
+
+func TestConnectionConfigUniversalOptions(t *testing.T) {
+	t.Run("cluster seeds, routing, timeouts and pool", func(t *testing.T) {
+		config := ConnectionConfig{
+			Host:         "localhost",
+			Port:         "6379",
+			Mode:         ModeCluster,
+			ClusterAddrs: []string{"n1:7000", "n2:7000"},
+			ReadRouting:  RouteReplica,
+			DialTimeout:  time.Second,
+			PoolSize:     20,
+		}
+
+		options, err := config.UniversalOptions()
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, options.Addrs, []string{"n1:7000", "n2:7000"})
+		testutils.Equal(t, options.ReadOnly, true)
+		testutils.Equal(t, options.DialTimeout, time.Second)
+		testutils.Equal(t, options.PoolSize, 20)
+		testutils.Equal(t, options.TLSConfig == nil, true)
+	})
+
+	t.Run("sentinel uses sentinel addresses", func(t *testing.T) {
+		config := ConnectionConfig{
+			Mode:          ModeSentinel,
+			MasterName:    "main",
+			SentinelAddrs: []string{"s1:26379", "s2:26379"},
+			TLS:           true,
+			TLSServerName: "redis.internal",
+		}
+
+		options, err := config.UniversalOptions()
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, options.MasterName, "main")
+		testutils.Equal(t, options.Addrs, []string{"s1:26379", "s2:26379"})
+		testutils.Equal(t, options.TLSConfig.ServerName, "redis.internal")
+	})
+
+	t.Run("missing CA file", func(t *testing.T) {
+		config := ConnectionConfig{TLS: true, TLSCACert: "/nonexistent/ca.pem"}
+
+		_, err := config.UniversalOptions()
+		testutils.Equal(t, errors.Is(err, ErrInvalidConfig), true)
+	})
+}
+
+func TestGetConnectionConfigFromEnvModes(t *testing.T) {
+	t.Setenv("REDIS_MODE", ModeCluster)
+	t.Setenv("REDIS_CLUSTERADDRS", "n1:7000,n2:7000")
+	t.Setenv("REDIS_READROUTING", RouteLatency)
+	t.Setenv("REDIS_TLS", "true")
+	t.Setenv("REDIS_READTIMEOUT", "3s")
+	t.Setenv("REDIS_POOLSIZE", "50")
+
+	config, err := GetConnectionConfigFromEnv()
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, config.Mode, ModeCluster)
+	testutils.Equal(t, config.ClusterAddrs, []string{"n1:7000", "n2:7000"})
+	testutils.Equal(t, config.ReadRouting, RouteLatency)
+	testutils.Equal(t, config.TLS, true)
+	testutils.Equal(t, config.ReadTimeout, 3*time.Second)
+	testutils.Equal(t, config.PoolSize, 50)
+}
@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewConnectionModes(t *testing.T) {
+	cases := []struct {
+		name   string
+		config ConnectionConfig
+		check  func(client redis.UniversalClient) bool
+	}{
+		{
+			name:   "single",
+			config: ConnectionConfig{Host: "localhost", Port: "6379"},
+			check: func(client redis.UniversalClient) bool {
+				_, ok := client.(*redis.Client)
+
+				return ok
+			},
+		},
+		{
+			name:   "cluster with one seed",
+			config: ConnectionConfig{Mode: ModeCluster, ClusterAddrs: []string{"localhost:7000"}},
+			check: func(client redis.UniversalClient) bool {
+				_, ok := client.(*redis.ClusterClient)
+
+				return ok
+			},
+		},
+		{
+			name: "sentinel reading from replicas",
+			config: ConnectionConfig{
+				Mode:          ModeSentinel,
+				MasterName:    "main",
+				SentinelAddrs: []string{"localhost:26379"},
+				ReadRouting:   RouteReplica,
+			},
+			check: func(client redis.UniversalClient) bool {
+				cluster, ok := client.(*redis.ClusterClient)
+
+				return ok && cluster.Options().ReadOnly
+			},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			connection, err := NewConnection(&test.config)
+			testutils.Equal(t, err, nil)
+			t.Cleanup(func() { _ = connection.Stop() })
+
+			testutils.Equal(t, test.check(connection.UniversalClient), true)
+		})
+	}
+
+	_, err := NewConnection(&ConnectionConfig{Mode: "ring"})
+	testutils.Equal(t, errors.Is(err, ErrInvalidConfig), true)
+}
+
+func TestNewRedisClientInvalidConfig(t *testing.T) {
+	connection := NewRedisClient(&ConnectionConfig{Mode: "ring", Host: "localhost", Port: "6379"})
+	testutils.Equal(t, connection != nil, true)
+	t.Cleanup(func() { _ = connection.Stop() })
+
+	client, ok := connection.UniversalClient.(*redis.Client)
+	testutils.Equal(t, ok, true)
+	testutils.Equal(t, client.Options().Addr, "localhost:6379")
+}
+
+func TestConnectionHealthCheck(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	connection, err := NewConnection(&ConnectionConfig{Host: server.Host(), Port: server.Port()})
+	testutils.Equal(t, err, nil)
+	t.Cleanup(func() { _ = connection.Stop() })
+
+	check := connection.HealthCheck(0)
+	testutils.Equal(t, check(), nil)
+
+	server.Close()
+
+	testutils.Equal(t, check() != nil, true)
+}
@@ -1,5 +1,19 @@
 package redis
 
-import "errors"
+import (
+	"errors"
 
-var ErrConnectionIsNotSet = errors.New("connection is not set")
+	"github.com/redis/go-redis/v9"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// All kind of errors for redis
+var (
+	ErrConnectionIsNotSet = errors.New("connection is not set")
+	ErrInvalidConfig      = errors.New("invalid redis config")
+)
+
+func init() {
+	coreerrors.RegisterClassifier(coreerrors.CategoryFor(redis.Nil, coreerrors.CategoryNotFound))
+}
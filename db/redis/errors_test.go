@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestErrorCategory(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want coreerrors.Category
+	}{
+		{name: "missing key", err: fmt.Errorf("get: %w", redis.Nil), want: coreerrors.CategoryNotFound},
+		{
+			name: "boundary missing key",
+			err:  coreerrors.WrapBoundary("redis", "get", redis.Nil),
+			want: coreerrors.CategoryNotFound,
+		},
+		{name: "other", err: errors.New("boom"), want: coreerrors.CategoryInternal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := coreerrors.CategoryOf(test.err); got != test.want {
+				t.Fatalf("CategoryOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -3,37 +3,85 @@ package redis
 import (
 	"context"
 	"errors"
-	"strings"
+	"log/slog"
+	"net"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/InsideGallery/core/db/instrument"
+	coreerrors "github.com/InsideGallery/core/errors"
 )
 
+// DefaultHealthCheckTimeout bounds HealthCheck pings when no timeout is given.
+const DefaultHealthCheckTimeout = 2 * time.Second
+
 // Connection is the legacy Redis SDK wrapper.
 //
 // Deprecated: use KeyValueStore and core-owned option/result types for new code.
 type Connection struct {
-	*redis.Client
+	redis.UniversalClient
+	observer instrument.Observer
+}
+
+// NewConnection creates a single-node, Sentinel or Cluster connection from config.
+func NewConnection(config *ConnectionConfig) (*Connection, error) {
+	options, err := config.UniversalOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Connection{UniversalClient: newUniversalClient(config.mode(), options)}, nil
 }
 
-// NewRedisClient creates the legacy Redis SDK wrapper.
+// NewRedisClient creates the legacy Redis SDK wrapper. It always returns a client: an
+// invalid config is logged and falls back to the single-node client of Host, Port, User,
+// Pass and Database; use NewConnection to get the error.
 //
-// Deprecated: use KeyValueStore methods on Connection for new code.
+// Deprecated: use NewConnection and KeyValueStore methods on Connection for new code.
 func NewRedisClient(config *ConnectionConfig) *Connection {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     strings.Join([]string{config.Host, config.Port}, ":"),
-		Username: config.User,
-		Password: config.Pass,
-		DB:       config.Database,
-	})
-
-	return &Connection{
-		Client: rdb,
+	connection, err := NewConnection(config)
+	if err != nil {
+		slog.Default().Error("Error creating redis connection, using single node", "err", err)
+
+		return &Connection{UniversalClient: redis.NewClient(&redis.Options{
+			Addr:     net.JoinHostPort(config.Host, config.Port),
+			Username: config.User,
+			Password: config.Pass,
+			DB:       config.Database,
+		})}
+	}
+
+	return connection
+}
+
+// newUniversalClient builds the client of mode. Sentinel reads routed away from the
+// primary need the failover cluster client, which knows the replicas. It ignores
+// ReplicaOnly, so replica reads enable ReadOnly on its options before it is shared.
+func newUniversalClient(mode string, options *redis.UniversalOptions) redis.UniversalClient { //nolint:ireturn // mode picks the client
+	switch mode {
+	case ModeSentinel:
+		failover := options.Failover()
+		if !options.ReadOnly && !options.RouteByLatency && !options.RouteRandomly {
+			return redis.NewFailoverClient(failover)
+		}
+
+		failover.RouteByLatency = options.RouteByLatency
+		failover.RouteRandomly = options.RouteRandomly
+
+		client := redis.NewFailoverClusterClient(failover)
+		client.Options().ReadOnly = true
+
+		return client
+	case ModeCluster:
+		return redis.NewClusterClient(options.Cluster())
+	default:
+		return redis.NewClient(options.Simple())
 	}
 }
 
 func (c Connection) Get(ctx context.Context, key string) (string, bool, error) {
-	result, err := c.Client.Get(ctx, key).Result()
+	result, err := c.UniversalClient.Get(ctx, key).Result()
 	if errors.Is(err, redis.Nil) {
 		return "", false, nil
 	}
@@ -42,9 +90,32 @@ func (c Connection) Get(ctx context.Context, key string) (string, bool, error) {
 }
 
 func (c Connection) Set(ctx context.Context, key, value string, expiration time.Duration) error {
-	return c.Client.Set(ctx, key, value, expiration).Err()
+	return c.UniversalClient.Set(ctx, key, value, expiration).Err()
 }
 
 func (c Connection) Stop() error {
 	return c.Close()
 }
+
+// HealthCheck returns a check for profiler.AddHealthCheck that pings the server, or
+// every shard of a Cluster connection; zero timeout uses DefaultHealthCheckTimeout.
+func (c Connection) HealthCheck(timeout time.Duration) func() error {
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if cluster, ok := c.UniversalClient.(*redis.ClusterClient); ok {
+			err := cluster.ForEachShard(ctx, func(ctx context.Context, shard *redis.Client) error {
+				return shard.Ping(ctx).Err()
+			})
+
+			return coreerrors.WrapBoundary("redis", "ping shards", err)
+		}
+
+		return coreerrors.WrapBoundary("redis", "ping", c.Ping(ctx).Err())
+	}
+}
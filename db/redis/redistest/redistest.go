@@ -0,0 +1,30 @@
+// Package redistest provides Redis clients for tests, backed by an in-process Redis
+// stand-in.
+//
+//	func TestStore(t *testing.T) {
+//		client, server := redistest.NewClient(t)
+//		server.FastForward(time.Minute)
+//	}
+package redistest
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewClient starts a miniredis server and returns a client of it. Both are closed when
+// the test ends.
+func NewClient(t testing.TB) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client, server
+}
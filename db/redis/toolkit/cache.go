@@ -0,0 +1,238 @@
+package toolkit
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/InsideGallery/core/dataconv/codec"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Read-through cache defaults.
+const (
+	DefaultCacheTTL            = time.Minute
+	DefaultCacheRefreshTimeout = 10 * time.Second
+
+	entryHeaderSize = 9
+	entryNegative   = 1
+)
+
+// errCorruptEntry reports a cached value written by something other than ReadThrough.
+var errCorruptEntry = errors.New("corrupt cache entry")
+
+// Loader loads the value of key from the source of truth. Returning ErrNotFound, or an
+// error classified as coreerrors.CategoryNotFound, marks the key as missing.
+type Loader[T any] func(ctx context.Context, key string) (T, error)
+
+// ReadThroughOptions configures a ReadThrough cache.
+type ReadThroughOptions struct {
+	// Prefix is prepended to every key.
+	Prefix string
+	// TTL is how long a loaded value is fresh; default DefaultCacheTTL.
+	TTL time.Duration
+	// StaleTTL is how long after TTL a value is still served while one background load
+	// refreshes it; zero disables stale-while-revalidate.
+	StaleTTL time.Duration
+	// NegativeTTL is how long a missing key is remembered; zero disables negative caching.
+	NegativeTTL time.Duration
+	// RefreshTimeout bounds loads, which are shared by the callers of a key and run
+	// without their cancellation, and background refreshes; default DefaultCacheRefreshTimeout.
+	RefreshTimeout time.Duration
+	Codec          Codec
+	// Now returns the current time; default time.Now.
+	Now func() time.Time
+}
+
+// ReadThrough is a Redis-backed read-through cache. Concurrent misses of one key in a
+// process share a single load.
+type ReadThrough[T any] struct {
+	client  redis.UniversalClient
+	loader  Loader[T]
+	options ReadThroughOptions
+	codec   Codec
+	group   singleflight.Group
+}
+
+// NewReadThrough returns a ReadThrough cache loading missing values with loader.
+func NewReadThrough[T any](client redis.UniversalClient, loader Loader[T], options ReadThroughOptions) *ReadThrough[T] {
+	if options.TTL <= 0 {
+		options.TTL = DefaultCacheTTL
+	}
+
+	if options.RefreshTimeout <= 0 {
+		options.RefreshTimeout = DefaultCacheRefreshTimeout
+	}
+
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	return &ReadThrough[T]{
+		client:  client,
+		loader:  loader,
+		options: options,
+		codec:   codec.OrDefault(options.Codec),
+	}
+}
+
+// Get returns the cached value of key, loading it on a miss. A stale value is returned
+// immediately while it is refreshed in the background. A missing key returns ErrNotFound.
+// Canceling ctx stops waiting for a load without failing the other callers sharing it.
+func (c *ReadThrough[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+
+	data, err := c.client.Get(ctx, c.options.Prefix+key).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return zero, coreerrors.WrapBoundary("redis", "cache get", err)
+	}
+
+	if err == nil {
+		value, fresh, negative, decodeErr := c.decode(data)
+		switch {
+		case decodeErr != nil:
+			slog.Default().Warn("Dropping unreadable cache entry", "key", key, "err", decodeErr)
+		case negative && fresh:
+			return zero, ErrNotFound
+		case fresh:
+			return value, nil
+		case !negative:
+			c.refresh(ctx, key)
+
+			return value, nil
+		}
+	}
+
+	loaded := c.group.DoChan(key, func() (any, error) {
+		return c.detachedLoad(ctx, key)
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case result := <-loaded:
+		if result.Err != nil {
+			return zero, result.Err
+		}
+
+		return result.Val.(T), nil //nolint:forcetypeassert // load only returns T
+	}
+}
+
+// Set stores value under key as freshly loaded.
+func (c *ReadThrough[T]) Set(ctx context.Context, key string, value T) error {
+	data, err := c.encode(value, false, c.options.TTL)
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Set(ctx, c.options.Prefix+key, data, c.options.TTL+c.options.StaleTTL).Err()
+
+	return coreerrors.WrapBoundary("redis", "cache set", err)
+}
+
+// Invalidate removes keys so that the next Get loads them again.
+func (c *ReadThrough[T]) Invalidate(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.options.Prefix + key
+	}
+
+	return coreerrors.WrapBoundary("redis", "cache invalidate", c.client.Del(ctx, prefixed...).Err())
+}
+
+func (c *ReadThrough[T]) load(ctx context.Context, key string) (T, error) {
+	value, err := c.loader(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) && coreerrors.CategoryOf(err) != coreerrors.CategoryNotFound {
+			return value, err
+		}
+
+		if c.options.NegativeTTL > 0 {
+			var zero T
+			c.store(ctx, key, zero, true, c.options.NegativeTTL, c.options.NegativeTTL)
+		}
+
+		return value, errors.Join(ErrNotFound, err)
+	}
+
+	c.store(ctx, key, value, false, c.options.TTL, c.options.TTL+c.options.StaleTTL)
+
+	return value, nil
+}
+
+// refresh reloads a stale key in the background; the singleflight group keeps it to one load.
+func (c *ReadThrough[T]) refresh(ctx context.Context, key string) {
+	c.group.DoChan(key, func() (any, error) {
+		return c.detachedLoad(ctx, key)
+	})
+}
+
+// detachedLoad loads key without the cancellation of ctx, which belongs to only one of the
+// callers sharing the load, bounded by RefreshTimeout.
+func (c *ReadThrough[T]) detachedLoad(ctx context.Context, key string) (T, error) {
+	loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.options.RefreshTimeout)
+	defer cancel()
+
+	return c.load(loadCtx, key)
+}
+
+// store writes a cache entry; failures are logged because the loaded value is still valid.
+func (c *ReadThrough[T]) store(ctx context.Context, key string, value T, negative bool, fresh, expiry time.Duration) {
+	data, err := c.encode(value, negative, fresh)
+	if err == nil {
+		err = c.client.Set(ctx, c.options.Prefix+key, data, expiry).Err()
+	}
+
+	if err != nil {
+		slog.Default().Warn("Error storing cache entry", "key", key, "err", err)
+	}
+}
+
+// encode prefixes the payload with a flag byte and the fresh-until time in unix milliseconds.
+func (c *ReadThrough[T]) encode(value T, negative bool, fresh time.Duration) ([]byte, error) {
+	header := make([]byte, entryHeaderSize)
+	if negative {
+		header[0] = entryNegative
+	}
+
+	binary.BigEndian.PutUint64(header[1:], uint64(c.options.Now().Add(fresh).UnixMilli()))
+
+	if negative {
+		return header, nil
+	}
+
+	payload, err := c.codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(header, payload...), nil
+}
+
+func (c *ReadThrough[T]) decode(data []byte) (value T, fresh bool, negative bool, err error) {
+	if len(data) < entryHeaderSize {
+		return value, false, false, errCorruptEntry
+	}
+
+	negative = data[0] == entryNegative
+	freshUntil := time.UnixMilli(int64(binary.BigEndian.Uint64(data[1:entryHeaderSize])))
+	fresh = c.options.Now().Before(freshUntil)
+
+	if negative {
+		return value, fresh, true, nil
+	}
+
+	err = c.codec.Unmarshal(data[entryHeaderSize:], &value)
+
+	return value, fresh, false, err
+}
@@ -0,0 +1,156 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/redis/redistest"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+type testProfile struct {
+	Name string `json:"name"`
+}
+
+func TestReadThrough(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("concurrent misses share one load", func(t *testing.T) {
+		client, _ := redistest.NewClient(t)
+
+		var loads atomic.Int32
+
+		release := make(chan struct{})
+		cache := NewReadThrough(client, func(context.Context, string) (testProfile, error) {
+			loads.Add(1)
+			<-release
+
+			return testProfile{Name: "Ada"}, nil
+		}, ReadThroughOptions{Prefix: "profile:"})
+
+		var wg sync.WaitGroup
+
+		for range 5 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				profile, err := cache.Get(ctx, "1")
+				testutils.Equal(t, err, nil)
+				testutils.Equal(t, profile.Name, "Ada")
+			}()
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		testutils.Equal(t, loads.Load(), int32(1))
+
+		_, err := cache.Get(ctx, "1")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, loads.Load(), int32(1))
+	})
+
+	t.Run("stale value is served while refreshed", func(t *testing.T) {
+		client, _ := redistest.NewClient(t)
+		now := time.Now()
+
+		var loads atomic.Int32
+
+		cache := NewReadThrough(client, func(context.Context, string) (testProfile, error) {
+			return testProfile{Name: string(rune('A' + loads.Add(1) - 1))}, nil
+		}, ReadThroughOptions{
+			TTL:      time.Minute,
+			StaleTTL: time.Hour,
+			Now:      func() time.Time { return now },
+		})
+
+		profile, err := cache.Get(ctx, "1")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, profile.Name, "A")
+
+		now = now.Add(2 * time.Minute)
+
+		profile, err = cache.Get(ctx, "1")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, profile.Name, "A")
+
+		deadline := time.Now().Add(time.Second)
+		for loads.Load() < 2 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		testutils.Equal(t, loads.Load(), int32(2))
+	})
+
+	t.Run("missing keys are cached negatively", func(t *testing.T) {
+		client, _ := redistest.NewClient(t)
+
+		var loads atomic.Int32
+
+		cache := NewReadThrough(client, func(context.Context, string) (testProfile, error) {
+			loads.Add(1)
+
+			return testProfile{}, coreerrors.NotFound("no profile")
+		}, ReadThroughOptions{NegativeTTL: time.Minute})
+
+		_, err := cache.Get(ctx, "1")
+		testutils.Equal(t, errors.Is(err, ErrNotFound), true)
+
+		_, err = cache.Get(ctx, "1")
+		testutils.Equal(t, errors.Is(err, ErrNotFound), true)
+		testutils.Equal(t, loads.Load(), int32(1))
+
+		testutils.Equal(t, cache.Invalidate(ctx, "1"), nil)
+
+		_, err = cache.Get(ctx, "1")
+		testutils.Equal(t, errors.Is(err, ErrNotFound), true)
+		testutils.Equal(t, loads.Load(), int32(2))
+	})
+
+	t.Run("canceled caller does not fail a shared load", func(t *testing.T) {
+		client, _ := redistest.NewClient(t)
+		started, release := make(chan struct{}), make(chan struct{})
+
+		var once sync.Once
+
+		cache := NewReadThrough(client, func(ctx context.Context, _ string) (testProfile, error) {
+			once.Do(func() { close(started) })
+			<-release
+
+			return testProfile{Name: "Ada"}, ctx.Err()
+		}, ReadThroughOptions{})
+
+		canceled, cancel := context.WithCancel(ctx)
+		first := make(chan error, 1)
+
+		go func() {
+			_, err := cache.Get(canceled, "1")
+			first <- err
+		}()
+
+		<-started
+
+		second := make(chan testProfile, 1)
+
+		go func() {
+			profile, err := cache.Get(ctx, "1")
+			testutils.Equal(t, err, nil)
+			second <- profile
+		}()
+
+		cancel()
+		testutils.Equal(t, errors.Is(<-first, context.Canceled), true)
+
+		close(release)
+		testutils.Equal(t, (<-second).Name, "Ada")
+	})
+}
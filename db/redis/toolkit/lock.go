@@ -0,0 +1,245 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Lock defaults.
+const (
+	DefaultLockPrefix     = "lock:"
+	DefaultLockTTL        = 30 * time.Second
+	DefaultLockRetryDelay = 50 * time.Millisecond
+	// MinLockTTL is the shortest TTL a Locker uses; Redis expires keys in milliseconds
+	// and auto-renewal runs every TTL/3.
+	MinLockTTL = 10 * time.Millisecond
+
+	renewDivisor = 3
+	fenceSuffix  = ":fence"
+)
+
+// acquireScript sets the lock and returns the next fencing token, or 0 when the lock is held.
+var acquireScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return redis.call('INCR', KEYS[2])
+end
+return 0
+`)
+
+// releaseScript deletes the lock only when it still holds the owner value.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// refreshScript extends the lock only when it still holds the owner value.
+var refreshScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// LockOptions configures a Locker.
+type LockOptions struct {
+	// Prefix is prepended to lock names; default DefaultLockPrefix.
+	Prefix string
+	// TTL is how long a lock is held without a refresh; default DefaultLockTTL, and at
+	// least MinLockTTL.
+	TTL time.Duration
+	// RetryDelay is the pause between attempts of Acquire; default DefaultLockRetryDelay.
+	RetryDelay time.Duration
+	// AutoRenew refreshes held locks every TTL/3 until they are released or lost.
+	AutoRenew bool
+}
+
+// Locker acquires distributed locks. Every acquisition returns a fencing token that
+// increases monotonically per lock name; storage written under a lock should reject
+// writes carrying a token lower than the last one it saw.
+type Locker struct {
+	client  redis.UniversalClient
+	options LockOptions
+}
+
+// NewLocker returns a Locker on client.
+func NewLocker(client redis.UniversalClient, options LockOptions) *Locker {
+	if options.Prefix == "" {
+		options.Prefix = DefaultLockPrefix
+	}
+
+	if options.TTL <= 0 {
+		options.TTL = DefaultLockTTL
+	}
+
+	options.TTL = max(options.TTL, MinLockTTL)
+
+	if options.RetryDelay <= 0 {
+		options.RetryDelay = DefaultLockRetryDelay
+	}
+
+	return &Locker{client: client, options: options}
+}
+
+// TryAcquire acquires the named lock once. It returns ErrLockNotHeld when another owner holds it.
+func (l *Locker) TryAcquire(ctx context.Context, name string) (*Lock, error) {
+	if l.client == nil {
+		return nil, ErrClientIsNil
+	}
+
+	key := l.options.Prefix + "{" + name + "}"
+	value := uuid.NewString()
+
+	keys := []string{key, key + fenceSuffix}
+
+	token, err := acquireScript.Run(ctx, l.client, keys, value, l.options.TTL.Milliseconds()).Int64()
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("redis", "acquire lock", err)
+	}
+
+	if token == 0 {
+		return nil, ErrLockNotHeld
+	}
+
+	lock := &Lock{
+		locker: l,
+		key:    key,
+		value:  value,
+		token:  uint64(token),
+		lost:   make(chan struct{}),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	if l.options.AutoRenew {
+		go lock.renew()
+	} else {
+		close(lock.done)
+	}
+
+	return lock, nil
+}
+
+// Acquire waits until the named lock is acquired or ctx is done, which returns ErrLockTimeout.
+func (l *Locker) Acquire(ctx context.Context, name string) (*Lock, error) {
+	for {
+		lock, err := l.TryAcquire(ctx, name)
+		if err != nil && ctx.Err() != nil {
+			return nil, errors.Join(ErrLockTimeout, ctx.Err())
+		}
+
+		if !errors.Is(err, ErrLockNotHeld) {
+			return lock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(ErrLockTimeout, ctx.Err())
+		case <-time.After(l.options.RetryDelay):
+		}
+	}
+}
+
+// Lock is a held distributed lock.
+type Lock struct {
+	locker *Locker
+	key    string
+	value  string
+	token  uint64
+
+	stopOnce sync.Once
+	lost     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Token returns the fencing token of this acquisition.
+func (l *Lock) Token() uint64 {
+	return l.token
+}
+
+// Lost is closed when auto-renewal finds the lock expired or taken by another owner.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Refresh extends the lock by the locker TTL. It returns ErrLockNotHeld when the lock expired.
+func (l *Lock) Refresh(ctx context.Context) error {
+	refreshed, err := refreshScript.Run(
+		ctx,
+		l.locker.client,
+		[]string{l.key},
+		l.value,
+		l.locker.options.TTL.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return coreerrors.WrapBoundary("redis", "refresh lock", err)
+	}
+
+	if refreshed == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// Release stops auto-renewal and deletes the lock. It returns ErrLockNotHeld when the lock
+// had already expired or been taken by another owner.
+func (l *Lock) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+
+	released, err := releaseScript.Run(ctx, l.locker.client, []string{l.key}, l.value).Int64()
+	if err != nil {
+		return coreerrors.WrapBoundary("redis", "release lock", err)
+	}
+
+	if released == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// renew refreshes the lock every TTL/3. Transient errors are retried until a whole TTL
+// passes without a successful refresh, after which the lock is considered lost.
+func (l *Lock) renew() {
+	defer close(l.done)
+
+	ttl := l.locker.options.TTL
+	ticker := time.NewTicker(ttl / renewDivisor)
+
+	defer ticker.Stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), ttl/renewDivisor)
+		err := l.Refresh(ctx)
+
+		cancel()
+
+		switch {
+		case err == nil:
+			renewed = time.Now()
+		case errors.Is(err, ErrLockNotHeld) || time.Since(renewed) >= ttl:
+			close(l.lost)
+
+			return
+		}
+	}
+}
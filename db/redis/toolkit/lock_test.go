@@ -0,0 +1,90 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/redis/redistest"
+)
+
+func TestLocker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fencing tokens increase and locks exclude", func(t *testing.T) {
+		client, _ := redistest.NewClient(t)
+		locker := NewLocker(client, LockOptions{TTL: time.Second})
+
+		first, err := locker.TryAcquire(ctx, "job")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, first.Token(), uint64(1))
+
+		_, err = locker.TryAcquire(ctx, "job")
+		testutils.Equal(t, errors.Is(err, ErrLockNotHeld), true)
+
+		testutils.Equal(t, first.Refresh(ctx), nil)
+		testutils.Equal(t, first.Release(ctx), nil)
+		testutils.Equal(t, errors.Is(first.Release(ctx), ErrLockNotHeld), true)
+
+		second, err := locker.TryAcquire(ctx, "job")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, second.Token(), uint64(2))
+	})
+
+	t.Run("acquire waits until timeout", func(t *testing.T) {
+		client, _ := redistest.NewClient(t)
+		locker := NewLocker(client, LockOptions{RetryDelay: time.Millisecond})
+
+		_, err := locker.Acquire(ctx, "job")
+		testutils.Equal(t, err, nil)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		_, err = locker.Acquire(waitCtx, "job")
+		testutils.Equal(t, errors.Is(err, ErrLockTimeout), true)
+	})
+
+	t.Run("expired lock cannot be refreshed", func(t *testing.T) {
+		client, server := redistest.NewClient(t)
+		locker := NewLocker(client, LockOptions{TTL: time.Second})
+
+		lock, err := locker.TryAcquire(ctx, "job")
+		testutils.Equal(t, err, nil)
+
+		server.FastForward(2 * time.Second)
+
+		testutils.Equal(t, errors.Is(lock.Refresh(ctx), ErrLockNotHeld), true)
+	})
+
+	t.Run("auto renew reports lost lock", func(t *testing.T) {
+		client, server := redistest.NewClient(t)
+		locker := NewLocker(client, LockOptions{TTL: 30 * time.Millisecond, AutoRenew: true})
+
+		lock, err := locker.TryAcquire(ctx, "job")
+		testutils.Equal(t, err, nil)
+
+		server.Del("lock:{job}")
+
+		select {
+		case <-lock.Lost():
+		case <-time.After(time.Second):
+			t.Fatal("lost lock was not reported")
+		}
+
+		testutils.Equal(t, errors.Is(lock.Release(ctx), ErrLockNotHeld), true)
+	})
+
+	t.Run("tiny ttl is raised to the minimum", func(t *testing.T) {
+		client, _ := redistest.NewClient(t)
+		locker := NewLocker(client, LockOptions{TTL: time.Nanosecond, AutoRenew: true})
+		testutils.Equal(t, locker.options.TTL, MinLockTTL)
+
+		lock, err := locker.TryAcquire(ctx, "tiny")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, lock.Release(ctx), nil)
+	})
+}
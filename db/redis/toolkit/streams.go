@@ -0,0 +1,302 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// Stream worker defaults.
+const (
+	DefaultStreamBatchSize     = 10
+	DefaultStreamBlock         = 2 * time.Second
+	DefaultStreamClaimIdle     = time.Minute
+	DefaultStreamMaxDeliveries = 5
+	DefaultDeadLetterSuffix    = ":dead"
+
+	// Fields added to dead-lettered messages.
+	DeadLetterSourceIDField   = "_source_id"
+	DeadLetterDeliveriesField = "_deliveries"
+	DeadLetterGroupField      = "_group"
+
+	busyGroupPrefix = "BUSYGROUP"
+	newMessagesID   = ">"
+)
+
+// StreamOptions configures a StreamWorker.
+type StreamOptions struct {
+	Stream string
+	Group  string
+	// Consumer names this worker in the group; default a random id.
+	Consumer string
+	// StartID is where a newly created group starts reading; default "$", only new messages.
+	StartID string
+	// BatchSize is the maximum number of messages read or claimed at once; default DefaultStreamBatchSize.
+	BatchSize int64
+	// Block is how long a read waits for new messages; default DefaultStreamBlock.
+	Block time.Duration
+	// ClaimIdle is how long a message stays unacknowledged before another consumer claims
+	// it; default DefaultStreamClaimIdle.
+	ClaimIdle time.Duration
+	// MaxDeliveries is how many deliveries a message gets before it is dead-lettered;
+	// default DefaultStreamMaxDeliveries.
+	MaxDeliveries int64
+	// DeadLetterStream receives messages over MaxDeliveries; default Stream + DefaultDeadLetterSuffix.
+	DeadLetterStream string
+}
+
+// StreamMessage is one message delivered to a StreamHandler.
+type StreamMessage struct {
+	ID         string
+	Stream     string
+	Values     map[string]any
+	Deliveries int64
+}
+
+// StreamHandler handles one message. A nil error acknowledges it; an error leaves it
+// pending, to be claimed again after StreamOptions.ClaimIdle.
+type StreamHandler func(ctx context.Context, message StreamMessage) error
+
+// StreamWorker consumes a Redis stream as one consumer of a consumer group.
+type StreamWorker struct {
+	client  redis.UniversalClient
+	options StreamOptions
+	handler StreamHandler
+}
+
+// NewStreamWorker returns a StreamWorker calling handler for every message.
+func NewStreamWorker(client redis.UniversalClient, options StreamOptions, handler StreamHandler) (*StreamWorker, error) {
+	switch {
+	case client == nil:
+		return nil, ErrClientIsNil
+	case handler == nil:
+		return nil, ErrHandlerIsNil
+	case options.Stream == "" || options.Group == "":
+		return nil, ErrStreamIsNotSet
+	}
+
+	if options.Consumer == "" {
+		options.Consumer = uuid.NewString()
+	}
+
+	if options.StartID == "" {
+		options.StartID = "$"
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultStreamBatchSize
+	}
+
+	if options.Block <= 0 {
+		options.Block = DefaultStreamBlock
+	}
+
+	if options.ClaimIdle <= 0 {
+		options.ClaimIdle = DefaultStreamClaimIdle
+	}
+
+	if options.MaxDeliveries <= 0 {
+		options.MaxDeliveries = DefaultStreamMaxDeliveries
+	}
+
+	if options.DeadLetterStream == "" {
+		options.DeadLetterStream = options.Stream + DefaultDeadLetterSuffix
+	}
+
+	return &StreamWorker{client: client, options: options, handler: handler}, nil
+}
+
+// Publish appends values to stream and returns the message id. A positive maxLen trims
+// the stream approximately to that length.
+func Publish(ctx context.Context, client redis.UniversalClient, stream string, values map[string]any, maxLen int64) (string, error) {
+	args := &redis.XAddArgs{Stream: stream, Values: values}
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+
+	id, err := client.XAdd(ctx, args).Result()
+
+	return id, coreerrors.WrapBoundary("redis", "stream publish", err)
+}
+
+// Run creates the group if needed and handles messages until ctx is done, which returns
+// nil. Each round first claims messages idle for ClaimIdle, dead-lettering those delivered
+// MaxDeliveries times, then reads new messages.
+func (w *StreamWorker) Run(ctx context.Context) error {
+	if err := w.ensureGroup(ctx); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		if err := w.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Poll runs one round of Run: claim stuck messages, then read and handle one batch.
+func (w *StreamWorker) Poll(ctx context.Context) error {
+	if err := w.reclaim(ctx); err != nil {
+		return err
+	}
+
+	streams, err := w.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    w.options.Group,
+		Consumer: w.options.Consumer,
+		Streams:  []string{w.options.Stream, newMessagesID},
+		Count:    w.options.BatchSize,
+		Block:    w.options.Block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+
+	if err != nil {
+		return coreerrors.WrapBoundary("redis", "stream read", err)
+	}
+
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			if err := w.handle(ctx, message, 1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *StreamWorker) ensureGroup(ctx context.Context) error {
+	err := w.client.XGroupCreateMkStream(ctx, w.options.Stream, w.options.Group, w.options.StartID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), busyGroupPrefix) {
+		return coreerrors.WrapBoundary("redis", "stream create group", err)
+	}
+
+	return nil
+}
+
+// reclaim claims messages left pending by failed handlers or dead consumers.
+func (w *StreamWorker) reclaim(ctx context.Context) error {
+	pending, err := w.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: w.options.Stream,
+		Group:  w.options.Group,
+		Idle:   w.options.ClaimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  w.options.BatchSize,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return coreerrors.WrapBoundary("redis", "stream pending", err)
+	}
+
+	deliveries := make(map[string]int64, len(pending))
+	claim := make([]string, 0, len(pending))
+
+	for _, entry := range pending {
+		if entry.RetryCount >= w.options.MaxDeliveries {
+			if err := w.deadLetter(ctx, entry.ID, entry.RetryCount); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		deliveries[entry.ID] = entry.RetryCount
+		claim = append(claim, entry.ID)
+	}
+
+	if len(claim) == 0 {
+		return nil
+	}
+
+	messages, err := w.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   w.options.Stream,
+		Group:    w.options.Group,
+		Consumer: w.options.Consumer,
+		MinIdle:  w.options.ClaimIdle,
+		Messages: claim,
+	}).Result()
+	if err != nil {
+		return coreerrors.WrapBoundary("redis", "stream claim", err)
+	}
+
+	for _, message := range messages {
+		if err := w.handle(ctx, message, deliveries[message.ID]+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handle runs the handler and acknowledges on success. Handler errors are logged and
+// leave the message pending; only Redis errors stop the worker.
+func (w *StreamWorker) handle(ctx context.Context, message redis.XMessage, deliveries int64) error {
+	err := w.handler(ctx, StreamMessage{
+		ID:         message.ID,
+		Stream:     w.options.Stream,
+		Values:     message.Values,
+		Deliveries: deliveries,
+	})
+	if err != nil {
+		slog.Default().Warn(
+			"Stream handler failed",
+			"stream", w.options.Stream,
+			"id", message.ID,
+			"deliveries", deliveries,
+			"err", err,
+		)
+
+		return nil
+	}
+
+	err = w.client.XAck(ctx, w.options.Stream, w.options.Group, message.ID).Err()
+
+	return coreerrors.WrapBoundary("redis", "stream ack", err)
+}
+
+// deadLetter copies a message to the dead-letter stream, then acknowledges it. The two
+// streams may live on different Cluster slots, so a crash in between can duplicate the
+// dead-lettered copy but never lose the message.
+func (w *StreamWorker) deadLetter(ctx context.Context, id string, deliveries int64) error {
+	messages, err := w.client.XRangeN(ctx, w.options.Stream, id, id, 1).Result()
+	if err != nil {
+		return coreerrors.WrapBoundary("redis", "stream dead letter", err)
+	}
+
+	_, err = w.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(messages) > 0 {
+			values := make(map[string]any, len(messages[0].Values)+3) //nolint:mnd // dead-letter fields
+			for key, value := range messages[0].Values {
+				values[key] = value
+			}
+
+			values[DeadLetterSourceIDField] = id
+			values[DeadLetterDeliveriesField] = strconv.FormatInt(deliveries, 10)
+			values[DeadLetterGroupField] = w.options.Group
+
+			pipe.XAdd(ctx, &redis.XAddArgs{Stream: w.options.DeadLetterStream, Values: values})
+		}
+
+		pipe.XAck(ctx, w.options.Stream, w.options.Group, id)
+
+		return nil
+	})
+
+	return coreerrors.WrapBoundary("redis", "stream dead letter", err)
+}
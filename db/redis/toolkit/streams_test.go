@@ -0,0 +1,65 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/redis/redistest"
+)
+
+func TestStreamWorker(t *testing.T) {
+	ctx := context.Background()
+	client, _ := redistest.NewClient(t)
+
+	handled := map[string]int64{}
+	worker, err := NewStreamWorker(client, StreamOptions{
+		Stream:        "orders",
+		Group:         "billing",
+		StartID:       "0",
+		Block:         10 * time.Millisecond,
+		ClaimIdle:     time.Millisecond,
+		MaxDeliveries: 2,
+	}, func(_ context.Context, message StreamMessage) error {
+		handled[message.Values["order"].(string)] = message.Deliveries
+
+		if message.Values["order"] == "bad" {
+			return errors.New("boom")
+		}
+
+		return nil
+	})
+	testutils.Equal(t, err, nil)
+
+	_, err = Publish(ctx, client, "orders", map[string]any{"order": "good"}, 0)
+	testutils.Equal(t, err, nil)
+
+	badID, err := Publish(ctx, client, "orders", map[string]any{"order": "bad"}, 0)
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, worker.ensureGroup(ctx), nil)
+	testutils.Equal(t, worker.ensureGroup(ctx), nil)
+
+	for range 3 {
+		time.Sleep(2 * time.Millisecond)
+		testutils.Equal(t, worker.Poll(ctx), nil)
+	}
+
+	testutils.Equal(t, handled, map[string]int64{"good": 1, "bad": 2})
+
+	pending, err := client.XPending(ctx, "orders", "billing").Result()
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, pending.Count, int64(0))
+
+	dead, err := client.XRange(ctx, "orders:dead", "-", "+").Result()
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(dead), 1)
+	testutils.Equal(t, dead[0].Values[DeadLetterSourceIDField], any(badID))
+	testutils.Equal(t, dead[0].Values["order"], any("bad"))
+
+	_, err = NewStreamWorker(client, StreamOptions{Stream: "orders"}, nil)
+	testutils.Equal(t, errors.Is(err, ErrHandlerIsNil), true)
+}
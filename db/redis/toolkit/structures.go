@@ -0,0 +1,378 @@
+package toolkit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/InsideGallery/core/dataconv/codec"
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// structure holds the key and codec shared by the typed data-structure helpers.
+type structure struct {
+	client redis.UniversalClient
+	key    string
+	codec  Codec
+}
+
+func newStructure(client redis.UniversalClient, key string, valueCodec Codec) structure {
+	return structure{client: client, key: key, codec: codec.OrDefault(valueCodec)}
+}
+
+// Key returns the Redis key of the structure.
+func (s structure) Key() string {
+	return s.key
+}
+
+// Expire sets the TTL of the whole structure.
+func (s structure) Expire(ctx context.Context, ttl time.Duration) error {
+	return coreerrors.WrapBoundary("redis", "expire", s.client.Expire(ctx, s.key, ttl).Err())
+}
+
+// Clear deletes the whole structure.
+func (s structure) Clear(ctx context.Context) error {
+	return coreerrors.WrapBoundary("redis", "clear", s.client.Del(ctx, s.key).Err())
+}
+
+func (s structure) encodeAll(values []any) ([]any, error) {
+	encoded := make([]any, len(values))
+
+	for i, value := range values {
+		data, err := s.codec.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded[i] = data
+	}
+
+	return encoded, nil
+}
+
+func decodeAll[T any](valueCodec Codec, values []string) ([]T, error) {
+	decoded := make([]T, len(values))
+
+	for i, value := range values {
+		if err := valueCodec.Unmarshal([]byte(value), &decoded[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return decoded, nil
+}
+
+func toAny[T any](values []T) []any {
+	converted := make([]any, len(values))
+	for i, value := range values {
+		converted[i] = value
+	}
+
+	return converted
+}
+
+// Hash is a Redis hash of T values keyed by field name.
+type Hash[T any] struct {
+	structure
+}
+
+// NewHash returns the hash stored under key; a nil codec uses JSONCodec.
+func NewHash[T any](client redis.UniversalClient, key string, codec Codec) *Hash[T] {
+	return &Hash[T]{structure: newStructure(client, key, codec)}
+}
+
+// Set stores value under field.
+func (h *Hash[T]) Set(ctx context.Context, field string, value T) error {
+	return h.SetMany(ctx, map[string]T{field: value})
+}
+
+// SetMany stores every field of values in one command.
+func (h *Hash[T]) SetMany(ctx context.Context, values map[string]T) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	pairs := make([]any, 0, len(values)*2) //nolint:mnd // field and value
+
+	for field, value := range values {
+		data, err := h.codec.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		pairs = append(pairs, field, data)
+	}
+
+	return coreerrors.WrapBoundary("redis", "hash set", h.client.HSet(ctx, h.key, pairs...).Err())
+}
+
+// Get returns the value of field and whether it is present.
+func (h *Hash[T]) Get(ctx context.Context, field string) (T, bool, error) {
+	var value T
+
+	data, err := h.client.HGet(ctx, h.key, field).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return value, false, nil
+	}
+
+	if err != nil {
+		return value, false, coreerrors.WrapBoundary("redis", "hash get", err)
+	}
+
+	return value, true, h.codec.Unmarshal(data, &value)
+}
+
+// GetAll returns every field of the hash.
+func (h *Hash[T]) GetAll(ctx context.Context) (map[string]T, error) {
+	fields, err := h.client.HGetAll(ctx, h.key).Result()
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("redis", "hash get all", err)
+	}
+
+	values := make(map[string]T, len(fields))
+
+	for field, data := range fields {
+		var value T
+		if err := h.codec.Unmarshal([]byte(data), &value); err != nil {
+			return nil, err
+		}
+
+		values[field] = value
+	}
+
+	return values, nil
+}
+
+// Delete removes fields and returns how many existed.
+func (h *Hash[T]) Delete(ctx context.Context, fields ...string) (int64, error) {
+	deleted, err := h.client.HDel(ctx, h.key, fields...).Result()
+
+	return deleted, coreerrors.WrapBoundary("redis", "hash delete", err)
+}
+
+// Len returns the number of fields.
+func (h *Hash[T]) Len(ctx context.Context) (int64, error) {
+	length, err := h.client.HLen(ctx, h.key).Result()
+
+	return length, coreerrors.WrapBoundary("redis", "hash len", err)
+}
+
+// Set is a Redis set of T members compared by their encoded form.
+type Set[T any] struct {
+	structure
+}
+
+// NewSet returns the set stored under key; a nil codec uses JSONCodec.
+func NewSet[T any](client redis.UniversalClient, key string, codec Codec) *Set[T] {
+	return &Set[T]{structure: newStructure(client, key, codec)}
+}
+
+// Add adds members and returns how many were new.
+func (s *Set[T]) Add(ctx context.Context, members ...T) (int64, error) {
+	encoded, err := s.encodeAll(toAny(members))
+	if err != nil {
+		return 0, err
+	}
+
+	added, err := s.client.SAdd(ctx, s.key, encoded...).Result()
+
+	return added, coreerrors.WrapBoundary("redis", "set add", err)
+}
+
+// Remove removes members and returns how many existed.
+func (s *Set[T]) Remove(ctx context.Context, members ...T) (int64, error) {
+	encoded, err := s.encodeAll(toAny(members))
+	if err != nil {
+		return 0, err
+	}
+
+	removed, err := s.client.SRem(ctx, s.key, encoded...).Result()
+
+	return removed, coreerrors.WrapBoundary("redis", "set remove", err)
+}
+
+// Contains reports whether member is in the set.
+func (s *Set[T]) Contains(ctx context.Context, member T) (bool, error) {
+	data, err := s.codec.Marshal(member)
+	if err != nil {
+		return false, err
+	}
+
+	found, err := s.client.SIsMember(ctx, s.key, data).Result()
+
+	return found, coreerrors.WrapBoundary("redis", "set contains", err)
+}
+
+// Members returns every member in no particular order.
+func (s *Set[T]) Members(ctx context.Context) ([]T, error) {
+	members, err := s.client.SMembers(ctx, s.key).Result()
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("redis", "set members", err)
+	}
+
+	return decodeAll[T](s.codec, members)
+}
+
+// Len returns the number of members.
+func (s *Set[T]) Len(ctx context.Context) (int64, error) {
+	length, err := s.client.SCard(ctx, s.key).Result()
+
+	return length, coreerrors.WrapBoundary("redis", "set len", err)
+}
+
+// ScoredMember is a sorted-set member with its score.
+type ScoredMember[T any] struct {
+	Member T
+	Score  float64
+}
+
+// SortedSet is a Redis sorted set of T members compared by their encoded form.
+type SortedSet[T any] struct {
+	structure
+}
+
+// NewSortedSet returns the sorted set stored under key; a nil codec uses JSONCodec.
+func NewSortedSet[T any](client redis.UniversalClient, key string, codec Codec) *SortedSet[T] {
+	return &SortedSet[T]{structure: newStructure(client, key, codec)}
+}
+
+// Add adds or rescores members and returns how many were new.
+func (s *SortedSet[T]) Add(ctx context.Context, members ...ScoredMember[T]) (int64, error) {
+	entries := make([]redis.Z, len(members))
+
+	for i, member := range members {
+		data, err := s.codec.Marshal(member.Member)
+		if err != nil {
+			return 0, err
+		}
+
+		entries[i] = redis.Z{Score: member.Score, Member: data}
+	}
+
+	added, err := s.client.ZAdd(ctx, s.key, entries...).Result()
+
+	return added, coreerrors.WrapBoundary("redis", "sorted set add", err)
+}
+
+// IncrBy adds delta to the score of member, adding it when missing, and returns the new score.
+func (s *SortedSet[T]) IncrBy(ctx context.Context, member T, delta float64) (float64, error) {
+	data, err := s.codec.Marshal(member)
+	if err != nil {
+		return 0, err
+	}
+
+	score, err := s.client.ZIncrBy(ctx, s.key, delta, string(data)).Result()
+
+	return score, coreerrors.WrapBoundary("redis", "sorted set incr", err)
+}
+
+// Score returns the score of member and whether it is present.
+func (s *SortedSet[T]) Score(ctx context.Context, member T) (float64, bool, error) {
+	data, err := s.codec.Marshal(member)
+	if err != nil {
+		return 0, false, err
+	}
+
+	score, err := s.client.ZScore(ctx, s.key, string(data)).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+
+	return score, err == nil, coreerrors.WrapBoundary("redis", "sorted set score", err)
+}
+
+// Rank returns the zero-based position of member by ascending score, or by descending
+// score when descending is set, and whether it is present.
+func (s *SortedSet[T]) Rank(ctx context.Context, member T, descending bool) (int64, bool, error) {
+	data, err := s.codec.Marshal(member)
+	if err != nil {
+		return 0, false, err
+	}
+
+	command := s.client.ZRank
+	if descending {
+		command = s.client.ZRevRank
+	}
+
+	rank, err := command(ctx, s.key, string(data)).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+
+	return rank, err == nil, coreerrors.WrapBoundary("redis", "sorted set rank", err)
+}
+
+// Range returns members between the start and stop positions, inclusive; negative
+// positions count from the end.
+func (s *SortedSet[T]) Range(ctx context.Context, start, stop int64, descending bool) ([]ScoredMember[T], error) {
+	entries, err := s.client.ZRangeArgsWithScores(ctx, redis.ZRangeArgs{
+		Key:   s.key,
+		Start: start,
+		Stop:  stop,
+		Rev:   descending,
+	}).Result()
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("redis", "sorted set range", err)
+	}
+
+	return s.decodeScored(entries)
+}
+
+// RangeByScore returns up to count members with min <= score <= max in ascending order,
+// skipping offset; a count of zero returns every match.
+func (s *SortedSet[T]) RangeByScore(ctx context.Context, minScore, maxScore float64, offset, count int64) ([]ScoredMember[T], error) {
+	args := redis.ZRangeArgs{
+		Key:     s.key,
+		Start:   minScore,
+		Stop:    maxScore,
+		ByScore: true,
+	}
+
+	if count > 0 {
+		args.Offset = offset
+		args.Count = count
+	}
+
+	entries, err := s.client.ZRangeArgsWithScores(ctx, args).Result()
+	if err != nil {
+		return nil, coreerrors.WrapBoundary("redis", "sorted set range by score", err)
+	}
+
+	return s.decodeScored(entries)
+}
+
+// Remove removes members and returns how many existed.
+func (s *SortedSet[T]) Remove(ctx context.Context, members ...T) (int64, error) {
+	encoded, err := s.encodeAll(toAny(members))
+	if err != nil {
+		return 0, err
+	}
+
+	removed, err := s.client.ZRem(ctx, s.key, encoded...).Result()
+
+	return removed, coreerrors.WrapBoundary("redis", "sorted set remove", err)
+}
+
+// Len returns the number of members.
+func (s *SortedSet[T]) Len(ctx context.Context) (int64, error) {
+	length, err := s.client.ZCard(ctx, s.key).Result()
+
+	return length, coreerrors.WrapBoundary("redis", "sorted set len", err)
+}
+
+func (s *SortedSet[T]) decodeScored(entries []redis.Z) ([]ScoredMember[T], error) {
+	members := make([]ScoredMember[T], len(entries))
+
+	for i, entry := range entries {
+		data, _ := entry.Member.(string)
+		if err := s.codec.Unmarshal([]byte(data), &members[i].Member); err != nil {
+			return nil, err
+		}
+
+		members[i].Score = entry.Score
+	}
+
+	return members, nil
+}
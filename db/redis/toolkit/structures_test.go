@@ -0,0 +1,84 @@
+package toolkit
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/redis/redistest"
+)
+
+func TestStructures(t *testing.T) {
+	ctx := context.Background()
+	client, _ := redistest.NewClient(t)
+
+	t.Run("hash", func(t *testing.T) {
+		hash := NewHash[testProfile](client, "profiles", nil)
+
+		testutils.Equal(t, hash.SetMany(ctx, map[string]testProfile{"1": {Name: "Ada"}, "2": {Name: "Bob"}}), nil)
+
+		profile, found, err := hash.Get(ctx, "1")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, found, true)
+		testutils.Equal(t, profile.Name, "Ada")
+
+		_, found, err = hash.Get(ctx, "3")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, found, false)
+
+		all, err := hash.GetAll(ctx)
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, all, map[string]testProfile{"1": {Name: "Ada"}, "2": {Name: "Bob"}})
+
+		deleted, err := hash.Delete(ctx, "1", "3")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, deleted, int64(1))
+	})
+
+	t.Run("set", func(t *testing.T) {
+		set := NewSet[testProfile](client, "members", nil)
+
+		added, err := set.Add(ctx, testProfile{Name: "Ada"}, testProfile{Name: "Bob"}, testProfile{Name: "Ada"})
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, added, int64(2))
+
+		found, err := set.Contains(ctx, testProfile{Name: "Bob"})
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, found, true)
+
+		members, err := set.Members(ctx)
+		testutils.Equal(t, err, nil)
+		sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+		testutils.Equal(t, members, []testProfile{{Name: "Ada"}, {Name: "Bob"}})
+	})
+
+	t.Run("sorted set", func(t *testing.T) {
+		board := NewSortedSet[string](client, "board", nil)
+
+		_, err := board.Add(ctx, ScoredMember[string]{Member: "ada", Score: 3}, ScoredMember[string]{Member: "bob", Score: 1})
+		testutils.Equal(t, err, nil)
+
+		score, err := board.IncrBy(ctx, "bob", 5)
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, score, float64(6))
+
+		rank, found, err := board.Rank(ctx, "bob", true)
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, found, true)
+		testutils.Equal(t, rank, int64(0))
+
+		top, err := board.Range(ctx, 0, -1, true)
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, top, []ScoredMember[string]{{Member: "bob", Score: 6}, {Member: "ada", Score: 3}})
+
+		low, err := board.RangeByScore(ctx, 0, 4, 0, 0)
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, low, []ScoredMember[string]{{Member: "ada", Score: 3}})
+
+		_, found, err = board.Score(ctx, "cid")
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, found, false)
+	})
+}
@@ -0,0 +1,35 @@
+// Package toolkit provides Redis data-structure helpers built on go-redis: distributed
+// locks with fencing tokens, a read-through cache, typed hash, set and sorted-set
+// wrappers, and a consumer-group worker for Redis Streams.
+//
+//	import "github.com/InsideGallery/core/db/redis/toolkit"
+//
+//	locker := toolkit.NewLocker(connection.UniversalClient, toolkit.LockOptions{TTL: 10 * time.Second})
+//	lock, err := locker.Acquire(ctx, "invoice:42")
+//
+// Every helper takes a redis.UniversalClient, so it works with single-node,
+// Sentinel and Cluster clients. Keys that must share a Cluster slot use hash tags.
+package toolkit
+
+import (
+	"errors"
+
+	"github.com/InsideGallery/core/dataconv/codec"
+)
+
+// Toolkit errors.
+var (
+	ErrNotFound       = errors.New("value is not found")
+	ErrLockNotHeld    = errors.New("lock is not held")
+	ErrLockTimeout    = errors.New("lock acquire timeout")
+	ErrClientIsNil    = errors.New("redis client is not set")
+	ErrHandlerIsNil   = errors.New("stream handler is not set")
+	ErrStreamIsNotSet = errors.New("stream or group is not set")
+)
+
+// Codec converts values stored in Redis to and from bytes.
+type Codec = codec.Codec
+
+// JSONCodec encodes values as JSON. Set and sorted-set members rely on its stable
+// encoding to compare values.
+type JSONCodec = codec.JSON
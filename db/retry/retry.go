@@ -0,0 +1,73 @@
+// Package retry re-runs the transactions and writes of the db adapters after errors a
+// new attempt can fix, such as serialization failures and concurrent modifications.
+//
+//	err := retry.Do(ctx, retry.Policy{MaxRetries: opts.MaxRetries, Delay: opts.RetryDelay},
+//		IsRetryable, func(int) error {
+//			return runTx(ctx, fn)
+//		})
+//
+// The delay grows linearly: the n-th retry waits n times Policy.Delay.
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Retry defaults.
+const (
+	DefaultMaxRetries = 3
+	DefaultDelay      = 10 * time.Millisecond
+)
+
+// Policy configures Do.
+type Policy struct {
+	// MaxRetries is how many times fn is retried; zero uses DefaultMaxRetries and a
+	// negative value disables retries.
+	MaxRetries int
+	// Delay is multiplied by the attempt number between retries; zero uses DefaultDelay.
+	Delay time.Duration
+}
+
+// Retries returns how many times Do retries under p.
+func (p Policy) Retries() int {
+	switch {
+	case p.MaxRetries == 0:
+		return DefaultMaxRetries
+	case p.MaxRetries < 0:
+		return 0
+	default:
+		return p.MaxRetries
+	}
+}
+
+// Wait returns the pause before the retry following attempt, counted from zero.
+func (p Policy) Wait(attempt int) time.Duration {
+	delay := p.Delay
+	if delay <= 0 {
+		delay = DefaultDelay
+	}
+
+	return delay * time.Duration(attempt+1)
+}
+
+// Do runs fn with the attempt number, counted from zero, until it succeeds, fails with an
+// error retryable rejects, or runs out of retries, and returns the last error. When ctx is
+// done during a pause, Do returns that error joined with ctx.Err().
+func Do(ctx context.Context, p Policy, retryable func(error) bool, fn func(attempt int) error) error {
+	retries := p.Retries()
+
+	for attempt := 0; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(p.Wait(attempt)):
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+)
+
+var errRetryable = errors.New("retryable")
+
+func isRetryable(err error) bool {
+	return errors.Is(err, errRetryable)
+}
+
+func TestDo(t *testing.T) {
+	errFatal := errors.New("fatal")
+
+	cases := []struct {
+		name     string
+		policy   Policy
+		errs     []error
+		want     error
+		attempts int
+	}{
+		{name: "success", errs: []error{nil}, attempts: 1},
+		{name: "retries until success", errs: []error{errRetryable, errRetryable, nil}, attempts: 3},
+		{name: "stops on other errors", errs: []error{errRetryable, errFatal}, want: errFatal, attempts: 2},
+		{
+			name:     "runs out of retries",
+			policy:   Policy{MaxRetries: 1},
+			errs:     []error{errRetryable, errRetryable},
+			want:     errRetryable,
+			attempts: 2,
+		},
+		{
+			name:     "negative disables retries",
+			policy:   Policy{MaxRetries: -1},
+			errs:     []error{errRetryable},
+			want:     errRetryable,
+			attempts: 1,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			test.policy.Delay = time.Microsecond
+
+			var attempts []int
+
+			err := Do(context.Background(), test.policy, isRetryable, func(attempt int) error {
+				attempts = append(attempts, attempt)
+
+				return test.errs[attempt]
+			})
+			testutils.Equal(t, errors.Is(err, test.want), true)
+			testutils.Equal(t, len(attempts), test.attempts)
+			testutils.Equal(t, attempts[len(attempts)-1], test.attempts-1)
+		})
+	}
+}
+
+func TestDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+
+	err := Do(ctx, Policy{Delay: time.Hour}, isRetryable, func(int) error {
+		calls++
+
+		return errRetryable
+	})
+	testutils.Equal(t, errors.Is(err, errRetryable), true)
+	testutils.Equal(t, errors.Is(err, context.Canceled), true)
+	testutils.Equal(t, calls, 1)
+}
+
+func TestPolicy(t *testing.T) {
+	testutils.Equal(t, Policy{}.Retries(), DefaultMaxRetries)
+	testutils.Equal(t, Policy{MaxRetries: -1}.Retries(), 0)
+	testutils.Equal(t, Policy{MaxRetries: 5}.Retries(), 5)
+
+	testutils.Equal(t, Policy{}.Wait(0), DefaultDelay)
+	testutils.Equal(t, Policy{Delay: time.Second}.Wait(2), 3*time.Second)
+}
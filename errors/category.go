@@ -0,0 +1,230 @@
+package errors //nolint:revive
+
+import (
+	"database/sql"
+	nativeErrors "errors"
+	"sync"
+	"time"
+)
+
+// Category classifies an error so transports can map it to a status consistently.
+type Category string
+
+// Error categories.
+const (
+	CategoryUnknown      Category = ""
+	CategoryValidation   Category = "validation"
+	CategoryNotFound     Category = "not_found"
+	CategoryConflict     Category = "conflict"
+	CategoryUnauthorized Category = "unauthorized"
+	CategoryForbidden    Category = "forbidden"
+	CategoryRateLimited  Category = "rate_limited"
+	CategoryUpstream     Category = "upstream"
+	CategoryInternal     Category = "internal"
+)
+
+// Category sentinels; every *Error of a category matches its sentinel with errors.Is.
+var (
+	ErrValidation   = New("validation failed")
+	ErrNotFound     = New("not found")
+	ErrConflict     = New("conflict")
+	ErrUnauthorized = New("unauthorized")
+	ErrForbidden    = New("forbidden")
+	ErrRateLimited  = New("rate limited")
+	ErrUpstream     = New("upstream failure")
+	ErrInternal     = New("internal error")
+)
+
+var categorySentinels = map[Category]error{
+	CategoryValidation:   ErrValidation,
+	CategoryNotFound:     ErrNotFound,
+	CategoryConflict:     ErrConflict,
+	CategoryUnauthorized: ErrUnauthorized,
+	CategoryForbidden:    ErrForbidden,
+	CategoryRateLimited:  ErrRateLimited,
+	CategoryUpstream:     ErrUpstream,
+	CategoryInternal:     ErrInternal,
+}
+
+// FieldError describes one invalid input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Categorized is implemented by errors that carry their own category.
+type Categorized interface {
+	ErrorCategory() Category
+}
+
+// FieldErrors is implemented by errors that carry field-level validation details.
+type FieldErrors interface {
+	FieldErrors() []FieldError
+}
+
+// Classifier maps an error to a category; CategoryUnknown means no match.
+type Classifier func(err error) Category
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   = []Classifier{
+		CategoryFor(sql.ErrNoRows, CategoryNotFound),
+	}
+)
+
+// Error is a categorized error with an optional public message, field errors and cause.
+type Error struct {
+	Category   Category
+	Message    string
+	Fields     []FieldError
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Error returns the message followed by the cause.
+func (e *Error) Error() string {
+	switch {
+	case e.Message != "" && e.Err != nil:
+		return e.Message + ": " + e.Err.Error()
+	case e.Message != "":
+		return e.Message
+	case e.Err != nil:
+		return e.Err.Error()
+	}
+
+	if sentinel, ok := categorySentinels[e.Category]; ok {
+		return sentinel.Error()
+	}
+
+	return ErrInternal.Error()
+}
+
+// Unwrap returns the cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel of the error category.
+func (e *Error) Is(target error) bool {
+	sentinel, ok := categorySentinels[e.Category]
+
+	return ok && sentinel == target
+}
+
+// ErrorCategory returns the error category.
+func (e *Error) ErrorCategory() Category {
+	return e.Category
+}
+
+// FieldErrors returns field-level validation details.
+func (e *Error) FieldErrors() []FieldError {
+	return e.Fields
+}
+
+// Validation returns a validation error with field details.
+func Validation(message string, fields ...FieldError) *Error {
+	return &Error{Category: CategoryValidation, Message: message, Fields: fields}
+}
+
+// NotFound returns a not found error.
+func NotFound(message string) *Error {
+	return &Error{Category: CategoryNotFound, Message: message}
+}
+
+// Conflict returns a conflict error.
+func Conflict(message string) *Error {
+	return &Error{Category: CategoryConflict, Message: message}
+}
+
+// Unauthorized returns an unauthorized error.
+func Unauthorized(message string) *Error {
+	return &Error{Category: CategoryUnauthorized, Message: message}
+}
+
+// Forbidden returns a forbidden error.
+func Forbidden(message string) *Error {
+	return &Error{Category: CategoryForbidden, Message: message}
+}
+
+// RateLimited returns a rate limited error; retryAfter is the suggested wait, if known.
+func RateLimited(message string, retryAfter time.Duration) *Error {
+	return &Error{Category: CategoryRateLimited, Message: message, RetryAfter: retryAfter}
+}
+
+// Upstream returns an upstream failure caused by err.
+func Upstream(message string, err error) *Error {
+	return &Error{Category: CategoryUpstream, Message: message, Err: err}
+}
+
+// Classify returns nil for nil errors or err wrapped with the given category.
+func Classify(category Category, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Category: category, Err: err}
+}
+
+// RegisterClassifier adds a classifier consulted by CategoryOf; adapters use it
+// to map driver errors such as missing records.
+func RegisterClassifier(classifier Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+
+	classifiers = append(classifiers, classifier)
+}
+
+// CategoryFor returns a classifier mapping errors matching target to category.
+func CategoryFor(target error, category Category) Classifier {
+	return func(err error) Category {
+		if nativeErrors.Is(err, target) {
+			return category
+		}
+
+		return CategoryUnknown
+	}
+}
+
+// CategoryOf classifies err.
+//
+// Categorized errors in the chain win, then registered classifiers. Remaining
+// errors wrapped in a BoundaryError are upstream failures and everything else
+// is internal.
+func CategoryOf(err error) Category {
+	if err == nil {
+		return CategoryUnknown
+	}
+
+	var categorized Categorized
+	if nativeErrors.As(err, &categorized) {
+		if category := categorized.ErrorCategory(); category != CategoryUnknown {
+			return category
+		}
+	}
+
+	classifiersMu.RLock()
+	defer classifiersMu.RUnlock()
+
+	for _, classifier := range classifiers {
+		if category := classifier(err); category != CategoryUnknown {
+			return category
+		}
+	}
+
+	var boundary BoundaryError
+	if nativeErrors.As(err, &boundary) {
+		return CategoryUpstream
+	}
+
+	return CategoryInternal
+}
+
+// FieldErrorsOf returns field details from the first error in the chain that has them.
+func FieldErrorsOf(err error) []FieldError {
+	var fields FieldErrors
+	if nativeErrors.As(err, &fields) {
+		return fields.FieldErrors()
+	}
+
+	return nil
+}
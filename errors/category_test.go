@@ -0,0 +1,112 @@
+package errors //nolint:revive
+
+import (
+	"database/sql"
+	nativeErrors "errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var errCategoryTestConflict = nativeErrors.New("driver duplicate")
+
+func init() {
+	RegisterClassifier(CategoryFor(errCategoryTestConflict, CategoryConflict))
+}
+
+func TestCategoryOf(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want Category
+	}{
+		{name: "nil", err: nil, want: CategoryUnknown},
+		{name: "typed error", err: NotFound("user not found"), want: CategoryNotFound},
+		{name: "wrapped typed error", err: fmt.Errorf("load: %w", Forbidden("")), want: CategoryForbidden},
+		{name: "sql no rows", err: WrapBoundary("postgres", "query", sql.ErrNoRows), want: CategoryNotFound},
+		{name: "registered classifier", err: WrapBoundary("db", "insert", errCategoryTestConflict), want: CategoryConflict},
+		{name: "unclassified boundary error", err: WrapBoundary("redis", "get", nativeErrors.New("eof")), want: CategoryUpstream},
+		{name: "classify", err: Classify(CategoryRateLimited, nativeErrors.New("quota")), want: CategoryRateLimited},
+		{name: "plain error", err: nativeErrors.New("boom"), want: CategoryInternal},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := CategoryOf(test.err); got != test.want {
+				t.Fatalf("CategoryOf() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCategoryError(t *testing.T) {
+	t.Parallel()
+
+	cause := nativeErrors.New("connection refused")
+	fields := []FieldError{{Field: "name", Message: "is required"}}
+
+	cases := []struct {
+		name       string
+		err        *Error
+		sentinel   error
+		wantText   string
+		wantFields []FieldError
+	}{
+		{
+			name:       "validation",
+			err:        Validation("invalid user", fields...),
+			sentinel:   ErrValidation,
+			wantText:   "invalid user",
+			wantFields: fields,
+		},
+		{name: "conflict", err: Conflict("email taken"), sentinel: ErrConflict, wantText: "email taken"},
+		{name: "unauthorized default text", err: Unauthorized(""), sentinel: ErrUnauthorized, wantText: "unauthorized"},
+		{
+			name:     "rate limited",
+			err:      RateLimited("slow down", time.Second),
+			sentinel: ErrRateLimited,
+			wantText: "slow down",
+		},
+		{
+			name:     "upstream with cause",
+			err:      Upstream("payments unavailable", cause),
+			sentinel: ErrUpstream,
+			wantText: "payments unavailable: connection refused",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.err.Error(); got != test.wantText {
+				t.Fatalf("Error() = %q, want %q", got, test.wantText)
+			}
+
+			if !nativeErrors.Is(fmt.Errorf("wrap: %w", test.err), test.sentinel) {
+				t.Fatalf("errors.Is(%v, %v) = false", test.err, test.sentinel)
+			}
+
+			if nativeErrors.Is(test.err, ErrInternal) {
+				t.Fatal("error should not match another category")
+			}
+
+			if got := FieldErrorsOf(test.err); !reflect.DeepEqual(got, test.wantFields) {
+				t.Fatalf("FieldErrorsOf() = %v, want %v", got, test.wantFields)
+			}
+		})
+	}
+
+	if !nativeErrors.Is(Upstream("", cause), cause) {
+		t.Fatal("upstream error should unwrap its cause")
+	}
+
+	if Classify(CategoryNotFound, nil) != nil {
+		t.Fatal("Classify(nil) should return nil")
+	}
+}
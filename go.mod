@@ -10,13 +10,13 @@ retract (
 require (
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/jmoiron/sqlx v1.3.5
-	github.com/nats-io/nats.go v1.49.0 // indirect
+	github.com/nats-io/nats.go v1.49.0
 	github.com/pkg/errors v0.9.1
 	github.com/tidwall/buntdb v1.3.0
 	github.com/valyala/fasthttp v1.69.0
 	go.mongodb.org/mongo-driver v1.17.6
 	go.uber.org/atomic v1.11.0
-	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/sync v0.19.0
 	golang.org/x/sys v0.42.0 // indirect
 	google.golang.org/grpc v1.65.0 // indirect
 )
@@ -24,6 +24,7 @@ require (
 require (
 	dario.cat/mergo v1.0.2
 	github.com/AlekSi/pointer v1.2.0
+	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/DataDog/datadog-api-client-go/v2 v2.44.0
 	github.com/DataDog/datadog-go/v5 v5.8.3
 	github.com/FrogoAI/fdb-client v1.2.1
@@ -35,12 +36,14 @@ require (
 	github.com/aerospike/aerospike-client-go/v7 v7.2.1
 	github.com/agoda-com/opentelemetry-go/otelslog v0.1.1
 	github.com/agoda-com/opentelemetry-logs-go v0.5.0
+	github.com/alicebob/miniredis/v2 v2.33.0
 	github.com/apache/tinkerpop/gremlin-go/v3 v3.7.2
 	github.com/caarlos0/env/v10 v10.0.0
 	github.com/dgryski/go-farm v0.0.0-20240924180020-3414d57e47da
 	github.com/dgryski/go-minhash v0.0.0-20190315135803-ad340ca03076
 	github.com/dgryski/go-spooky v0.0.0-20170606183049-ed3d087f40e2
 	github.com/elastic/go-elasticsearch/v8 v8.16.0
+	github.com/fasthttp/websocket v1.5.12
 	github.com/go-faster/xor v1.0.0
 	github.com/go-jose/go-jose/v3 v3.0.4
 	github.com/gofiber/contrib/v3/jwt v1.1.0
@@ -61,8 +64,10 @@ require (
 	github.com/spf13/cast v1.10.0
 	github.com/stretchr/testify v1.11.1
 	github.com/sugarme/tokenizer v0.3.0
+	github.com/tidwall/gjson v1.14.3
 	github.com/tink-crypto/tink-go/v2 v2.5.0
 	github.com/twmb/murmur3 v1.1.8
+	github.com/vmihailenco/msgpack/v5 v5.4.1
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.53.0
 	go.opentelemetry.io/otel v1.28.0
 	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.27.0
@@ -81,6 +86,7 @@ require (
 	github.com/FrogoAI/multiproc v1.0.0 // indirect
 	github.com/MicahParks/keyfunc/v2 v2.1.0 // indirect
 	github.com/Microsoft/go-winio v0.5.0 // indirect
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
 	github.com/andybalholm/brotli v1.2.0 // indirect
 	github.com/apache/arrow/go/arrow v0.0.0-20211112161151-bc219186db40 // indirect
 	github.com/awalterschulze/gographviz v2.0.3+incompatible // indirect
@@ -132,10 +138,10 @@ require (
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/samber/lo v1.47.0 // indirect
 	github.com/samber/slog-common v0.17.0 // indirect
+	github.com/savsgio/gotils v0.0.0-20240704082632-aef3928b8a38 // indirect
 	github.com/schollz/progressbar/v2 v2.15.0 // indirect
 	github.com/sugarme/regexpset v0.0.0-20200920021344-4d4ec8eaf93c // indirect
 	github.com/tidwall/btree v1.4.2 // indirect
-	github.com/tidwall/gjson v1.14.3 // indirect
 	github.com/tidwall/grect v0.1.4 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
@@ -143,7 +149,6 @@ require (
 	github.com/tidwall/tinyqueue v0.1.1 // indirect
 	github.com/tinylib/msgp v1.6.3 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.2 // indirect
@@ -9,7 +9,13 @@
 // usage has an algorithm-specific import path.
 package rsaoaep
 
-import legacy "github.com/InsideGallery/core/pki/rsa"
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	legacy "github.com/InsideGallery/core/pki/rsa"
+)
 
 const (
 	// PrivateKeyPEMBlockType is the PEM block type used for PKCS#1 private keys.
@@ -33,3 +39,31 @@ func New(bits int) (*Cipher, error) {
 func FromPrivateKey(data []byte) (*Cipher, error) {
 	return legacy.FromPrivateKey(data)
 }
+
+// PrivateKey returns the crypto/rsa private key held by the cipher.
+//
+// The key is restored from the cipher's PKCS#1 encoding so callers such as JOSE
+// libraries can use it without reaching into the legacy package.
+func PrivateKey(cipher *Cipher) (*rsa.PrivateKey, error) {
+	raw, err := cipher.ToBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, ErrFailedToParsePEMBlock
+	}
+
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// PublicKey returns the crypto/rsa public key held by the cipher.
+func PublicKey(cipher *Cipher) (*rsa.PublicKey, error) {
+	privateKey, err := PrivateKey(cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	return &privateKey.PublicKey, nil
+}
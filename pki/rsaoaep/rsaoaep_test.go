@@ -1,6 +1,8 @@
 package rsaoaep
 
 import (
+	"crypto/rsa"
+	"crypto/sha256"
 	"errors"
 	"testing"
 )
@@ -48,6 +50,45 @@ func TestRSAOAEP(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "exposes crypto rsa keys",
+			run: func(t *testing.T) {
+				t.Helper()
+
+				cipher, err := New(2048)
+				if err != nil {
+					t.Fatalf("new cipher: %v", err)
+				}
+
+				privateKey, err := PrivateKey(cipher)
+				if err != nil {
+					t.Fatalf("private key: %v", err)
+				}
+
+				publicKey, err := PublicKey(cipher)
+				if err != nil {
+					t.Fatalf("public key: %v", err)
+				}
+
+				if !privateKey.PublicKey.Equal(publicKey) {
+					t.Fatal("public key does not match private key")
+				}
+
+				encrypted, err := cipher.Encrypt([]byte("message"))
+				if err != nil {
+					t.Fatalf("encrypt: %v", err)
+				}
+
+				got, err := rsa.DecryptOAEP(sha256.New(), nil, privateKey, encrypted, nil)
+				if err != nil {
+					t.Fatalf("decrypt with exported key: %v", err)
+				}
+
+				if string(got) != "message" {
+					t.Fatalf("plaintext = %q, want %q", got, "message")
+				}
+			},
+		},
 		{
 			name: "invalid private key returns sentinel",
 			run: func(t *testing.T) {
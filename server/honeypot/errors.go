@@ -0,0 +1,14 @@
+package honeypot
+
+import "errors"
+
+// All kind of errors
+var (
+	ErrNoListeners      = errors.New("honeypot needs at least one listener")
+	ErrUnknownProtocol  = errors.New("unknown honeypot protocol")
+	ErrEmptyAddress     = errors.New("honeypot listener address is empty")
+	ErrNotListening     = errors.New("honeypot is not listening")
+	ErrAlreadyListening = errors.New("honeypot is already listening")
+
+	errInvalidRedisCommand = errors.New("invalid redis command")
+)
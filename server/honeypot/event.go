@@ -0,0 +1,130 @@
+package honeypot
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/InsideGallery/core/metrics"
+)
+
+// Metric names recorded by MetricsSink.
+const (
+	MetricConnections = "honeypot.connections"
+	MetricCredentials = "honeypot.credentials"
+	MetricBytes       = "honeypot.bytes"
+)
+
+// Credential is one username and password pair tried by a client.
+type Credential struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Event describes one honeypot connection once it ends.
+type Event struct {
+	Time       time.Time     `json:"time"`
+	Listener   string        `json:"listener"`
+	Protocol   Protocol      `json:"protocol"`
+	SourceIP   string        `json:"source_ip"`
+	SourcePort int           `json:"source_port"`
+	LocalAddr  string        `json:"local_addr"`
+	Duration   time.Duration `json:"duration"`
+	// Credentials holds the logins attempted, in order.
+	Credentials []Credential `json:"credentials,omitempty"`
+	// Commands holds request lines, commands and client identifiers, capped at MaxCommands.
+	Commands []string `json:"commands,omitempty"`
+	// BytesRead and PayloadHash (hex SHA-256) cover everything the client sent.
+	BytesRead   int64  `json:"bytes_read"`
+	PayloadHash string `json:"payload_hash"`
+	// Truncated is set when the client hit Config.MaxBytes.
+	Truncated bool `json:"truncated,omitempty"`
+	// Rejected is set when the connection was closed at once because of Config.MaxConnections.
+	Rejected bool `json:"rejected,omitempty"`
+}
+
+// Sink receives honeypot events. Handle is called from the connection goroutine.
+type Sink interface {
+	Handle(ctx context.Context, event Event)
+}
+
+// SinkFunc adapts a function, such as one feeding an IP blocklist, to Sink.
+type SinkFunc func(ctx context.Context, event Event)
+
+// Handle calls f.
+func (f SinkFunc) Handle(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+// MultiSink delivers every event to each sink in order.
+type MultiSink []Sink
+
+// Handle calls every sink.
+func (m MultiSink) Handle(ctx context.Context, event Event) {
+	for _, sink := range m {
+		sink.Handle(ctx, event)
+	}
+}
+
+// SlogSink logs events as warnings.
+type SlogSink struct {
+	Logger *slog.Logger
+}
+
+// NewSlogSink returns a sink logging to logger; nil uses slog.Default().
+func NewSlogSink(logger *slog.Logger) SlogSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return SlogSink{Logger: logger}
+}
+
+// Handle logs event.
+func (s SlogSink) Handle(ctx context.Context, event Event) {
+	s.Logger.WarnContext(ctx, "Intrusion attempt detected",
+		"listener", event.Listener,
+		"protocol", event.Protocol,
+		"source_ip", event.SourceIP,
+		"source_port", event.SourcePort,
+		"duration", event.Duration,
+		"credentials", event.Credentials,
+		"commands", event.Commands,
+		"bytes_read", event.BytesRead,
+		"payload_hash", event.PayloadHash,
+		"truncated", event.Truncated,
+		"rejected", event.Rejected,
+	)
+}
+
+// MetricsSink counts connections and attempted credentials and records the bytes
+// read, tagged by listener, protocol and rejection. Source addresses are not tagged
+// to keep cardinality bounded.
+type MetricsSink struct {
+	Client *metrics.Client
+}
+
+// Handle records event.
+func (s MetricsSink) Handle(_ context.Context, event Event) {
+	tags := []string{
+		"listener:" + event.Listener,
+		"protocol:" + string(event.Protocol),
+		"rejected:" + strconv.FormatBool(event.Rejected),
+	}
+
+	errs := []error{
+		s.Client.Count(MetricConnections, 1, tags),
+		s.Client.Distribution(MetricBytes, float64(event.BytesRead), tags),
+	}
+
+	if len(event.Credentials) > 0 {
+		errs = append(errs, s.Client.Count(MetricCredentials, int64(len(event.Credentials)), tags))
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			slog.Default().Warn("record honeypot metric failed", "error", err)
+		}
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"time"
 )
 
+// Honeypot listens on listenPort, sends a fake SSH banner and logs every input line.
+//
+// Deprecated: use New and Server.Run, which support several protocols, limits and shutdown.
 func Honeypot(listenPort string) error {
 	listener, err := net.Listen("tcp", ":"+listenPort)
 	if err != nil {
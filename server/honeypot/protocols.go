@@ -0,0 +1,371 @@
+package honeypot
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// Protocol is the service a listener emulates.
+type Protocol string
+
+// Supported protocols.
+const (
+	ProtocolSSH    Protocol = "ssh"
+	ProtocolHTTP   Protocol = "http"
+	ProtocolTelnet Protocol = "telnet"
+	ProtocolRedis  Protocol = "redis"
+	ProtocolSMTP   Protocol = "smtp"
+)
+
+// Default banners; ListenerConfig.Banner overrides them.
+const (
+	DefaultSSHBanner    = "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3ubuntu0.6"
+	DefaultHTTPBanner   = "Apache/2.4.52 (Ubuntu)"
+	DefaultTelnetBanner = "Ubuntu 22.04.4 LTS"
+	DefaultRedisBanner  = ""
+	DefaultSMTPBanner   = "mail.example.com ESMTP Postfix (Ubuntu)"
+)
+
+const (
+	telnetLoginAttempts = 3
+	telnetIAC           = 255
+	telnetWill          = 251
+	telnetSB            = 250
+	telnetSE            = 240
+	redisAuthPassword   = 2
+	redisAuthUser       = 3
+	saslPlainParts      = 3
+	crlf                = "\r\n"
+)
+
+// emulator runs the server side of a protocol until the client leaves or misbehaves.
+type emulator func(s *session, banner string)
+
+var emulators = map[Protocol]emulator{
+	ProtocolSSH:    emulateSSH,
+	ProtocolHTTP:   emulateHTTP,
+	ProtocolTelnet: emulateTelnet,
+	ProtocolRedis:  emulateRedis,
+	ProtocolSMTP:   emulateSMTP,
+}
+
+var defaultBanners = map[Protocol]string{
+	ProtocolSSH:    DefaultSSHBanner,
+	ProtocolHTTP:   DefaultHTTPBanner,
+	ProtocolTelnet: DefaultTelnetBanner,
+	ProtocolRedis:  DefaultRedisBanner,
+	ProtocolSMTP:   DefaultSMTPBanner,
+}
+
+// emulateSSH sends the identification string, records the client one and drains the
+// binary key exchange; credentials are never reached without real key exchange.
+func emulateSSH(s *session, banner string) {
+	if err := s.write(banner + crlf); err != nil {
+		return
+	}
+
+	ident, err := s.readLine()
+	if err != nil {
+		return
+	}
+
+	s.command(ident)
+	s.drain()
+}
+
+// emulateHTTP answers one request with 401 and a Basic challenge, recording the
+// request line and any Basic credentials.
+func emulateHTTP(s *session, banner string) {
+	requestLine, err := s.readLine()
+	if err != nil {
+		return
+	}
+
+	s.command(requestLine)
+
+	for {
+		line, err := s.readLine()
+		if err != nil || line == "" {
+			break
+		}
+
+		name, value, _ := strings.Cut(line, ":")
+		if strings.EqualFold(strings.TrimSpace(name), "Authorization") {
+			if username, password, ok := basicCredentials(strings.TrimSpace(value)); ok {
+				s.credential(username, password)
+			}
+		}
+	}
+
+	_ = s.write("HTTP/1.1 401 Unauthorized" + crlf +
+		"Server: " + banner + crlf +
+		`WWW-Authenticate: Basic realm="Restricted"` + crlf +
+		"Content-Length: 0" + crlf +
+		"Connection: close" + crlf + crlf)
+}
+
+// emulateTelnet prompts for a login a few times and always rejects it.
+func emulateTelnet(s *session, banner string) {
+	if err := s.write(banner + crlf + crlf); err != nil {
+		return
+	}
+
+	for range telnetLoginAttempts {
+		if err := s.write("login: "); err != nil {
+			return
+		}
+
+		username, err := s.readLine()
+		if err != nil {
+			return
+		}
+
+		if err := s.write("Password: "); err != nil {
+			return
+		}
+
+		password, err := s.readLine()
+		if err != nil {
+			return
+		}
+
+		s.credential(stripTelnet(username), stripTelnet(password))
+
+		if err := s.write(crlf + "Login incorrect" + crlf); err != nil {
+			return
+		}
+	}
+}
+
+// emulateRedis answers inline and RESP commands as a password-protected server.
+func emulateRedis(s *session, _ string) {
+	for {
+		args, err := readRedisCommand(s)
+		if err != nil {
+			return
+		}
+
+		if len(args) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(args[0])
+		s.command(name)
+
+		reply := "-NOAUTH Authentication required."
+
+		switch name {
+		case "AUTH":
+			switch len(args) {
+			case redisAuthPassword:
+				s.credential("", args[1])
+			case redisAuthUser:
+				s.credential(args[1], args[2])
+			}
+
+			reply = "-WRONGPASS invalid username-password pair or user is disabled."
+		case "PING":
+			reply = "+PONG"
+		case "QUIT":
+			_ = s.write("+OK" + crlf)
+
+			return
+		}
+
+		if err := s.write(reply + crlf); err != nil {
+			return
+		}
+	}
+}
+
+// emulateSMTP speaks enough ESMTP to collect AUTH PLAIN and AUTH LOGIN credentials.
+func emulateSMTP(s *session, banner string) {
+	host, _, _ := strings.Cut(banner, " ")
+
+	if err := s.write("220 " + banner + crlf); err != nil {
+		return
+	}
+
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return
+		}
+
+		verb, argument, _ := strings.Cut(strings.TrimSpace(line), " ")
+		verb = strings.ToUpper(verb)
+
+		if verb == "AUTH" {
+			// Keep the mechanism only; the initial response holds the credentials.
+			mechanism, _, _ := strings.Cut(strings.TrimSpace(argument), " ")
+			s.command(verb + " " + strings.ToUpper(mechanism))
+		} else {
+			s.command(line)
+		}
+
+		var reply string
+
+		switch verb {
+		case "EHLO":
+			reply = "250-" + host + crlf + "250-AUTH PLAIN LOGIN" + crlf + "250 8BITMIME"
+		case "HELO":
+			reply = "250 " + host
+		case "AUTH":
+			if !smtpAuth(s, argument) {
+				return
+			}
+
+			reply = "535 5.7.8 Error: authentication failed"
+		case "NOOP", "RSET":
+			reply = "250 2.0.0 Ok"
+		case "MAIL", "RCPT", "DATA":
+			reply = "530 5.7.0 Authentication required"
+		case "QUIT":
+			_ = s.write("221 2.0.0 Bye" + crlf)
+
+			return
+		default:
+			reply = "502 5.5.2 Error: command not recognized"
+		}
+
+		if err := s.write(reply + crlf); err != nil {
+			return
+		}
+	}
+}
+
+// smtpAuth runs the AUTH exchange and records the credentials; it returns false when
+// the connection failed.
+func smtpAuth(s *session, argument string) bool {
+	mechanism, initial, _ := strings.Cut(strings.TrimSpace(argument), " ")
+
+	prompt := func(challenge string) (string, bool) {
+		if err := s.write("334 " + challenge + crlf); err != nil {
+			return "", false
+		}
+
+		line, err := s.readLine()
+		if err != nil {
+			return "", false
+		}
+
+		return decodeBase64(line), true
+	}
+
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		response := decodeBase64(initial)
+
+		if initial == "" {
+			var ok bool
+			if response, ok = prompt(""); !ok {
+				return false
+			}
+		}
+
+		// authzid, authcid and password
+		parts := strings.SplitN(response, "\x00", saslPlainParts)
+		if len(parts) == saslPlainParts {
+			s.credential(parts[1], parts[2])
+		}
+	case "LOGIN":
+		username, ok := prompt(base64.StdEncoding.EncodeToString([]byte("Username:")))
+		if !ok {
+			return false
+		}
+
+		password, ok := prompt(base64.StdEncoding.EncodeToString([]byte("Password:")))
+		if !ok {
+			return false
+		}
+
+		s.credential(username, password)
+	}
+
+	return true
+}
+
+// readRedisCommand reads a RESP array of bulk strings or an inline command.
+func readRedisCommand(s *session) ([]string, error) {
+	line, err := s.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil || count < 0 || count > MaxCommands {
+		return nil, errInvalidRedisCommand
+	}
+
+	args := make([]string, 0, count)
+
+	for range count {
+		header, err := s.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.HasPrefix(header, "$") {
+			return nil, errInvalidRedisCommand
+		}
+
+		value, err := s.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, value)
+	}
+
+	return args, nil
+}
+
+func basicCredentials(header string) (string, string, bool) {
+	scheme, encoded, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Basic") {
+		return "", "", false
+	}
+
+	return strings.Cut(decodeBase64(encoded), ":")
+}
+
+func decodeBase64(value string) string {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return ""
+	}
+
+	return string(decoded)
+}
+
+// stripTelnet removes IAC option negotiation sent by real telnet clients.
+func stripTelnet(value string) string {
+	raw := []byte(value)
+	clean := make([]byte, 0, len(raw))
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != telnetIAC || i+1 >= len(raw) {
+			clean = append(clean, raw[i])
+
+			continue
+		}
+
+		switch command := raw[i+1]; {
+		case command >= telnetWill:
+			i += 2
+		case command == telnetSB:
+			for i < len(raw) && raw[i] != telnetSE {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+
+	return string(clean)
+}
@@ -0,0 +1,321 @@
+package honeypot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Server defaults.
+const (
+	DefaultMaxConnections = 256
+	DefaultReadTimeout    = 30 * time.Second
+	DefaultSessionTimeout = 2 * time.Minute
+	DefaultMaxBytes       = 64 << 10
+
+	// MaxCommands caps Event.Commands.
+	MaxCommands = 32
+
+	maxCommandLength = 512
+	acceptRetryDelay = 50 * time.Millisecond
+)
+
+// ListenerConfig is one emulated service.
+type ListenerConfig struct {
+	// Name identifies the listener in events; empty uses the protocol.
+	Name     string
+	Address  string
+	Protocol Protocol
+	// Banner overrides the protocol default: the SSH identification, HTTP Server header,
+	// Telnet greeting or SMTP greeting.
+	Banner string
+}
+
+// Config configures a Server; zero values use the defaults.
+type Config struct {
+	Listeners []ListenerConfig
+	// MaxConnections limits concurrent connections across listeners; extra connections are
+	// closed at once and reported with Event.Rejected.
+	MaxConnections int
+	// ReadTimeout bounds each read and SessionTimeout the whole connection.
+	ReadTimeout    time.Duration
+	SessionTimeout time.Duration
+	// MaxBytes caps what is read from one connection.
+	MaxBytes int64
+	// Sink receives an event per connection; nil logs through slog.Default().
+	Sink Sink
+}
+
+// Server runs honeypot listeners until its context is done.
+type Server struct {
+	cfg   Config
+	slots chan struct{}
+
+	mu        sync.Mutex
+	listeners []net.Listener
+	active    map[net.Conn]struct{}
+	stopping  bool
+	wg        sync.WaitGroup
+}
+
+// New validates cfg and applies defaults.
+func New(cfg Config) (*Server, error) {
+	if len(cfg.Listeners) == 0 {
+		return nil, ErrNoListeners
+	}
+
+	for i, listener := range cfg.Listeners {
+		if _, ok := emulators[listener.Protocol]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownProtocol, listener.Protocol)
+		}
+
+		if listener.Address == "" {
+			return nil, ErrEmptyAddress
+		}
+
+		if listener.Name == "" {
+			cfg.Listeners[i].Name = string(listener.Protocol)
+		}
+
+		if listener.Banner == "" {
+			cfg.Listeners[i].Banner = defaultBanners[listener.Protocol]
+		}
+	}
+
+	if cfg.MaxConnections <= 0 {
+		cfg.MaxConnections = DefaultMaxConnections
+	}
+
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = DefaultReadTimeout
+	}
+
+	if cfg.SessionTimeout <= 0 {
+		cfg.SessionTimeout = DefaultSessionTimeout
+	}
+
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultMaxBytes
+	}
+
+	if cfg.Sink == nil {
+		cfg.Sink = NewSlogSink(nil)
+	}
+
+	return &Server{
+		cfg:    cfg,
+		slots:  make(chan struct{}, cfg.MaxConnections),
+		active: make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Run listens on every configured address and serves until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+
+	return s.Serve(ctx)
+}
+
+// Listen binds every listener; on failure the already bound ones are closed.
+func (s *Server) Listen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listeners != nil {
+		return ErrAlreadyListening
+	}
+
+	listeners := make([]net.Listener, 0, len(s.cfg.Listeners))
+
+	for _, cfg := range s.cfg.Listeners {
+		listener, err := net.Listen("tcp", cfg.Address)
+		if err != nil {
+			for _, opened := range listeners {
+				_ = opened.Close()
+			}
+
+			return fmt.Errorf("honeypot %s: %w", cfg.Name, err)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	s.listeners = listeners
+
+	return nil
+}
+
+// Addrs returns the bound addresses in listener order.
+func (s *Server) Addrs() []net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	addrs := make([]net.Addr, 0, len(s.listeners))
+	for _, listener := range s.listeners {
+		addrs = append(addrs, listener.Addr())
+	}
+
+	return addrs
+}
+
+// Serve accepts connections until ctx is done, then closes the listeners and open
+// connections and waits for their events to be delivered.
+func (s *Server) Serve(ctx context.Context) error {
+	s.mu.Lock()
+	listeners := s.listeners
+	s.mu.Unlock()
+
+	if listeners == nil {
+		return ErrNotListening
+	}
+
+	for i, listener := range listeners {
+		slog.Default().Info("Honeypot listening",
+			"listener", s.cfg.Listeners[i].Name,
+			"protocol", s.cfg.Listeners[i].Protocol,
+			"addr", listener.Addr().String())
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+
+			s.accept(ctx, listener, s.cfg.Listeners[i])
+		}()
+	}
+
+	<-ctx.Done()
+
+	s.mu.Lock()
+	s.stopping = true
+
+	for _, listener := range listeners {
+		_ = listener.Close()
+	}
+
+	for conn := range s.active {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	return nil
+}
+
+func (s *Server) accept(ctx context.Context, listener net.Listener, cfg ListenerConfig) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			slog.Default().Error("Error accepting connection", "listener", cfg.Name, "err", err)
+			time.Sleep(acceptRetryDelay)
+
+			continue
+		}
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			defer s.recover(conn, cfg)
+
+			s.handle(ctx, conn, cfg)
+		}()
+	}
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn, cfg ListenerConfig) {
+	ctx = context.WithoutCancel(ctx)
+	event := newEvent(conn, cfg)
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		_ = conn.Close()
+		event.Rejected = true
+		s.cfg.Sink.Handle(ctx, event)
+
+		return
+	}
+
+	defer func() { <-s.slots }()
+
+	if !s.track(conn) {
+		_ = conn.Close()
+
+		return
+	}
+
+	sess := newSession(conn, s.cfg, &event)
+	emulators[cfg.Protocol](sess, cfg.Banner)
+	sess.finish()
+
+	s.untrack(conn)
+	_ = conn.Close()
+
+	event.Duration = time.Since(event.Time)
+	s.cfg.Sink.Handle(ctx, event)
+}
+
+// recover keeps a panicking emulator from stopping the server: it logs the panic and
+// drops the connection.
+func (s *Server) recover(conn net.Conn, cfg ListenerConfig) {
+	rval := recover()
+	if rval == nil {
+		return
+	}
+
+	slog.Default().Error("Recovered honeypot connection panic", "listener", cfg.Name, "rval", rval)
+
+	s.untrack(conn)
+	_ = conn.Close()
+}
+
+// track registers conn for shutdown; it returns false when the server is stopping.
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopping {
+		return false
+	}
+
+	s.active[conn] = struct{}{}
+
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.active, conn)
+	s.mu.Unlock()
+}
+
+func newEvent(conn net.Conn, cfg ListenerConfig) Event {
+	host, port, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		host = conn.RemoteAddr().String()
+	}
+
+	sourcePort, _ := strconv.Atoi(port)
+
+	return Event{
+		Time:       time.Now(),
+		Listener:   cfg.Name,
+		Protocol:   cfg.Protocol,
+		SourceIP:   host,
+		SourcePort: sourcePort,
+		LocalAddr:  conn.LocalAddr().String(),
+	}
+}
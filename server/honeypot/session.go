@@ -0,0 +1,117 @@
+package honeypot
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// session tracks one emulated connection: reads are deadline-bound, capped at the
+// byte limit and hashed into the event.
+type session struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	hash   hash.Hash
+	event  *Event
+
+	limit       int64
+	readTimeout time.Duration
+	deadline    time.Time
+}
+
+func newSession(conn net.Conn, cfg Config, event *Event) *session {
+	s := &session{
+		conn:        conn,
+		hash:        sha256.New(),
+		event:       event,
+		limit:       cfg.MaxBytes,
+		readTimeout: cfg.ReadTimeout,
+		deadline:    time.Now().Add(cfg.SessionTimeout),
+	}
+	s.reader = bufio.NewReader(s)
+
+	return s
+}
+
+// Read implements io.Reader for the buffered reader.
+func (s *session) Read(p []byte) (int, error) {
+	remaining := s.limit - s.event.BytesRead
+	if remaining <= 0 {
+		s.event.Truncated = true
+
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	if err := s.conn.SetReadDeadline(s.nextDeadline()); err != nil {
+		return 0, err
+	}
+
+	n, err := s.conn.Read(p)
+	s.hash.Write(p[:n])
+	s.event.BytesRead += int64(n)
+
+	return n, err
+}
+
+// readLine returns the next line without its line ending.
+func (s *session) readLine() (string, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// drain reads and discards input until the client stops, for binary protocols.
+func (s *session) drain() {
+	_, _ = io.Copy(io.Discard, s.reader)
+}
+
+func (s *session) write(text string) error {
+	if err := s.conn.SetWriteDeadline(s.nextDeadline()); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(s.conn, text)
+
+	return err
+}
+
+func (s *session) credential(username, password string) {
+	s.event.Credentials = append(s.event.Credentials, Credential{Username: username, Password: password})
+}
+
+func (s *session) command(command string) {
+	if command == "" || len(s.event.Commands) >= MaxCommands {
+		return
+	}
+
+	if len(command) > maxCommandLength {
+		command = command[:maxCommandLength]
+	}
+
+	s.event.Commands = append(s.event.Commands, command)
+}
+
+func (s *session) finish() {
+	s.event.PayloadHash = hex.EncodeToString(s.hash.Sum(nil))
+}
+
+func (s *session) nextDeadline() time.Time {
+	deadline := time.Now().Add(s.readTimeout)
+	if deadline.After(s.deadline) {
+		return s.deadline
+	}
+
+	return deadline
+}
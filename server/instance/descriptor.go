@@ -0,0 +1,79 @@
+package instance
+
+import (
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+// Environment variables read by Describe, as set by the Kubernetes downward API.
+const (
+	EnvPodName      = "POD_NAME"
+	EnvPodNamespace = "POD_NAMESPACE"
+)
+
+// Version is the build version; set it with
+// -ldflags "-X github.com/InsideGallery/core/server/instance.Version=v1.2.3".
+// Empty falls back to the main module version from the build info.
+var Version string
+
+var startedAt = time.Now().UTC()
+
+// Descriptor identifies one running instance of a service.
+type Descriptor struct {
+	ID        string            `json:"id"`
+	ShortID   string            `json:"short_id,omitempty"`
+	Service   string            `json:"service"`
+	Hostname  string            `json:"hostname,omitempty"`
+	Pod       string            `json:"pod,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	StartedAt time.Time         `json:"started_at"`
+	Ports     map[string]int    `json:"ports,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Describe returns the descriptor of the current process for service, with named
+// ports such as {"http": 8080, "grpc": 9090}.
+func Describe(service string, ports map[string]int) Descriptor {
+	hostname, _ := os.Hostname()
+
+	return Descriptor{
+		ID:        GetInstanceID(),
+		ShortID:   GetShortInstanceID(),
+		Service:   service,
+		Hostname:  hostname,
+		Pod:       os.Getenv(EnvPodName),
+		Namespace: os.Getenv(EnvPodNamespace),
+		Version:   BuildVersion(),
+		StartedAt: startedAt,
+		Ports:     ports,
+	}
+}
+
+// Name returns the pod name, or the hostname outside Kubernetes.
+func (d Descriptor) Name() string {
+	if d.Pod != "" {
+		return d.Pod
+	}
+
+	return d.Hostname
+}
+
+// BuildVersion returns Version or, when empty, the main module version.
+func BuildVersion() string {
+	if Version != "" {
+		return Version
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Version
+	}
+
+	return ""
+}
+
+// StartedAt returns when the process loaded this package.
+func StartedAt() time.Time {
+	return startedAt
+}
@@ -0,0 +1,238 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dgryski/go-farm"
+)
+
+// Membership defaults.
+const (
+	DefaultMembershipTTL = 15 * time.Second
+	heartbeatsPerTTL     = 3
+)
+
+// Membership errors.
+var (
+	ErrEmptyService   = errors.New("membership service is empty")
+	ErrAlreadyStarted = errors.New("membership is already started")
+)
+
+// Change reports the members that joined or left since the previous refresh and
+// the full live member list.
+type Change struct {
+	Joined  []Descriptor
+	Left    []Descriptor
+	Members []Descriptor
+}
+
+// MembershipConfig configures a Membership.
+type MembershipConfig struct {
+	// Store shares membership between replicas; nil uses a MemoryStore.
+	Store Store
+	// Self is announced on every heartbeat; its Service groups the replicas.
+	Self Descriptor
+	// TTL is how long a member stays alive without heartbeats.
+	TTL time.Duration
+	// Interval between heartbeats and refreshes; defaults to a third of TTL.
+	Interval time.Duration
+	// OnChange is called from the heartbeat goroutine when the member list changes.
+	OnChange func(change Change)
+}
+
+// Membership announces the current instance and tracks its live peers.
+type Membership struct {
+	cfg MembershipConfig
+
+	mu      sync.RWMutex
+	members []Descriptor
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewMembership applies defaults to cfg.
+func NewMembership(cfg MembershipConfig) (*Membership, error) {
+	if cfg.Self.Service == "" {
+		return nil, ErrEmptyService
+	}
+
+	if cfg.Self.ID == "" {
+		cfg.Self.ID = GetInstanceID()
+	}
+
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore()
+	}
+
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultMembershipTTL
+	}
+
+	if cfg.Interval <= 0 || cfg.Interval >= cfg.TTL {
+		cfg.Interval = cfg.TTL / heartbeatsPerTTL
+	}
+
+	return &Membership{cfg: cfg}, nil
+}
+
+// Start sends the first heartbeat, loads the members and keeps both fresh in the
+// background until ctx is done or Stop is called. When the first heartbeat fails,
+// Start returns its error and may be called again.
+func (m *Membership) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.done != nil {
+		m.mu.Unlock()
+
+		return ErrAlreadyStarted
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.done = make(chan struct{})
+	m.mu.Unlock()
+
+	if err := m.tick(ctx); err != nil {
+		cancel()
+
+		m.mu.Lock()
+		close(m.done)
+		m.cancel, m.done = nil, nil
+		m.mu.Unlock()
+
+		return err
+	}
+
+	go m.run(ctx)
+
+	return nil
+}
+
+// Stop ends the heartbeats and removes the instance from the store so peers see it
+// leave at once instead of after the TTL.
+func (m *Membership) Stop(ctx context.Context) error {
+	m.mu.RLock()
+	cancel, done := m.cancel, m.done
+	m.mu.RUnlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	<-done
+
+	return m.cfg.Store.Leave(ctx, m.cfg.Self.Service, m.cfg.Self.ID)
+}
+
+// Self returns the announced descriptor.
+func (m *Membership) Self() Descriptor {
+	return m.cfg.Self
+}
+
+// Peers returns the live members, including this instance, sorted by id.
+func (m *Membership) Peers() []Descriptor {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]Descriptor(nil), m.members...)
+}
+
+// Owner returns the member responsible for key using rendezvous hashing, so only
+// about 1/n of the keys move when a member joins or leaves. It returns false when
+// there are no members.
+func (m *Membership) Owner(key string) (Descriptor, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		owner Descriptor
+		best  uint64
+	)
+
+	for i, member := range m.members {
+		score := farm.Hash64([]byte(member.ID + "\x00" + key))
+		if i == 0 || score > best {
+			owner, best = member, score
+		}
+	}
+
+	return owner, len(m.members) > 0
+}
+
+// Owns reports whether this instance owns key.
+func (m *Membership) Owns(key string) bool {
+	owner, ok := m.Owner(key)
+
+	return ok && owner.ID == m.cfg.Self.ID
+}
+
+func (m *Membership) run(ctx context.Context) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.tick(ctx); err != nil && ctx.Err() == nil {
+				slog.Default().Error("Error refreshing instance membership", "service", m.cfg.Self.Service, "err", err)
+			}
+		}
+	}
+}
+
+func (m *Membership) tick(ctx context.Context) error {
+	if err := m.cfg.Store.Heartbeat(ctx, m.cfg.Self, m.cfg.TTL); err != nil {
+		return err
+	}
+
+	members, err := m.cfg.Store.Members(ctx, m.cfg.Self.Service)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	change := diffMembers(m.members, members)
+	m.members = members
+	m.mu.Unlock()
+
+	if m.cfg.OnChange != nil && (len(change.Joined) > 0 || len(change.Left) > 0) {
+		m.cfg.OnChange(change)
+	}
+
+	return nil
+}
+
+func diffMembers(previous, current []Descriptor) Change {
+	change := Change{Members: append([]Descriptor(nil), current...)}
+
+	known := make(map[string]struct{}, len(previous))
+	for _, member := range previous {
+		known[member.ID] = struct{}{}
+	}
+
+	live := make(map[string]struct{}, len(current))
+
+	for _, member := range current {
+		live[member.ID] = struct{}{}
+
+		if _, ok := known[member.ID]; !ok {
+			change.Joined = append(change.Joined, member)
+		}
+	}
+
+	for _, member := range previous {
+		if _, ok := live[member.ID]; !ok {
+			change.Left = append(change.Left, member)
+		}
+	}
+
+	return change
+}
@@ -0,0 +1,212 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+)
+
+const testTTL = 60 * time.Millisecond
+
+type changeRecorder struct {
+	mu      sync.Mutex
+	changes []Change
+}
+
+func (r *changeRecorder) record(change Change) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.changes = append(r.changes, change)
+}
+
+func (r *changeRecorder) last() Change {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.changes) == 0 {
+		return Change{}
+	}
+
+	return r.changes[len(r.changes)-1]
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func ids(members []Descriptor) []string {
+	result := make([]string, 0, len(members))
+	for _, member := range members {
+		result = append(result, member.ID)
+	}
+
+	return result
+}
+
+func TestDescribe(t *testing.T) {
+	t.Setenv(EnvPodName, "api-7d9f")
+	t.Setenv(EnvPodNamespace, "prod")
+
+	previous := Version
+	Version = "v1.2.3"
+
+	t.Cleanup(func() { Version = previous })
+
+	descriptor := Describe("api", map[string]int{"http": 8080})
+
+	testutils.Equal(t, descriptor.ID, GetInstanceID())
+	testutils.Equal(t, descriptor.ShortID, GetShortInstanceID())
+	testutils.Equal(t, descriptor.Service, "api")
+	testutils.Equal(t, descriptor.Pod, "api-7d9f")
+	testutils.Equal(t, descriptor.Namespace, "prod")
+	testutils.Equal(t, descriptor.Name(), "api-7d9f")
+	testutils.Equal(t, descriptor.Version, "v1.2.3")
+	testutils.Equal(t, descriptor.StartedAt, StartedAt())
+	testutils.Equal(t, descriptor.Ports["http"], 8080)
+}
+
+func TestNewMembershipRequiresService(t *testing.T) {
+	_, err := NewMembership(MembershipConfig{})
+	testutils.Equal(t, errors.Is(err, ErrEmptyService), true)
+}
+
+func TestMembership(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	recorder := &changeRecorder{}
+
+	first, err := NewMembership(MembershipConfig{
+		Store:    store,
+		Self:     Descriptor{ID: "a", Service: "api"},
+		TTL:      testTTL,
+		OnChange: recorder.record,
+	})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, first.Start(ctx), nil)
+	testutils.Equal(t, errors.Is(first.Start(ctx), ErrAlreadyStarted), true)
+
+	t.Cleanup(func() { _ = first.Stop(ctx) })
+
+	testutils.Equal(t, ids(first.Peers()), []string{"a"})
+
+	second, err := NewMembership(MembershipConfig{Store: store, Self: Descriptor{ID: "b", Service: "api"}, TTL: testTTL})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, second.Start(ctx), nil)
+
+	waitFor(t, func() bool { return len(first.Peers()) == 2 })
+	testutils.Equal(t, ids(recorder.last().Joined), []string{"b"})
+	testutils.Equal(t, ids(recorder.last().Members), []string{"a", "b"})
+
+	testutils.Equal(t, second.Stop(ctx), nil)
+
+	waitFor(t, func() bool { return len(first.Peers()) == 1 })
+	testutils.Equal(t, ids(recorder.last().Left), []string{"b"})
+
+	crashCtx, crash := context.WithCancel(ctx)
+	third, err := NewMembership(MembershipConfig{Store: store, Self: Descriptor{ID: "c", Service: "api"}, TTL: testTTL})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, third.Start(crashCtx), nil)
+
+	waitFor(t, func() bool { return len(first.Peers()) == 2 })
+
+	// Without Stop the member only disappears once its TTL expires.
+	crash()
+	waitFor(t, func() bool { return len(first.Peers()) == 1 })
+	testutils.Equal(t, ids(recorder.last().Left), []string{"c"})
+}
+
+// flakyStore fails heartbeats while down is set.
+type flakyStore struct {
+	*MemoryStore
+	down bool
+}
+
+func (s *flakyStore) Heartbeat(ctx context.Context, member Descriptor, ttl time.Duration) error {
+	if s.down {
+		return errStoreDown
+	}
+
+	return s.MemoryStore.Heartbeat(ctx, member, ttl)
+}
+
+var errStoreDown = errors.New("store is down")
+
+func TestMembershipRestartsAfterFailedStart(t *testing.T) {
+	ctx := context.Background()
+	store := &flakyStore{MemoryStore: NewMemoryStore(), down: true}
+
+	membership, err := NewMembership(MembershipConfig{Store: store, Self: Descriptor{ID: "a", Service: "api"}, TTL: testTTL})
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, errors.Is(membership.Start(ctx), errStoreDown), true)
+	testutils.Equal(t, membership.Stop(ctx), nil)
+
+	store.down = false
+
+	testutils.Equal(t, membership.Start(ctx), nil)
+	t.Cleanup(func() { _ = membership.Stop(ctx) })
+
+	testutils.Equal(t, ids(membership.Peers()), []string{"a"})
+}
+
+func TestMembershipOwner(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	memberships := make([]*Membership, 0, 3)
+
+	for _, id := range []string{"a", "b", "c"} {
+		membership, err := NewMembership(MembershipConfig{Store: store, Self: Descriptor{ID: id, Service: "jobs"}, TTL: time.Minute})
+		testutils.Equal(t, err, nil)
+		testutils.Equal(t, membership.Start(ctx), nil)
+
+		t.Cleanup(func() { _ = membership.Stop(ctx) })
+
+		memberships = append(memberships, membership)
+	}
+
+	// The last member to start sees every peer; refresh the others explicitly.
+	for _, membership := range memberships {
+		testutils.Equal(t, membership.tick(ctx), nil)
+	}
+
+	owned := make(map[string]int)
+
+	for i := range 300 {
+		key := "job-" + strconv.Itoa(i)
+		owners := 0
+
+		for _, membership := range memberships {
+			if membership.Owns(key) {
+				owners++
+				owned[membership.Self().ID]++
+			}
+		}
+
+		testutils.Equal(t, owners, 1)
+	}
+
+	for id, count := range owned {
+		if count < 50 {
+			t.Fatalf("member %s owns %d of 300 keys, want a fair share", id, count)
+		}
+	}
+
+	_, ok := (&Membership{}).Owner("job")
+	testutils.Equal(t, ok, false)
+}
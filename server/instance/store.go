@@ -0,0 +1,214 @@
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisPrefix prefixes the Redis keys of RedisStore.
+const DefaultRedisPrefix = "instance:members:"
+
+// heartbeatScript stores a member and extends the keys of its service to outlive it.
+var heartbeatScript = redis.NewScript(`
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[3])
+for _, key in ipairs(KEYS) do
+	if redis.call('PTTL', key) < tonumber(ARGV[4]) then
+		redis.call('PEXPIRE', key, ARGV[4])
+	end
+end
+return 1
+`)
+
+// pruneScript removes the members expired at ARGV[1] with their descriptors, so a
+// concurrent heartbeat cannot lose its descriptor.
+var pruneScript = redis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+for i = 1, #expired, 1000 do
+	local chunk = {unpack(expired, i, math.min(i + 999, #expired))}
+	redis.call('ZREM', KEYS[1], unpack(chunk))
+	redis.call('HDEL', KEYS[2], unpack(chunk))
+end
+return #expired
+`)
+
+// Store keeps members alive for a TTL after each heartbeat.
+type Store interface {
+	Heartbeat(ctx context.Context, member Descriptor, ttl time.Duration) error
+	Leave(ctx context.Context, service string, id string) error
+	// Members returns the live members of service sorted by id.
+	Members(ctx context.Context, service string) ([]Descriptor, error)
+}
+
+type memoryMember struct {
+	descriptor Descriptor
+	expiresAt  time.Time
+}
+
+// MemoryStore is an in-process Store for single-node deployments and tests.
+type MemoryStore struct {
+	mu       sync.Mutex
+	services map[string]map[string]memoryMember
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{services: make(map[string]map[string]memoryMember)}
+}
+
+// Heartbeat stores member until ttl elapses.
+func (s *MemoryStore) Heartbeat(_ context.Context, member Descriptor, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members, ok := s.services[member.Service]
+	if !ok {
+		members = make(map[string]memoryMember)
+		s.services[member.Service] = members
+	}
+
+	members[member.ID] = memoryMember{descriptor: member, expiresAt: time.Now().Add(ttl)}
+
+	return nil
+}
+
+// Leave removes a member.
+func (s *MemoryStore) Leave(_ context.Context, service string, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.services[service], id)
+
+	return nil
+}
+
+// Members returns the live members and forgets expired ones.
+func (s *MemoryStore) Members(_ context.Context, service string) ([]Descriptor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	members := make([]Descriptor, 0, len(s.services[service]))
+
+	for id, member := range s.services[service] {
+		if !member.expiresAt.After(now) {
+			delete(s.services[service], id)
+
+			continue
+		}
+
+		members = append(members, member.descriptor)
+	}
+
+	sortMembers(members)
+
+	return members, nil
+}
+
+// RedisStore shares membership through Redis: a sorted set scored by expiry time
+// and a hash of descriptors per service. Both keys expire once the last heartbeat
+// TTL elapses. A db/redis Connection can be passed as its embedded Client.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore returns a store using keys under prefix; empty uses DefaultRedisPrefix.
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	if prefix == "" {
+		prefix = DefaultRedisPrefix
+	}
+
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Heartbeat stores member until ttl elapses.
+func (s *RedisStore) Heartbeat(ctx context.Context, member Descriptor, ttl time.Duration) error {
+	payload, err := json.Marshal(member)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(ttl).UnixMilli()
+	index, descriptors := s.keys(member.Service)
+
+	return heartbeatScript.Run(
+		ctx, s.client, []string{index, descriptors}, member.ID, expiresAt, payload, max(ttl.Milliseconds(), 1),
+	).Err()
+}
+
+// Leave removes a member.
+func (s *RedisStore) Leave(ctx context.Context, service string, id string) error {
+	index, descriptors := s.keys(service)
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, index, id)
+		pipe.HDel(ctx, descriptors, id)
+
+		return nil
+	})
+
+	return err
+}
+
+// Members returns the live members and removes expired ones.
+func (s *RedisStore) Members(ctx context.Context, service string) ([]Descriptor, error) {
+	index, descriptors := s.keys(service)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	if err := pruneScript.Run(ctx, s.client, []string{index, descriptors}, now).Err(); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.client.ZRangeByScore(ctx, index, &redis.ZRangeBy{Min: "(" + now, Max: "+inf"}).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	payloads, err := s.client.HMGet(ctx, descriptors, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]Descriptor, 0, len(payloads))
+
+	for _, payload := range payloads {
+		raw, ok := payload.(string)
+		if !ok {
+			continue
+		}
+
+		var member Descriptor
+		if err := json.Unmarshal([]byte(raw), &member); err != nil {
+			slog.Default().Warn("Error decoding instance member", "service", service, "err", err)
+
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	sortMembers(members)
+
+	return members, nil
+}
+
+// keys share a hash tag so the scripts and transactions work on Redis Cluster.
+func (s *RedisStore) keys(service string) (string, string) {
+	index := s.prefix + "{" + service + "}"
+
+	return index, index + ":descriptors"
+}
+
+func sortMembers(members []Descriptor) {
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+}
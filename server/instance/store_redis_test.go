@@ -0,0 +1,45 @@
+//go:build local_test
+// +build local_test
+
+package instance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	guuid "github.com/google/uuid"
+
+	coreredis "github.com/InsideGallery/core/db/redis"
+)
+
+func TestRedisStore(t *testing.T) {
+	config, err := coreredis.GetConnectionConfigFromEnv()
+	testutils.Equal(t, err, nil)
+
+	conn, err := coreredis.NewConnection(config)
+	testutils.Equal(t, err, nil)
+	t.Cleanup(func() { _ = conn.Stop() })
+
+	ctx := context.Background()
+	store := NewRedisStore(conn.UniversalClient, "test:instance:")
+	service := guuid.NewString()
+
+	testutils.Equal(t, store.Heartbeat(ctx, Descriptor{ID: "b", Service: service}, time.Minute), nil)
+	testutils.Equal(t, store.Heartbeat(ctx, Descriptor{ID: "a", Service: service}, time.Minute), nil)
+	testutils.Equal(t, store.Heartbeat(ctx, Descriptor{ID: "c", Service: service}, 100*time.Millisecond), nil)
+
+	members, err := store.Members(ctx, service)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ids(members), []string{"a", "b", "c"})
+
+	time.Sleep(200 * time.Millisecond)
+	testutils.Equal(t, store.Leave(ctx, service, "b"), nil)
+
+	members, err = store.Members(ctx, service)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ids(members), []string{"a"})
+
+	testutils.Equal(t, store.Leave(ctx, service, "a"), nil)
+}
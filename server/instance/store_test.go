@@ -0,0 +1,45 @@
+package instance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+
+	"github.com/InsideGallery/core/db/redis/redistest"
+)
+
+func TestRedisStoreExpiry(t *testing.T) {
+	client, server := redistest.NewClient(t)
+
+	ctx := context.Background()
+	store := NewRedisStore(client, "")
+	index, descriptors := store.keys("api")
+
+	testutils.Equal(t, store.Heartbeat(ctx, Descriptor{ID: "a", Service: "api"}, time.Minute), nil)
+	testutils.Equal(t, store.Heartbeat(ctx, Descriptor{ID: "b", Service: "api"}, time.Millisecond), nil)
+
+	testutils.Equal(t, server.TTL(index), time.Minute)
+	testutils.Equal(t, server.TTL(descriptors), time.Minute)
+
+	time.Sleep(5 * time.Millisecond)
+
+	members, err := store.Members(ctx, "api")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ids(members), []string{"a"})
+
+	keys, err := server.HKeys(descriptors)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, keys, []string{"a"})
+
+	testutils.Equal(t, store.Heartbeat(ctx, Descriptor{ID: "b", Service: "api"}, 2*time.Minute), nil)
+	testutils.Equal(t, server.TTL(index), 2*time.Minute)
+
+	testutils.Equal(t, store.Heartbeat(ctx, Descriptor{ID: "a", Service: "api"}, time.Second), nil)
+	testutils.Equal(t, server.TTL(descriptors), 2*time.Minute)
+
+	server.FastForward(3 * time.Minute)
+	testutils.Equal(t, server.Exists(index), false)
+	testutils.Equal(t, server.Exists(descriptors), false)
+}
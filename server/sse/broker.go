@@ -0,0 +1,156 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultBrokerChannel is the Redis channel or NATS subject used by the pub-sub brokers.
+const DefaultBrokerChannel = "sse.events"
+
+// EventHandler receives events delivered by a broker.
+type EventHandler func(event Event)
+
+// Broker fans events out to every hub replica, including the publishing one.
+type Broker interface {
+	Publish(ctx context.Context, event Event) error
+	// Subscribe delivers every published event to handler until unsubscribe is called.
+	Subscribe(ctx context.Context, handler EventHandler) (unsubscribe func() error, err error)
+}
+
+// MemoryBroker delivers events to subscribers in the same process.
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers map[int]EventHandler
+	next     int
+}
+
+// NewMemoryBroker returns an in-process broker for single-node deployments and tests.
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: make(map[int]EventHandler)}
+}
+
+// Publish delivers event to every subscriber synchronously.
+func (b *MemoryBroker) Publish(_ context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+
+	return nil
+}
+
+// Subscribe registers handler.
+func (b *MemoryBroker) Subscribe(_ context.Context, handler EventHandler) (func() error, error) {
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	return func() error {
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+
+		return nil
+	}, nil
+}
+
+// RedisBroker fans events out through Redis pub-sub.
+type RedisBroker struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisBroker returns a broker publishing to channel; empty uses DefaultBrokerChannel.
+func NewRedisBroker(client redis.UniversalClient, channel string) *RedisBroker {
+	if channel == "" {
+		channel = DefaultBrokerChannel
+	}
+
+	return &RedisBroker{client: client, channel: channel}
+}
+
+// Publish sends event to the Redis channel.
+func (b *RedisBroker) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+// Subscribe listens on the Redis channel until unsubscribe is called.
+func (b *RedisBroker) Subscribe(ctx context.Context, handler EventHandler) (func() error, error) {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+
+		return nil, err
+	}
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			deliver(handler, []byte(msg.Payload))
+		}
+	}()
+
+	return pubsub.Close, nil
+}
+
+// NATSBroker fans events out through a NATS subject.
+type NATSBroker struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATSBroker returns a broker publishing to subject; empty uses DefaultBrokerChannel.
+func NewNATSBroker(conn *nats.Conn, subject string) *NATSBroker {
+	if subject == "" {
+		subject = DefaultBrokerChannel
+	}
+
+	return &NATSBroker{conn: conn, subject: subject}
+}
+
+// Publish sends event to the NATS subject.
+func (b *NATSBroker) Publish(_ context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return b.conn.Publish(b.subject, payload)
+}
+
+// Subscribe listens on the NATS subject until unsubscribe is called.
+func (b *NATSBroker) Subscribe(_ context.Context, handler EventHandler) (func() error, error) {
+	subscription, err := b.conn.Subscribe(b.subject, func(msg *nats.Msg) {
+		deliver(handler, msg.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription.Unsubscribe, nil
+}
+
+func deliver(handler EventHandler, payload []byte) {
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		slog.Default().Error("Error decoding sse event", "err", err)
+
+		return
+	}
+
+	handler(event)
+}
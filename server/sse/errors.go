@@ -7,4 +7,6 @@ var (
 	ErrInvalidUserID              = errors.New("invalid user id")
 	ErrNotFoundConnectedUser      = errors.New("not found connected user")
 	ErrResponseWriterIsNotFlusher = errors.New("response does not support flush")
+	ErrHubClosed                  = errors.New("hub is closed")
+	ErrReservedTopic              = errors.New("user topics cannot be subscribed explicitly")
 )
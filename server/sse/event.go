@@ -0,0 +1,70 @@
+package sse
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a Server-Sent Event delivered to the connections subscribed to Topic.
+type Event struct {
+	ID    string        `json:"id,omitempty"`
+	Topic string        `json:"topic"`
+	Event string        `json:"event,omitempty"`
+	Data  []string      `json:"data,omitempty"`
+	Retry time.Duration `json:"retry,omitempty"`
+}
+
+// NewEvent returns an event for topic; data lines containing newlines are split.
+func NewEvent(topic string, event string, data ...string) Event {
+	return Event{
+		Topic: topic,
+		Event: event,
+		Data:  data,
+	}
+}
+
+// lineBreaks strips the line breaks that would end an id, event or comment field
+// and start another one.
+var lineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+// dataLines splits data on the \r\n, \r and \n line breaks of the event stream format.
+var dataLines = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// Format renders the event in the text/event-stream format. Line breaks are removed
+// from ID and Event and split Data into several data lines.
+func (e Event) Format() []byte {
+	var builder strings.Builder
+
+	if id := lineBreaks.Replace(e.ID); id != "" {
+		builder.WriteString("id: " + id + "\n")
+	}
+
+	if event := lineBreaks.Replace(e.Event); event != "" {
+		builder.WriteString("event: " + event + "\n")
+	}
+
+	if e.Retry > 0 {
+		builder.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	for _, data := range e.Data {
+		for _, line := range strings.Split(dataLines.Replace(data), "\n") {
+			builder.WriteString("data: " + line + "\n")
+		}
+	}
+
+	builder.WriteString("\n")
+
+	return []byte(builder.String())
+}
+
+// FormatComment renders a comment line, used for keep-alives.
+func FormatComment(text string) []byte {
+	return []byte(": " + lineBreaks.Replace(text) + "\n\n")
+}
+
+// FormatRetry renders a standalone reconnection delay hint.
+func FormatRetry(retry time.Duration) []byte {
+	return []byte("retry: " + strconv.FormatInt(retry.Milliseconds(), 10) + "\n\n")
+}
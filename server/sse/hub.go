@@ -0,0 +1,461 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FrogoAI/set"
+	"github.com/gofiber/fiber/v3"
+)
+
+// Hub defaults.
+const (
+	DefaultConnectionBuffer = 64
+	DefaultReplaySize       = 1000
+	DefaultRetry            = 3 * time.Second
+	DefaultKeepAlive        = 15 * time.Second
+
+	// BroadcastTopic is delivered to every connection.
+	BroadcastTopic = "broadcast"
+	// HeaderLastEventID is sent by EventSource when it reconnects.
+	HeaderLastEventID = "Last-Event-ID"
+
+	userTopicPrefix = "user:"
+	nodeIDSize      = 4
+)
+
+// HubConfig configures a Hub.
+type HubConfig struct {
+	// Broker fans events out across replicas; nil uses a MemoryBroker.
+	Broker Broker
+	// NodeID prefixes generated event ids so they are unique across replicas; empty uses a random id.
+	NodeID string
+	// BufferSize is the per-connection queue; a connection that falls this far behind is evicted.
+	BufferSize int
+	// ReplaySize is the number of recent events kept for Last-Event-ID replay.
+	ReplaySize int
+	// Retry is the reconnection delay hint sent when a stream opens; negative disables it.
+	Retry time.Duration
+	// KeepAlive is the comment interval that keeps idle streams open through proxies.
+	KeepAlive time.Duration
+	// UserID resolves the user of a Fiber request; nil reads the ContextUserID local.
+	UserID func(c fiber.Ctx) (string, error)
+	// Topics resolves extra topics of a Fiber request; nil reads comma-separated topic query values.
+	Topics func(c fiber.Ctx) ([]string, error)
+}
+
+// Connection is one subscribed stream. A user may hold several connections.
+type Connection struct {
+	ID     string
+	UserID string
+
+	topics map[string]struct{}
+	replay []Event
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Events returns live events for the connection.
+func (c *Connection) Events() <-chan Event {
+	return c.events
+}
+
+// Replay returns the events missed since Last-Event-ID, to be sent before live events.
+func (c *Connection) Replay() []Event {
+	return c.replay
+}
+
+// Done is closed when the connection is removed, evicted or the hub closes.
+func (c *Connection) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Connection) subscribed(topic string) bool {
+	_, ok := c.topics[topic]
+
+	return ok
+}
+
+func (c *Connection) stop() {
+	c.once.Do(func() {
+		close(c.done)
+	})
+}
+
+// Hub delivers events to SSE connections by user and topic and replays missed
+// events to reconnecting clients. Events go through the Broker, so every
+// replica sharing it delivers them to its own connections.
+type Hub struct {
+	cfg         HubConfig
+	broker      Broker
+	unsubscribe func() error
+
+	mu          sync.RWMutex
+	connections map[string]*Connection
+	replay      []Event
+	closed      bool
+
+	sequence      atomic.Uint64
+	connectionSeq atomic.Uint64
+	evicted       atomic.Uint64
+}
+
+// NewHub subscribes a hub to its broker.
+func NewHub(ctx context.Context, cfg HubConfig) (*Hub, error) {
+	if cfg.Broker == nil {
+		cfg.Broker = NewMemoryBroker()
+	}
+
+	if cfg.NodeID == "" {
+		cfg.NodeID = randomID()
+	}
+
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultConnectionBuffer
+	}
+
+	if cfg.ReplaySize <= 0 {
+		cfg.ReplaySize = DefaultReplaySize
+	}
+
+	if cfg.Retry == 0 {
+		cfg.Retry = DefaultRetry
+	}
+
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = DefaultKeepAlive
+	}
+
+	hub := &Hub{
+		cfg:         cfg,
+		broker:      cfg.Broker,
+		connections: make(map[string]*Connection),
+	}
+
+	unsubscribe, err := cfg.Broker.Subscribe(ctx, hub.dispatch)
+	if err != nil {
+		return nil, err
+	}
+
+	hub.unsubscribe = unsubscribe
+
+	return hub, nil
+}
+
+// UserTopic returns the topic every connection of userID is subscribed to.
+func UserTopic(userID string) string {
+	return userTopicPrefix + userID
+}
+
+// Publish assigns an id when missing and publishes event through the broker.
+func (h *Hub) Publish(ctx context.Context, event Event) error {
+	if h.isClosed() {
+		return ErrHubClosed
+	}
+
+	if event.ID == "" {
+		event.ID = h.cfg.NodeID + "-" + strconv.FormatUint(h.sequence.Add(1), 10)
+	}
+
+	return h.broker.Publish(ctx, event)
+}
+
+// SendToUser publishes event to every connection of userID on any replica.
+func (h *Hub) SendToUser(ctx context.Context, userID string, event Event) error {
+	event.Topic = UserTopic(userID)
+
+	return h.Publish(ctx, event)
+}
+
+// Broadcast publishes event to every connection on any replica.
+func (h *Hub) Broadcast(ctx context.Context, event Event) error {
+	event.Topic = BroadcastTopic
+
+	return h.Publish(ctx, event)
+}
+
+// Subscribe registers a connection for userID, its user topic, the broadcast topic
+// and topics. Events after lastEventID that are still buffered are returned by
+// Connection.Replay; an unknown id replays nothing.
+func (h *Hub) Subscribe(userID string, topics []string, lastEventID string) (*Connection, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	conn := &Connection{
+		ID:     strconv.FormatUint(h.connectionSeq.Add(1), 10),
+		UserID: userID,
+		topics: map[string]struct{}{UserTopic(userID): {}, BroadcastTopic: {}},
+		events: make(chan Event, h.cfg.BufferSize),
+		done:   make(chan struct{}),
+	}
+
+	for _, topic := range topics {
+		if strings.HasPrefix(topic, userTopicPrefix) {
+			return nil, ErrReservedTopic
+		}
+
+		conn.topics[topic] = struct{}{}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+
+	conn.replay = h.missed(conn, lastEventID)
+	h.connections[conn.ID] = conn
+
+	return conn, nil
+}
+
+// Unsubscribe removes conn from the hub.
+func (h *Hub) Unsubscribe(conn *Connection) {
+	h.mu.Lock()
+	delete(h.connections, conn.ID)
+	h.mu.Unlock()
+
+	conn.stop()
+}
+
+// Connections returns the number of local connections.
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.connections)
+}
+
+// ConnectedUsers returns the users with at least one local connection.
+func (h *Hub) ConnectedUsers() set.GenericDataSet[string] {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	users := set.NewGenericDataSet[string]()
+	for _, conn := range h.connections {
+		users.Add(conn.UserID)
+	}
+
+	return users
+}
+
+// Evicted returns how many slow connections were dropped.
+func (h *Hub) Evicted() uint64 {
+	return h.evicted.Load()
+}
+
+// Close unsubscribes from the broker and ends every local connection.
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+
+		return nil
+	}
+
+	h.closed = true
+	connections := h.connections
+	h.connections = make(map[string]*Connection)
+	h.mu.Unlock()
+
+	for _, conn := range connections {
+		conn.stop()
+	}
+
+	return h.unsubscribe()
+}
+
+// Handler returns a Fiber handler that streams events to the requesting user.
+//
+// Extra topics come from HubConfig.Topics and missed events are replayed from
+// the Last-Event-ID header or lastEventId query value.
+func (h *Hub) Handler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		userID, err := h.userID(c)
+		if err != nil || userID == "" {
+			return fiber.ErrUnauthorized
+		}
+
+		topics, err := h.topics(c)
+		if err != nil {
+			return err
+		}
+
+		lastEventID := c.Get(HeaderLastEventID, c.Query("lastEventId"))
+
+		conn, err := h.Subscribe(userID, topics, lastEventID)
+		if err != nil {
+			if errors.Is(err, ErrReservedTopic) {
+				return fiber.NewError(fiber.StatusForbidden, err.Error())
+			}
+
+			return err
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+
+		return c.SendStreamWriter(func(w *bufio.Writer) {
+			defer h.Unsubscribe(conn)
+
+			if err := h.Stream(conn, w, w.Flush); err != nil {
+				slog.Default().Debug("sse stream closed", "user", conn.UserID, "err", err)
+			}
+		})
+	}
+}
+
+// Stream writes the retry hint, replayed events and live events of conn to w,
+// flushing after each write, until the connection is done or a write fails.
+func (h *Hub) Stream(conn *Connection, w io.Writer, flush func() error) error {
+	write := func(payload []byte) error {
+		if _, err := w.Write(payload); err != nil {
+			return err
+		}
+
+		return flush()
+	}
+
+	if h.cfg.Retry > 0 {
+		if err := write(FormatRetry(h.cfg.Retry)); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range conn.Replay() {
+		if err := write(event.Format()); err != nil {
+			return err
+		}
+	}
+
+	keepAlive := time.NewTicker(h.cfg.KeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event := <-conn.events:
+			if err := write(event.Format()); err != nil {
+				return err
+			}
+		case <-keepAlive.C:
+			if err := write(FormatComment("keep-alive")); err != nil {
+				return err
+			}
+		case <-conn.done:
+			return nil
+		}
+	}
+}
+
+func (h *Hub) dispatch(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.replay = append(h.replay, event)
+	if overflow := len(h.replay) - h.cfg.ReplaySize; overflow > 0 {
+		h.replay = append(h.replay[:0:0], h.replay[overflow:]...)
+	}
+
+	for id, conn := range h.connections {
+		if !conn.subscribed(event.Topic) {
+			continue
+		}
+
+		select {
+		case conn.events <- event:
+		default:
+			delete(h.connections, id)
+			conn.stop()
+			h.evicted.Add(1)
+			slog.Default().Warn("sse slow consumer evicted", "user", conn.UserID, "connection", conn.ID)
+		}
+	}
+}
+
+func (h *Hub) missed(conn *Connection, lastEventID string) []Event {
+	if lastEventID == "" {
+		return nil
+	}
+
+	for i := len(h.replay) - 1; i >= 0; i-- {
+		if h.replay[i].ID != lastEventID {
+			continue
+		}
+
+		var missed []Event
+
+		for _, event := range h.replay[i+1:] {
+			if conn.subscribed(event.Topic) {
+				missed = append(missed, event)
+			}
+		}
+
+		return missed
+	}
+
+	return nil
+}
+
+func (h *Hub) isClosed() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.closed
+}
+
+func (h *Hub) userID(c fiber.Ctx) (string, error) {
+	if h.cfg.UserID != nil {
+		return h.cfg.UserID(c)
+	}
+
+	userID, ok := c.Locals(ContextUserID).(string)
+	if !ok {
+		return "", ErrInvalidUserID
+	}
+
+	return userID, nil
+}
+
+func (h *Hub) topics(c fiber.Ctx) ([]string, error) {
+	if h.cfg.Topics != nil {
+		return h.cfg.Topics(c)
+	}
+
+	var topics []string
+
+	for _, value := range c.Request().URI().QueryArgs().PeekMulti("topic") {
+		for _, topic := range strings.Split(string(value), ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				topics = append(topics, topic)
+			}
+		}
+	}
+
+	return topics, nil
+}
+
+func randomID() string {
+	raw := make([]byte, nodeIDSize)
+	_, _ = rand.Read(raw)
+
+	return hex.EncodeToString(raw)
+}
@@ -0,0 +1,337 @@
+package sse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	"github.com/gofiber/fiber/v3"
+)
+
+func newTestHub(t *testing.T, cfg HubConfig) *Hub {
+	t.Helper()
+
+	hub, err := NewHub(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("new hub: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = hub.Close()
+	})
+
+	return hub
+}
+
+func receive(t *testing.T, conn *Connection) Event {
+	t.Helper()
+
+	select {
+	case event := <-conn.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("event was not delivered")
+	}
+
+	return Event{}
+}
+
+func assertEmpty(t *testing.T, conn *Connection) {
+	t.Helper()
+
+	select {
+	case event := <-conn.Events():
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestEventFormat(t *testing.T) {
+	event := Event{ID: "n-1", Event: "update", Data: []string{"a\nb", "c"}, Retry: 2 * time.Second}
+
+	testutils.Equal(t, string(event.Format()), "id: n-1\nevent: update\nretry: 2000\ndata: a\ndata: b\ndata: c\n\n")
+	testutils.Equal(t, string(FormatComment("ping")), ": ping\n\n")
+}
+
+func TestEventFormatLineBreaks(t *testing.T) {
+	event := Event{ID: "n-1\nretry: 1", Event: "update\r\ndata: x", Data: []string{"a\r\nb\rc\nd"}}
+
+	testutils.Equal(t, string(event.Format()),
+		"id: n-1retry: 1\nevent: updatedata: x\ndata: a\ndata: b\ndata: c\ndata: d\n\n")
+	testutils.Equal(t, string(FormatComment("ping\n\nevent: x")), ": pingevent: x\n\n")
+}
+
+func TestHubDelivery(t *testing.T) {
+	ctx := context.Background()
+	hub := newTestHub(t, HubConfig{NodeID: "node"})
+
+	firstTab, err := hub.Subscribe("alice", nil, "")
+	testutils.Equal(t, err, nil)
+
+	secondTab, err := hub.Subscribe("alice", []string{"orders"}, "")
+	testutils.Equal(t, err, nil)
+
+	bob, err := hub.Subscribe("bob", nil, "")
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, hub.Connections(), 3)
+	testutils.Equal(t, hub.ConnectedUsers().Count(), 2)
+
+	testutils.Equal(t, hub.SendToUser(ctx, "alice", NewEvent("", "notice", "hi")), nil)
+	testutils.Equal(t, receive(t, firstTab).ID, "node-1")
+	testutils.Equal(t, receive(t, secondTab).Event, "notice")
+	assertEmpty(t, bob)
+
+	testutils.Equal(t, hub.Publish(ctx, NewEvent("orders", "created", "42")), nil)
+	testutils.Equal(t, receive(t, secondTab).Topic, "orders")
+	assertEmpty(t, firstTab)
+	assertEmpty(t, bob)
+
+	testutils.Equal(t, hub.Broadcast(ctx, NewEvent("", "maintenance")), nil)
+
+	for _, conn := range []*Connection{firstTab, secondTab, bob} {
+		testutils.Equal(t, receive(t, conn).Topic, BroadcastTopic)
+	}
+
+	hub.Unsubscribe(firstTab)
+	testutils.Equal(t, hub.Connections(), 2)
+
+	select {
+	case <-firstTab.Done():
+	default:
+		t.Fatal("unsubscribed connection is not done")
+	}
+}
+
+func TestHubReplay(t *testing.T) {
+	ctx := context.Background()
+	hub := newTestHub(t, HubConfig{NodeID: "node", ReplaySize: 3})
+
+	for _, topic := range []string{"orders", "prices", "orders", "orders"} {
+		testutils.Equal(t, hub.Publish(ctx, NewEvent(topic, "tick")), nil)
+	}
+
+	cases := []struct {
+		name        string
+		lastEventID string
+		want        []string
+	}{
+		{name: "no last event id"},
+		{name: "replays subscribed topics after id", lastEventID: "node-2", want: []string{"node-3", "node-4"}},
+		{name: "latest id replays nothing", lastEventID: "node-4"},
+		{name: "evicted from buffer replays nothing", lastEventID: "node-1"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			conn, err := hub.Subscribe("alice", []string{"orders"}, test.lastEventID)
+			testutils.Equal(t, err, nil)
+
+			defer hub.Unsubscribe(conn)
+
+			var got []string
+			for _, event := range conn.Replay() {
+				got = append(got, event.ID)
+			}
+
+			testutils.Equal(t, got, test.want)
+		})
+	}
+}
+
+func TestHubEvictsSlowConsumer(t *testing.T) {
+	ctx := context.Background()
+	hub := newTestHub(t, HubConfig{BufferSize: 1})
+
+	slow, err := hub.Subscribe("alice", nil, "")
+	testutils.Equal(t, err, nil)
+
+	fast, err := hub.Subscribe("bob", nil, "")
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, hub.Broadcast(ctx, NewEvent("", "one")), nil)
+	receive(t, fast)
+	testutils.Equal(t, hub.Broadcast(ctx, NewEvent("", "two")), nil)
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("slow connection was not evicted")
+	}
+
+	testutils.Equal(t, hub.Evicted(), uint64(1))
+	testutils.Equal(t, hub.Connections(), 1)
+	testutils.Equal(t, receive(t, fast).Event, "two")
+}
+
+func TestHubErrors(t *testing.T) {
+	hub := newTestHub(t, HubConfig{})
+
+	cases := []struct {
+		name    string
+		userID  string
+		topics  []string
+		wantErr error
+	}{
+		{name: "missing user", wantErr: ErrInvalidUserID},
+		{name: "reserved topic", userID: "alice", topics: []string{UserTopic("bob")}, wantErr: ErrReservedTopic},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := hub.Subscribe(test.userID, test.topics, "")
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("err = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+
+	conn, err := hub.Subscribe("alice", nil, "")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, hub.Close(), nil)
+
+	<-conn.Done()
+
+	if _, err := hub.Subscribe("alice", nil, ""); !errors.Is(err, ErrHubClosed) {
+		t.Fatalf("subscribe after close err = %v", err)
+	}
+
+	if err := hub.Broadcast(context.Background(), NewEvent("", "late")); !errors.Is(err, ErrHubClosed) {
+		t.Fatalf("publish after close err = %v", err)
+	}
+}
+
+func TestHubFanOutAcrossReplicas(t *testing.T) {
+	broker := NewMemoryBroker()
+	first := newTestHub(t, HubConfig{Broker: broker, NodeID: "a"})
+	second := newTestHub(t, HubConfig{Broker: broker, NodeID: "b"})
+
+	conn, err := second.Subscribe("alice", nil, "")
+	testutils.Equal(t, err, nil)
+
+	testutils.Equal(t, first.SendToUser(context.Background(), "alice", NewEvent("", "notice")), nil)
+	testutils.Equal(t, receive(t, conn).ID, "a-1")
+
+	replayed, err := second.Subscribe("alice", nil, "")
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, len(replayed.Replay()), 0)
+}
+
+func TestHubStreamKeepAlive(t *testing.T) {
+	hub := newTestHub(t, HubConfig{KeepAlive: 5 * time.Millisecond, Retry: time.Second})
+
+	conn, err := hub.Subscribe("alice", nil, "")
+	testutils.Equal(t, err, nil)
+
+	time.AfterFunc(30*time.Millisecond, func() {
+		hub.Unsubscribe(conn)
+	})
+
+	var buffer bytes.Buffer
+
+	testutils.Equal(t, hub.Stream(conn, &buffer, func() error { return nil }), nil)
+
+	output := buffer.String()
+	if !strings.HasPrefix(output, "retry: 1000\n\n") || !strings.Contains(output, ": keep-alive\n\n") {
+		t.Fatalf("stream output = %q", output)
+	}
+}
+
+func TestHubHandler(t *testing.T) {
+	ctx := context.Background()
+	hub := newTestHub(t, HubConfig{NodeID: "node", Retry: -1})
+
+	testutils.Equal(t, hub.Publish(ctx, NewEvent("orders", "created", "1")), nil)
+	testutils.Equal(t, hub.Publish(ctx, NewEvent("orders", "created", "2")), nil)
+	testutils.Equal(t, hub.Publish(ctx, NewEvent("prices", "tick", "3")), nil)
+
+	app := fiber.New()
+	app.Get("/events", func(c fiber.Ctx) error {
+		if user := c.Query("user"); user != "" {
+			c.Locals(ContextUserID, user)
+		}
+
+		return c.Next()
+	}, hub.Handler())
+
+	cases := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "missing user", target: "/events", wantStatus: http.StatusUnauthorized},
+		{name: "reserved topic", target: "/events?user=alice&topic=user:bob", wantStatus: http.StatusForbidden},
+		{
+			name:       "replays missed events",
+			target:     "/events?user=alice&topic=orders",
+			wantStatus: http.StatusOK,
+			wantBody:   "id: node-2\nevent: created\ndata: 2\n\n",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			req.Header.Set(HeaderLastEventID, "node-1")
+
+			if test.wantStatus == http.StatusOK {
+				go func() {
+					for hub.Connections() == 0 {
+						time.Sleep(time.Millisecond)
+					}
+
+					for _, conn := range hubConnections(hub) {
+						hub.Unsubscribe(conn)
+					}
+				}()
+			}
+
+			resp, err := app.Test(req, fiber.TestConfig{Timeout: 5 * time.Second})
+			testutils.Equal(t, err, nil)
+
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			testutils.Equal(t, err, nil)
+			testutils.Equal(t, resp.StatusCode, test.wantStatus)
+
+			if test.wantBody != "" {
+				testutils.Equal(t, resp.Header.Get(fiber.HeaderContentType), "text/event-stream")
+				testutils.Equal(t, string(body), test.wantBody)
+			}
+		})
+	}
+}
+
+func hubConnections(hub *Hub) []*Connection {
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+
+	connections := make([]*Connection, 0, len(hub.connections))
+	for _, conn := range hub.connections {
+		connections = append(connections, conn)
+	}
+
+	return connections
+}
+
+func TestBrokerPayload(t *testing.T) {
+	var got []Event
+
+	handler := func(event Event) {
+		got = append(got, event)
+	}
+
+	deliver(handler, []byte(`{"id":"n-1","topic":"orders","event":"created","data":["1"],"retry":1000000000}`))
+	deliver(handler, []byte(`not json`))
+
+	testutils.Equal(t, got, []Event{{ID: "n-1", Topic: "orders", Event: "created", Data: []string{"1"}, Retry: time.Second}})
+}
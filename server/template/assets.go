@@ -0,0 +1,84 @@
+package template //nolint:revive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+const assetHashSize = 8
+
+// Assets builds fingerprinted URLs for static files so they can be cached forever.
+type Assets struct {
+	fsys   fs.FS
+	prefix string
+
+	mu     sync.RWMutex
+	hashes map[string]string
+}
+
+// NewAssets returns asset URLs rooted at prefix, such as /static, for files in fsys.
+func NewAssets(fsys fs.FS, prefix string) *Assets {
+	return &Assets{
+		fsys:   fsys,
+		prefix: strings.TrimSuffix(prefix, "/"),
+		hashes: make(map[string]string),
+	}
+}
+
+// Path returns prefix/name?v=<content hash>. Files that cannot be read get no
+// version so a missing asset does not break rendering.
+func (a *Assets) Path(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if a == nil {
+		return "/" + name
+	}
+
+	url := a.prefix + "/" + name
+
+	hash, ok := a.hash(name)
+	if !ok {
+		return url
+	}
+
+	return url + "?v=" + hash
+}
+
+// Reset forgets computed hashes, for development reloads.
+func (a *Assets) Reset() {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.hashes = make(map[string]string)
+}
+
+func (a *Assets) hash(name string) (string, bool) {
+	a.mu.RLock()
+	hash, ok := a.hashes[name]
+	a.mu.RUnlock()
+
+	if ok {
+		return hash, true
+	}
+
+	data, err := fs.ReadFile(a.fsys, name)
+	if err != nil {
+		return "", false
+	}
+
+	sum := sha256.Sum256(data)
+	hash = hex.EncodeToString(sum[:])[:assetHashSize]
+
+	a.mu.Lock()
+	a.hashes[name] = hash
+	a.mu.Unlock()
+
+	return hash, true
+}
@@ -0,0 +1,67 @@
+package template //nolint:revive
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const truncateSuffix = "…"
+
+// Funcs returns the function map shared by views: formatting helpers, "t" for
+// translator lookups and "asset" for fingerprinted asset URLs. Nil translator or
+// assets keep the functions available, returning keys and plain paths.
+//
+//	{{ upper .Name }} {{ truncate 20 .Title }} {{ default "n/a" .Value }}
+//	{{ date "2006-01-02" .CreatedAt }} {{ join ", " .Tags }} {{ json .Payload }}
+//	{{ t .Locale "greeting" .Name }} {{ asset "css/app.css" }}
+func Funcs(translator *Translator, assets *Assets) map[string]any {
+	return map[string]any{
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"trim":     strings.TrimSpace,
+		"truncate": truncate,
+		"default":  defaultValue,
+		"join":     join,
+		"date":     date,
+		"json":     toJSON,
+		"t":        translator.Translate,
+		"asset":    assets.Path,
+	}
+}
+
+func truncate(length int, value string) string {
+	runes := []rune(value)
+	if length < 0 || len(runes) <= length {
+		return value
+	}
+
+	return string(runes[:length]) + truncateSuffix
+}
+
+func defaultValue(fallback any, value any) any {
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		return fallback
+	}
+
+	return value
+}
+
+func join(separator string, values []string) string {
+	return strings.Join(values, separator)
+}
+
+func date(layout string, value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+
+	return value.Format(layout)
+}
+
+func toJSON(value any) (string, error) {
+	data, err := json.Marshal(value)
+
+	return string(data), err
+}
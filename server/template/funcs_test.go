@@ -0,0 +1,66 @@
+package template //nolint:revive
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+)
+
+func TestTranslator(t *testing.T) {
+	translator := NewTranslator("en")
+	testutils.Equal(t, translator.LoadFS(fstest.MapFS{
+		"i18n/en.json":    {Data: []byte(`{"hello": "Hello %s", "bye": "Bye"}`)},
+		"i18n/pt.json":    {Data: []byte(`{"hello": "Olá %s"}`)},
+		"i18n/pt-BR.json": {Data: []byte(`{"bye": "Tchau"}`)},
+	}, "i18n/*.json"), nil)
+
+	cases := []struct {
+		name   string
+		locale string
+		key    string
+		args   []any
+		want   string
+	}{
+		{name: "exact locale", locale: "pt_BR", key: "bye", want: "Tchau"},
+		{name: "language fallback", locale: "pt-BR", key: "hello", args: []any{"Ana"}, want: "Olá Ana"},
+		{name: "default locale fallback", locale: "de", key: "bye", want: "Bye"},
+		{name: "missing key", locale: "en", key: "missing", want: "missing"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			testutils.Equal(t, translator.Translate(test.locale, test.key, test.args...), test.want)
+		})
+	}
+
+	var missing *Translator
+	testutils.Equal(t, missing.Translate("en", "key"), "key")
+}
+
+func TestAssetsPath(t *testing.T) {
+	assets := NewAssets(fstest.MapFS{"css/app.css": {Data: []byte("body{}")}}, "/static/")
+
+	testutils.Equal(t, assets.Path("/css/app.css"), "/static/css/app.css?v=7c98040a")
+	testutils.Equal(t, assets.Path("../css/app.css"), "/static/css/app.css?v=7c98040a")
+	testutils.Equal(t, assets.Path("js/missing.js"), "/static/js/missing.js")
+
+	var missing *Assets
+	testutils.Equal(t, missing.Path("css/app.css"), "/css/app.css")
+}
+
+func TestFormattingFuncs(t *testing.T) {
+	testutils.Equal(t, truncate(3, "héllo"), "hél…")
+	testutils.Equal(t, truncate(10, "hello"), "hello")
+	testutils.Equal(t, defaultValue("n/a", ""), any("n/a"))
+	testutils.Equal(t, defaultValue("n/a", 0), any("n/a"))
+	testutils.Equal(t, defaultValue("n/a", "set"), any("set"))
+	testutils.Equal(t, join(", ", []string{"a", "b"}), "a, b")
+	testutils.Equal(t, date("2006-01-02", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), "2024-05-01")
+	testutils.Equal(t, date("2006-01-02", time.Time{}), "")
+
+	encoded, err := toJSON(map[string]int{"a": 1})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, encoded, `{"a":1}`)
+}
@@ -0,0 +1,117 @@
+package template //nolint:revive
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Translator looks up localized messages, falling back from a regional locale to
+// its language and then to the fallback locale.
+type Translator struct {
+	mu       sync.RWMutex
+	fallback string
+	messages map[string]map[string]string
+}
+
+// NewTranslator returns a translator that falls back to the fallback locale.
+func NewTranslator(fallback string) *Translator {
+	return &Translator{
+		fallback: normalizeLocale(fallback),
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Add merges messages into locale.
+func (t *Translator) Add(locale string, messages map[string]string) {
+	locale = normalizeLocale(locale)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	catalog, ok := t.messages[locale]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		t.messages[locale] = catalog
+	}
+
+	for key, message := range messages {
+		catalog[key] = message
+	}
+}
+
+// LoadFS adds every JSON file matching pattern in fsys; the file name without
+// extension is the locale, such as en.json or pt-BR.json.
+func (t *Translator) LoadFS(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		t.Add(strings.TrimSuffix(path.Base(file), path.Ext(file)), messages)
+	}
+
+	return nil
+}
+
+// Translate returns the message for key in locale, formatted with args when given.
+// A key missing in every candidate locale is returned as is.
+func (t *Translator) Translate(locale string, key string, args ...any) string {
+	if t == nil {
+		return key
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, candidate := range t.candidates(normalizeLocale(locale)) {
+		message, ok := t.messages[candidate][key]
+		if !ok {
+			continue
+		}
+
+		if len(args) > 0 {
+			return fmt.Sprintf(message, args...)
+		}
+
+		return message
+	}
+
+	return key
+}
+
+func (t *Translator) candidates(locale string) []string {
+	var candidates []string
+
+	for _, value := range []string{locale, t.fallback} {
+		if value == "" {
+			continue
+		}
+
+		candidates = append(candidates, value)
+
+		if language, _, ok := strings.Cut(value, "-"); ok {
+			candidates = append(candidates, language)
+		}
+	}
+
+	return candidates
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
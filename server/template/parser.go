@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 )
@@ -106,8 +107,9 @@ func NewTemplateBySource(source embed.FS, name, pattern string) (*Template, erro
 	}, nil
 }
 
-// Engine type template engine
+// Engine type template engine, safe for concurrent use
 type Engine struct {
+	mu    sync.RWMutex
 	pages map[string]*Template
 }
 
@@ -120,12 +122,19 @@ func NewEngine() *Engine {
 
 // Add add template
 func (e *Engine) Add(t *Template) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	e.pages[t.Name] = t
 }
 
 // Exists return true if page exists
 func (e *Engine) Exists(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	_, exists := e.pages[name]
+
 	return exists
 }
 
@@ -136,7 +145,11 @@ func (e *Engine) Execute(name string, data interface{}) ([]byte, error) {
 		tpl bytes.Buffer
 	)
 
-	if t, ok := e.pages[name]; ok {
+	e.mu.RLock()
+	t, ok := e.pages[name]
+	e.mu.RUnlock()
+
+	if ok {
 		err = t.Execute(&tpl, data)
 		if err != nil {
 			err = errors.Wrap(ErrExecuteTemplate, err.Error())
@@ -0,0 +1,293 @@
+package template //nolint:revive
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/pkg/errors"
+)
+
+// View defaults.
+const (
+	// ContentTemplate is the page body inside a layout: {{ template "content" . }}.
+	ContentTemplate = "content"
+
+	DefaultHTMLExtension = ".html"
+	DefaultTextExtension = ".tmpl"
+	DefaultLayoutsDir    = "layouts"
+	DefaultPartialsDir   = "partials"
+)
+
+// ViewConfig configures a ViewEngine.
+type ViewConfig struct {
+	// FS holds the templates, typically an embed.FS; Root is the directory inside it.
+	FS   fs.FS
+	Root string
+	// Extension selects template files; defaults to DefaultHTMLExtension, or DefaultTextExtension with Text.
+	Extension string
+	// LayoutsDir and PartialsDir are relative to Root. Their templates are shared by every
+	// page and referenced by path without extension, such as "partials/header".
+	LayoutsDir  string
+	PartialsDir string
+	// Layout wraps pages rendered without an explicit layout, such as "layouts/main"; empty renders pages alone.
+	Layout string
+	// Text parses with text/template instead of html/template, for emails and other plain text.
+	Text bool
+	// Reload re-reads templates from ReloadDir on every render so edits show up without a rebuild,
+	// and recomputes asset hashes. It is meant for development only.
+	Reload bool
+	// ReloadDir replaces FS in reload mode, so templates are read from Root inside it. It defaults
+	// to GetDefaultTemplateDir(EnvPrefix).
+	ReloadDir string
+	EnvPrefix string
+	// Translator and Assets back the "t" and "asset" functions; Funcs adds or overrides functions.
+	Translator *Translator
+	Assets     *Assets
+	Funcs      map[string]any
+}
+
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+type templateFile struct {
+	name    string
+	content string
+}
+
+// ViewEngine renders pages with shared layouts and partials. It is safe for
+// concurrent use and implements Fiber's Views interface:
+//
+//	app := fiber.New(fiber.Config{Views: engine})
+//	return c.Render("pages/home", data)
+type ViewEngine struct {
+	cfg   ViewConfig
+	funcs map[string]any
+
+	mu      sync.RWMutex
+	pages   map[string]executor
+	layouts map[string]struct{}
+}
+
+// NewViewEngine returns an engine with defaults applied to cfg. Templates are parsed by Load.
+func NewViewEngine(cfg ViewConfig) *ViewEngine {
+	if cfg.Extension == "" {
+		cfg.Extension = DefaultHTMLExtension
+		if cfg.Text {
+			cfg.Extension = DefaultTextExtension
+		}
+	}
+
+	if cfg.LayoutsDir == "" {
+		cfg.LayoutsDir = DefaultLayoutsDir
+	}
+
+	if cfg.PartialsDir == "" {
+		cfg.PartialsDir = DefaultPartialsDir
+	}
+
+	if cfg.Root == "" {
+		cfg.Root = "."
+	}
+
+	funcs := Funcs(cfg.Translator, cfg.Assets)
+	for name, fn := range cfg.Funcs {
+		funcs[name] = fn
+	}
+
+	return &ViewEngine{
+		cfg:     cfg,
+		funcs:   funcs,
+		pages:   make(map[string]executor),
+		layouts: make(map[string]struct{}),
+	}
+}
+
+// Load parses every template. Pages are the files outside LayoutsDir and PartialsDir,
+// named by path without extension, such as "pages/home".
+func (e *ViewEngine) Load() error {
+	source, root := e.source()
+
+	var shared, pages []templateFile
+
+	layouts := make(map[string]struct{})
+
+	err := fs.WalkDir(source, root, func(file string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if entry.IsDir() || path.Ext(file) != e.cfg.Extension {
+			return nil
+		}
+
+		content, err := fs.ReadFile(source, file)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(file, root+"/"), e.cfg.Extension)
+		if root == "." {
+			name = strings.TrimSuffix(file, e.cfg.Extension)
+		}
+
+		current := templateFile{name: name, content: string(content)}
+
+		switch {
+		case strings.HasPrefix(name, e.cfg.LayoutsDir+"/"):
+			layouts[name] = struct{}{}
+			shared = append(shared, current)
+		case strings.HasPrefix(name, e.cfg.PartialsDir+"/"):
+			shared = append(shared, current)
+		default:
+			pages = append(pages, current)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	parsed, err := e.parse(shared, pages)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.pages = parsed
+	e.layouts = layouts
+	e.mu.Unlock()
+
+	return nil
+}
+
+// Render writes page name to out inside layout, or inside ViewConfig.Layout when no
+// layout is given; an empty layout renders the page alone.
+func (e *ViewEngine) Render(out io.Writer, name string, binding any, layout ...string) error {
+	if e.cfg.Reload {
+		e.cfg.Assets.Reset()
+
+		if err := e.Load(); err != nil {
+			return err
+		}
+	}
+
+	wrapper := e.cfg.Layout
+	if len(layout) > 0 {
+		wrapper = layout[0]
+	}
+
+	e.mu.RLock()
+	page, ok := e.pages[name]
+	_, hasLayout := e.layouts[wrapper]
+	e.mu.RUnlock()
+
+	if !ok {
+		return errors.Wrap(ErrNotFoundTemplate, name)
+	}
+
+	target := name
+
+	if wrapper != "" {
+		if !hasLayout {
+			return errors.Wrap(ErrNotFoundTemplate, wrapper)
+		}
+
+		target = wrapper
+	}
+
+	if err := page.ExecuteTemplate(out, target, binding); err != nil {
+		return errors.Wrap(ErrExecuteTemplate, err.Error())
+	}
+
+	return nil
+}
+
+// Execute renders page name with the default layout and returns the output.
+func (e *ViewEngine) Execute(name string, data any) ([]byte, error) {
+	var output bytes.Buffer
+
+	if err := e.Render(&output, name, data); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+// Exists reports whether page name is loaded.
+func (e *ViewEngine) Exists(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_, ok := e.pages[name]
+
+	return ok
+}
+
+// source returns the templates file system and the Root directory inside it.
+func (e *ViewEngine) source() (fs.FS, string) {
+	root := path.Clean(e.cfg.Root)
+	if !e.cfg.Reload {
+		return e.cfg.FS, root
+	}
+
+	dir := e.cfg.ReloadDir
+	if dir == "" {
+		dir = GetDefaultTemplateDir(e.cfg.EnvPrefix)
+	}
+
+	return os.DirFS(dir), root
+}
+
+// parse builds one template set per page holding the shared templates, the page and
+// the page again as ContentTemplate, so layouts and page blocks resolve per page.
+func (e *ViewEngine) parse(shared, pages []templateFile) (map[string]executor, error) {
+	if e.cfg.Text {
+		return parseSets(texttemplate.New("").Funcs(e.funcs).Option("missingkey=error"), shared, pages)
+	}
+
+	return parseSets(htmltemplate.New("").Funcs(e.funcs).Option("missingkey=error"), shared, pages)
+}
+
+// templateSet is the part of html/template and text/template used to build pages.
+type templateSet[T any] interface {
+	executor
+	New(name string) T
+	Parse(text string) (T, error)
+	Clone() (T, error)
+}
+
+func parseSets[T templateSet[T]](base T, shared, pages []templateFile) (map[string]executor, error) {
+	for _, file := range shared {
+		if _, err := base.New(file.name).Parse(file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sets := make(map[string]executor, len(pages))
+
+	for _, page := range pages {
+		set, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range []string{page.name, ContentTemplate} {
+			if _, err := set.New(name).Parse(page.content); err != nil {
+				return nil, errors.Wrap(err, page.name)
+			}
+		}
+
+		sets[page.name] = set
+	}
+
+	return sets, nil
+}
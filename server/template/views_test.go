@@ -0,0 +1,187 @@
+package template //nolint:revive
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/FrogoAI/testutils"
+	"github.com/gofiber/fiber/v3"
+)
+
+func viewsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"views/layouts/main.html": {
+			Data: []byte(`<title>{{block "title" .}}Site{{end}}</title>{{template "partials/nav" .}}<main>{{template "content" .}}</main>`),
+		},
+		"views/partials/nav.html": {Data: []byte(`<nav>{{upper .User}}</nav>`)},
+		"views/pages/home.html":   {Data: []byte(`{{define "title"}}Home{{end}}Hello {{.User}}`)},
+		"views/pages/about.html":  {Data: []byte(`About {{t .Locale "team"}}`)},
+		"views/emails/welcome.tmpl": {
+			Data: []byte(`Hi {{.User}}, {{t .Locale "welcome"}}`),
+		},
+	}
+}
+
+func TestViewEngineRender(t *testing.T) {
+	translator := NewTranslator("en")
+	translator.Add("en", map[string]string{"team": "the team", "welcome": "welcome aboard"})
+
+	engine := NewViewEngine(ViewConfig{FS: viewsFS(), Root: "views", Layout: "layouts/main", Translator: translator})
+	testutils.Equal(t, engine.Load(), nil)
+	testutils.Equal(t, engine.Exists("pages/home"), true)
+	testutils.Equal(t, engine.Exists("partials/nav"), false)
+
+	data := map[string]string{"User": "<ada>", "Locale": "en-GB"}
+
+	cases := []struct {
+		name    string
+		page    string
+		layout  []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "default layout with block and partial",
+			page: "pages/home",
+			want: `<title>Home</title><nav>&lt;ADA&gt;</nav><main>Hello &lt;ada&gt;</main>`,
+		},
+		{
+			name: "block default",
+			page: "pages/about",
+			want: `<title>Site</title><nav>&lt;ADA&gt;</nav><main>About the team</main>`,
+		},
+		{name: "without layout", page: "pages/about", layout: []string{""}, want: "About the team"},
+		{name: "missing page", page: "pages/missing", wantErr: ErrNotFoundTemplate},
+		{name: "missing layout", page: "pages/home", layout: []string{"layouts/none"}, wantErr: ErrNotFoundTemplate},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			var output bytes.Buffer
+
+			err := engine.Render(&output, test.page, data, test.layout...)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Render error = %v, want %v", err, test.wantErr)
+			}
+
+			testutils.Equal(t, output.String(), test.want)
+		})
+	}
+
+	_, err := engine.Execute("pages/home", map[string]string{})
+	testutils.Equal(t, errors.Is(err, ErrExecuteTemplate), true)
+}
+
+func TestViewEngineText(t *testing.T) {
+	engine := NewViewEngine(ViewConfig{FS: viewsFS(), Root: "views", Text: true})
+	testutils.Equal(t, engine.Load(), nil)
+
+	output, err := engine.Execute("emails/welcome", map[string]string{"User": "<ada>", "Locale": "en"})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, string(output), "Hi <ada>, welcome")
+	testutils.Equal(t, engine.Exists("pages/home"), false)
+}
+
+func TestViewEngineConcurrentRender(t *testing.T) {
+	engine := NewViewEngine(ViewConfig{FS: viewsFS(), Root: "views", Layout: "layouts/main"})
+	testutils.Equal(t, engine.Load(), nil)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := engine.Render(io.Discard, "pages/home", map[string]string{"User": "ada"}); err != nil {
+				t.Errorf("Render: %v", err)
+			}
+		}()
+	}
+
+	for range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := engine.Load(); err != nil {
+				t.Errorf("Load: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestViewEngineReload(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "home.html")
+
+	testutils.Equal(t, os.WriteFile(page, []byte("v1"), 0o600), nil)
+
+	engine := NewViewEngine(ViewConfig{Reload: true, ReloadDir: dir})
+
+	output, err := engine.Execute("home", nil)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, string(output), "v1")
+
+	testutils.Equal(t, os.WriteFile(page, []byte("v2"), 0o600), nil)
+
+	output, err = engine.Execute("home", nil)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, string(output), "v2")
+}
+
+func TestViewEngineReloadRootAndAssets(t *testing.T) {
+	dir := t.TempDir()
+	asset := filepath.Join(dir, "app.css")
+
+	testutils.Equal(t, os.MkdirAll(filepath.Join(dir, "views", "pages"), 0o700), nil)
+	testutils.Equal(t, os.WriteFile(filepath.Join(dir, "views", "pages", "home.html"),
+		[]byte(`{{asset "app.css"}}`), 0o600), nil)
+	testutils.Equal(t, os.WriteFile(asset, []byte("v1"), 0o600), nil)
+
+	engine := NewViewEngine(ViewConfig{
+		Root:      "views",
+		Reload:    true,
+		ReloadDir: dir,
+		Assets:    NewAssets(os.DirFS(dir), "/static"),
+	})
+
+	first, err := engine.Execute("pages/home", nil)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, bytes.HasPrefix(first, []byte("/static/app.css?v=")), true)
+
+	testutils.Equal(t, os.WriteFile(asset, []byte("v2"), 0o600), nil)
+
+	second, err := engine.Execute("pages/home", nil)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, bytes.Equal(first, second), false)
+}
+
+func TestViewEngineFiber(t *testing.T) {
+	engine := NewViewEngine(ViewConfig{FS: viewsFS(), Root: "views", Layout: "layouts/main"})
+
+	app := fiber.New(fiber.Config{Views: engine})
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.Render("pages/home", fiber.Map{"User": "ada"})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, resp.StatusCode, http.StatusOK)
+
+	body, err := io.ReadAll(resp.Body)
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, string(body), "<title>Home</title><nav>ADA</nav><main>Hello ada</main>")
+}
@@ -0,0 +1,419 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/InsideGallery/core/server/webserver/openapi"
+)
+
+// DefaultOpenAPIPath is the route serving the generated OpenAPI document.
+const DefaultOpenAPIPath = "/openapi.json"
+
+// DisabledOpenAPIPath turns off serving the OpenAPI document.
+const DisabledOpenAPIPath = "-"
+
+const headerContentType = "Content-Type"
+
+// APIConfig configures OpenAPI generation for an APIRouter.
+type APIConfig struct {
+	Title       string
+	Version     string
+	Description string
+	// DocumentPath is the GET route serving the document. Empty uses
+	// DefaultOpenAPIPath and DisabledOpenAPIPath turns it off.
+	DocumentPath string
+}
+
+// Param describes a path, query or header parameter of a route.
+type Param struct {
+	Name string
+	// In is openapi.InPath, openapi.InQuery or openapi.InHeader; empty means query.
+	In string
+	// Type is a scalar openapi type such as openapi.TypeInteger; empty means string.
+	Type        string
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// RouteSpec describes a route for OpenAPI generation and request validation.
+type RouteSpec struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Params      []Param
+	// Request is a value of the JSON request body type; nil means no body.
+	Request any
+	// Response is a value of the type returned in Response.Data.
+	Response any
+	// Status is the success status code; zero means 200.
+	Status int
+}
+
+// NoBody is used as the request type of typed routes without a body.
+type NoBody struct{}
+
+// TypedRequest is a validated request with its JSON body decoded into Data.
+type TypedRequest[T any] struct {
+	RouteRequest
+	Data T
+}
+
+// TypedHandler handles a typed route; the result is sent as GetSuccessResponse data.
+type TypedHandler[Req any, Resp any] func(ctx context.Context, req TypedRequest[Req]) (Resp, error)
+
+// APIRouter registers routes with schemas, validates requests and serves an OpenAPI document.
+type APIRouter struct {
+	router    Router
+	info      openapi.Info
+	generator *openapi.Generator
+	validator *openapi.Validator
+
+	mu       sync.Mutex
+	routes   []*apiRoute
+	document []byte
+}
+
+type apiRoute struct {
+	spec     RouteSpec
+	params   []Param
+	request  *openapi.Schema
+	response *openapi.Schema
+}
+
+// NewAPIRouter wraps a core-owned router with OpenAPI generation and validation.
+func NewAPIRouter(router Router, cfg APIConfig) *APIRouter {
+	generator := openapi.NewGenerator()
+	api := &APIRouter{
+		router: router,
+		info: openapi.Info{
+			Title:       cfg.Title,
+			Version:     cfg.Version,
+			Description: cfg.Description,
+		},
+		generator: generator,
+		validator: openapi.NewValidator(generator),
+	}
+
+	path := cfg.DocumentPath
+	if path == "" {
+		path = DefaultOpenAPIPath
+	}
+
+	if path != DisabledOpenAPIPath {
+		router.Handle(http.MethodGet, path, api.serveDocument)
+	}
+
+	return api
+}
+
+// Handle registers a route without a request schema; path parameters are still documented.
+func (r *APIRouter) Handle(method string, path string, handler RouteHandler) {
+	r.HandleSpec(RouteSpec{Method: method, Path: path}, handler)
+}
+
+// HandleSpec registers a route described by spec and validates requests before calling handler.
+//
+// Invalid requests return an *openapi.ValidationError, which the app error
+// handler renders as a 400 response.
+func (r *APIRouter) HandleSpec(spec RouteSpec, handler RouteHandler) {
+	route := &apiRoute{
+		spec:     spec,
+		params:   specParams(spec),
+		request:  r.generator.SchemaOf(spec.Request),
+		response: r.generator.SchemaOf(spec.Response),
+	}
+
+	r.mu.Lock()
+	r.routes = append(r.routes, route)
+	r.document = nil
+	r.mu.Unlock()
+
+	r.router.Handle(spec.Method, spec.Path, func(ctx context.Context, req RouteRequest) (RouteResponse, error) {
+		if err := r.validate(route, req); err != nil {
+			return RouteResponse{}, err
+		}
+
+		return handler(ctx, req)
+	})
+}
+
+// HandleTyped registers a route whose JSON body is decoded into Req and whose result is
+// sent as JSON with GetSuccessResponse.
+func HandleTyped[Req any, Resp any](r *APIRouter, spec RouteSpec, handler TypedHandler[Req, Resp]) {
+	requestType := reflect.TypeFor[Req]()
+	if spec.Request == nil && requestType != reflect.TypeFor[NoBody]() {
+		spec.Request = reflect.New(requestType).Elem().Interface()
+	}
+
+	if spec.Response == nil {
+		spec.Response = reflect.New(reflect.TypeFor[Resp]()).Elem().Interface()
+	}
+
+	status := spec.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	r.HandleSpec(spec, func(ctx context.Context, req RouteRequest) (RouteResponse, error) {
+		typed := TypedRequest[Req]{RouteRequest: req}
+
+		if spec.Request != nil {
+			if err := json.Unmarshal(req.Body, &typed.Data); err != nil {
+				errs := &openapi.ValidationError{}
+				errs.Add("body", "invalid JSON: "+err.Error())
+
+				return RouteResponse{}, errs
+			}
+		}
+
+		result, err := handler(ctx, typed)
+		if err != nil {
+			return RouteResponse{}, err
+		}
+
+		return JSONResponse(status, GetSuccessResponse(result))
+	})
+}
+
+// Document returns the OpenAPI 3.1 document for all registered routes.
+func (r *APIRouter) Document() *openapi.Document {
+	r.mu.Lock()
+	routes := append([]*apiRoute(nil), r.routes...)
+	r.mu.Unlock()
+
+	document := openapi.NewDocument(r.info)
+	errorSchema := r.generator.SchemaOf(Response{})
+
+	for _, route := range routes {
+		document.AddOperation(route.spec.Method, openapi.Path(route.spec.Path), r.operation(route, errorSchema))
+	}
+
+	document.Components = &openapi.Components{Schemas: r.generator.Components()}
+
+	return document
+}
+
+// DocumentJSON returns the OpenAPI document encoded as JSON; the result is cached until a route is added.
+func (r *APIRouter) DocumentJSON() ([]byte, error) {
+	r.mu.Lock()
+	cached := r.document
+	r.mu.Unlock()
+
+	if cached != nil {
+		return cached, nil
+	}
+
+	data, err := json.Marshal(r.Document())
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.document = data
+	r.mu.Unlock()
+
+	return data, nil
+}
+
+// ValidationErrorResponse returns a 400 JSON response carrying an ErrorResponse.
+// Typed routes return validation failures as errors for the app error handler instead.
+func ValidationErrorResponse(err error) RouteResponse {
+	response, marshalErr := JSONResponse(http.StatusBadRequest, GetResponseWithError(err, http.StatusBadRequest))
+	if marshalErr != nil {
+		return RouteResponse{StatusCode: http.StatusBadRequest, Body: []byte(err.Error())}
+	}
+
+	return response
+}
+
+// JSONResponse encodes value as a JSON route response.
+func JSONResponse(status int, value any) (RouteResponse, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return RouteResponse{}, err
+	}
+
+	return RouteResponse{
+		StatusCode: status,
+		Header:     map[string][]string{headerContentType: {openapi.MediaTypeJSON}},
+		Body:       body,
+	}, nil
+}
+
+func (r *APIRouter) serveDocument(_ context.Context, _ RouteRequest) (RouteResponse, error) {
+	data, err := r.DocumentJSON()
+	if err != nil {
+		return RouteResponse{}, err
+	}
+
+	return RouteResponse{
+		StatusCode: http.StatusOK,
+		Header:     map[string][]string{headerContentType: {openapi.MediaTypeJSON}},
+		Body:       data,
+	}, nil
+}
+
+func (r *APIRouter) validate(route *apiRoute, req RouteRequest) error {
+	errs := &openapi.ValidationError{}
+
+	for _, param := range route.params {
+		value, ok := paramValue(param, req)
+		field := param.In + "." + param.Name
+
+		if !ok || value == "" {
+			if param.Required {
+				errs.Add(field, "is required")
+			}
+
+			continue
+		}
+
+		r.validator.ValidateParam(errs, field, paramSchema(param), value)
+	}
+
+	if route.request != nil {
+		if len(strings.TrimSpace(string(req.Body))) == 0 {
+			errs.Add("body", "is required")
+		} else {
+			r.validator.ValidateJSON(errs, "body", route.request, req.Body)
+		}
+	}
+
+	return errs.Err()
+}
+
+func (r *APIRouter) operation(route *apiRoute, errorSchema *openapi.Schema) *openapi.Operation {
+	operation := &openapi.Operation{
+		OperationID: route.spec.OperationID,
+		Summary:     route.spec.Summary,
+		Description: route.spec.Description,
+		Tags:        route.spec.Tags,
+		Responses:   make(map[string]openapi.Response),
+	}
+
+	for _, param := range route.params {
+		operation.Parameters = append(operation.Parameters, openapi.Parameter{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      paramSchema(param),
+		})
+	}
+
+	if route.request != nil {
+		operation.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]openapi.MediaType{openapi.MediaTypeJSON: {Schema: route.request}},
+		}
+	}
+
+	status := route.spec.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	operation.Responses[strconv.Itoa(status)] = openapi.Response{
+		Description: http.StatusText(status),
+		Content:     map[string]openapi.MediaType{openapi.MediaTypeJSON: {Schema: successSchema(route.response)}},
+	}
+
+	if route.request != nil || len(route.params) > 0 {
+		operation.Responses[strconv.Itoa(http.StatusBadRequest)] = openapi.Response{
+			Description: http.StatusText(http.StatusBadRequest),
+			Content:     map[string]openapi.MediaType{openapi.MediaTypeJSON: {Schema: errorSchema}},
+		}
+	}
+
+	return operation
+}
+
+func successSchema(data *openapi.Schema) *openapi.Schema {
+	schema := &openapi.Schema{
+		Type: openapi.TypeObject,
+		Properties: map[string]*openapi.Schema{
+			"ok": {Type: openapi.TypeBoolean},
+		},
+		Required: []string{"ok"},
+	}
+
+	if data != nil {
+		schema.Properties["data"] = data
+	}
+
+	return schema
+}
+
+func specParams(spec RouteSpec) []Param {
+	params := make([]Param, 0, len(spec.Params))
+	declared := make(map[string]bool, len(spec.Params))
+
+	for _, param := range spec.Params {
+		if param.In == "" {
+			param.In = openapi.InQuery
+		}
+
+		if param.In == openapi.InPath {
+			param.Required = true
+			declared[param.Name] = true
+		}
+
+		params = append(params, param)
+	}
+
+	for _, name := range openapi.PathParams(spec.Path) {
+		if !declared[name] {
+			params = append(params, Param{Name: name, In: openapi.InPath, Required: true})
+		}
+	}
+
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].In == openapi.InPath && params[j].In != openapi.InPath
+	})
+
+	return params
+}
+
+func paramSchema(param Param) *openapi.Schema {
+	schema := &openapi.Schema{Type: param.Type}
+	if schema.Type == "" {
+		schema.Type = openapi.TypeString
+	}
+
+	for _, value := range param.Enum {
+		schema.Enum = append(schema.Enum, value)
+	}
+
+	return schema
+}
+
+func paramValue(param Param, req RouteRequest) (string, bool) {
+	switch param.In {
+	case openapi.InPath:
+		value, ok := req.Params[param.Name]
+
+		return value, ok
+	case openapi.InHeader:
+		values := http.Header(req.Header).Values(param.Name)
+		if len(values) == 0 {
+			return "", false
+		}
+
+		return values[0], true
+	default:
+		value, ok := req.Query[param.Name]
+
+		return value, ok
+	}
+}
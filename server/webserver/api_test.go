@@ -0,0 +1,252 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/InsideGallery/core/server/webserver/openapi"
+)
+
+type apiCreateUser struct {
+	Name  string   `json:"name" minLength:"2"`
+	Email string   `json:"email" pattern:"^[^@]+@[^@]+$"`
+	Age   int      `json:"age,omitempty" minimum:"18"`
+	Role  string   `json:"role,omitempty" enum:"admin,user"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type apiUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestAPIRouterValidationAndTypedRoutes(t *testing.T) {
+	t.Parallel()
+
+	app := NewFiberApp("api")
+	api := NewAPIRouter(NewFiberRouter(app), APIConfig{Title: "users", Version: "1.0.0"})
+
+	HandleTyped(api, RouteSpec{
+		Method:      http.MethodPost,
+		Path:        "/orgs/:org/users",
+		OperationID: "createUser",
+		Params: []Param{
+			{Name: "org", In: openapi.InPath, Type: openapi.TypeInteger},
+			{Name: "notify", Type: openapi.TypeBoolean},
+		},
+		Status: http.StatusCreated,
+	}, func(_ context.Context, req TypedRequest[apiCreateUser]) (apiUser, error) {
+		return apiUser{ID: req.Params["org"] + "-1", Name: req.Data.Name}, nil
+	})
+
+	HandleTyped(api, RouteSpec{
+		Method: http.MethodGet,
+		Path:   "/users/:id",
+		Params: []Param{{Name: "view", Enum: []string{"short", "full"}}},
+	}, func(_ context.Context, req TypedRequest[NoBody]) (apiUser, error) {
+		return apiUser{ID: req.Params["id"]}, nil
+	})
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   []string
+	}{
+		{
+			name:       "valid typed request",
+			method:     http.MethodPost,
+			path:       "/orgs/7/users?notify=true",
+			body:       `{"name":"Ann","email":"ann@example.test","role":"admin"}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   []string{`"ok":true`, `"id":"7-1"`, `"name":"Ann"`},
+		},
+		{
+			name:       "invalid body fields are reported together",
+			method:     http.MethodPost,
+			path:       "/orgs/7/users",
+			body:       `{"name":"A","age":12,"role":"root","tags":[1]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody: []string{
+				`"ok":false`,
+				`"code":400`,
+				"body.email: is required",
+				"body.name: must be at least 2 characters",
+				"body.age: must be at least 18",
+				"body.role: must be one of [admin, user]",
+				"body.tags[0]: must be a string",
+			},
+		},
+		{
+			name:       "invalid params",
+			method:     http.MethodPost,
+			path:       "/orgs/x/users?notify=maybe",
+			body:       `{"name":"Ann","email":"ann@example.test"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"path.org: must be an integer", "query.notify: must be a boolean"},
+		},
+		{
+			name:       "missing body",
+			method:     http.MethodPost,
+			path:       "/orgs/7/users",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"body: is required"},
+		},
+		{
+			name:       "malformed body",
+			method:     http.MethodPost,
+			path:       "/orgs/7/users",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"body: invalid JSON"},
+		},
+		{
+			name:       "no body route with enum query",
+			method:     http.MethodGet,
+			path:       "/users/42?view=full",
+			wantStatus: http.StatusOK,
+			wantBody:   []string{`"id":"42"`},
+		},
+		{
+			name:       "enum query rejected",
+			method:     http.MethodGet,
+			path:       "/users/42?view=raw",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"query.view: must be one of [short, full]"},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			status, body := doAPIRequest(t, app, test.method, test.path, test.body)
+			if status != test.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", status, test.wantStatus, body)
+			}
+
+			for _, want := range test.wantBody {
+				if !strings.Contains(body, want) {
+					t.Fatalf("body = %s, want substring %q", body, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIRouterDocument(t *testing.T) {
+	t.Parallel()
+
+	app := NewFiberApp("api")
+	api := NewAPIRouter(NewFiberRouter(app), APIConfig{
+		Title:        "users",
+		Version:      "1.0.0",
+		DocumentPath: "/docs/openapi.json",
+	})
+
+	HandleTyped(api, RouteSpec{
+		Method:      http.MethodPut,
+		Path:        "/users/:id",
+		OperationID: "updateUser",
+		Tags:        []string{"users"},
+	}, func(_ context.Context, _ TypedRequest[apiCreateUser]) (*apiUser, error) {
+		return &apiUser{}, nil
+	})
+	api.Handle(http.MethodDelete, "/users/:id", func(_ context.Context, _ RouteRequest) (RouteResponse, error) {
+		return RouteResponse{StatusCode: http.StatusNoContent}, nil
+	})
+
+	status, body := doAPIRequest(t, app, http.MethodGet, "/docs/openapi.json", "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+
+	var document openapi.Document
+	if err := json.Unmarshal([]byte(body), &document); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+
+	if document.OpenAPI != openapi.Version || document.Info.Title != "users" {
+		t.Fatalf("document header = %q %q", document.OpenAPI, document.Info.Title)
+	}
+
+	update := document.Paths["/users/{id}"]["put"]
+	if update == nil || update.OperationID != "updateUser" {
+		t.Fatalf("put operation = %+v", update)
+	}
+
+	if len(update.Parameters) != 1 || update.Parameters[0].In != openapi.InPath || !update.Parameters[0].Required {
+		t.Fatalf("parameters = %+v", update.Parameters)
+	}
+
+	if ref := update.RequestBody.Content[openapi.MediaTypeJSON].Schema.Ref; ref != "#/components/schemas/apiCreateUser" {
+		t.Fatalf("request ref = %q", ref)
+	}
+
+	data := update.Responses["200"].Content[openapi.MediaTypeJSON].Schema.Properties["data"]
+	if data == nil || data.Ref != "#/components/schemas/apiUser" {
+		t.Fatalf("response data = %+v", data)
+	}
+
+	if _, ok := update.Responses["400"]; !ok {
+		t.Fatal("validation error response is not documented")
+	}
+
+	if document.Paths["/users/{id}"]["delete"] == nil {
+		t.Fatal("plain Handle route is not documented")
+	}
+
+	user := document.Components.Schemas["apiCreateUser"]
+	if strings.Join(user.Required, ",") != "email,name" {
+		t.Fatalf("required = %v", user.Required)
+	}
+
+	if _, ok := document.Components.Schemas["ErrorResponse"]; !ok {
+		t.Fatal("error response schema is missing")
+	}
+}
+
+func doAPIRequest(t *testing.T, app *fiber.App, method, path, body string) (int, string) {
+	t.Helper()
+
+	response, err := app.Test(httptest.NewRequest(method, path, strings.NewReader(body)))
+	if err != nil {
+		t.Fatalf("api request: %v", err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	return response.StatusCode, string(data)
+}
+
+func TestValidationErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	errs := &openapi.ValidationError{}
+	errs.Add("body.name", "is required")
+
+	response := ValidationErrorResponse(errs)
+	if response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", response.StatusCode, http.StatusBadRequest)
+	}
+
+	var body Response
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if body.Ok || body.Error == nil || body.Error.Code != http.StatusBadRequest {
+		t.Fatalf("response = %+v", body)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/InsideGallery/core/oslistener"
 	"github.com/InsideGallery/core/profiler"
+	"github.com/InsideGallery/core/server/webserver/request"
 )
 
 const (
@@ -33,6 +35,8 @@ type Config struct {
 	Name             string                     `env:"_NAME" envDefault:"server"`
 	MonitorAddr      string                     `env:"_MONITOR_ADDR" envDefault:":8011"`
 	ShutdownTimeout  time.Duration              `env:"_SHUTDOWN_TIMEOUT" envDefault:"10s"`
+	ProblemDetails   bool                       `env:"_PROBLEM_DETAILS" envDefault:"false"`
+	TrustedProxies   []string                   `env:"_TRUSTED_PROXIES" envSeparator:","`
 	ShutdownListener *oslistener.SignalListener `env:"-"`
 	ProfilerState    *profiler.State            `env:"-"`
 }
@@ -45,6 +49,8 @@ type Options struct {
 	Name             string
 	MonitorAddr      string
 	ShutdownTimeout  time.Duration
+	ProblemDetails   bool
+	TrustedProxies   []string
 	ShutdownListener *oslistener.SignalListener
 	ProfilerState    *profiler.State
 	InitRoutes       RouteInitializer
@@ -57,6 +63,7 @@ type RouteRequest struct {
 	OriginalURL string
 	Header      map[string][]string
 	Query       map[string]string
+	Params      map[string]string
 	Body        []byte
 }
 
@@ -98,13 +105,26 @@ func GetEnvConfig(prefix ...string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// Validate checks the configuration; an invalid TrustedProxies entry returns
+// request.ErrInvalidProxy.
+func (c *Config) Validate() error {
+	_, err := request.NewTrustedProxies(c.TrustedProxies...)
+
+	return err
+}
+
 // Server wraps fiber.App with graceful shutdown support.
 type Server struct {
-	App *fiber.App
-	cfg *Config
+	App     *fiber.App
+	cfg     *Config
+	proxies *request.TrustedProxies
 }
 
 // FiberRouter adapts Fiber routers to the core-owned Router contract.
@@ -118,12 +138,65 @@ type Runtime struct {
 	initRoutes RouteInitializer
 }
 
-// New creates a new HTTP server with the given configuration.
+// New creates a new HTTP server with the given configuration. An invalid
+// TrustedProxies entry is logged and no proxy is trusted; use NewServer to get the
+// error.
 func New(cfg *Config) *Server {
-	return &Server{
-		App: NewFiberApp(cfg.Name),
-		cfg: cfg,
+	server, err := NewServer(cfg)
+	if err == nil {
+		return server
+	}
+
+	slog.Default().Error("Error creating server, trusting no proxies", "name", cfg.Name, "err", err)
+
+	untrusted := *cfg
+	untrusted.TrustedProxies = nil
+
+	server, _ = NewServer(&untrusted) // without proxies the config is valid
+	server.cfg = cfg
+
+	return server
+}
+
+// NewServer creates a new HTTP server with the given configuration.
+//
+// ProblemDetails switches the error handler to RFC 9457 problem details.
+// TrustedProxies enables Fiber proxy trust for those addresses so scheme, host and
+// client IP are only taken from forwarding headers they send; an invalid entry
+// returns request.ErrInvalidProxy.
+func NewServer(cfg *Config) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	errorHandler := ErrorHandler
+	if cfg.ProblemDetails {
+		errorHandler = ProblemErrorHandler
+	}
+
+	fiberCfg := fiberConfig(cfg.Name, errorHandler)
+
+	if len(cfg.TrustedProxies) > 0 {
+		fiberCfg.TrustProxy = true
+		fiberCfg.TrustProxyConfig = fiber.TrustProxyConfig{Proxies: cfg.TrustedProxies}
+	}
+
+	proxies, err := request.NewTrustedProxiesFromConfig(fiberCfg.TrustProxyConfig)
+	if err != nil {
+		return nil, err
 	}
+
+	return &Server{
+		App:     fiber.New(fiberCfg),
+		cfg:     cfg,
+		proxies: proxies,
+	}, nil
+}
+
+// ClientIP returns the request client address, honoring forwarding headers only
+// from the configured trusted proxies.
+func (s *Server) ClientIP(c fiber.Ctx) net.IP {
+	return s.proxies.ClientIP(c)
 }
 
 // NewFiberRouter wraps a Fiber router with the core-owned Router contract.
@@ -131,19 +204,24 @@ func NewFiberRouter(router fiber.Router) *FiberRouter {
 	return &FiberRouter{router: router}
 }
 
-// NewRuntime creates a server runtime from core-owned options.
+// NewRuntime creates a server runtime from core-owned options. Like New, it logs an
+// invalid TrustedProxies entry and trusts no proxy.
 func NewRuntime(options Options) *Runtime {
+	server := New(&Config{
+		Address:          options.Address,
+		Host:             options.Host,
+		Scheme:           options.Scheme,
+		Name:             options.Name,
+		MonitorAddr:      options.MonitorAddr,
+		ShutdownTimeout:  options.ShutdownTimeout,
+		ProblemDetails:   options.ProblemDetails,
+		TrustedProxies:   options.TrustedProxies,
+		ShutdownListener: options.ShutdownListener,
+		ProfilerState:    options.ProfilerState,
+	})
+
 	return &Runtime{
-		server: New(&Config{
-			Address:          options.Address,
-			Host:             options.Host,
-			Scheme:           options.Scheme,
-			Name:             options.Name,
-			MonitorAddr:      options.MonitorAddr,
-			ShutdownTimeout:  options.ShutdownTimeout,
-			ShutdownListener: options.ShutdownListener,
-			ProfilerState:    options.ProfilerState,
-		}),
+		server:     server,
 		initRoutes: options.InitRoutes,
 	}
 }
@@ -323,10 +401,25 @@ func routeRequest(c fiber.Ctx) RouteRequest {
 		OriginalURL: c.OriginalURL(),
 		Header:      cloneHeader(c.GetReqHeaders()),
 		Query:       cloneQuery(c.Queries()),
+		Params:      requestParams(c),
 		Body:        append([]byte(nil), c.Body()...),
 	}
 }
 
+func requestParams(c fiber.Ctx) map[string]string {
+	names := c.Route().Params
+	if len(names) == 0 {
+		return nil
+	}
+
+	params := make(map[string]string, len(names))
+	for _, name := range names {
+		params[name] = c.Params(name)
+	}
+
+	return params
+}
+
 func cloneQuery(query map[string]string) map[string]string {
 	if len(query) == 0 {
 		return nil
@@ -3,6 +3,7 @@ package webserver
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -10,7 +11,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofiber/fiber/v3"
+
 	"github.com/InsideGallery/core/profiler"
+	"github.com/InsideGallery/core/server/webserver/request"
 )
 
 func TestGetEnvConfig(t *testing.T) {
@@ -23,8 +27,11 @@ func TestGetEnvConfig(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name:  "defaults",
-			unset: []string{"APP_ADDR", "APP_HOST", "APP_SCHEME", "APP_NAME", "APP_MONITOR_ADDR", "APP_SHUTDOWN_TIMEOUT"},
+			name: "defaults",
+			unset: []string{
+				"APP_ADDR", "APP_HOST", "APP_SCHEME", "APP_NAME", "APP_MONITOR_ADDR", "APP_SHUTDOWN_TIMEOUT",
+				"APP_TRUSTED_PROXIES",
+			},
 			want: &Config{
 				Address:         ":8080",
 				Host:            "localhost:8080",
@@ -44,6 +51,7 @@ func TestGetEnvConfig(t *testing.T) {
 				"API_NAME":             "api",
 				"API_MONITOR_ADDR":     ":9011",
 				"API_SHUTDOWN_TIMEOUT": "2s",
+				"API_TRUSTED_PROXIES":  "10.0.0.0/8,192.0.2.1",
 			},
 			want: &Config{
 				Address:         ":9090",
@@ -52,8 +60,16 @@ func TestGetEnvConfig(t *testing.T) {
 				Name:            "api",
 				MonitorAddr:     ":9011",
 				ShutdownTimeout: 2 * time.Second,
+				TrustedProxies:  []string{"10.0.0.0/8", "192.0.2.1"},
 			},
 		},
+		{
+			name: "invalid trusted proxy",
+			env: map[string]string{
+				"APP_TRUSTED_PROXIES": "proxy.local",
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid duration",
 			env: map[string]string{
@@ -115,6 +131,75 @@ func TestServerHelpers(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "client ip honors trusted proxies only",
+			run: func(t *testing.T) {
+				t.Helper()
+
+				for _, test := range []struct {
+					proxies []string
+					want    string
+				}{
+					{proxies: []string{"0.0.0.0/32"}, want: "203.0.113.7"},
+					{want: "0.0.0.0"},
+				} {
+					server, err := NewServer(&Config{Name: "unit", TrustedProxies: test.proxies})
+					if err != nil {
+						t.Fatalf("new server: %v", err)
+					}
+
+					server.App.Get("/", func(c fiber.Ctx) error {
+						return c.SendString(server.ClientIP(c).String())
+					})
+
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					req.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+					resp, err := server.App.Test(req)
+					if err != nil {
+						t.Fatalf("request: %v", err)
+					}
+
+					body, err := io.ReadAll(resp.Body)
+					resp.Body.Close()
+
+					if err != nil {
+						t.Fatalf("read body: %v", err)
+					}
+
+					if string(body) != test.want {
+						t.Fatalf("client ip = %q, want %q", body, test.want)
+					}
+				}
+			},
+		},
+		{
+			name: "invalid trusted proxy is rejected",
+			run: func(t *testing.T) {
+				t.Helper()
+
+				cfg := &Config{Name: "unit", TrustedProxies: []string{"10.0.0.0/40"}}
+
+				if err := cfg.Validate(); !errors.Is(err, request.ErrInvalidProxy) {
+					t.Fatalf("validate err = %v", err)
+				}
+
+				server, err := NewServer(cfg)
+				if !errors.Is(err, request.ErrInvalidProxy) || server != nil {
+					t.Fatalf("new server = %v, %v", server, err)
+				}
+
+				server = New(cfg)
+				if server.cfg != cfg || server.App.Config().TrustProxy {
+					t.Fatal("legacy server must keep the config and trust no proxy")
+				}
+
+				runtime := NewRuntime(Options{Name: "unit", TrustedProxies: []string{"bad"}})
+				if runtime.server.App.Config().TrustProxy {
+					t.Fatal("runtime must trust no proxy")
+				}
+			},
+		},
 		{
 			name: "shutdown timeout falls back to default",
 			run: func(t *testing.T) {
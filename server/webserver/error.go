@@ -1,17 +1,61 @@
 package webserver
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gofiber/fiber/v3"
+
+	coreerrors "github.com/InsideGallery/core/errors"
 )
 
+var categoryStatuses = map[coreerrors.Category]int{
+	coreerrors.CategoryValidation:   http.StatusBadRequest,
+	coreerrors.CategoryNotFound:     http.StatusNotFound,
+	coreerrors.CategoryConflict:     http.StatusConflict,
+	coreerrors.CategoryUnauthorized: http.StatusUnauthorized,
+	coreerrors.CategoryForbidden:    http.StatusForbidden,
+	coreerrors.CategoryRateLimited:  http.StatusTooManyRequests,
+	coreerrors.CategoryUpstream:     http.StatusBadGateway,
+	coreerrors.CategoryInternal:     http.StatusInternalServerError,
+}
+
+// ErrorHandler renders errors as Response with the status chosen by StatusFromError.
 var ErrorHandler = func(c fiber.Ctx, err error) error {
-	return c.Status(http.StatusInternalServerError).JSON(Response{
+	status := StatusFromError(err)
+
+	return c.Status(status).JSON(Response{
 		Ok: false,
 		Error: &ErrorResponse{
 			Message: err.Error(),
-			Code:    0,
+			Code:    status,
 		},
 	})
 }
+
+// StatusFromError maps an error to an HTTP status.
+//
+// Fiber errors keep their code, deadline errors become 504 and everything else
+// is mapped from its core error category.
+func StatusFromError(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return fiberError.Code
+	}
+
+	category := coreerrors.CategoryOf(err)
+	if category == coreerrors.CategoryUpstream && errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+
+	if status, ok := categoryStatuses[category]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
@@ -5,10 +5,19 @@ import "github.com/gofiber/fiber/v3"
 const ReadBufferSize = 16384
 
 func NewFiberApp(name string) *fiber.App {
-	return fiber.New(fiber.Config{
+	return NewFiberAppWithErrorHandler(name, ErrorHandler)
+}
+
+// NewFiberAppWithErrorHandler creates a Fiber app with a custom error handler such as ProblemErrorHandler.
+func NewFiberAppWithErrorHandler(name string, errorHandler fiber.ErrorHandler) *fiber.App {
+	return fiber.New(fiberConfig(name, errorHandler))
+}
+
+func fiberConfig(name string, errorHandler fiber.ErrorHandler) fiber.Config {
+	return fiber.Config{
 		ReadBufferSize: ReadBufferSize,
 		ServerHeader:   name,
-		ErrorHandler:   ErrorHandler,
+		ErrorHandler:   errorHandler,
 		AppName:        name,
-	})
+	}
 }
@@ -0,0 +1,234 @@
+package middlewares
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+)
+
+// Content codings supported by Compress.
+const (
+	EncodingBrotli  = "br"
+	EncodingZstd    = "zstd"
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+
+	encodingIdentity = "identity"
+	encodingAny      = "*"
+)
+
+// DefaultCompressMinSize is the smallest body, in bytes, that Compress encodes.
+const DefaultCompressMinSize = 1024
+
+// DefaultCompressEncodings is the server preference used to break Accept-Encoding ties.
+var DefaultCompressEncodings = []string{EncodingBrotli, EncodingZstd, EncodingGzip, EncodingDeflate}
+
+// DefaultCompressContentTypes are the media types and type prefixes Compress encodes.
+var DefaultCompressContentTypes = []string{
+	fiber.MIMEApplicationJSON,
+	"application/problem+json",
+	fiber.MIMEApplicationXML,
+	fiber.MIMEApplicationJavaScript,
+	"image/svg+xml",
+	"text/",
+}
+
+// CompressConfig configures response compression.
+type CompressConfig struct {
+	// Encodings lists the supported codings in server preference order; empty uses DefaultCompressEncodings.
+	Encodings []string
+	// MinSize skips smaller bodies; zero uses DefaultCompressMinSize and a negative value compresses everything.
+	MinSize int
+	// ContentTypes lists media types, or prefixes ending in "/", that may be compressed; empty uses
+	// DefaultCompressContentTypes.
+	ContentTypes []string
+	// Next skips the middleware when it returns true.
+	Next func(c fiber.Ctx) bool
+}
+
+// Compress returns a Fiber middleware that encodes response bodies with the best coding
+// accepted by the client.
+//
+// Streamed bodies, responses that already have a Content-Encoding, and responses marked
+// Cache-Control: no-transform are left untouched. A strong ETag becomes weak once the
+// body is encoded because the bytes no longer match the identity representation.
+func Compress(cfg CompressConfig) fiber.Handler {
+	encodings := cfg.Encodings
+	if len(encodings) == 0 {
+		encodings = DefaultCompressEncodings
+	}
+
+	minSize := cfg.MinSize
+	if minSize == 0 {
+		minSize = DefaultCompressMinSize
+	}
+
+	contentTypes := cfg.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = DefaultCompressContentTypes
+	}
+
+	return func(c fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		response := c.Response()
+		if !compressible(response, contentTypes) {
+			return nil
+		}
+
+		body := response.Body()
+		if len(body) < minSize {
+			return nil
+		}
+
+		c.Vary(fiber.HeaderAcceptEncoding)
+
+		encoding := NegotiateEncoding(c.Get(fiber.HeaderAcceptEncoding), encodings)
+		if encoding == "" {
+			return nil
+		}
+
+		encoded := encode(encoding, body)
+		if len(encoded) >= len(body) {
+			return nil
+		}
+
+		response.SetBodyRaw(encoded)
+		response.Header.Set(fiber.HeaderContentEncoding, encoding)
+
+		if etag := string(response.Header.Peek(fiber.HeaderETag)); etag != "" && !isWeakETag(etag) {
+			response.Header.Set(fiber.HeaderETag, weakETagPrefix+etag)
+		}
+
+		return nil
+	}
+}
+
+// NegotiateEncoding picks the coding from supported with the highest Accept-Encoding
+// quality, preferring earlier entries of supported on ties; it returns "" for identity.
+func NegotiateEncoding(acceptEncoding string, supported []string) string {
+	if strings.TrimSpace(acceptEncoding) == "" {
+		return ""
+	}
+
+	qualities := parseAcceptEncoding(acceptEncoding)
+	wildcard, hasWildcard := qualities[encodingAny]
+
+	type candidate struct {
+		encoding string
+		quality  float64
+		order    int
+	}
+
+	candidates := make([]candidate, 0, len(supported))
+
+	for i, encoding := range supported {
+		quality, ok := qualities[encoding]
+		if !ok && hasWildcard {
+			quality, ok = wildcard, true
+		}
+
+		if ok && quality > 0 {
+			candidates = append(candidates, candidate{encoding: encoding, quality: quality, order: i})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	if identity, ok := qualities[encodingIdentity]; ok && identity > candidates[0].quality {
+		return ""
+	}
+
+	return candidates[0].encoding
+}
+
+func parseAcceptEncoding(header string) map[string]float64 {
+	qualities := make(map[string]float64)
+
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		quality := 1.0
+
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+				continue
+			}
+
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err == nil {
+				quality = parsed
+			}
+		}
+
+		qualities[name] = quality
+	}
+
+	return qualities
+}
+
+func compressible(response *fasthttp.Response, contentTypes []string) bool {
+	if response.IsBodyStream() || len(response.Header.Peek(fiber.HeaderContentEncoding)) > 0 {
+		return false
+	}
+
+	switch response.StatusCode() {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+
+	if strings.Contains(strings.ToLower(string(response.Header.Peek(fiber.HeaderCacheControl))), "no-transform") {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(string(response.Header.ContentType()))
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range contentTypes {
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encode(encoding string, body []byte) []byte {
+	switch encoding {
+	case EncodingBrotli:
+		return fasthttp.AppendBrotliBytesLevel(nil, body, fasthttp.CompressBrotliDefaultCompression)
+	case EncodingZstd:
+		return fasthttp.AppendZstdBytesLevel(nil, body, fasthttp.CompressZstdDefault)
+	case EncodingGzip:
+		return fasthttp.AppendGzipBytesLevel(nil, body, fasthttp.CompressDefaultCompression)
+	case EncodingDeflate:
+		return fasthttp.AppendDeflateBytesLevel(nil, body, fasthttp.CompressDefaultCompression)
+	default:
+		return body
+	}
+}
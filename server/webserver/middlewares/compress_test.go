@@ -0,0 +1,164 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "empty", header: "", want: ""},
+		{name: "server preference on ties", header: "gzip, br", want: EncodingBrotli},
+		{name: "quality wins", header: "br;q=0.5, gzip", want: EncodingGzip},
+		{name: "excluded coding", header: "br;q=0, zstd;q=0, gzip;q=0.1", want: EncodingGzip},
+		{name: "wildcard", header: "*", want: EncodingBrotli},
+		{name: "wildcard with exclusion", header: "*;q=0.5, br;q=0", want: EncodingZstd},
+		{name: "identity preferred", header: "identity, gzip;q=0.5", want: ""},
+		{name: "unsupported", header: "compress", want: ""},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NegotiateEncoding(test.header, DefaultCompressEncodings); got != test.want {
+				t.Fatalf("NegotiateEncoding(%q) = %q, want %q", test.header, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	large := `{"data":"` + strings.Repeat("a", 2048) + `"}`
+
+	cases := []struct {
+		name         string
+		accept       string
+		contentType  string
+		body         string
+		cacheControl string
+		etag         string
+		wantEncoding string
+		wantETag     string
+	}{
+		{name: "gzip json", accept: "gzip", contentType: fiber.MIMEApplicationJSON, body: large, wantEncoding: "gzip"},
+		{name: "deflate", accept: "deflate", contentType: fiber.MIMEApplicationJSON, body: large, wantEncoding: "deflate"},
+		{name: "brotli", accept: "br, gzip", contentType: fiber.MIMETextPlainCharsetUTF8, body: large, wantEncoding: "br"},
+		{name: "zstd", accept: "zstd", contentType: fiber.MIMEApplicationJSON, body: large, wantEncoding: "zstd"},
+		{name: "small body", accept: "gzip", contentType: fiber.MIMEApplicationJSON, body: `{"ok":true}`},
+		{name: "content type not allowed", accept: "gzip", contentType: "image/png", body: large},
+		{name: "no accept encoding", contentType: fiber.MIMEApplicationJSON, body: large},
+		{
+			name:         "no transform",
+			accept:       "gzip",
+			contentType:  fiber.MIMEApplicationJSON,
+			body:         large,
+			cacheControl: "no-transform",
+		},
+		{
+			name:         "strong etag becomes weak",
+			accept:       "gzip",
+			contentType:  fiber.MIMEApplicationJSON,
+			body:         large,
+			etag:         `"v1"`,
+			wantEncoding: "gzip",
+			wantETag:     `W/"v1"`,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := fiber.New()
+			app.Use(Compress(CompressConfig{}))
+			app.Get("/", func(c fiber.Ctx) error {
+				c.Set(fiber.HeaderContentType, test.contentType)
+
+				if test.cacheControl != "" {
+					c.Set(fiber.HeaderCacheControl, test.cacheControl)
+				}
+
+				if test.etag != "" {
+					c.Set(fiber.HeaderETag, test.etag)
+				}
+
+				return c.SendString(test.body)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.accept != "" {
+				req.Header.Set(fiber.HeaderAcceptEncoding, test.accept)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+
+			if got := resp.Header.Get(fiber.HeaderContentEncoding); got != test.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, test.wantEncoding)
+			}
+
+			if got := decodeBody(t, test.wantEncoding, raw); got != test.body {
+				t.Fatalf("decoded body length = %d, want %d", len(got), len(test.body))
+			}
+
+			if test.wantETag != "" && resp.Header.Get(fiber.HeaderETag) != test.wantETag {
+				t.Fatalf("ETag = %q, want %q", resp.Header.Get(fiber.HeaderETag), test.wantETag)
+			}
+
+			if test.wantEncoding != "" && resp.Header.Get(fiber.HeaderVary) != fiber.HeaderAcceptEncoding {
+				t.Fatalf("Vary = %q", resp.Header.Get(fiber.HeaderVary))
+			}
+		})
+	}
+}
+
+func decodeBody(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+
+	var (
+		decoded []byte
+		err     error
+	)
+
+	switch encoding {
+	case EncodingGzip:
+		decoded, err = fasthttp.AppendGunzipBytes(nil, body)
+	case EncodingDeflate:
+		decoded, err = fasthttp.AppendInflateBytes(nil, body)
+	case EncodingBrotli:
+		decoded, err = fasthttp.AppendUnbrotliBytes(nil, body)
+	case EncodingZstd:
+		decoded, err = fasthttp.AppendUnzstdBytes(nil, body)
+	default:
+		decoded = body
+	}
+
+	if err != nil {
+		t.Fatalf("decode %s: %v", encoding, err)
+	}
+
+	return string(decoded)
+}
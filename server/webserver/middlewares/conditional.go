@@ -0,0 +1,206 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	weakETagPrefix = "W/"
+	etagHashSize   = 16
+)
+
+// ETagConfig configures ETag generation and conditional GET handling.
+type ETagConfig struct {
+	// Weak generates weak validators (W/"...") instead of strong ones.
+	Weak bool
+	// Next skips the middleware when it returns true.
+	Next func(c fiber.Ctx) bool
+}
+
+// ETag returns a Fiber middleware that adds an ETag to successful GET and HEAD responses
+// and answers matching If-None-Match or If-Modified-Since requests with 304.
+//
+// An ETag or Last-Modified header set by the handler is kept; otherwise the ETag is a
+// hash of the response body. Register it after Compress so the hash covers the
+// identity body.
+func ETag(cfg ETagConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		method := c.Method()
+		if method != http.MethodGet && method != http.MethodHead {
+			return nil
+		}
+
+		response := c.Response()
+		if response.StatusCode() != http.StatusOK || response.IsBodyStream() {
+			return nil
+		}
+
+		etag := string(response.Header.Peek(fiber.HeaderETag))
+		if etag == "" {
+			etag = GenerateETag(response.Body(), cfg.Weak)
+			response.Header.Set(fiber.HeaderETag, etag)
+		}
+
+		lastModified := string(response.Header.Peek(fiber.HeaderLastModified))
+		if !NotModified(c.Get(fiber.HeaderIfNoneMatch), c.Get(fiber.HeaderIfModifiedSince), etag, lastModified) {
+			return nil
+		}
+
+		response.SetStatusCode(http.StatusNotModified)
+		response.ResetBody()
+		response.Header.Del(fiber.HeaderContentLength)
+
+		return nil
+	}
+}
+
+// GenerateETag returns a quoted validator derived from body.
+func GenerateETag(body []byte, weak bool) string {
+	sum := sha256.Sum256(body)
+	etag := strconv.Quote(base64.RawURLEncoding.EncodeToString(sum[:etagHashSize]))
+
+	if weak {
+		return weakETagPrefix + etag
+	}
+
+	return etag
+}
+
+// NotModified evaluates If-None-Match and If-Modified-Since for a GET or HEAD request.
+//
+// If-None-Match uses weak comparison and takes precedence; If-Modified-Since is only
+// used when no If-None-Match is sent.
+func NotModified(ifNoneMatch string, ifModifiedSince string, etag string, lastModified string) bool {
+	if ifNoneMatch = strings.TrimSpace(ifNoneMatch); ifNoneMatch != "" {
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || opaqueTag(candidate) == opaqueTag(etag) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ifModifiedSince == "" || lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+
+	return !modified.After(since)
+}
+
+// CachePolicy describes a Cache-Control header.
+type CachePolicy struct {
+	Public               bool
+	Private              bool
+	NoCache              bool
+	NoStore              bool
+	NoTransform          bool
+	MustRevalidate       bool
+	Immutable            bool
+	MaxAge               time.Duration
+	SharedMaxAge         time.Duration
+	StaleWhileRevalidate time.Duration
+	StaleIfError         time.Duration
+}
+
+// String renders the policy as a Cache-Control value.
+func (p CachePolicy) String() string {
+	directives := make([]string, 0)
+
+	flags := []struct {
+		set  bool
+		name string
+	}{
+		{p.Public, "public"},
+		{p.Private, "private"},
+		{p.NoCache, "no-cache"},
+		{p.NoStore, "no-store"},
+		{p.NoTransform, "no-transform"},
+		{p.MustRevalidate, "must-revalidate"},
+		{p.Immutable, "immutable"},
+	}
+
+	for _, flag := range flags {
+		if flag.set {
+			directives = append(directives, flag.name)
+		}
+	}
+
+	ages := []struct {
+		value time.Duration
+		name  string
+	}{
+		{p.MaxAge, "max-age"},
+		{p.SharedMaxAge, "s-maxage"},
+		{p.StaleWhileRevalidate, "stale-while-revalidate"},
+		{p.StaleIfError, "stale-if-error"},
+	}
+
+	for _, age := range ages {
+		if age.value > 0 {
+			directives = append(directives, age.name+"="+strconv.FormatInt(int64(age.value/time.Second), 10))
+		}
+	}
+
+	return strings.Join(directives, ", ")
+}
+
+// CacheControl returns a Fiber middleware that sets Cache-Control on successful responses
+// of the routes it is registered on, unless the handler already set one.
+func CacheControl(policy CachePolicy) fiber.Handler {
+	value := policy.String()
+
+	return func(c fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		response := c.Response()
+		if value == "" || response.StatusCode() >= http.StatusBadRequest ||
+			len(response.Header.Peek(fiber.HeaderCacheControl)) > 0 {
+			return nil
+		}
+
+		response.Header.Set(fiber.HeaderCacheControl, value)
+
+		return nil
+	}
+}
+
+func isWeakETag(etag string) bool {
+	return strings.HasPrefix(etag, weakETagPrefix)
+}
+
+func opaqueTag(etag string) string {
+	return strings.TrimPrefix(etag, weakETagPrefix)
+}
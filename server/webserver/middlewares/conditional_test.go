@@ -0,0 +1,255 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestETag(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"ok":true}`
+
+	strong := GenerateETag([]byte(body), false)
+	modified := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		name         string
+		cfg          ETagConfig
+		method       string
+		headers      map[string]string
+		lastModified bool
+		status       int
+		wantStatus   int
+		wantETag     string
+		wantBody     string
+	}{
+		{name: "strong etag", method: http.MethodGet, wantStatus: http.StatusOK, wantETag: strong, wantBody: body},
+		{
+			name:       "weak etag",
+			cfg:        ETagConfig{Weak: true},
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantETag:   weakETagPrefix + strong,
+			wantBody:   body,
+		},
+		{
+			name:       "if none match",
+			method:     http.MethodGet,
+			headers:    map[string]string{fiber.HeaderIfNoneMatch: `"other", ` + strong},
+			wantStatus: http.StatusNotModified,
+			wantETag:   strong,
+		},
+		{
+			name:       "weak comparison",
+			method:     http.MethodGet,
+			headers:    map[string]string{fiber.HeaderIfNoneMatch: weakETagPrefix + strong},
+			wantStatus: http.StatusNotModified,
+			wantETag:   strong,
+		},
+		{
+			name:       "if none match differs",
+			method:     http.MethodGet,
+			headers:    map[string]string{fiber.HeaderIfNoneMatch: `"other"`},
+			wantStatus: http.StatusOK,
+			wantETag:   strong,
+			wantBody:   body,
+		},
+		{
+			name:         "if modified since",
+			method:       http.MethodGet,
+			headers:      map[string]string{fiber.HeaderIfModifiedSince: modified.Format(http.TimeFormat)},
+			lastModified: true,
+			wantStatus:   http.StatusNotModified,
+			wantETag:     strong,
+		},
+		{
+			name:         "modified after",
+			method:       http.MethodGet,
+			headers:      map[string]string{fiber.HeaderIfModifiedSince: modified.Add(-time.Hour).Format(http.TimeFormat)},
+			lastModified: true,
+			wantStatus:   http.StatusOK,
+			wantETag:     strong,
+			wantBody:     body,
+		},
+		{
+			name:   "if none match takes precedence",
+			method: http.MethodGet,
+			headers: map[string]string{
+				fiber.HeaderIfNoneMatch:     `"other"`,
+				fiber.HeaderIfModifiedSince: modified.Format(http.TimeFormat),
+			},
+			lastModified: true,
+			wantStatus:   http.StatusOK,
+			wantETag:     strong,
+			wantBody:     body,
+		},
+		{name: "post is skipped", method: http.MethodPost, wantStatus: http.StatusOK, wantBody: body},
+		{
+			name:       "error status is skipped",
+			method:     http.MethodGet,
+			status:     http.StatusNotFound,
+			headers:    map[string]string{fiber.HeaderIfNoneMatch: "*"},
+			wantStatus: http.StatusNotFound,
+			wantBody:   body,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := fiber.New()
+			app.Use(ETag(test.cfg))
+			app.Add([]string{test.method}, "/", func(c fiber.Ctx) error {
+				if test.lastModified {
+					c.Set(fiber.HeaderLastModified, modified.Format(http.TimeFormat))
+				}
+
+				if test.status != 0 {
+					c.Status(test.status)
+				}
+
+				return c.SendString(body)
+			})
+
+			req := httptest.NewRequest(test.method, "/", nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+
+			if resp.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+
+			if resp.Header.Get(fiber.HeaderETag) != test.wantETag {
+				t.Fatalf("ETag = %q, want %q", resp.Header.Get(fiber.HeaderETag), test.wantETag)
+			}
+
+			if string(got) != test.wantBody {
+				t.Fatalf("body = %q, want %q", got, test.wantBody)
+			}
+		})
+	}
+}
+
+func TestCompressWithETag(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("x", 4096)
+
+	app := fiber.New()
+	app.Use(Compress(CompressConfig{}))
+	app.Use(ETag(ETagConfig{}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(body)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAcceptEncoding, EncodingGzip)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+
+	etag := resp.Header.Get(fiber.HeaderETag)
+	if etag != weakETagPrefix+GenerateETag([]byte(body), false) {
+		t.Fatalf("ETag = %q", etag)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAcceptEncoding, EncodingGzip)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotModified)
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		policy   CachePolicy
+		preset   string
+		status   int
+		expected string
+	}{
+		{
+			name:     "public max age",
+			policy:   CachePolicy{Public: true, MaxAge: time.Minute, StaleWhileRevalidate: 30 * time.Second},
+			expected: "public, max-age=60, stale-while-revalidate=30",
+		},
+		{
+			name:     "no store",
+			policy:   CachePolicy{Private: true, NoStore: true},
+			expected: "private, no-store",
+		},
+		{
+			name:     "handler value wins",
+			policy:   CachePolicy{Public: true, MaxAge: time.Hour},
+			preset:   "no-cache",
+			expected: "no-cache",
+		},
+		{
+			name:   "error responses are skipped",
+			policy: CachePolicy{Public: true, MaxAge: time.Hour},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := fiber.New()
+			app.Get("/", CacheControl(test.policy), func(c fiber.Ctx) error {
+				if test.preset != "" {
+					c.Set(fiber.HeaderCacheControl, test.preset)
+				}
+
+				if test.status != 0 {
+					c.Status(test.status)
+				}
+
+				return c.SendString("ok")
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			resp.Body.Close()
+
+			if got := resp.Header.Get(fiber.HeaderCacheControl); got != test.expected {
+				t.Fatalf("Cache-Control = %q, want %q", got, test.expected)
+			}
+		})
+	}
+}
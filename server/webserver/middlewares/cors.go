@@ -1,10 +1,44 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
 )
 
+// DefaultCORSMaxAge is how long browsers may cache a preflight response.
+const DefaultCORSMaxAge = time.Hour
+
+const corsAnyOrigin = "*"
+
+var ErrCORSCredentialsWithWildcard = errors.New("cors: credentials cannot be allowed for any origin")
+
+// DefaultCORSMethods are the methods allowed when CORSConfig.AllowMethods is empty.
+var DefaultCORSMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// CORSConfig configures a Fiber CORS policy.
+type CORSConfig struct {
+	// AllowOrigins lists exact origins such as https://app.example.com, subdomain
+	// patterns such as https://*.example.com, or "*" for any origin.
+	AllowOrigins []string
+	// AllowOriginFunc allows origins not matched by AllowOrigins.
+	AllowOriginFunc func(origin string) bool
+	// AllowMethods defaults to DefaultCORSMethods.
+	AllowMethods []string
+	// AllowHeaders lists request headers allowed in preflight; empty echoes the requested headers.
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	// MaxAge is the preflight cache lifetime; zero uses DefaultCORSMaxAge and a negative value disables caching.
+	MaxAge time.Duration
+}
+
 // CORSMiddleware implement cors request
 func CORSMiddleware(methods ...string) http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
@@ -15,3 +49,120 @@ func CORSMiddleware(methods ...string) http.HandlerFunc {
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// CORS returns a Fiber middleware enforcing cfg.
+//
+// Preflight requests from allowed origins are answered with 204; requests from
+// other origins get no CORS headers and are left for the browser to block.
+func CORS(cfg CORSConfig) (fiber.Handler, error) {
+	anyOrigin := false
+	origins := make([]string, 0, len(cfg.AllowOrigins))
+
+	for _, origin := range cfg.AllowOrigins {
+		origin = normalizeOrigin(origin)
+		if origin == corsAnyOrigin {
+			anyOrigin = true
+		}
+
+		origins = append(origins, origin)
+	}
+
+	if anyOrigin && cfg.AllowCredentials {
+		return nil, ErrCORSCredentialsWithWildcard
+	}
+
+	methods := cfg.AllowMethods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ", ")
+
+	maxAge := cfg.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultCORSMaxAge
+	}
+
+	allowed := func(origin string) bool {
+		if anyOrigin || originAllowed(origins, normalizeOrigin(origin)) {
+			return true
+		}
+
+		return cfg.AllowOriginFunc != nil && cfg.AllowOriginFunc(origin)
+	}
+
+	return func(c fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		preflight := c.Method() == http.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) != ""
+
+		c.Vary(fiber.HeaderOrigin)
+
+		if origin == "" || !allowed(origin) {
+			if preflight {
+				return c.SendStatus(http.StatusNoContent)
+			}
+
+			return c.Next()
+		}
+
+		if anyOrigin {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, corsAnyOrigin)
+		} else {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+		}
+
+		if cfg.AllowCredentials {
+			c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+		}
+
+		if !preflight {
+			if exposeHeaders != "" {
+				c.Set(fiber.HeaderAccessControlExposeHeaders, exposeHeaders)
+			}
+
+			return c.Next()
+		}
+
+		c.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+		c.Set(fiber.HeaderAccessControlAllowMethods, allowMethods)
+
+		if allowHeaders != "" {
+			c.Set(fiber.HeaderAccessControlAllowHeaders, allowHeaders)
+		} else if requested := c.Get(fiber.HeaderAccessControlRequestHeaders); requested != "" {
+			c.Set(fiber.HeaderAccessControlAllowHeaders, requested)
+		}
+
+		if maxAge > 0 {
+			c.Set(fiber.HeaderAccessControlMaxAge, strconv.Itoa(int(maxAge/time.Second)))
+		}
+
+		return c.SendStatus(http.StatusNoContent)
+	}, nil
+}
+
+func originAllowed(patterns []string, origin string) bool {
+	for _, pattern := range patterns {
+		if pattern == origin {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(pattern, "://*.")
+		if !ok {
+			continue
+		}
+
+		prefix := scheme + "://"
+		if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, "."+host) &&
+			len(origin) > len(prefix)+len(host)+1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeOrigin(origin string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+}
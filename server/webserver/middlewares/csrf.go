@@ -0,0 +1,210 @@
+package middlewares
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// CSRF defaults.
+const (
+	CSRFTokenValueKey     = "csrf_token"
+	DefaultCSRFCookieName = "csrf_token"
+	DefaultCSRFHeaderName = "X-CSRF-Token"
+	DefaultCSRFFormField  = "_csrf"
+	DefaultCSRFCookieTTL  = 12 * time.Hour
+
+	csrfTokenSize = 32
+)
+
+// CSRF errors returned to the Fiber error handler.
+var (
+	ErrCSRFTokenMissing = fiber.NewError(fiber.StatusForbidden, "csrf: token missing")
+	ErrCSRFTokenInvalid = fiber.NewError(fiber.StatusForbidden, "csrf: token invalid")
+	ErrCSRFOrigin       = fiber.NewError(fiber.StatusForbidden, "csrf: origin not allowed")
+)
+
+// CSRFConfig configures double-submit cookie CSRF protection.
+type CSRFConfig struct {
+	// Key signs tokens with HMAC-SHA256 so a cookie planted by a sibling subdomain is
+	// rejected; empty uses unsigned random tokens.
+	Key []byte
+	// CookieName, HeaderName and FormField default to the DefaultCSRF values.
+	CookieName string
+	HeaderName string
+	FormField  string
+	CookiePath string
+	// CookieDomain scopes the cookie; leave empty to keep it host-only.
+	CookieDomain string
+	// CookieSecure is always on for HTTPS requests.
+	CookieSecure bool
+	// CookieHTTPOnly hides the cookie from scripts; enable it when tokens are only
+	// rendered into templates.
+	CookieHTTPOnly bool
+	// CookieSameSite defaults to Lax.
+	CookieSameSite string
+	// CookieTTL defaults to DefaultCSRFCookieTTL.
+	CookieTTL time.Duration
+	// TrustedOrigins lists extra origins, such as https://admin.example.com, allowed
+	// to submit unsafe requests in addition to the request host.
+	TrustedOrigins []string
+	// Next skips the middleware when it returns true.
+	Next func(c fiber.Ctx) bool
+}
+
+// CSRF returns a Fiber middleware implementing the double-submit cookie pattern.
+//
+// Safe requests (GET, HEAD, OPTIONS, TRACE) get a token cookie and the token in
+// CSRFTokenValueKey for templates. Unsafe requests must echo the cookie value in the
+// header or form field and, when Origin or Referer is sent, come from the request
+// host or a trusted origin.
+func CSRF(cfg CSRFConfig) fiber.Handler {
+	cookieName := valueOr(cfg.CookieName, DefaultCSRFCookieName)
+	headerName := valueOr(cfg.HeaderName, DefaultCSRFHeaderName)
+	formField := valueOr(cfg.FormField, DefaultCSRFFormField)
+	cookiePath := valueOr(cfg.CookiePath, "/")
+	sameSite := valueOr(cfg.CookieSameSite, fiber.CookieSameSiteLaxMode)
+
+	ttl := cfg.CookieTTL
+	if ttl <= 0 {
+		ttl = DefaultCSRFCookieTTL
+	}
+
+	trusted := make([]string, 0, len(cfg.TrustedOrigins))
+	for _, origin := range cfg.TrustedOrigins {
+		trusted = append(trusted, normalizeOrigin(origin))
+	}
+
+	return func(c fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		cookie := c.Cookies(cookieName)
+		valid := cookie != "" && csrfTokenValid(cfg.Key, cookie)
+
+		if csrfSafeMethod(c.Method()) {
+			token := cookie
+			if !valid {
+				token = newCSRFToken(cfg.Key)
+			}
+
+			c.Cookie(&fiber.Cookie{
+				Name:     cookieName,
+				Value:    token,
+				Path:     cookiePath,
+				Domain:   cfg.CookieDomain,
+				MaxAge:   int(ttl / time.Second),
+				Expires:  time.Now().Add(ttl),
+				Secure:   cfg.CookieSecure || c.Scheme() == schemeHTTPS,
+				HTTPOnly: cfg.CookieHTTPOnly,
+				SameSite: sameSite,
+			})
+			c.Locals(CSRFTokenValueKey, token)
+
+			return c.Next()
+		}
+
+		if !csrfOriginAllowed(c, trusted) {
+			return ErrCSRFOrigin
+		}
+
+		submitted := c.Get(headerName)
+		if submitted == "" {
+			submitted = c.FormValue(formField)
+		}
+
+		if cookie == "" || submitted == "" {
+			return ErrCSRFTokenMissing
+		}
+
+		if !valid || subtle.ConstantTimeCompare([]byte(cookie), []byte(submitted)) != 1 {
+			return ErrCSRFTokenInvalid
+		}
+
+		c.Locals(CSRFTokenValueKey, cookie)
+
+		return c.Next()
+	}
+}
+
+// CSRFToken returns the token stored by CSRF for rendering in forms.
+func CSRFToken(c fiber.Ctx) string {
+	token, _ := c.Locals(CSRFTokenValueKey).(string)
+
+	return token
+}
+
+func csrfSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+func csrfOriginAllowed(c fiber.Ctx, trusted []string) bool {
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" || origin == "null" {
+		referer, err := url.Parse(c.Get(fiber.HeaderReferer))
+		if err != nil || referer.Host == "" {
+			return origin == ""
+		}
+
+		origin = referer.Scheme + "://" + referer.Host
+	}
+
+	origin = normalizeOrigin(origin)
+	if origin == normalizeOrigin(c.Scheme()+"://"+c.Host()) {
+		return true
+	}
+
+	for _, allowed := range trusted {
+		if allowed == origin {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newCSRFToken(key []byte) string {
+	raw := make([]byte, csrfTokenSize)
+	_, _ = rand.Read(raw)
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	if len(key) == 0 {
+		return token
+	}
+
+	return token + "." + csrfSignature(key, token)
+}
+
+func csrfTokenValid(key []byte, token string) bool {
+	if len(key) == 0 {
+		return len(token) >= base64.RawURLEncoding.EncodedLen(csrfTokenSize)
+	}
+
+	value, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(csrfSignature(key, value)))
+}
+
+func csrfSignature(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,164 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func newCSRFTestApp(cfg CSRFConfig) *fiber.App {
+	app := fiber.New()
+	app.Use(CSRF(cfg))
+	app.Get("/form", func(c fiber.Ctx) error {
+		return c.SendString(CSRFToken(c))
+	})
+	app.Post("/form", func(c fiber.Ctx) error {
+		return c.SendString("saved")
+	})
+
+	return app
+}
+
+func csrfCookie(t *testing.T, app *fiber.App) (string, string) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "http://example.test/form", nil))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == DefaultCSRFCookieName {
+			return cookie.Value, string(body)
+		}
+	}
+
+	t.Fatal("csrf cookie is not set")
+
+	return "", ""
+}
+
+func TestCSRF(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		cfg        CSRFConfig
+		cookie     func(token string) string
+		header     func(token string) string
+		form       bool
+		origin     string
+		wantStatus int
+	}{
+		{
+			name:       "header token",
+			header:     func(token string) string { return token },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "form token",
+			form:       true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "signed token",
+			cfg:        CSRFConfig{Key: []byte("secret")},
+			header:     func(token string) string { return token },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing token",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "mismatched token",
+			header:     func(string) string { return strings.Repeat("a", 43) },
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "planted unsigned cookie with signing key",
+			cfg:        CSRFConfig{Key: []byte("secret")},
+			cookie:     func(string) string { return strings.Repeat("b", 43) },
+			header:     func(string) string { return strings.Repeat("b", 43) },
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "same origin",
+			header:     func(token string) string { return token },
+			origin:     "http://example.test",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "trusted origin",
+			cfg:        CSRFConfig{TrustedOrigins: []string{"https://admin.example.test"}},
+			header:     func(token string) string { return token },
+			origin:     "https://admin.example.test",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "cross origin",
+			header:     func(token string) string { return token },
+			origin:     "https://evil.test",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := newCSRFTestApp(test.cfg)
+			token, rendered := csrfCookie(t, app)
+
+			if token != rendered {
+				t.Fatalf("rendered token = %q, want cookie value %q", rendered, token)
+			}
+
+			cookie := token
+			if test.cookie != nil {
+				cookie = test.cookie(token)
+			}
+
+			var body io.Reader
+			if test.form {
+				body = strings.NewReader(url.Values{DefaultCSRFFormField: {token}}.Encode())
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.test/form", body)
+			req.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: cookie})
+
+			if test.form {
+				req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+			}
+
+			if test.header != nil {
+				req.Header.Set(DefaultCSRFHeaderName, test.header(token))
+			}
+
+			if test.origin != "" {
+				req.Header.Set(fiber.HeaderOrigin, test.origin)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+		})
+	}
+}
@@ -1,57 +1,322 @@
 package middlewares
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/go-jose/go-jose/v3"
 	"github.com/gofiber/fiber/v3"
 	"golang.org/x/crypto/hkdf"
+
+	"github.com/InsideGallery/core/pki/rsaoaep"
 )
 
 const (
 	DecryptValueKey  = "decrypted_body"
 	ResponseValueKey = "response_body"
+	KeyIDValueKey    = "jwe_key_id"
 
 	HeaderJOSE = "application/jose"
+	// HeaderJWEResponseKey carries the client public JWK used to encrypt responses.
+	HeaderJWEResponseKey = "X-JWE-Response-Key"
+)
+
+// All kind of JWE errors
+var (
+	ErrJWEPlaintextBody      = fiber.NewError(fiber.StatusUnsupportedMediaType, "jwe: encrypted request body required")
+	ErrJWEUnknownKeyID       = fiber.NewError(fiber.StatusUnauthorized, "jwe: unknown key id")
+	ErrJWEAlgorithmMismatch  = fiber.NewError(fiber.StatusUnauthorized, "jwe: key algorithm mismatch")
+	ErrJWEInvalidResponseKey = fiber.NewError(fiber.StatusBadRequest, "jwe: invalid response key")
+	ErrJWEDecryptionKey      = errors.New("jwe: decryption key is not configured")
+	ErrJWEResponseKey        = errors.New("jwe: response encryption key is not available")
+	ErrJWEKeyID              = errors.New("jwe: key id is required")
+	ErrJWEUnsupportedKey     = errors.New("jwe: unsupported key type for algorithm")
 )
 
+// JWEKey is a decryption key with the key management algorithm it accepts.
+//
+// Key holds []byte for dir, *rsa.PrivateKey for RSA-OAEP-256 and
+// *ecdsa.PrivateKey for the ECDH-ES family.
+type JWEKey struct {
+	ID        string
+	Algorithm jose.KeyAlgorithm
+	Key       any
+}
+
+// NewDirectKey returns a dir key for a shared content encryption key.
+func NewDirectKey(id string, secret []byte) JWEKey {
+	return JWEKey{
+		ID:        id,
+		Algorithm: jose.DIRECT,
+		Key:       secret,
+	}
+}
+
+// NewRSAOAEPKey returns an RSA-OAEP-256 key backed by a pki/rsaoaep cipher.
+func NewRSAOAEPKey(id string, cipher *rsaoaep.Cipher) (JWEKey, error) {
+	privateKey, err := rsaoaep.PrivateKey(cipher)
+	if err != nil {
+		return JWEKey{}, err
+	}
+
+	return JWEKey{
+		ID:        id,
+		Algorithm: jose.RSA_OAEP_256,
+		Key:       privateKey,
+	}, nil
+}
+
+// NewECDHKey returns an ECDH-ES key agreement key; alg is ECDH-ES or one of its key wrap variants.
+func NewECDHKey(id string, alg jose.KeyAlgorithm, privateKey *ecdsa.PrivateKey) (JWEKey, error) {
+	key := JWEKey{
+		ID:        id,
+		Algorithm: alg,
+		Key:       privateKey,
+	}
+
+	if err := key.validate(); err != nil {
+		return JWEKey{}, err
+	}
+
+	return key, nil
+}
+
+// PublicJWK returns the public part of an asymmetric key; dir keys have none.
+func (k JWEKey) PublicJWK() (jose.JSONWebKey, bool) {
+	var public any
+
+	switch key := k.Key.(type) {
+	case *rsa.PrivateKey:
+		public = &key.PublicKey
+	case *ecdsa.PrivateKey:
+		public = &key.PublicKey
+	default:
+		return jose.JSONWebKey{}, false
+	}
+
+	return jose.JSONWebKey{
+		Key:       public,
+		KeyID:     k.ID,
+		Algorithm: string(k.Algorithm),
+		Use:       "enc",
+	}, true
+}
+
+func (k JWEKey) validate() error {
+	var ok bool
+
+	switch k.Algorithm {
+	case jose.DIRECT:
+		_, ok = k.Key.([]byte)
+	case jose.RSA_OAEP_256:
+		_, ok = k.Key.(*rsa.PrivateKey)
+	case jose.ECDH_ES, jose.ECDH_ES_A128KW, jose.ECDH_ES_A192KW, jose.ECDH_ES_A256KW:
+		_, ok = k.Key.(*ecdsa.PrivateKey)
+	}
+
+	if !ok {
+		return ErrJWEUnsupportedKey
+	}
+
+	return nil
+}
+
+// JWEKeyRing holds decryption keys by kid and tracks the primary key.
+//
+// Rotation adds a new primary key while older keys keep decrypting requests
+// until they are retired.
+type JWEKeyRing struct {
+	mu      sync.RWMutex
+	keys    map[string]JWEKey
+	primary string
+}
+
+// NewJWEKeyRing returns a key ring; the first key becomes primary.
+func NewJWEKeyRing(keys ...JWEKey) (*JWEKeyRing, error) {
+	ring := &JWEKeyRing{
+		keys: make(map[string]JWEKey, len(keys)),
+	}
+
+	for _, key := range keys {
+		if err := ring.Add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return ring, nil
+}
+
+// Add stores a key without changing the primary key unless none is set.
+func (r *JWEKeyRing) Add(key JWEKey) error {
+	if key.ID == "" {
+		return ErrJWEKeyID
+	}
+
+	if err := key.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[key.ID] = key
+	if r.primary == "" {
+		r.primary = key.ID
+	}
+
+	return nil
+}
+
+// Rotate stores a key and makes it primary.
+func (r *JWEKeyRing) Rotate(key JWEKey) error {
+	if err := r.Add(key); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.primary = key.ID
+
+	return nil
+}
+
+// Retire removes a key; retiring the primary key leaves the ring without one.
+func (r *JWEKeyRing) Retire(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.keys, id)
+
+	if r.primary == id {
+		r.primary = ""
+	}
+}
+
+// Key returns the key with the given kid.
+func (r *JWEKeyRing) Key(id string) (JWEKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+
+	return key, ok
+}
+
+// Primary returns the current primary key.
+func (r *JWEKeyRing) Primary() (JWEKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[r.primary]
+
+	return key, ok
+}
+
+// PublicKeySet returns public JWKs for all asymmetric keys, for publishing to clients.
+func (r *JWEKeyRing) PublicKeySet() jose.JSONWebKeySet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := jose.JSONWebKeySet{}
+
+	for _, key := range r.keys {
+		if public, ok := key.PublicJWK(); ok {
+			set.Keys = append(set.Keys, public)
+		}
+	}
+
+	return set
+}
+
+// JWEConfig configures JWE request decryption and response encryption.
+type JWEConfig struct {
+	// KeyGetter returns a dir key for requests that are not resolved by KeyRing.
+	KeyGetter func(c fiber.Ctx) ([]byte, error)
+	// KeyRing resolves decryption keys by the kid header.
+	KeyRing *JWEKeyRing
+	// ResponseKeyGetter returns the client public key responses are encrypted to.
+	// A nil key falls back to the request dir key.
+	ResponseKeyGetter func(c fiber.Ctx) (*jose.JSONWebKey, error)
+	// ContentEncryption defaults to A256GCM.
+	ContentEncryption jose.ContentEncryption
+	// Strict rejects empty and plaintext request bodies.
+	Strict bool
+}
+
 type JWE struct {
 	decryptionKeyGetter func(c fiber.Ctx) ([]byte, error)
+	responseKeyGetter   func(c fiber.Ctx) (*jose.JSONWebKey, error)
+	keyRing             *JWEKeyRing
+	contentEncryption   jose.ContentEncryption
+	strict              bool
 }
 
 func NewJWE(decryptionKeyGetter func(c fiber.Ctx) ([]byte, error)) *JWE {
+	return NewJWEWithConfig(JWEConfig{
+		KeyGetter: decryptionKeyGetter,
+	})
+}
+
+// NewJWEWithConfig returns JWE middleware with key ring, strict mode and response key support.
+func NewJWEWithConfig(cfg JWEConfig) *JWE {
+	enc := cfg.ContentEncryption
+	if enc == "" {
+		enc = jose.A256GCM
+	}
+
 	return &JWE{
-		decryptionKeyGetter: decryptionKeyGetter,
+		decryptionKeyGetter: cfg.KeyGetter,
+		responseKeyGetter:   cfg.ResponseKeyGetter,
+		keyRing:             cfg.KeyRing,
+		contentEncryption:   enc,
+		strict:              cfg.Strict,
 	}
 }
 
 func (j *JWE) DecryptMiddleware(c fiber.Ctx) error {
-	// 1. Отримуємо тіло запиту (очікуємо рядок JWE)
-	jweString := string(c.Body())
+	jweString := strings.TrimSpace(string(c.Body()))
 	if jweString == "" {
+		if j.strict {
+			return ErrJWEPlaintextBody
+		}
+
 		return c.Next()
 	}
 
-	decryptionKey, err := j.decryptionKeyGetter(c)
+	jweObject, err := jose.ParseEncrypted(jweString)
 	if err != nil {
+		if j.strict {
+			return ErrJWEPlaintextBody
+		}
+
 		return err
 	}
 
-	// 2. Парсимо JWE
-	jweObject, err := jose.ParseEncrypted(jweString)
+	key, err := j.decryptionKey(c, jweObject.Header)
 	if err != nil {
 		return err
 	}
 
-	// 3. Розшифровуємо за допомогою приватного ключа сервера
-	decryptedPayload, err := jweObject.Decrypt(decryptionKey)
+	if jweObject.Header.Algorithm != string(key.Algorithm) {
+		return ErrJWEAlgorithmMismatch
+	}
+
+	decryptedPayload, err := jweObject.Decrypt(key.Key)
 	if err != nil {
 		return err
 	}
 
 	c.Locals(DecryptValueKey, decryptedPayload)
+	c.Locals(KeyIDValueKey, key.ID)
 
 	err = c.Next()
 	if err != nil {
@@ -60,7 +325,7 @@ func (j *JWE) DecryptMiddleware(c fiber.Ctx) error {
 
 	resp, ok := c.Locals(ResponseValueKey).([]byte)
 	if ok && len(resp) != 0 {
-		result, err := EncryptResponse(decryptionKey, resp)
+		result, err := j.encryptResponse(c, key, resp)
 		if err != nil {
 			return err
 		}
@@ -76,12 +341,129 @@ func (j *JWE) DecryptMiddleware(c fiber.Ctx) error {
 	return nil
 }
 
+func (j *JWE) decryptionKey(c fiber.Ctx, header jose.Header) (JWEKey, error) {
+	if j.keyRing != nil && header.KeyID != "" {
+		if key, ok := j.keyRing.Key(header.KeyID); ok {
+			return key, nil
+		}
+	}
+
+	if j.decryptionKeyGetter != nil {
+		secret, err := j.decryptionKeyGetter(c)
+		if err != nil {
+			return JWEKey{}, err
+		}
+
+		return NewDirectKey(header.KeyID, secret), nil
+	}
+
+	if j.keyRing == nil {
+		return JWEKey{}, ErrJWEDecryptionKey
+	}
+
+	if header.KeyID != "" {
+		return JWEKey{}, ErrJWEUnknownKeyID
+	}
+
+	key, ok := j.keyRing.Primary()
+	if !ok {
+		return JWEKey{}, ErrJWEDecryptionKey
+	}
+
+	return key, nil
+}
+
+func (j *JWE) encryptResponse(c fiber.Ctx, key JWEKey, payload []byte) (string, error) {
+	if j.responseKeyGetter != nil {
+		responseKey, err := j.responseKeyGetter(c)
+		if err != nil {
+			return "", err
+		}
+
+		if responseKey != nil {
+			return encryptForKey(j.contentEncryption, responseKey, payload)
+		}
+	}
+
+	if key.Algorithm != jose.DIRECT {
+		return "", ErrJWEResponseKey
+	}
+
+	return encrypt(j.contentEncryption, jose.Recipient{
+		Algorithm: jose.DIRECT,
+		Key:       key.Key,
+		KeyID:     key.ID,
+	}, payload)
+}
+
+// ResponseKeyFromHeader returns a ResponseKeyGetter that reads a public JWK from the header.
+//
+// The header value is either JSON or base64url-encoded JSON. A missing header
+// returns a nil key.
+func ResponseKeyFromHeader(name string) func(c fiber.Ctx) (*jose.JSONWebKey, error) {
+	return func(c fiber.Ctx) (*jose.JSONWebKey, error) {
+		raw := strings.TrimSpace(c.Get(name))
+		if raw == "" {
+			return nil, nil //nolint:nilnil // absent header means no client key
+		}
+
+		data := []byte(raw)
+		if !strings.HasPrefix(raw, "{") {
+			decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(raw, "="))
+			if err != nil {
+				return nil, ErrJWEInvalidResponseKey
+			}
+
+			data = decoded
+		}
+
+		key := new(jose.JSONWebKey)
+		if err := json.Unmarshal(data, key); err != nil {
+			return nil, ErrJWEInvalidResponseKey
+		}
+
+		if !key.Valid() || !key.IsPublic() {
+			return nil, ErrJWEInvalidResponseKey
+		}
+
+		return key, nil
+	}
+}
+
 func EncryptResponse(sesKey, payload []byte) (string, error) {
-	encrypter, err := jose.NewEncrypter(
-		jose.A256GCM,
-		jose.Recipient{Algorithm: jose.DIRECT, Key: sesKey},
-		nil,
-	)
+	return encrypt(jose.A256GCM, jose.Recipient{Algorithm: jose.DIRECT, Key: sesKey}, payload)
+}
+
+// EncryptResponseForKey encrypts payload with A256GCM to a client public JWK.
+//
+// The key algorithm comes from the JWK alg, or defaults to RSA-OAEP-256 for RSA
+// and ECDH-ES+A256KW for EC keys.
+func EncryptResponseForKey(key *jose.JSONWebKey, payload []byte) (string, error) {
+	return encryptForKey(jose.A256GCM, key, payload)
+}
+
+func encryptForKey(enc jose.ContentEncryption, key *jose.JSONWebKey, payload []byte) (string, error) {
+	alg := jose.KeyAlgorithm(key.Algorithm)
+	if alg == "" {
+		switch key.Key.(type) {
+		case *rsa.PublicKey:
+			alg = jose.RSA_OAEP_256
+		case *ecdsa.PublicKey:
+			alg = jose.ECDH_ES_A256KW
+		default:
+			return "", ErrJWEUnsupportedKey
+		}
+	}
+
+	return encrypt(enc, jose.Recipient{
+		Algorithm: alg,
+		Key:       key.Key,
+		KeyID:     key.KeyID,
+	}, payload)
+}
+
+func encrypt(enc jose.ContentEncryption, recipient jose.Recipient, payload []byte) (string, error) {
+	encrypter, err := jose.NewEncrypter(enc, recipient, nil)
 	if err != nil {
 		return "", err
 	}
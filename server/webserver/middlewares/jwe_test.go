@@ -2,6 +2,11 @@ package middlewares
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	"io"
 	"net/http"
 	"testing"
@@ -11,6 +16,7 @@ import (
 	"github.com/gofiber/fiber/v3"
 
 	"github.com/InsideGallery/core/pki/aes"
+	"github.com/InsideGallery/core/pki/rsaoaep"
 )
 
 func TestJWEAES(t *testing.T) {
@@ -61,3 +67,229 @@ func TestJWEAES(t *testing.T) {
 
 	testutils.Equal(t, string(decryptedResponse), responseStr)
 }
+
+func jweTestApp(t *testing.T, j *JWE, requestStr, responseStr string) *fiber.App {
+	t.Helper()
+
+	app := fiber.New()
+	app.Use(j.DecryptMiddleware)
+	app.Post("/", func(ctx fiber.Ctx) error {
+		data, _ := ctx.Locals(DecryptValueKey).([]byte)
+		if string(data) != requestStr {
+			return fiber.NewError(http.StatusTeapot, "unexpected request "+string(data))
+		}
+
+		ctx.Locals(ResponseValueKey, []byte(responseStr))
+
+		return nil
+	})
+
+	return app
+}
+
+func doJWERequest(t *testing.T, app *fiber.App, body string, header map[string]string) (int, string) {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := app.Test(req, fiber.TestConfig{Timeout: 0})
+	testutils.Equal(t, err, nil)
+
+	defer res.Body.Close()
+
+	result, err := io.ReadAll(res.Body)
+	testutils.Equal(t, err, nil)
+
+	return res.StatusCode, string(result)
+}
+
+func encryptTo(t *testing.T, alg jose.KeyAlgorithm, kid string, key any, payload string) string {
+	t.Helper()
+
+	raw, err := encrypt(jose.A256GCM, jose.Recipient{Algorithm: alg, Key: key, KeyID: kid}, []byte(payload))
+	testutils.Equal(t, err, nil)
+
+	return raw
+}
+
+func TestJWEKeyManagementModes(t *testing.T) {
+	serverRSA, err := rsaoaep.New(2048)
+	testutils.Equal(t, err, nil)
+
+	rsaKey, err := NewRSAOAEPKey("rsa-1", serverRSA)
+	testutils.Equal(t, err, nil)
+
+	serverEC, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutils.Equal(t, err, nil)
+
+	ecKey, err := NewECDHKey("ec-1", jose.ECDH_ES_A256KW, serverEC)
+	testutils.Equal(t, err, nil)
+
+	clientEC, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutils.Equal(t, err, nil)
+
+	clientJWK, err := json.Marshal(jose.JSONWebKey{Key: &clientEC.PublicKey, KeyID: "client"})
+	testutils.Equal(t, err, nil)
+
+	ring, err := NewJWEKeyRing(rsaKey, ecKey)
+	testutils.Equal(t, err, nil)
+
+	j := NewJWEWithConfig(JWEConfig{
+		KeyRing:           ring,
+		ResponseKeyGetter: ResponseKeyFromHeader(HeaderJWEResponseKey),
+	})
+	app := jweTestApp(t, j, "request", "response")
+
+	rsaPublic, err := rsaoaep.PublicKey(serverRSA)
+	testutils.Equal(t, err, nil)
+
+	cases := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "rsa oaep 256",
+			body: encryptTo(t, jose.RSA_OAEP_256, "rsa-1", rsaPublic, "request"),
+		},
+		{
+			name: "ecdh es a256kw",
+			body: encryptTo(t, jose.ECDH_ES_A256KW, "ec-1", &serverEC.PublicKey, "request"),
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			status, body := doJWERequest(t, app, test.body, map[string]string{
+				HeaderJWEResponseKey: base64.RawURLEncoding.EncodeToString(clientJWK),
+			})
+			testutils.Equal(t, status, http.StatusOK)
+
+			parsed, err := jose.ParseEncrypted(body)
+			testutils.Equal(t, err, nil)
+			testutils.Equal(t, parsed.Header.KeyID, "client")
+			testutils.Equal(t, parsed.Header.Algorithm, string(jose.ECDH_ES_A256KW))
+
+			decrypted, err := parsed.Decrypt(clientEC)
+			testutils.Equal(t, err, nil)
+			testutils.Equal(t, string(decrypted), "response")
+		})
+	}
+
+	t.Run("asymmetric request without response key fails closed", func(t *testing.T) {
+		status, body := doJWERequest(t, app, cases[1].body, nil)
+		testutils.Equal(t, status, http.StatusInternalServerError)
+		testutils.Equal(t, body, ErrJWEResponseKey.Error())
+	})
+
+	t.Run("private response key is rejected", func(t *testing.T) {
+		privateJWK, err := json.Marshal(jose.JSONWebKey{Key: clientEC})
+		testutils.Equal(t, err, nil)
+
+		status, _ := doJWERequest(t, app, cases[1].body, map[string]string{
+			HeaderJWEResponseKey: string(privateJWK),
+		})
+		testutils.Equal(t, status, http.StatusBadRequest)
+	})
+
+	t.Run("public key set excludes private parts", func(t *testing.T) {
+		set := ring.PublicKeySet()
+		testutils.Equal(t, len(set.Keys), 2)
+
+		for _, key := range set.Keys {
+			testutils.Equal(t, key.IsPublic(), true)
+		}
+	})
+}
+
+func TestJWEKeyRingRotation(t *testing.T) {
+	oldSecret := bytes.Repeat([]byte{1}, 32)
+	newSecret := bytes.Repeat([]byte{2}, 32)
+
+	ring, err := NewJWEKeyRing(NewDirectKey("old", oldSecret))
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, ring.Rotate(NewDirectKey("new", newSecret)), nil)
+
+	primary, ok := ring.Primary()
+	testutils.Equal(t, ok, true)
+	testutils.Equal(t, primary.ID, "new")
+
+	app := jweTestApp(t, NewJWEWithConfig(JWEConfig{KeyRing: ring}), "request", "response")
+
+	cases := []struct {
+		name    string
+		kid     string
+		secret  []byte
+		wantKey []byte
+	}{
+		{name: "old key still decrypts", kid: "old", secret: oldSecret, wantKey: oldSecret},
+		{name: "new key decrypts", kid: "new", secret: newSecret, wantKey: newSecret},
+		{name: "missing kid uses primary", secret: newSecret, wantKey: newSecret},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			status, body := doJWERequest(t, app, encryptTo(t, jose.DIRECT, test.kid, test.secret, "request"), nil)
+			testutils.Equal(t, status, http.StatusOK)
+
+			parsed, err := jose.ParseEncrypted(body)
+			testutils.Equal(t, err, nil)
+
+			decrypted, err := parsed.Decrypt(test.wantKey)
+			testutils.Equal(t, err, nil)
+			testutils.Equal(t, string(decrypted), "response")
+		})
+	}
+
+	ring.Retire("old")
+
+	status, _ := doJWERequest(t, app, encryptTo(t, jose.DIRECT, "old", oldSecret, "request"), nil)
+	testutils.Equal(t, status, http.StatusUnauthorized)
+}
+
+func TestJWEStrictAndValidation(t *testing.T) {
+	secret := bytes.Repeat([]byte{3}, 32)
+
+	ring, err := NewJWEKeyRing(NewDirectKey("dir", secret))
+	testutils.Equal(t, err, nil)
+
+	strict := jweTestApp(t, NewJWEWithConfig(JWEConfig{KeyRing: ring, Strict: true}), "request", "response")
+	lenient := jweTestApp(t, NewJWE(func(_ fiber.Ctx) ([]byte, error) {
+		return secret, nil
+	}), "", "")
+
+	serverEC, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutils.Equal(t, err, nil)
+
+	cases := []struct {
+		name       string
+		app        *fiber.App
+		body       string
+		wantStatus int
+	}{
+		{name: "strict rejects empty body", app: strict, body: "", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "strict rejects plaintext", app: strict, body: `{"a":1}`, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "lenient passes empty body", app: lenient, body: "", wantStatus: http.StatusOK},
+		{
+			name:       "algorithm mismatch is rejected",
+			app:        strict,
+			body:       encryptTo(t, jose.ECDH_ES, "dir", &serverEC.PublicKey, "request"),
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			status, _ := doJWERequest(t, test.app, test.body, nil)
+			testutils.Equal(t, status, test.wantStatus)
+		})
+	}
+
+	_, err = NewJWEKeyRing(JWEKey{ID: "bad", Algorithm: jose.RSA_OAEP_256, Key: secret})
+	testutils.Equal(t, err, ErrJWEUnsupportedKey)
+
+	_, err = NewJWEKeyRing(NewDirectKey("", secret))
+	testutils.Equal(t, err, ErrJWEKeyID)
+}
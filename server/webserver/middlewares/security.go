@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// HeaderCrossOriginOpenerPolicy isolates the browsing context from cross-origin popups.
+const HeaderCrossOriginOpenerPolicy = "Cross-Origin-Opener-Policy"
+
+// Security header defaults.
+const (
+	DefaultFrameOptions   = "DENY"
+	DefaultReferrerPolicy = "strict-origin-when-cross-origin"
+	DefaultHSTSMaxAge     = 365 * 24 * time.Hour
+
+	// SecurityHeaderDisabled turns off a header that has a default value.
+	SecurityHeaderDisabled = "-"
+
+	schemeHTTPS = "https"
+)
+
+// SecurityHeadersConfig configures SecurityHeaders; empty string fields use the defaults.
+type SecurityHeadersConfig struct {
+	// HSTSMaxAge is sent on HTTPS requests only; zero uses DefaultHSTSMaxAge and a negative value disables HSTS.
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	// ContentSecurityPolicy is sent when set; CSPReportOnly sends the policy in report-only mode instead.
+	ContentSecurityPolicy string
+	CSPReportOnly         bool
+	// FrameOptions is the X-Frame-Options value; defaults to DefaultFrameOptions.
+	FrameOptions string
+	// ReferrerPolicy defaults to DefaultReferrerPolicy.
+	ReferrerPolicy          string
+	PermissionsPolicy       string
+	CrossOriginOpenerPolicy string
+	// Next skips the middleware when it returns true.
+	Next func(c fiber.Ctx) bool
+}
+
+// SecurityHeaders returns a Fiber middleware that sets HSTS, CSP, framing, referrer and
+// content-type sniffing headers before the handler runs, so error responses carry them too.
+//
+// HSTS is only sent when c.Scheme() is https; behind a proxy that relies on the
+// server trusting the proxy, see webserver.Config.TrustedProxies.
+func SecurityHeaders(cfg SecurityHeadersConfig) fiber.Handler {
+	hsts := ""
+
+	if cfg.HSTSMaxAge >= 0 {
+		maxAge := cfg.HSTSMaxAge
+		if maxAge == 0 {
+			maxAge = DefaultHSTSMaxAge
+		}
+
+		hsts = "max-age=" + strconv.FormatInt(int64(maxAge/time.Second), 10)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	cspHeader := fiber.HeaderContentSecurityPolicy
+	if cfg.CSPReportOnly {
+		cspHeader = fiber.HeaderContentSecurityPolicyReportOnly
+	}
+
+	headers := []struct {
+		name  string
+		value string
+	}{
+		{fiber.HeaderXContentTypeOptions, "nosniff"},
+		{fiber.HeaderXFrameOptions, valueOr(cfg.FrameOptions, DefaultFrameOptions)},
+		{fiber.HeaderReferrerPolicy, valueOr(cfg.ReferrerPolicy, DefaultReferrerPolicy)},
+		{cspHeader, cfg.ContentSecurityPolicy},
+		{fiber.HeaderPermissionsPolicy, cfg.PermissionsPolicy},
+		{HeaderCrossOriginOpenerPolicy, cfg.CrossOriginOpenerPolicy},
+	}
+
+	return func(c fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		for _, header := range headers {
+			if header.value != "" && header.value != SecurityHeaderDisabled {
+				c.Set(header.name, header.value)
+			}
+		}
+
+		if hsts != "" && c.Scheme() == schemeHTTPS {
+			c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+		}
+
+		return c.Next()
+	}
+}
+
+func valueOr(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
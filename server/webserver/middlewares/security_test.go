@@ -0,0 +1,247 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		cfg     SecurityHeadersConfig
+		https   bool
+		want    map[string]string
+		missing []string
+	}{
+		{
+			name: "defaults over http",
+			want: map[string]string{
+				fiber.HeaderXContentTypeOptions: "nosniff",
+				fiber.HeaderXFrameOptions:       DefaultFrameOptions,
+				fiber.HeaderReferrerPolicy:      DefaultReferrerPolicy,
+			},
+			missing: []string{fiber.HeaderStrictTransportSecurity, fiber.HeaderContentSecurityPolicy},
+		},
+		{
+			name: "hsts and csp over https",
+			cfg: SecurityHeadersConfig{
+				HSTSMaxAge:            time.Hour,
+				HSTSIncludeSubdomains: true,
+				HSTSPreload:           true,
+				ContentSecurityPolicy: "default-src 'self'",
+				FrameOptions:          "SAMEORIGIN",
+				PermissionsPolicy:     "camera=()",
+			},
+			https: true,
+			want: map[string]string{
+				fiber.HeaderStrictTransportSecurity: "max-age=3600; includeSubDomains; preload",
+				fiber.HeaderContentSecurityPolicy:   "default-src 'self'",
+				fiber.HeaderXFrameOptions:           "SAMEORIGIN",
+				fiber.HeaderPermissionsPolicy:       "camera=()",
+			},
+		},
+		{
+			name: "report only csp and disabled headers",
+			cfg: SecurityHeadersConfig{
+				HSTSMaxAge:            -1,
+				ContentSecurityPolicy: "default-src 'self'",
+				CSPReportOnly:         true,
+				FrameOptions:          SecurityHeaderDisabled,
+			},
+			https: true,
+			want: map[string]string{
+				fiber.HeaderContentSecurityPolicyReportOnly: "default-src 'self'",
+			},
+			missing: []string{
+				fiber.HeaderStrictTransportSecurity,
+				fiber.HeaderContentSecurityPolicy,
+				fiber.HeaderXFrameOptions,
+			},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := fiber.New(fiber.Config{
+				TrustProxy:       true,
+				TrustProxyConfig: fiber.TrustProxyConfig{Proxies: []string{"0.0.0.0"}},
+			})
+			app.Use(SecurityHeaders(test.cfg))
+			app.Get("/", func(_ fiber.Ctx) error {
+				return fiber.ErrTeapot
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.https {
+				req.Header.Set(fiber.HeaderXForwardedProto, "https")
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			resp.Body.Close()
+
+			for name, value := range test.want {
+				if got := resp.Header.Get(name); got != value {
+					t.Fatalf("%s = %q, want %q", name, got, value)
+				}
+			}
+
+			for _, name := range test.missing {
+				if got := resp.Header.Get(name); got != "" {
+					t.Fatalf("%s = %q, want empty", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+
+	cfg := CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com", "https://*.example.org"},
+		AllowHeaders:     []string{fiber.HeaderContentType, DefaultCSRFHeaderName},
+		ExposeHeaders:    []string{fiber.HeaderETag},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	cases := []struct {
+		name        string
+		cfg         CORSConfig
+		method      string
+		origin      string
+		requestFor  string
+		wantStatus  int
+		wantOrigin  string
+		wantHeaders map[string]string
+	}{
+		{
+			name:       "allowed simple request",
+			cfg:        cfg,
+			method:     http.MethodGet,
+			origin:     "https://app.example.com",
+			wantStatus: http.StatusOK,
+			wantOrigin: "https://app.example.com",
+			wantHeaders: map[string]string{
+				fiber.HeaderAccessControlAllowCredentials: "true",
+				fiber.HeaderAccessControlExposeHeaders:    fiber.HeaderETag,
+			},
+		},
+		{
+			name:       "subdomain preflight",
+			cfg:        cfg,
+			method:     http.MethodOptions,
+			origin:     "https://shop.example.org",
+			requestFor: http.MethodPut,
+			wantStatus: http.StatusNoContent,
+			wantOrigin: "https://shop.example.org",
+			wantHeaders: map[string]string{
+				fiber.HeaderAccessControlAllowMethods: "GET, HEAD, POST, PUT, PATCH, DELETE",
+				fiber.HeaderAccessControlAllowHeaders: "Content-Type, X-CSRF-Token",
+				fiber.HeaderAccessControlMaxAge:       "600",
+			},
+		},
+		{
+			name:       "bare parent domain is not a subdomain",
+			cfg:        cfg,
+			method:     http.MethodGet,
+			origin:     "https://example.org",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "disallowed preflight",
+			cfg:        cfg,
+			method:     http.MethodOptions,
+			origin:     "https://evil.test",
+			requestFor: http.MethodDelete,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "wildcard",
+			cfg:        CORSConfig{AllowOrigins: []string{"*"}},
+			method:     http.MethodGet,
+			origin:     "https://any.test",
+			wantStatus: http.StatusOK,
+			wantOrigin: "*",
+		},
+		{
+			name: "origin func",
+			cfg: CORSConfig{AllowOriginFunc: func(origin string) bool {
+				return origin == "http://localhost:3000"
+			}},
+			method:     http.MethodGet,
+			origin:     "http://localhost:3000",
+			wantStatus: http.StatusOK,
+			wantOrigin: "http://localhost:3000",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := CORS(test.cfg)
+			if err != nil {
+				t.Fatalf("cors: %v", err)
+			}
+
+			app := fiber.New()
+			app.Use(handler)
+			app.Get("/", func(c fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			req := httptest.NewRequest(test.method, "/", nil)
+			req.Header.Set(fiber.HeaderOrigin, test.origin)
+
+			if test.requestFor != "" {
+				req.Header.Set(fiber.HeaderAccessControlRequestMethod, test.requestFor)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+
+			if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != test.wantOrigin {
+				t.Fatalf("allow origin = %q, want %q", got, test.wantOrigin)
+			}
+
+			if got := resp.Header.Get(fiber.HeaderVary); got == "" {
+				t.Fatal("Vary is not set")
+			}
+
+			for name, value := range test.wantHeaders {
+				if got := resp.Header.Get(name); got != value {
+					t.Fatalf("%s = %q, want %q", name, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSRejectsCredentialsWithWildcard(t *testing.T) {
+	t.Parallel()
+
+	_, err := CORS(CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	if !errors.Is(err, ErrCORSCredentialsWithWildcard) {
+		t.Fatalf("err = %v, want %v", err, ErrCORSCredentialsWithWildcard)
+	}
+}
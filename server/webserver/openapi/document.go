@@ -0,0 +1,169 @@
+// Package openapi builds OpenAPI 3.1 documents from Go types and validates
+// request values against the generated schemas.
+package openapi
+
+import "strings"
+
+// Version is the OpenAPI specification version emitted by Document.
+const Version = "3.1.0"
+
+// Parameter locations.
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+)
+
+// Schema types.
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeArray   = "array"
+	TypeObject  = "object"
+)
+
+// MediaTypeJSON is the content type used for generated request and response bodies.
+const MediaTypeJSON = "application/json"
+
+// Document is an OpenAPI 3.1 document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components *Components         `json:"components,omitempty"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+// Operation describes one route.
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes a path, query or header parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes a request body.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response describes a response for one status code.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType binds a schema to a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Components holds reusable schemas referenced with $ref.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is the JSON Schema subset used for generated documents and validation.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+}
+
+// NewDocument returns an empty document with the given info.
+func NewDocument(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+	}
+}
+
+// AddOperation stores an operation for a method and OpenAPI path.
+func (d *Document) AddOperation(method string, path string, operation *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+
+	item[strings.ToLower(method)] = operation
+}
+
+// Path converts a Fiber route path such as /users/:id to /users/{id}.
+func Path(route string) string {
+	segments := strings.Split(route, "/")
+
+	for i, segment := range segments {
+		if name, ok := PathParamName(segment); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// PathParams returns the parameter names of a Fiber route path in order.
+func PathParams(route string) []string {
+	var names []string
+
+	for _, segment := range strings.Split(route, "/") {
+		if name, ok := PathParamName(segment); ok {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// PathParamName returns the parameter name of a Fiber path segment such as :id or :id?.
+func PathParamName(segment string) (string, bool) {
+	if !strings.HasPrefix(segment, ":") {
+		return "", false
+	}
+
+	name := strings.TrimSuffix(strings.TrimPrefix(segment, ":"), "?")
+	if name == "" {
+		return "", false
+	}
+
+	return name, true
+}
@@ -0,0 +1,203 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaBase struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type schemaNode struct {
+	schemaBase
+	Name     string            `json:"name" doc:"display name" maxLength:"5"`
+	Parent   *schemaNode       `json:"parent,omitempty"`
+	Children []schemaNode      `json:"children,omitempty" minLength:"1"`
+	Labels   map[string]int    `json:"labels,omitempty"`
+	Raw      []byte            `json:"raw,omitempty"`
+	Score    float64           `json:"score" required:"false" maximum:"1"`
+	Level    int32             `json:"level,omitempty" enum:"1,2,3"`
+	Hidden   string            `json:"-"`
+	Extra    json.RawMessage   `json:"extra,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+func TestGeneratorSchema(t *testing.T) {
+	t.Parallel()
+
+	generator := NewGenerator()
+
+	ref := generator.SchemaOf(schemaNode{})
+	if ref.Ref != "#/components/schemas/schemaNode" {
+		t.Fatalf("ref = %q", ref.Ref)
+	}
+
+	node := generator.Resolve(ref)
+
+	cases := []struct {
+		name  string
+		field string
+		check func(schema *Schema) bool
+	}{
+		{name: "embedded field is flattened", field: "id", check: func(s *Schema) bool { return s.Type == TypeString }},
+		{name: "time is date-time", field: "created", check: func(s *Schema) bool { return s.Format == "date-time" }},
+		{name: "doc and length tags", field: "name", check: func(s *Schema) bool {
+			return s.Description == "display name" && *s.MaxLength == 5
+		}},
+		{name: "recursive pointer uses ref", field: "parent", check: func(s *Schema) bool { return s.Ref == ref.Ref }},
+		{name: "array length tags use items", field: "children", check: func(s *Schema) bool {
+			return s.Type == TypeArray && s.Items.Ref == ref.Ref && *s.MinItems == 1
+		}},
+		{name: "map uses additional properties", field: "labels", check: func(s *Schema) bool {
+			return s.Type == TypeObject && s.AdditionalProperties.Type == TypeInteger
+		}},
+		{name: "bytes are base64 strings", field: "raw", check: func(s *Schema) bool { return s.Format == "byte" }},
+		{name: "numeric enum", field: "level", check: func(s *Schema) bool {
+			return s.Format == "int32" && reflect.DeepEqual(s.Enum, []any{int64(1), int64(2), int64(3)})
+		}},
+		{name: "raw json accepts anything", field: "extra", check: func(s *Schema) bool { return s.Type == "" }},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			property, ok := node.Properties[test.field]
+			if !ok || !test.check(property) {
+				t.Fatalf("property %s = %+v", test.field, property)
+			}
+		})
+	}
+
+	if strings.Join(node.Required, ",") != "created,id,name" {
+		t.Fatalf("required = %v", node.Required)
+	}
+
+	if _, ok := node.Properties["Hidden"]; ok {
+		t.Fatal("json:\"-\" property should be skipped")
+	}
+
+	if len(generator.Components()) != 1 {
+		t.Fatalf("components = %v", generator.Components())
+	}
+}
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+
+	generator := NewGenerator()
+	validator := NewValidator(generator)
+	schema := generator.SchemaOf(schemaNode{})
+
+	cases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "valid document",
+			body: `{"id":"1","created":"2024-01-01T00:00:00Z","name":"root","parent":null,"children":[` +
+				`{"id":"2","created":"2024-01-01T00:00:00Z","name":"leaf"}]}`,
+		},
+		{
+			name: "nested errors",
+			body: `{"id":1,"created":"x","name":"too long","score":2,"level":4,"labels":{"a":1.5},` +
+				`"children":[{"id":"2","created":"x"}]}`,
+			want: []string{
+				"children[0].name: is required",
+				"id: must be a string",
+				"labels.a: must be an integer",
+				"level: must be one of [1, 2, 3]",
+				"name: must be at most 5 characters",
+				"score: must be at most 1",
+			},
+		},
+		{name: "null required value", body: `{"id":null,"created":"x","name":"a"}`, want: []string{"id: must not be null"}},
+		{name: "wrong root type", body: `[]`, want: []string{": must be an object"}},
+		{name: "trailing data", body: `{} {}`, want: []string{": invalid JSON: unexpected data after value"}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			errs := &ValidationError{}
+			validator.ValidateJSON(errs, "", schema, []byte(test.body))
+
+			got := make([]string, 0, len(errs.Fields))
+			for _, field := range errs.Fields {
+				got = append(got, field.Field+": "+field.Message)
+			}
+
+			if !reflect.DeepEqual(got, test.want) && !(len(got) == 0 && len(test.want) == 0) {
+				t.Fatalf("errors = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateParam(t *testing.T) {
+	t.Parallel()
+
+	validator := NewValidator(NewGenerator())
+	minimum := 1.0
+
+	cases := []struct {
+		name    string
+		schema  *Schema
+		raw     string
+		wantErr bool
+	}{
+		{name: "integer", schema: &Schema{Type: TypeInteger, Minimum: &minimum}, raw: "3"},
+		{name: "integer below minimum", schema: &Schema{Type: TypeInteger, Minimum: &minimum}, raw: "0", wantErr: true},
+		{name: "fraction is not integer", schema: &Schema{Type: TypeInteger}, raw: "1.5", wantErr: true},
+		{name: "number", schema: &Schema{Type: TypeNumber}, raw: "1.5"},
+		{name: "boolean", schema: &Schema{Type: TypeBoolean}, raw: "false"},
+		{name: "array of integers", schema: &Schema{Type: TypeArray, Items: &Schema{Type: TypeInteger}}, raw: "1,2"},
+		{
+			name:    "array with invalid item",
+			schema:  &Schema{Type: TypeArray, Items: &Schema{Type: TypeInteger}},
+			raw:     "1,a",
+			wantErr: true,
+		},
+		{name: "pattern", schema: &Schema{Type: TypeString, Pattern: "^[a-z]+$"}, raw: "Abc", wantErr: true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			errs := &ValidationError{}
+			validator.ValidateParam(errs, "param", test.schema, test.raw)
+
+			if (errs.Err() != nil) != test.wantErr {
+				t.Fatalf("err = %v, wantErr %v", errs.Err(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		route      string
+		wantPath   string
+		wantParams []string
+	}{
+		{route: "/users", wantPath: "/users"},
+		{route: "/users/:id", wantPath: "/users/{id}", wantParams: []string{"id"}},
+		{route: "/orgs/:org/users/:id?", wantPath: "/orgs/{org}/users/{id}", wantParams: []string{"org", "id"}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.route, func(t *testing.T) {
+			if got := Path(test.route); got != test.wantPath {
+				t.Fatalf("Path() = %q, want %q", got, test.wantPath)
+			}
+
+			if got := PathParams(test.route); !reflect.DeepEqual(got, test.wantParams) {
+				t.Fatalf("PathParams() = %v, want %v", got, test.wantParams)
+			}
+		})
+	}
+}
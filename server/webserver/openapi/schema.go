@@ -0,0 +1,354 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Struct tags read by the schema generator in addition to json.
+const (
+	TagDoc       = "doc"
+	TagEnum      = "enum"
+	TagFormat    = "format"
+	TagPattern   = "pattern"
+	TagMinimum   = "minimum"
+	TagMaximum   = "maximum"
+	TagMinLength = "minLength"
+	TagMaxLength = "maxLength"
+	TagRequired  = "required"
+)
+
+const componentsRefPrefix = "#/components/schemas/"
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Generator builds schemas from Go types and collects named struct schemas as components.
+//
+// Struct fields use their json names. A field is required unless it is a
+// pointer, has omitempty, or sets required:"false"; required:"true" forces it.
+// The doc, enum, format, pattern, minimum, maximum, minLength and maxLength tags
+// add constraints.
+type Generator struct {
+	mu         sync.Mutex
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+// NewGenerator returns an empty schema generator.
+func NewGenerator() *Generator {
+	return &Generator{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// SchemaOf returns the schema for the dynamic type of value; nil returns nil.
+func (g *Generator) SchemaOf(value any) *Schema {
+	if value == nil {
+		return nil
+	}
+
+	return g.Schema(reflect.TypeOf(value))
+}
+
+// Schema returns the schema for t; named structs are returned as $ref to a component.
+func (g *Generator) Schema(t reflect.Type) *Schema {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.schema(t)
+}
+
+// Components returns a copy of the collected component schemas.
+func (g *Generator) Components() map[string]*Schema {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	components := make(map[string]*Schema, len(g.components))
+	for name, schema := range g.components {
+		components[name] = schema
+	}
+
+	return components
+}
+
+// Resolve follows a component $ref; other schemas are returned unchanged.
+func (g *Generator) Resolve(schema *Schema) *Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	resolved, ok := g.components[strings.TrimPrefix(schema.Ref, componentsRefPrefix)]
+	if !ok {
+		return schema
+	}
+
+	return resolved
+}
+
+func (g *Generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: TypeString, Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: TypeString}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: TypeString}
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TypeInteger, Format: integerFormat(t)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: TypeString, Format: "byte"}
+		}
+
+		return &Schema{Type: TypeArray, Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: TypeObject, AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *Generator) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.structSchema(t)
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = g.componentName(t)
+		g.names[t] = name
+		g.components[name] = &Schema{Type: TypeObject}
+		g.components[name] = g.structSchema(t)
+	}
+
+	return &Schema{Ref: componentsRefPrefix + name}
+}
+
+func (g *Generator) componentName(t reflect.Type) string {
+	name := sanitizeName(t.Name())
+	if _, exists := g.components[name]; !exists {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+		pkg = pkg[idx+1:]
+	}
+
+	name = sanitizeName(pkg) + name
+
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		if _, exists := g.components[candidate]; !exists {
+			return candidate
+		}
+	}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       TypeObject,
+		Properties: make(map[string]*Schema),
+	}
+
+	g.addFields(schema, t)
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, omitEmpty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := applyTags(g.schema(field.Type), field.Tag)
+		schema.Properties[name] = property
+
+		if fieldRequired(field, omitEmpty) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func jsonField(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" || option == "omitzero" {
+			omitEmpty = true
+		}
+	}
+
+	return parts[0], omitEmpty, false
+}
+
+func fieldRequired(field reflect.StructField, omitEmpty bool) bool {
+	if value, ok := field.Tag.Lookup(TagRequired); ok {
+		required, err := strconv.ParseBool(value)
+
+		return err == nil && required
+	}
+
+	return !omitEmpty && field.Type.Kind() != reflect.Pointer
+}
+
+func applyTags(schema *Schema, tag reflect.StructTag) *Schema {
+	if schema.Ref != "" {
+		if doc := tag.Get(TagDoc); doc != "" {
+			return &Schema{Ref: schema.Ref, Description: doc}
+		}
+
+		return schema
+	}
+
+	schema.Description = tag.Get(TagDoc)
+
+	if value := tag.Get(TagFormat); value != "" {
+		schema.Format = value
+	}
+
+	if value := tag.Get(TagPattern); value != "" {
+		schema.Pattern = value
+	}
+
+	if value := tag.Get(TagEnum); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			schema.Enum = append(schema.Enum, enumValue(schema.Type, strings.TrimSpace(item)))
+		}
+	}
+
+	schema.Minimum = floatTag(tag, TagMinimum)
+	schema.Maximum = floatTag(tag, TagMaximum)
+
+	lengthMin, lengthMax := intTag(tag, TagMinLength), intTag(tag, TagMaxLength)
+	if schema.Type == TypeArray {
+		schema.MinItems, schema.MaxItems = lengthMin, lengthMax
+	} else {
+		schema.MinLength, schema.MaxLength = lengthMin, lengthMax
+	}
+
+	return schema
+}
+
+func enumValue(schemaType string, raw string) any {
+	switch schemaType {
+	case TypeInteger:
+		if value, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return value
+		}
+	case TypeNumber:
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			return value
+		}
+	case TypeBoolean:
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	}
+
+	return raw
+}
+
+func floatTag(tag reflect.StructTag, key string) *float64 {
+	value, err := strconv.ParseFloat(tag.Get(key), 64)
+	if err != nil {
+		return nil
+	}
+
+	return &value
+}
+
+func intTag(tag reflect.StructTag, key string) *int {
+	value, err := strconv.Atoi(tag.Get(key))
+	if err != nil {
+		return nil
+	}
+
+	return &value
+}
+
+func integerFormat(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int32, reflect.Uint32:
+		return "int32"
+	case reflect.Int64, reflect.Uint64, reflect.Int, reflect.Uint:
+		return "int64"
+	default:
+		return ""
+	}
+}
+
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
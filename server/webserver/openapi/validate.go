@@ -0,0 +1,351 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// FieldError describes one value that does not match its schema.
+type FieldError = coreerrors.FieldError
+
+// ValidationError collects every field error found while validating a request.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error returns all field errors in one message.
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		parts = append(parts, field.Field+": "+field.Message)
+	}
+
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Err returns nil when no field errors were collected.
+func (e *ValidationError) Err() error {
+	if e == nil || len(e.Fields) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// ErrorCategory classifies validation errors for status mapping.
+func (e *ValidationError) ErrorCategory() coreerrors.Category {
+	return coreerrors.CategoryValidation
+}
+
+// FieldErrors returns the collected field errors.
+func (e *ValidationError) FieldErrors() []FieldError {
+	return e.Fields
+}
+
+// Add records a field error.
+func (e *ValidationError) Add(field string, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Validator checks values against schemas, resolving $ref through a generator.
+type Validator struct {
+	generator *Generator
+	patterns  sync.Map
+}
+
+// NewValidator returns a validator that resolves references with generator.
+func NewValidator(generator *Generator) *Validator {
+	return &Validator{generator: generator}
+}
+
+// ValidateJSON validates a JSON document against schema and records errors under field.
+func (v *Validator) ValidateJSON(errs *ValidationError, field string, schema *Schema, data []byte) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		errs.Add(field, "invalid JSON: "+err.Error())
+
+		return
+	}
+
+	if decoder.More() {
+		errs.Add(field, "invalid JSON: unexpected data after value")
+
+		return
+	}
+
+	v.Validate(errs, field, schema, value)
+}
+
+// ValidateParam validates a raw path, query or header value against a scalar schema.
+func (v *Validator) ValidateParam(errs *ValidationError, field string, schema *Schema, raw string) {
+	schema = v.generator.Resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	var value any = raw
+
+	switch schema.Type {
+	case TypeInteger, TypeNumber:
+		value = json.Number(raw)
+	case TypeBoolean:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs.Add(field, "must be a boolean")
+
+			return
+		}
+
+		value = parsed
+	case TypeArray:
+		numeric := schema.Items != nil && (schema.Items.Type == TypeInteger || schema.Items.Type == TypeNumber)
+		items := make([]any, 0)
+
+		for _, item := range strings.Split(raw, ",") {
+			if numeric {
+				items = append(items, json.Number(item))
+			} else {
+				items = append(items, item)
+			}
+		}
+
+		value = items
+	}
+
+	v.Validate(errs, field, schema, value)
+}
+
+// Validate checks a decoded JSON value; numbers must be json.Number or float64.
+func (v *Validator) Validate(errs *ValidationError, field string, schema *Schema, value any) {
+	schema = v.generator.Resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	if !v.validateType(errs, field, schema, value) {
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		errs.Add(field, "must be one of "+formatEnum(schema.Enum))
+	}
+
+	switch typed := value.(type) {
+	case string:
+		v.validateString(errs, field, schema, typed)
+	case json.Number, float64:
+		validateNumber(errs, field, schema, toFloat(typed))
+	case []any:
+		v.validateArray(errs, field, schema, typed)
+	case map[string]any:
+		v.validateObject(errs, field, schema, typed)
+	}
+}
+
+func (v *Validator) validateType(errs *ValidationError, field string, schema *Schema, value any) bool {
+	if schema.Type == "" {
+		return true
+	}
+
+	if value == nil {
+		errs.Add(field, "must not be null")
+
+		return false
+	}
+
+	var ok bool
+
+	switch schema.Type {
+	case TypeString:
+		_, ok = value.(string)
+	case TypeBoolean:
+		_, ok = value.(bool)
+	case TypeNumber:
+		ok = isNumber(value)
+	case TypeInteger:
+		ok = isNumber(value) && isInteger(toFloat(value))
+	case TypeArray:
+		_, ok = value.([]any)
+	case TypeObject:
+		_, ok = value.(map[string]any)
+	default:
+		ok = true
+	}
+
+	if !ok {
+		errs.Add(field, "must be "+article(schema.Type)+" "+schema.Type)
+	}
+
+	return ok
+}
+
+func (v *Validator) validateString(errs *ValidationError, field string, schema *Schema, value string) {
+	length := utf8.RuneCountInString(value)
+
+	if schema.MinLength != nil && length < *schema.MinLength {
+		errs.Add(field, fmt.Sprintf("must be at least %d characters", *schema.MinLength))
+	}
+
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		errs.Add(field, fmt.Sprintf("must be at most %d characters", *schema.MaxLength))
+	}
+
+	if schema.Pattern != "" {
+		pattern, err := v.pattern(schema.Pattern)
+		if err != nil {
+			errs.Add(field, "invalid pattern in schema")
+
+			return
+		}
+
+		if !pattern.MatchString(value) {
+			errs.Add(field, "must match pattern "+schema.Pattern)
+		}
+	}
+}
+
+func validateNumber(errs *ValidationError, field string, schema *Schema, value float64) {
+	if schema.Minimum != nil && value < *schema.Minimum {
+		errs.Add(field, "must be at least "+strconv.FormatFloat(*schema.Minimum, 'f', -1, 64))
+	}
+
+	if schema.Maximum != nil && value > *schema.Maximum {
+		errs.Add(field, "must be at most "+strconv.FormatFloat(*schema.Maximum, 'f', -1, 64))
+	}
+}
+
+func (v *Validator) validateArray(errs *ValidationError, field string, schema *Schema, value []any) {
+	if schema.MinItems != nil && len(value) < *schema.MinItems {
+		errs.Add(field, fmt.Sprintf("must contain at least %d items", *schema.MinItems))
+	}
+
+	if schema.MaxItems != nil && len(value) > *schema.MaxItems {
+		errs.Add(field, fmt.Sprintf("must contain at most %d items", *schema.MaxItems))
+	}
+
+	for i, item := range value {
+		v.Validate(errs, field+"["+strconv.Itoa(i)+"]", schema.Items, item)
+	}
+}
+
+func (v *Validator) validateObject(errs *ValidationError, field string, schema *Schema, value map[string]any) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			errs.Add(joinField(field, name), "is required")
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if value[key] == nil && !slices.Contains(schema.Required, key) {
+			continue
+		}
+
+		property, ok := schema.Properties[key]
+		if !ok {
+			property = schema.AdditionalProperties
+		}
+
+		v.Validate(errs, joinField(field, key), property, value[key])
+	}
+}
+
+func (v *Validator) pattern(expr string) (*regexp.Regexp, error) {
+	if cached, ok := v.patterns.Load(expr); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	v.patterns.Store(expr, compiled)
+
+	return compiled, nil
+}
+
+func isNumber(value any) bool {
+	switch typed := value.(type) {
+	case float64:
+		return true
+	case json.Number:
+		_, err := typed.Float64()
+
+		return err == nil
+	default:
+		return false
+	}
+}
+
+func toFloat(value any) float64 {
+	switch typed := value.(type) {
+	case float64:
+		return typed
+	case json.Number:
+		parsed, _ := typed.Float64()
+
+		return parsed
+	default:
+		return 0
+	}
+}
+
+func isInteger(value float64) bool {
+	return value == math.Trunc(value) && !math.IsInf(value, 0)
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, item := range enum {
+		if fmt.Sprint(item) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, 0, len(enum))
+	for _, item := range enum {
+		parts = append(parts, fmt.Sprint(item))
+	}
+
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func joinField(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+func article(schemaType string) string {
+	if schemaType == TypeInteger || schemaType == TypeArray || schemaType == TypeObject {
+		return "an"
+	}
+
+	return "a"
+}
@@ -0,0 +1,120 @@
+package webserver
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel/trace"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+// MIMEProblemJSON is the RFC 9457 problem details media type.
+const MIMEProblemJSON = "application/problem+json"
+
+// ProblemTypeBlank is the default problem type when no type base URI is configured.
+const ProblemTypeBlank = "about:blank"
+
+const headerTraceID = "X-Trace-ID"
+
+// Problem is an RFC 9457 problem details document.
+type Problem struct {
+	Type     string                  `json:"type"`
+	Title    string                  `json:"title"`
+	Status   int                     `json:"status"`
+	Detail   string                  `json:"detail,omitempty"`
+	Instance string                  `json:"instance,omitempty"`
+	TraceID  string                  `json:"trace_id,omitempty"`
+	Errors   []coreerrors.FieldError `json:"errors,omitempty"`
+}
+
+// ProblemConfig configures problem details rendering.
+type ProblemConfig struct {
+	// TypeBaseURI prefixes the error category to build the problem type, for
+	// example https://errors.example.com/ gives https://errors.example.com/not_found.
+	// Empty uses about:blank.
+	TypeBaseURI string
+	// ExposeInternal includes the error text of 5xx problems in detail.
+	ExposeInternal bool
+}
+
+// ProblemErrorHandler renders errors as application/problem+json with default settings.
+var ProblemErrorHandler = NewProblemErrorHandler(ProblemConfig{})
+
+// NewProblemErrorHandler returns a Fiber error handler rendering RFC 9457 problem details.
+func NewProblemErrorHandler(cfg ProblemConfig) fiber.ErrorHandler {
+	return func(c fiber.Ctx, err error) error {
+		problem := cfg.Problem(err)
+		problem.Instance = c.Path()
+		problem.TraceID = traceID(c)
+
+		if retryAfter := retryAfterSeconds(err); retryAfter > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		}
+
+		return c.Status(problem.Status).JSON(problem, MIMEProblemJSON)
+	}
+}
+
+// Problem builds problem details for err without request-specific fields.
+func (cfg ProblemConfig) Problem(err error) Problem {
+	status := StatusFromError(err)
+	problem := Problem{
+		Type:   ProblemTypeBlank,
+		Title:  http.StatusText(status),
+		Status: status,
+		Errors: coreerrors.FieldErrorsOf(err),
+	}
+
+	if problem.Title == "" {
+		problem.Title = strconv.Itoa(status)
+	}
+
+	category := coreerrors.CategoryOf(err)
+	if cfg.TypeBaseURI != "" && category != coreerrors.CategoryUnknown {
+		problem.Type = strings.TrimSuffix(cfg.TypeBaseURI, "/") + "/" + string(category)
+	}
+
+	detail, public := problemDetail(err)
+	if public || status < http.StatusInternalServerError || cfg.ExposeInternal {
+		problem.Detail = detail
+	}
+
+	return problem
+}
+
+// problemDetail returns the detail text and whether it was written for clients.
+func problemDetail(err error) (string, bool) {
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return fiberError.Message, true
+	}
+
+	var coreError *coreerrors.Error
+	if errors.As(err, &coreError) && coreError.Message != "" {
+		return coreError.Message, true
+	}
+
+	return err.Error(), false
+}
+
+func retryAfterSeconds(err error) int {
+	var coreError *coreerrors.Error
+	if !errors.As(err, &coreError) || coreError.RetryAfter <= 0 {
+		return 0
+	}
+
+	return int(math.Ceil(coreError.RetryAfter.Seconds()))
+}
+
+func traceID(c fiber.Ctx) string {
+	if spanContext := trace.SpanContextFromContext(c.Context()); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+
+	return c.Get(headerTraceID)
+}
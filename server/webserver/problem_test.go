@@ -0,0 +1,194 @@
+package webserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	coreerrors "github.com/InsideGallery/core/errors"
+)
+
+func TestStatusFromError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: http.StatusOK},
+		{name: "fiber error", err: fiber.ErrForbidden, want: http.StatusForbidden},
+		{name: "validation", err: coreerrors.Validation("bad"), want: http.StatusBadRequest},
+		{name: "db not found", err: coreerrors.WrapBoundary("postgres", "query", sql.ErrNoRows), want: http.StatusNotFound},
+		{name: "conflict", err: coreerrors.Conflict("taken"), want: http.StatusConflict},
+		{name: "unauthorized", err: coreerrors.Unauthorized(""), want: http.StatusUnauthorized},
+		{name: "rate limited", err: coreerrors.RateLimited("", 0), want: http.StatusTooManyRequests},
+		{name: "upstream", err: coreerrors.WrapBoundary("redis", "get", errors.New("eof")), want: http.StatusBadGateway},
+		{
+			name: "upstream timeout",
+			err:  coreerrors.WrapBoundary("redis", "get", context.DeadlineExceeded),
+			want: http.StatusGatewayTimeout,
+		},
+		{name: "internal", err: errors.New("boom"), want: http.StatusInternalServerError},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := StatusFromError(test.err); got != test.want {
+				t.Fatalf("StatusFromError() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestProblemErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name           string
+		cfg            ProblemConfig
+		err            error
+		wantStatus     int
+		wantType       string
+		wantDetail     string
+		wantFields     int
+		wantRetryAfter string
+	}{
+		{
+			name:       "validation with fields",
+			err:        coreerrors.Validation("invalid user", coreerrors.FieldError{Field: "name", Message: "is required"}),
+			wantStatus: http.StatusBadRequest,
+			wantType:   ProblemTypeBlank,
+			wantDetail: "invalid user",
+			wantFields: 1,
+		},
+		{
+			name:       "not found from adapter with type base",
+			cfg:        ProblemConfig{TypeBaseURI: "https://errors.example.test/"},
+			err:        coreerrors.WrapBoundary("postgres", "query", sql.ErrNoRows),
+			wantStatus: http.StatusNotFound,
+			wantType:   "https://errors.example.test/not_found",
+			wantDetail: "postgres: query: sql: no rows in result set",
+		},
+		{
+			name:           "rate limited sets retry after",
+			err:            coreerrors.RateLimited("too many requests", 1500*time.Millisecond),
+			wantStatus:     http.StatusTooManyRequests,
+			wantType:       ProblemTypeBlank,
+			wantDetail:     "too many requests",
+			wantRetryAfter: "2",
+		},
+		{
+			name:       "internal detail is hidden",
+			err:        errors.New("password=secret"),
+			wantStatus: http.StatusInternalServerError,
+			wantType:   ProblemTypeBlank,
+		},
+		{
+			name:       "internal detail can be exposed",
+			cfg:        ProblemConfig{ExposeInternal: true},
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantType:   ProblemTypeBlank,
+			wantDetail: "boom",
+		},
+		{
+			name:       "public upstream message is kept",
+			err:        coreerrors.Upstream("payments unavailable", errors.New("dial tcp")),
+			wantStatus: http.StatusBadGateway,
+			wantType:   ProblemTypeBlank,
+			wantDetail: "payments unavailable",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := NewFiberAppWithErrorHandler("problem", NewProblemErrorHandler(test.cfg))
+			app.Get("/items/:id", func(fiber.Ctx) error {
+				return test.err
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+			request.Header.Set(headerTraceID, "trace-1")
+
+			response, err := app.Test(request)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer response.Body.Close()
+
+			body, err := io.ReadAll(response.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+
+			if got := response.Header.Get(fiber.HeaderContentType); got != MIMEProblemJSON {
+				t.Fatalf("content type = %q, want %q", got, MIMEProblemJSON)
+			}
+
+			var problem Problem
+			if err := json.Unmarshal(body, &problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+
+			if response.StatusCode != test.wantStatus || problem.Status != test.wantStatus {
+				t.Fatalf("status = %d/%d, want %d", response.StatusCode, problem.Status, test.wantStatus)
+			}
+
+			if problem.Type != test.wantType || problem.Detail != test.wantDetail {
+				t.Fatalf("problem = %+v", problem)
+			}
+
+			if problem.Title != http.StatusText(test.wantStatus) || problem.Instance != "/items/1" {
+				t.Fatalf("problem = %+v", problem)
+			}
+
+			if problem.TraceID != "trace-1" || len(problem.Errors) != test.wantFields {
+				t.Fatalf("problem = %+v", problem)
+			}
+
+			if got := response.Header.Get(fiber.HeaderRetryAfter); got != test.wantRetryAfter {
+				t.Fatalf("retry after = %q, want %q", got, test.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestAPIRouterWithProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	server := New(&Config{Name: "problem", ProblemDetails: true})
+
+	api := NewAPIRouter(NewFiberRouter(server.App), APIConfig{DocumentPath: DisabledOpenAPIPath})
+
+	HandleTyped(api, RouteSpec{Method: http.MethodPost, Path: "/users"},
+		func(_ context.Context, _ TypedRequest[apiCreateUser]) (apiUser, error) {
+			return apiUser{}, nil
+		})
+
+	status, body := doAPIRequest(t, server.App, http.MethodPost, "/users", `{"name":"A"}`)
+	if status != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	var problem Problem
+	if err := json.Unmarshal([]byte(body), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	if len(problem.Errors) != 2 || problem.Errors[0].Field != "body.email" {
+		t.Fatalf("errors = %+v", problem.Errors)
+	}
+}
@@ -3,13 +3,15 @@ package request
 import (
 	"errors"
 	"net"
-	"strings"
 
 	"github.com/gofiber/fiber/v3"
 )
 
 var ErrAddressIsNotValid = errors.New("address is not valid")
 
+// stateTrustedProxies is the app State key of the parsed TrustProxyConfig.
+const stateTrustedProxies = "github.com/InsideGallery/core/server/webserver/request.TrustedProxies"
+
 // IsPrivateAddress checks whether address is loopback, private, or link-local.
 func IsPrivateAddress(address string) (bool, error) {
 	ipAddress := net.ParseIP(address)
@@ -21,34 +23,44 @@ func IsPrivateAddress(address string) (bool, error) {
 }
 
 // IPFromRequest extracts the real client IP from a Fiber request.
+//
+// Forwarding headers are only honored when the app trusts proxies, as webserver.New
+// configures from Config.TrustedProxies, and are resolved by TrustedProxies built once
+// per app from its TrustProxyConfig. Otherwise the connection peer is returned.
 func IPFromRequest(c fiber.Ctx) (net.IP, error) {
-	xRealIP := c.Get("X-Real-Ip")
-	xForwardedFor := c.Get("X-Forwarded-For")
-
-	if xRealIP == "" && xForwardedFor == "" {
-		remoteIP := c.IP()
-		if strings.ContainsRune(remoteIP, ':') {
-			var err error
-
-			remoteIP, _, err = net.SplitHostPort(remoteIP)
-			if err != nil {
-				return nil, err
-			}
-		}
+	app := c.App()
+	if !app.Config().TrustProxy {
+		return c.RequestCtx().RemoteIP(), nil
+	}
 
-		return net.ParseIP(remoteIP), nil
+	proxies, err := appTrustedProxies(app)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, address := range strings.Split(xForwardedFor, ",") {
-		address = strings.TrimSpace(address)
-		isPrivate, err := IsPrivateAddress(address)
+	return proxies.ClientIP(c), nil
+}
 
-		if !isPrivate && err == nil {
-			return net.ParseIP(address), nil
+// appProxies is the parsed TrustProxyConfig of an app, kept in the app State.
+type appProxies struct {
+	proxies *TrustedProxies
+	err     error
+}
+
+// appTrustedProxies returns the TrustedProxies of the app TrustProxyConfig, parsing it on
+// first use. The config of a running app does not change, so a concurrent first parse
+// stores the same result.
+func appTrustedProxies(app *fiber.App) (*TrustedProxies, error) {
+	if cached, ok := app.State().Get(stateTrustedProxies); ok {
+		if parsed, ok := cached.(appProxies); ok {
+			return parsed.proxies, parsed.err
 		}
 	}
 
-	return net.ParseIP(xRealIP), nil
+	proxies, err := NewTrustedProxiesFromConfig(app.Config().TrustProxyConfig)
+	app.State().Set(stateTrustedProxies, appProxies{proxies: proxies, err: err})
+
+	return proxies, err
 }
 
 // IPStringFromRequest returns the request IP as a strings.
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
 )
 
 func TestIsPrivateAddress(t *testing.T) {
@@ -60,36 +61,58 @@ func TestIsPrivateAddress(t *testing.T) {
 func TestIPFromRequest(t *testing.T) {
 	cases := []struct {
 		name          string
+		proxies       []string
 		xRealIP       string
 		xForwardedFor string
 		want          net.IP
 	}{
 		{
-			name:          "first public forwarded address wins",
+			name:          "nearest untrusted forwarded address wins",
+			proxies:       []string{"0.0.0.0", "10.0.0.0/8"},
 			xRealIP:       "198.51.100.10",
-			xForwardedFor: "10.0.0.1, 203.0.113.7",
+			xForwardedFor: "203.0.113.7, 10.0.0.1",
 			want:          net.ParseIP("203.0.113.7"),
 		},
 		{
-			name:          "real ip is fallback when forwarded addresses are private or invalid",
+			name:          "invalid forwarded address stops at the trusted peer",
+			proxies:       []string{"0.0.0.0"},
 			xRealIP:       "198.51.100.11",
 			xForwardedFor: "10.0.0.1, bad",
-			want:          net.ParseIP("198.51.100.11"),
+			want:          net.ParseIP("0.0.0.0"),
 		},
 		{
 			name:    "real ip without forwarded list",
+			proxies: []string{"0.0.0.0"},
 			xRealIP: "198.51.100.12",
 			want:    net.ParseIP("198.51.100.12"),
 		},
+		{
+			name:          "headers are ignored without trusted proxies",
+			xRealIP:       "198.51.100.13",
+			xForwardedFor: "203.0.113.7",
+			want:          net.ParseIP("0.0.0.0"),
+		},
+		{
+			name:          "headers are ignored from an untrusted peer",
+			proxies:       []string{"10.0.0.1"},
+			xRealIP:       "198.51.100.14",
+			xForwardedFor: "203.0.113.7",
+			want:          net.ParseIP("0.0.0.0"),
+		},
 		{
 			name:    "invalid real ip falls back to request ip strings",
+			proxies: []string{"0.0.0.0"},
 			xRealIP: "bad",
+			want:    net.ParseIP("0.0.0.0"),
 		},
 	}
 
 	for _, test := range cases {
 		t.Run(test.name, func(t *testing.T) {
-			app := fiber.New()
+			app := fiber.New(fiber.Config{
+				TrustProxy:       len(test.proxies) > 0,
+				TrustProxyConfig: fiber.TrustProxyConfig{Proxies: test.proxies},
+			})
 			app.Get("/", func(c fiber.Ctx) error {
 				ip, err := IPFromRequest(c)
 				if err != nil {
@@ -132,3 +155,90 @@ func TestIPFromRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestIPFromRequestTrustProxyFlags(t *testing.T) {
+	cases := []struct {
+		name   string
+		config fiber.TrustProxyConfig
+		remote net.Addr
+		want   net.IP
+	}{
+		{
+			name:   "private peer and hops",
+			config: fiber.TrustProxyConfig{Private: true},
+			remote: &net.TCPAddr{IP: net.ParseIP("10.0.0.5"), Port: 4000},
+			want:   net.ParseIP("203.0.113.7"),
+		},
+		{
+			name:   "loopback peer",
+			config: fiber.TrustProxyConfig{Loopback: true},
+			remote: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 4000},
+			want:   net.ParseIP("192.168.1.1"),
+		},
+		{
+			name:   "public peer is not trusted by flags",
+			config: fiber.TrustProxyConfig{Private: true, Loopback: true},
+			remote: &net.TCPAddr{IP: net.ParseIP("198.51.100.1"), Port: 4000},
+			want:   net.ParseIP("198.51.100.1"),
+		},
+		{
+			name:   "unix socket peer",
+			config: fiber.TrustProxyConfig{UnixSocket: true},
+			remote: &net.UnixAddr{Name: "/run/app.sock", Net: "unix"},
+			want:   net.ParseIP("192.168.1.1"),
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{TrustProxy: true, TrustProxyConfig: test.config})
+
+			var got net.IP
+
+			app.Get("/", func(c fiber.Ctx) error {
+				var err error
+
+				got, err = IPFromRequest(c)
+
+				return err
+			})
+
+			var req fasthttp.Request
+			req.SetRequestURI("/")
+			req.Header.Set(HeaderXForwardedFor, "203.0.113.7, 192.168.1.1")
+
+			var ctx fasthttp.RequestCtx
+			ctx.Init(&req, test.remote, nil)
+			app.Handler()(&ctx)
+
+			if status := ctx.Response.StatusCode(); status != http.StatusOK {
+				t.Fatalf("status = %d", status)
+			}
+
+			if !got.Equal(test.want) {
+				t.Fatalf("ip = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIPFromRequestParsesProxiesOnce(t *testing.T) {
+	app := fiber.New(fiber.Config{
+		TrustProxy:       true,
+		TrustProxyConfig: fiber.TrustProxyConfig{Proxies: []string{"10.0.0.0/8"}},
+	})
+
+	first, err := appTrustedProxies(app)
+	if err != nil {
+		t.Fatalf("trusted proxies: %v", err)
+	}
+
+	second, err := appTrustedProxies(app)
+	if err != nil {
+		t.Fatalf("trusted proxies: %v", err)
+	}
+
+	if first != second {
+		t.Fatal("trusted proxies are parsed again")
+	}
+}
@@ -0,0 +1,249 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Forwarding headers read by TrustedProxies.
+const (
+	HeaderForwarded     = "Forwarded"
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIP       = "X-Real-Ip"
+)
+
+var ErrInvalidProxy = errors.New("invalid trusted proxy")
+
+// ForwardedElement is one hop of an RFC 7239 Forwarded header.
+type ForwardedElement struct {
+	For   string
+	By    string
+	Host  string
+	Proto string
+}
+
+// TrustedProxies resolves client addresses, honoring forwarding headers only from
+// listed proxies.
+type TrustedProxies struct {
+	networks   []*net.IPNet
+	loopback   bool
+	private    bool
+	linkLocal  bool
+	unixSocket bool
+}
+
+// NewTrustedProxies parses proxy IP addresses and CIDR ranges.
+func NewTrustedProxies(proxies ...string) (*TrustedProxies, error) {
+	trusted := &TrustedProxies{networks: make([]*net.IPNet, 0, len(proxies))}
+
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidProxy, proxy)
+			}
+
+			bits := net.IPv6len * 8
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, net.IPv4len*8
+			}
+
+			trusted.networks = append(trusted.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidProxy, proxy)
+		}
+
+		trusted.networks = append(trusted.networks, network)
+	}
+
+	return trusted, nil
+}
+
+// NewTrustedProxiesFromConfig builds TrustedProxies from a Fiber TrustProxyConfig: its
+// Proxies, and the loopback, private, link-local and Unix socket peers its flags trust.
+func NewTrustedProxiesFromConfig(config fiber.TrustProxyConfig) (*TrustedProxies, error) {
+	trusted, err := NewTrustedProxies(config.Proxies...)
+	if err != nil {
+		return nil, err
+	}
+
+	trusted.loopback = config.Loopback
+	trusted.private = config.Private
+	trusted.linkLocal = config.LinkLocal
+	trusted.unixSocket = config.UnixSocket
+
+	return trusted, nil
+}
+
+// Trusted reports whether ip belongs to a trusted proxy.
+func (p *TrustedProxies) Trusted(ip net.IP) bool {
+	if p == nil || ip == nil {
+		return false
+	}
+
+	if (p.loopback && ip.IsLoopback()) || (p.private && ip.IsPrivate()) || (p.linkLocal && ip.IsLinkLocalUnicast()) {
+		return true
+	}
+
+	for _, network := range p.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClientIP returns the client address of a Fiber request.
+//
+// Forwarding headers are only read when the connection peer is trusted. The hop
+// chain from Forwarded, or X-Forwarded-For when Forwarded is absent, is walked
+// from the nearest proxy and the first untrusted address is the client.
+func (p *TrustedProxies) ClientIP(c fiber.Ctx) net.IP {
+	headers := make(map[string]string)
+
+	for _, name := range []string{HeaderForwarded, HeaderXForwardedFor, HeaderXRealIP} {
+		if value := c.Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+
+	remote := c.RequestCtx().RemoteIP()
+
+	if _, unix := c.RequestCtx().RemoteAddr().(*net.UnixAddr); unix {
+		return p.resolve(remote, p != nil && p.unixSocket, headers)
+	}
+
+	return p.resolve(remote, p.Trusted(remote), headers)
+}
+
+// ClientIPFromHeaders resolves the client address from the connection peer and
+// forwarding headers keyed by their canonical names.
+func (p *TrustedProxies) ClientIPFromHeaders(remote net.IP, headers map[string]string) net.IP {
+	return p.resolve(remote, p.Trusted(remote), headers)
+}
+
+func (p *TrustedProxies) resolve(remote net.IP, trustedPeer bool, headers map[string]string) net.IP {
+	if !trustedPeer {
+		return remote
+	}
+
+	var chain []string
+
+	if forwarded := headers[HeaderForwarded]; forwarded != "" {
+		for _, element := range ParseForwarded(forwarded) {
+			chain = append(chain, element.For)
+		}
+	} else if forwardedFor := headers[HeaderXForwardedFor]; forwardedFor != "" {
+		chain = strings.Split(forwardedFor, ",")
+	}
+
+	if len(chain) == 0 {
+		if ip := parseHostIP(headers[HeaderXRealIP]); ip != nil {
+			return ip
+		}
+
+		return remote
+	}
+
+	client := remote
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := parseHostIP(chain[i])
+		if ip == nil {
+			return client
+		}
+
+		client = ip
+		if !p.Trusted(ip) {
+			return ip
+		}
+	}
+
+	return client
+}
+
+// ParseForwarded parses an RFC 7239 Forwarded header into hops, nearest client first.
+func ParseForwarded(header string) []ForwardedElement {
+	var elements []ForwardedElement
+
+	for _, hop := range splitQuoted(header, ',') {
+		var element ForwardedElement
+
+		for _, pair := range splitQuoted(hop, ';') {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "for":
+				element.For = value
+			case "by":
+				element.By = value
+			case "host":
+				element.Host = value
+			case "proto":
+				element.Proto = value
+			}
+		}
+
+		elements = append(elements, element)
+	}
+
+	return elements
+}
+
+// parseHostIP parses an address that may carry a port or IPv6 brackets.
+func parseHostIP(value string) net.IP {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	if ip := net.ParseIP(value); ip != nil {
+		return ip
+	}
+
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return net.ParseIP(host)
+	}
+
+	return net.ParseIP(strings.Trim(value, "[]"))
+}
+
+func splitQuoted(value string, separator rune) []string {
+	var (
+		parts  []string
+		start  int
+		quoted bool
+	)
+
+	for i, r := range value {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == separator && !quoted:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, value[start:])
+}
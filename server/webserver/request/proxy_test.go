@@ -0,0 +1,158 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestNewTrustedProxies(t *testing.T) {
+	cases := []struct {
+		name    string
+		proxies []string
+		ip      string
+		want    bool
+		wantErr error
+	}{
+		{name: "single address", proxies: []string{"10.0.0.1"}, ip: "10.0.0.1", want: true},
+		{name: "cidr", proxies: []string{"10.0.0.0/8"}, ip: "10.20.30.40", want: true},
+		{name: "ipv6 cidr", proxies: []string{"fd00::/8"}, ip: "fd00::1", want: true},
+		{name: "outside range", proxies: []string{"10.0.0.0/8"}, ip: "192.0.2.1"},
+		{name: "empty list", ip: "10.0.0.1"},
+		{name: "invalid", proxies: []string{"proxy"}, wantErr: ErrInvalidProxy},
+		{name: "invalid cidr", proxies: []string{"10.0.0.0/40"}, wantErr: ErrInvalidProxy},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			proxies, err := NewTrustedProxies(test.proxies...)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("err = %v, want %v", err, test.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := proxies.Trusted(net.ParseIP(test.ip)); got != test.want {
+				t.Fatalf("trusted = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestClientIPFromHeaders(t *testing.T) {
+	proxies, err := NewTrustedProxies("10.0.0.0/8", "2001:db8::/32")
+	if err != nil {
+		t.Fatalf("trusted proxies: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name:    "untrusted peer ignores headers",
+			remote:  "198.51.100.1",
+			headers: map[string]string{HeaderXForwardedFor: "203.0.113.7", HeaderXRealIP: "203.0.113.8"},
+			want:    "198.51.100.1",
+		},
+		{
+			name:    "forwarded for from trusted peer",
+			remote:  "10.0.0.2",
+			headers: map[string]string{HeaderXForwardedFor: "203.0.113.7"},
+			want:    "203.0.113.7",
+		},
+		{
+			name:    "spoofed leftmost address is skipped",
+			remote:  "10.0.0.2",
+			headers: map[string]string{HeaderXForwardedFor: "192.0.2.66, 203.0.113.7, 10.0.0.3"},
+			want:    "203.0.113.7",
+		},
+		{
+			name:    "all hops trusted returns leftmost",
+			remote:  "10.0.0.2",
+			headers: map[string]string{HeaderXForwardedFor: "10.1.1.1, 10.0.0.3"},
+			want:    "10.1.1.1",
+		},
+		{
+			name:   "rfc 7239 forwarded wins",
+			remote: "10.0.0.2",
+			headers: map[string]string{
+				HeaderForwarded:     `for=192.0.2.60;proto=https, for="[2001:db8::1]:4711"`,
+				HeaderXForwardedFor: "203.0.113.7",
+			},
+			want: "192.0.2.60",
+		},
+		{
+			name:    "obfuscated hop stops the walk",
+			remote:  "10.0.0.2",
+			headers: map[string]string{HeaderForwarded: "for=192.0.2.60, for=_hidden"},
+			want:    "10.0.0.2",
+		},
+		{
+			name:    "real ip from trusted peer",
+			remote:  "10.0.0.2",
+			headers: map[string]string{HeaderXRealIP: "203.0.113.9"},
+			want:    "203.0.113.9",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			got := proxies.ClientIPFromHeaders(net.ParseIP(test.remote), test.headers)
+			if !got.Equal(net.ParseIP(test.want)) {
+				t.Fatalf("client ip = %v, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseForwarded(t *testing.T) {
+	elements := ParseForwarded(`for=192.0.2.43;proto=https;host="example.com";by=10.0.0.1, for="[2001:db8::17]"`)
+	if len(elements) != 2 {
+		t.Fatalf("elements = %+v", elements)
+	}
+
+	want := ForwardedElement{For: "192.0.2.43", By: "10.0.0.1", Host: "example.com", Proto: "https"}
+	if elements[0] != want || elements[1].For != "[2001:db8::17]" {
+		t.Fatalf("elements = %+v", elements)
+	}
+}
+
+func TestTrustedProxiesClientIP(t *testing.T) {
+	proxies, err := NewTrustedProxies("0.0.0.0/32")
+	if err != nil {
+		t.Fatalf("trusted proxies: %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(proxies.ClientIP(c).String())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderXForwardedFor, "203.0.113.7")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	if got := string(body); got != "203.0.113.7" {
+		t.Fatalf("client ip = %q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package websocket
+
+import (
+	"encoding"
+	"encoding/json"
+
+	ws "github.com/fasthttp/websocket"
+)
+
+// Data message types, as defined by RFC 6455.
+const (
+	TextMessage   = ws.TextMessage
+	BinaryMessage = ws.BinaryMessage
+)
+
+// Message is one data frame sent or received on a connection.
+type Message struct {
+	Type int
+	Data []byte
+}
+
+// Codec converts values to and from messages.
+type Codec interface {
+	Encode(v any) (Message, error)
+	Decode(msg Message, v any) error
+}
+
+// JSONCodec encodes values as JSON text messages.
+type JSONCodec struct{}
+
+// Encode marshals v into a text message.
+func (JSONCodec) Encode(v any) (Message, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{Type: TextMessage, Data: data}, nil
+}
+
+// Decode unmarshals the message payload into v.
+func (JSONCodec) Decode(msg Message, v any) error {
+	return json.Unmarshal(msg.Data, v)
+}
+
+// BinaryCodec sends []byte, string and encoding.BinaryMarshaler values as binary messages.
+type BinaryCodec struct{}
+
+// Encode wraps v in a binary message.
+func (BinaryCodec) Encode(v any) (Message, error) {
+	switch value := v.(type) {
+	case []byte:
+		return Message{Type: BinaryMessage, Data: value}, nil
+	case string:
+		return Message{Type: BinaryMessage, Data: []byte(value)}, nil
+	case encoding.BinaryMarshaler:
+		data, err := value.MarshalBinary()
+		if err != nil {
+			return Message{}, err
+		}
+
+		return Message{Type: BinaryMessage, Data: data}, nil
+	default:
+		return Message{}, ErrUnsupportedType
+	}
+}
+
+// Decode copies the payload into *[]byte or *string, or calls encoding.BinaryUnmarshaler.
+func (BinaryCodec) Decode(msg Message, v any) error {
+	switch value := v.(type) {
+	case *[]byte:
+		*value = append((*value)[:0], msg.Data...)
+
+		return nil
+	case *string:
+		*value = string(msg.Data)
+
+		return nil
+	case encoding.BinaryUnmarshaler:
+		return value.UnmarshalBinary(msg.Data)
+	default:
+		return ErrUnsupportedType
+	}
+}
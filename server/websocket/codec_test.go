@@ -0,0 +1,63 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FrogoAI/testutils"
+)
+
+type point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func TestJSONCodec(t *testing.T) {
+	codec := JSONCodec{}
+
+	msg, err := codec.Encode(point{X: 1, Y: 2})
+	testutils.Equal(t, err, nil)
+	testutils.Equal(t, msg.Type, TextMessage)
+	testutils.Equal(t, string(msg.Data), `{"x":1,"y":2}`)
+
+	var decoded point
+	testutils.Equal(t, codec.Decode(msg, &decoded), nil)
+	testutils.Equal(t, decoded, point{X: 1, Y: 2})
+}
+
+func TestBinaryCodec(t *testing.T) {
+	codec := BinaryCodec{}
+
+	cases := []struct {
+		name    string
+		value   any
+		want    string
+		wantErr error
+	}{
+		{name: "bytes", value: []byte("raw"), want: "raw"},
+		{name: "string", value: "text", want: "text"},
+		{name: "unsupported", value: 42, wantErr: ErrUnsupportedType},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			msg, err := codec.Encode(test.value)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Encode error = %v, want %v", err, test.wantErr)
+			}
+
+			if test.wantErr != nil {
+				return
+			}
+
+			testutils.Equal(t, msg.Type, BinaryMessage)
+
+			var decoded []byte
+			testutils.Equal(t, codec.Decode(msg, &decoded), nil)
+			testutils.Equal(t, string(decoded), test.want)
+		})
+	}
+
+	var unsupported int
+	testutils.Equal(t, errors.Is(codec.Decode(Message{}, &unsupported), ErrUnsupportedType), true)
+}
@@ -0,0 +1,181 @@
+package websocket
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	ws "github.com/fasthttp/websocket"
+)
+
+// Conn is one WebSocket connection registered in a Hub. A user may hold several connections.
+type Conn struct {
+	ID     string
+	UserID string
+
+	hub   *Hub
+	conn  *ws.Conn
+	rooms map[string]struct{}
+	queue chan Message
+	done  chan struct{}
+
+	once      sync.Once
+	closeCode int
+	closeText string
+}
+
+func newConn(hub *Hub, id string, userID string, raw *ws.Conn) *Conn {
+	return &Conn{
+		ID:     id,
+		UserID: userID,
+		hub:    hub,
+		conn:   raw,
+		rooms:  make(map[string]struct{}),
+		queue:  make(chan Message, hub.cfg.QueueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// Send encodes v with the hub codec and queues it for the connection.
+func (c *Conn) Send(v any) error {
+	msg, err := c.hub.cfg.Codec.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	return c.SendMessage(msg)
+}
+
+// SendMessage queues msg for the connection. A full queue returns ErrQueueFull
+// and applies the hub Backpressure policy.
+func (c *Conn) SendMessage(msg Message) error {
+	if msg.Type != TextMessage && msg.Type != BinaryMessage {
+		return ErrInvalidMessageType
+	}
+
+	return c.hub.enqueue(c, msg)
+}
+
+// Decode decodes msg into v with the hub codec.
+func (c *Conn) Decode(msg Message, v any) error {
+	return c.hub.cfg.Codec.Decode(msg, v)
+}
+
+// Join adds the connection to room.
+func (c *Conn) Join(room string) {
+	c.hub.Join(c, room)
+}
+
+// Leave removes the connection from room.
+func (c *Conn) Leave(room string) {
+	c.hub.Leave(c, room)
+}
+
+// Rooms returns the rooms the connection belongs to, sorted.
+func (c *Conn) Rooms() []string {
+	c.hub.mu.RLock()
+	defer c.hub.mu.RUnlock()
+
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+
+	sort.Strings(rooms)
+
+	return rooms
+}
+
+// Done is closed when the connection is closed, evicted or the hub closes.
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close sends a normal close frame and disconnects the client.
+func (c *Conn) Close() error {
+	c.stop(ws.CloseNormalClosure, "")
+
+	return nil
+}
+
+func (c *Conn) stop(code int, text string) {
+	c.once.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.done)
+	})
+}
+
+// read delivers incoming messages to the hub until the peer disconnects, a pong
+// is missed or a message exceeds the size limit.
+func (c *Conn) read() error {
+	cfg := c.hub.cfg
+
+	c.conn.SetReadLimit(cfg.MaxMessageSize)
+
+	if err := c.conn.SetReadDeadline(time.Now().Add(cfg.PongTimeout)); err != nil {
+		return err
+	}
+
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(cfg.PongTimeout))
+	})
+
+	for {
+		messageType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		c.hub.count(metricMessagesReceived)
+
+		if cfg.OnMessage != nil {
+			cfg.OnMessage(c, Message{Type: messageType, Data: data})
+		}
+	}
+}
+
+// write drains the queue and pings the peer until the connection is done, then
+// sends the close frame and closes the socket, which also ends read.
+func (c *Conn) write() {
+	cfg := c.hub.cfg
+
+	ping := time.NewTicker(cfg.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg := <-c.queue:
+			if err := c.writeMessage(msg); err != nil {
+				c.stop(ws.CloseAbnormalClosure, "")
+			}
+		case <-ping.C:
+			if err := c.conn.WriteControl(ws.PingMessage, nil, time.Now().Add(cfg.WriteTimeout)); err != nil {
+				c.stop(ws.CloseAbnormalClosure, "")
+			}
+		case <-c.done:
+			if c.closeCode != ws.CloseAbnormalClosure {
+				payload := ws.FormatCloseMessage(c.closeCode, c.closeText)
+				_ = c.conn.WriteControl(ws.CloseMessage, payload, time.Now().Add(cfg.WriteTimeout))
+			}
+
+			_ = c.conn.Close()
+
+			return
+		}
+	}
+}
+
+func (c *Conn) writeMessage(msg Message) error {
+	if err := c.conn.SetWriteDeadline(time.Now().Add(c.hub.cfg.WriteTimeout)); err != nil {
+		return err
+	}
+
+	if err := c.conn.WriteMessage(msg.Type, msg.Data); err != nil {
+		return err
+	}
+
+	c.hub.count(metricMessagesSent)
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package websocket
+
+import "errors"
+
+// All kind of errors
+var (
+	ErrInvalidUserID       = errors.New("invalid user id")
+	ErrHubClosed           = errors.New("hub is closed")
+	ErrConnectionClosed    = errors.New("connection is closed")
+	ErrQueueFull           = errors.New("connection write queue is full")
+	ErrUserNotConnected    = errors.New("user has no connections")
+	ErrUnsupportedType     = errors.New("value is not supported by the codec")
+	ErrInvalidMessageType  = errors.New("message type is not text or binary")
+	ErrPongTimeoutTooShort = errors.New("pong timeout must exceed the ping interval")
+)
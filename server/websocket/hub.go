@@ -0,0 +1,514 @@
+package websocket
+
+import (
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FrogoAI/set"
+	ws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+
+	"github.com/InsideGallery/core/metrics"
+	corestrings "github.com/InsideGallery/core/stdx/strings"
+)
+
+// ContextUserID is the Fiber local read by Hub.Handler; it equals sse.ContextUserID
+// so one authentication middleware serves both.
+var ContextUserID corestrings.ContextKey = "userID"
+
+// Hub defaults.
+const (
+	DefaultQueueSize      = 64
+	DefaultMaxMessageSize = 64 << 10
+	DefaultPingInterval   = 30 * time.Second
+	DefaultPongTimeout    = 60 * time.Second
+	DefaultWriteTimeout   = 10 * time.Second
+)
+
+// Metric names recorded through HubConfig.Metrics.
+const (
+	metricConnections      = "websocket.connections"
+	metricConnectionsEvict = "websocket.connections.evicted"
+	metricMessagesReceived = "websocket.messages.received"
+	metricMessagesSent     = "websocket.messages.sent"
+	metricMessagesDropped  = "websocket.messages.dropped"
+	metricCountValue       = 1
+)
+
+const slowConsumerCloseReason = "slow consumer"
+
+// Backpressure selects what happens when a connection write queue is full.
+type Backpressure int
+
+const (
+	// BackpressureClose disconnects the slow connection with close code 1013 (try again later).
+	BackpressureClose Backpressure = iota
+	// BackpressureDrop drops the message and keeps the connection.
+	BackpressureDrop
+)
+
+// HubConfig configures a Hub.
+type HubConfig struct {
+	// Codec encodes values passed to Send, Broadcast, SendToUser and SendToRoom; nil uses JSONCodec.
+	Codec Codec
+	// Metrics records connection and message metrics; nil disables them.
+	Metrics *metrics.Client
+	// QueueSize is the number of outgoing messages buffered per connection.
+	QueueSize    int
+	Backpressure Backpressure
+	// MaxMessageSize limits incoming messages in bytes; larger messages close the connection with 1009.
+	MaxMessageSize int64
+	// PingInterval is how often the server pings; PongTimeout is how long it waits for any frame
+	// or pong before closing and must exceed PingInterval.
+	PingInterval time.Duration
+	PongTimeout  time.Duration
+	// WriteTimeout bounds each frame write.
+	WriteTimeout time.Duration
+	// UserID resolves the user of a Fiber request; nil reads the ContextUserID local.
+	UserID func(c fiber.Ctx) (string, error)
+	// Rooms resolves the rooms joined on connect; nil reads comma-separated room query values.
+	Rooms func(c fiber.Ctx) ([]string, error)
+	// CheckOrigin accepts the upgrade request; nil allows requests without Origin or from the request host.
+	CheckOrigin func(c fiber.Ctx) bool
+	// Subprotocols are negotiated in order of preference.
+	Subprotocols []string
+	// OnConnect, OnMessage and OnDisconnect are called from the connection read goroutine.
+	OnConnect    func(conn *Conn)
+	OnMessage    func(conn *Conn, msg Message)
+	OnDisconnect func(conn *Conn, err error)
+}
+
+// Hub tracks WebSocket connections by user and room and queues messages to them.
+type Hub struct {
+	cfg      HubConfig
+	upgrader ws.FastHTTPUpgrader
+
+	mu          sync.RWMutex
+	connections map[string]*Conn
+	users       map[string]map[string]*Conn
+	rooms       map[string]map[string]*Conn
+	closed      bool
+
+	connectionSeq atomic.Uint64
+}
+
+// NewHub returns a hub with defaults applied to cfg.
+func NewHub(cfg HubConfig) (*Hub, error) {
+	if cfg.Codec == nil {
+		cfg.Codec = JSONCodec{}
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = DefaultMaxMessageSize
+	}
+
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = DefaultPingInterval
+	}
+
+	if cfg.PongTimeout <= 0 {
+		cfg.PongTimeout = DefaultPongTimeout
+	}
+
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = DefaultWriteTimeout
+	}
+
+	if cfg.PongTimeout <= cfg.PingInterval {
+		return nil, ErrPongTimeoutTooShort
+	}
+
+	return &Hub{
+		cfg: cfg,
+		upgrader: ws.FastHTTPUpgrader{
+			Subprotocols: cfg.Subprotocols,
+			CheckOrigin:  func(*fasthttp.RequestCtx) bool { return true },
+		},
+		connections: make(map[string]*Conn),
+		users:       make(map[string]map[string]*Conn),
+		rooms:       make(map[string]map[string]*Conn),
+	}, nil
+}
+
+// Handler returns a Fiber handler that upgrades the request and serves the connection
+// until it closes. The user and rooms are resolved before the upgrade.
+func (h *Hub) Handler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if !ws.FastHTTPIsWebSocketUpgrade(c.RequestCtx()) {
+			return fiber.ErrUpgradeRequired
+		}
+
+		userID, err := h.userID(c)
+		if err != nil || userID == "" {
+			return fiber.ErrUnauthorized
+		}
+
+		rooms, err := h.roomsOf(c)
+		if err != nil {
+			return err
+		}
+
+		if !h.originAllowed(c) {
+			return fiber.ErrForbidden
+		}
+
+		if h.isClosed() {
+			return fiber.ErrServiceUnavailable
+		}
+
+		err = h.upgrader.Upgrade(c.RequestCtx(), func(raw *ws.Conn) {
+			h.serve(raw, userID, rooms)
+		})
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		return nil
+	}
+}
+
+// Broadcast encodes v once and queues it to every connection.
+func (h *Hub) Broadcast(v any) error {
+	msg, err := h.cfg.Codec.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	targets := make([]*Conn, 0, len(h.connections))
+
+	for _, conn := range h.connections {
+		targets = append(targets, conn)
+	}
+
+	h.mu.RUnlock()
+
+	return h.deliver(targets, msg)
+}
+
+// SendToUser queues v to every connection of userID and returns ErrUserNotConnected when
+// the user has none.
+func (h *Hub) SendToUser(userID string, v any) error {
+	msg, err := h.cfg.Codec.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	targets := h.members(h.users, userID)
+	if len(targets) == 0 {
+		return ErrUserNotConnected
+	}
+
+	return h.deliver(targets, msg)
+}
+
+// SendToRoom queues v to every connection in room.
+func (h *Hub) SendToRoom(room string, v any) error {
+	msg, err := h.cfg.Codec.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	return h.deliver(h.members(h.rooms, room), msg)
+}
+
+// Join adds conn to room.
+func (h *Hub) Join(conn *Conn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.connections[conn.ID]; !ok {
+		return
+	}
+
+	conn.rooms[room] = struct{}{}
+	addMember(h.rooms, room, conn)
+}
+
+// Leave removes conn from room.
+func (h *Hub) Leave(conn *Conn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(conn.rooms, room)
+	removeMember(h.rooms, room, conn)
+}
+
+// Connections returns the number of connections.
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.connections)
+}
+
+// ConnectedUsers returns the users with at least one connection.
+func (h *Hub) ConnectedUsers() set.GenericDataSet[string] {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	users := set.NewGenericDataSet[string]()
+	for userID := range h.users {
+		users.Add(userID)
+	}
+
+	return users
+}
+
+// RoomSize returns the number of connections in room.
+func (h *Hub) RoomSize(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.rooms[room])
+}
+
+// Close disconnects every connection with close code 1001 (going away) and rejects new ones.
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	h.closed = true
+	connections := h.connections
+	h.connections = make(map[string]*Conn)
+	h.users = make(map[string]map[string]*Conn)
+	h.rooms = make(map[string]map[string]*Conn)
+	h.mu.Unlock()
+
+	for _, conn := range connections {
+		conn.stop(ws.CloseGoingAway, "")
+	}
+
+	h.gauge(metricConnections, 0)
+
+	return nil
+}
+
+func (h *Hub) serve(raw *ws.Conn, userID string, rooms []string) {
+	conn, err := h.register(raw, userID, rooms)
+	if err != nil {
+		payload := ws.FormatCloseMessage(ws.CloseGoingAway, "")
+		_ = raw.WriteControl(ws.CloseMessage, payload, time.Now().Add(h.cfg.WriteTimeout))
+		_ = raw.Close()
+
+		return
+	}
+
+	written := make(chan struct{})
+
+	go func() {
+		defer close(written)
+
+		conn.write()
+	}()
+
+	if h.cfg.OnConnect != nil {
+		h.cfg.OnConnect(conn)
+	}
+
+	err = conn.read()
+
+	conn.stop(ws.CloseNormalClosure, "")
+	<-written
+	h.unregister(conn)
+
+	if h.cfg.OnDisconnect != nil {
+		h.cfg.OnDisconnect(conn, err)
+	}
+
+	slog.Default().Debug("websocket connection closed", "user", conn.UserID, "connection", conn.ID, "err", err)
+}
+
+func (h *Hub) register(raw *ws.Conn, userID string, rooms []string) (*Conn, error) {
+	conn := newConn(h, strconv.FormatUint(h.connectionSeq.Add(1), 10), userID, raw)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+
+		return nil, ErrHubClosed
+	}
+
+	h.connections[conn.ID] = conn
+	addMember(h.users, userID, conn)
+
+	for _, room := range rooms {
+		conn.rooms[room] = struct{}{}
+		addMember(h.rooms, room, conn)
+	}
+
+	total := len(h.connections)
+	h.mu.Unlock()
+
+	h.gauge(metricConnections, float64(total))
+
+	return conn, nil
+}
+
+func (h *Hub) unregister(conn *Conn) {
+	h.mu.Lock()
+	if _, ok := h.connections[conn.ID]; !ok {
+		h.mu.Unlock()
+
+		return
+	}
+
+	delete(h.connections, conn.ID)
+	removeMember(h.users, conn.UserID, conn)
+
+	for room := range conn.rooms {
+		removeMember(h.rooms, room, conn)
+	}
+
+	total := len(h.connections)
+	h.mu.Unlock()
+
+	h.gauge(metricConnections, float64(total))
+}
+
+func (h *Hub) deliver(targets []*Conn, msg Message) error {
+	if h.isClosed() {
+		return ErrHubClosed
+	}
+
+	for _, conn := range targets {
+		_ = h.enqueue(conn, msg)
+	}
+
+	return nil
+}
+
+func (h *Hub) enqueue(conn *Conn, msg Message) error {
+	select {
+	case <-conn.done:
+		return ErrConnectionClosed
+	default:
+	}
+
+	select {
+	case conn.queue <- msg:
+		return nil
+	default:
+	}
+
+	h.count(metricMessagesDropped)
+
+	if h.cfg.Backpressure == BackpressureClose {
+		conn.stop(ws.CloseTryAgainLater, slowConsumerCloseReason)
+		h.count(metricConnectionsEvict)
+		slog.Default().Warn("websocket slow consumer evicted", "user", conn.UserID, "connection", conn.ID)
+	}
+
+	return ErrQueueFull
+}
+
+func (h *Hub) members(index map[string]map[string]*Conn, key string) []*Conn {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	targets := make([]*Conn, 0, len(index[key]))
+	for _, conn := range index[key] {
+		targets = append(targets, conn)
+	}
+
+	return targets
+}
+
+func (h *Hub) isClosed() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.closed
+}
+
+func (h *Hub) userID(c fiber.Ctx) (string, error) {
+	if h.cfg.UserID != nil {
+		return h.cfg.UserID(c)
+	}
+
+	userID, ok := c.Locals(ContextUserID).(string)
+	if !ok {
+		return "", ErrInvalidUserID
+	}
+
+	return userID, nil
+}
+
+func (h *Hub) roomsOf(c fiber.Ctx) ([]string, error) {
+	if h.cfg.Rooms != nil {
+		return h.cfg.Rooms(c)
+	}
+
+	var rooms []string
+
+	for _, value := range c.Request().URI().QueryArgs().PeekMulti("room") {
+		for _, room := range strings.Split(string(value), ",") {
+			if room = strings.TrimSpace(room); room != "" {
+				rooms = append(rooms, room)
+			}
+		}
+	}
+
+	return rooms, nil
+}
+
+func (h *Hub) originAllowed(c fiber.Ctx) bool {
+	if h.cfg.CheckOrigin != nil {
+		return h.cfg.CheckOrigin(c)
+	}
+
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(parsed.Host, c.Host())
+}
+
+func (h *Hub) count(name string) {
+	if err := h.cfg.Metrics.Count(name, metricCountValue, nil); err != nil {
+		slog.Default().Warn("record websocket metric failed", "metric", name, "error", err)
+	}
+}
+
+func (h *Hub) gauge(name string, value float64) {
+	if err := h.cfg.Metrics.Gauge(name, value, nil); err != nil {
+		slog.Default().Warn("record websocket metric failed", "metric", name, "error", err)
+	}
+}
+
+func addMember(index map[string]map[string]*Conn, key string, conn *Conn) {
+	members, ok := index[key]
+	if !ok {
+		members = make(map[string]*Conn)
+		index[key] = members
+	}
+
+	members[conn.ID] = conn
+}
+
+func removeMember(index map[string]map[string]*Conn, key string, conn *Conn) {
+	members, ok := index[key]
+	if !ok {
+		return
+	}
+
+	delete(members, conn.ID)
+
+	if len(members) == 0 {
+		delete(index, key)
+	}
+}
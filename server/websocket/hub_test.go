@@ -0,0 +1,333 @@
+package websocket
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FrogoAI/testutils"
+	ws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/InsideGallery/core/metrics"
+)
+
+const testTimeout = 5 * time.Second
+
+type recordingProcessor struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (p *recordingProcessor) Close() error { return nil }
+
+func (p *recordingProcessor) Count(name string, value int64, _ []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.counts[name] += value
+
+	return nil
+}
+
+func (p *recordingProcessor) Gauge(string, float64, []string) error { return nil }
+
+func (p *recordingProcessor) Distribution(string, float64, []string) error { return nil }
+
+func (p *recordingProcessor) count(name string) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.counts[name]
+}
+
+func newTestMetrics(t *testing.T) (*metrics.Client, *recordingProcessor) {
+	t.Helper()
+
+	processor := &recordingProcessor{counts: make(map[string]int64)}
+	kind := "websocket-" + t.Name()
+
+	metrics.Register(kind, func(metrics.Config, string) (metrics.Processor, error) {
+		return processor, nil
+	})
+
+	client, err := metrics.New(metrics.Config{Processors: []string{kind}}, "test")
+	testutils.Equal(t, err, nil)
+
+	return client, processor
+}
+
+func startHub(t *testing.T, cfg HubConfig) (*Hub, string) {
+	t.Helper()
+
+	hub, err := NewHub(cfg)
+	testutils.Equal(t, err, nil)
+
+	app := fiber.New()
+	app.Get("/ws", func(c fiber.Ctx) error {
+		if user := c.Query("user"); user != "" {
+			c.Locals(ContextUserID, user)
+		}
+
+		return c.Next()
+	}, hub.Handler())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutils.Equal(t, err, nil)
+
+	go func() {
+		_ = app.Listener(listener, fiber.ListenConfig{DisableStartupMessage: true})
+	}()
+
+	t.Cleanup(func() {
+		_ = hub.Close()
+		_ = app.ShutdownWithTimeout(time.Second)
+	})
+
+	return hub, "ws://" + listener.Addr().String() + "/ws"
+}
+
+func dial(t *testing.T, target string) *ws.Conn {
+	t.Helper()
+
+	conn, resp, err := ws.DefaultDialer.Dial(target, nil)
+	if err != nil {
+		t.Fatalf("dial %s: %v", target, err)
+	}
+
+	_ = resp.Body.Close()
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(testTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func readText(t *testing.T, conn *ws.Conn) string {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(testTimeout))
+
+	_, data, err := conn.ReadMessage()
+	testutils.Equal(t, err, nil)
+
+	return string(data)
+}
+
+func TestNewHubValidatesTimeouts(t *testing.T) {
+	_, err := NewHub(HubConfig{PingInterval: time.Minute, PongTimeout: time.Second})
+	testutils.Equal(t, errors.Is(err, ErrPongTimeoutTooShort), true)
+}
+
+func TestHubHandlerRejections(t *testing.T) {
+	hub, err := NewHub(HubConfig{})
+	testutils.Equal(t, err, nil)
+
+	app := fiber.New()
+	app.Get("/ws", func(c fiber.Ctx) error {
+		if user := c.Query("user"); user != "" {
+			c.Locals(ContextUserID, user)
+		}
+
+		return c.Next()
+	}, hub.Handler())
+
+	upgrade := map[string]string{
+		fiber.HeaderConnection:  "Upgrade",
+		fiber.HeaderUpgrade:     "websocket",
+		"Sec-WebSocket-Version": "13",
+		"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+	}
+
+	cases := []struct {
+		name       string
+		target     string
+		headers    map[string]string
+		origin     string
+		wantStatus int
+	}{
+		{name: "plain request", target: "/ws?user=alice", wantStatus: http.StatusUpgradeRequired},
+		{name: "missing user", target: "/ws", headers: upgrade, wantStatus: http.StatusUnauthorized},
+		{
+			name:       "cross origin",
+			target:     "/ws?user=alice",
+			headers:    upgrade,
+			origin:     "https://evil.example",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			if test.origin != "" {
+				req.Header.Set(fiber.HeaderOrigin, test.origin)
+			}
+
+			resp, err := app.Test(req)
+			testutils.Equal(t, err, nil)
+			testutils.Equal(t, resp.StatusCode, test.wantStatus)
+		})
+	}
+}
+
+func TestHubDelivery(t *testing.T) {
+	client, processor := newTestMetrics(t)
+	hub, target := startHub(t, HubConfig{Metrics: client})
+
+	alice := dial(t, target+"?user=alice&room=lobby")
+	bob := dial(t, target+"?user=bob")
+
+	waitFor(t, func() bool { return hub.Connections() == 2 })
+	testutils.Equal(t, hub.ConnectedUsers().Contains("alice"), true)
+	testutils.Equal(t, hub.RoomSize("lobby"), 1)
+
+	testutils.Equal(t, hub.SendToUser("bob", point{X: 1}), nil)
+	testutils.Equal(t, readText(t, bob), `{"x":1,"y":0}`)
+
+	testutils.Equal(t, hub.SendToRoom("lobby", point{X: 2}), nil)
+	testutils.Equal(t, readText(t, alice), `{"x":2,"y":0}`)
+
+	testutils.Equal(t, hub.Broadcast(point{X: 3}), nil)
+	testutils.Equal(t, readText(t, alice), `{"x":3,"y":0}`)
+	testutils.Equal(t, readText(t, bob), `{"x":3,"y":0}`)
+
+	testutils.Equal(t, errors.Is(hub.SendToUser("carol", point{}), ErrUserNotConnected), true)
+
+	testutils.Equal(t, processor.count(metricMessagesSent), int64(4))
+
+	_ = bob.Close()
+
+	waitFor(t, func() bool { return hub.Connections() == 1 })
+	testutils.Equal(t, hub.ConnectedUsers().Contains("bob"), false)
+}
+
+func TestHubOnMessageAndRooms(t *testing.T) {
+	received := make(chan point, 1)
+
+	hub, target := startHub(t, HubConfig{
+		OnMessage: func(conn *Conn, msg Message) {
+			var value point
+			if err := conn.Decode(msg, &value); err != nil {
+				return
+			}
+
+			conn.Join("room-" + conn.UserID)
+			received <- value
+		},
+	})
+
+	alice := dial(t, target+"?user=alice")
+	testutils.Equal(t, alice.WriteMessage(ws.TextMessage, []byte(`{"x":5,"y":6}`)), nil)
+
+	select {
+	case value := <-received:
+		testutils.Equal(t, value, point{X: 5, Y: 6})
+	case <-time.After(testTimeout):
+		t.Fatal("message was not received")
+	}
+
+	waitFor(t, func() bool { return hub.RoomSize("room-alice") == 1 })
+	testutils.Equal(t, hub.SendToRoom("room-alice", point{X: 7}), nil)
+	testutils.Equal(t, readText(t, alice), `{"x":7,"y":0}`)
+}
+
+func TestHubMessageSizeLimit(t *testing.T) {
+	hub, target := startHub(t, HubConfig{MaxMessageSize: 8})
+
+	conn := dial(t, target+"?user=alice")
+	waitFor(t, func() bool { return hub.Connections() == 1 })
+
+	testutils.Equal(t, conn.WriteMessage(ws.TextMessage, []byte("too large message")), nil)
+
+	_ = conn.SetReadDeadline(time.Now().Add(testTimeout))
+	_, _, err := conn.ReadMessage()
+	testutils.Equal(t, ws.IsCloseError(err, ws.CloseMessageTooBig), true)
+
+	waitFor(t, func() bool { return hub.Connections() == 0 })
+}
+
+func TestHubPongTimeout(t *testing.T) {
+	hub, target := startHub(t, HubConfig{
+		PingInterval: 20 * time.Millisecond,
+		PongTimeout:  60 * time.Millisecond,
+	})
+
+	// The client never reads, so it never answers pings.
+	dial(t, target+"?user=alice")
+
+	waitFor(t, func() bool { return hub.Connections() == 1 })
+	waitFor(t, func() bool { return hub.Connections() == 0 })
+}
+
+func TestHubBackpressure(t *testing.T) {
+	cases := []struct {
+		name         string
+		backpressure Backpressure
+		wantClosed   bool
+	}{
+		{name: "drop keeps the connection", backpressure: BackpressureDrop},
+		{name: "close evicts the connection", backpressure: BackpressureClose, wantClosed: true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			client, processor := newTestMetrics(t)
+
+			hub, err := NewHub(HubConfig{QueueSize: 1, Backpressure: test.backpressure, Metrics: client})
+			testutils.Equal(t, err, nil)
+
+			conn, err := hub.register(nil, "alice", nil)
+			testutils.Equal(t, err, nil)
+
+			testutils.Equal(t, conn.Send(point{}), nil)
+			testutils.Equal(t, errors.Is(conn.Send(point{}), ErrQueueFull), true)
+			testutils.Equal(t, processor.count(metricMessagesDropped), int64(1))
+
+			select {
+			case <-conn.Done():
+				testutils.Equal(t, test.wantClosed, true)
+				testutils.Equal(t, conn.closeCode, ws.CloseTryAgainLater)
+				testutils.Equal(t, errors.Is(conn.Send(point{}), ErrConnectionClosed), true)
+			default:
+				testutils.Equal(t, test.wantClosed, false)
+			}
+		})
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	hub, target := startHub(t, HubConfig{})
+
+	conn := dial(t, target+"?user=alice")
+	waitFor(t, func() bool { return hub.Connections() == 1 })
+
+	testutils.Equal(t, hub.Close(), nil)
+
+	_ = conn.SetReadDeadline(time.Now().Add(testTimeout))
+	_, _, err := conn.ReadMessage()
+	testutils.Equal(t, ws.IsCloseError(err, ws.CloseGoingAway), true)
+	testutils.Equal(t, errors.Is(hub.Broadcast(point{}), ErrHubClosed), true)
+}